@@ -0,0 +1,146 @@
+package main
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var certsCmd = &cobra.Command{
+	Use:   "certs",
+	Short: "Generate a local CA plus server and client certificates for mutual TLS in dev and tests",
+	Long: `Writes ca.pem, server.pem/server-key.pem and client.pem/client-key.pem to --out.
+Point b-service at TLS_CERT_FILE=server.pem, TLS_KEY_FILE=server-key.pem and TLS_CLIENT_CA_FILE=ca.pem,
+and a-service at TLS_CA_FILE=ca.pem, TLS_CERT_FILE=client.pem and TLS_KEY_FILE=client-key.pem.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		out, _ := cmd.Flags().GetString("out")
+		hosts, _ := cmd.Flags().GetString("hosts")
+		clientCN, _ := cmd.Flags().GetString("client-cn")
+		validFor, _ := cmd.Flags().GetDuration("valid-for")
+
+		if err := generateCerts(out, strings.Split(hosts, ","), clientCN, validFor); err != nil {
+			fmt.Printf("Error generating certificates: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Certificates written to %s\n", out)
+	},
+}
+
+func init() {
+	certsCmd.Flags().String("out", "./certs", "Directory the PEM files are written to")
+	certsCmd.Flags().String("hosts", "localhost,127.0.0.1,b-service", "Comma separated DNS names and IPs of the server certificate")
+	certsCmd.Flags().String("client-cn", "a-service", "Common name of the client certificate")
+	certsCmd.Flags().Duration("valid-for", 365*24*time.Hour, "Validity of every certificate")
+}
+
+func generateCerts(out string, hosts []string, clientCN string, validFor time.Duration) error {
+	if err := os.MkdirAll(out, 0o755); err != nil {
+		return err
+	}
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+	caTmpl, err := certTemplate("worlder dev CA", validFor)
+	if err != nil {
+		return err
+	}
+	caTmpl.IsCA = true
+	caTmpl.BasicConstraintsValid = true
+	caTmpl.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageCRLSign
+	caDER, err := x509.CreateCertificate(rand.Reader, caTmpl, caTmpl, caKey.Public(), caKey)
+	if err != nil {
+		return err
+	}
+	caCert, err := x509.ParseCertificate(caDER)
+	if err != nil {
+		return err
+	}
+	if err := writePEM(filepath.Join(out, "ca.pem"), "CERTIFICATE", caDER, 0o644); err != nil {
+		return err
+	}
+	if err := writeKey(filepath.Join(out, "ca-key.pem"), caKey); err != nil {
+		return err
+	}
+
+	serverTmpl, err := certTemplate(hosts[0], validFor)
+	if err != nil {
+		return err
+	}
+	serverTmpl.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
+	for _, h := range hosts {
+		h = strings.TrimSpace(h)
+		if ip := net.ParseIP(h); ip != nil {
+			serverTmpl.IPAddresses = append(serverTmpl.IPAddresses, ip)
+		} else if h != "" {
+			serverTmpl.DNSNames = append(serverTmpl.DNSNames, h)
+		}
+	}
+	if err := issue(out, "server", serverTmpl, caCert, caKey); err != nil {
+		return err
+	}
+
+	clientTmpl, err := certTemplate(clientCN, validFor)
+	if err != nil {
+		return err
+	}
+	clientTmpl.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}
+	return issue(out, "client", clientTmpl, caCert, caKey)
+}
+
+func certTemplate(cn string, validFor time.Duration) (*x509.Certificate, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	return &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(validFor),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}, nil
+}
+
+// issue signs tmpl with the CA and writes <name>.pem and <name>-key.pem.
+func issue(out, name string, tmpl, ca *x509.Certificate, caKey crypto.Signer) error {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca, key.Public(), caKey)
+	if err != nil {
+		return err
+	}
+	if err := writePEM(filepath.Join(out, name+".pem"), "CERTIFICATE", der, 0o644); err != nil {
+		return err
+	}
+	return writeKey(filepath.Join(out, name+"-key.pem"), key)
+}
+
+func writeKey(path string, key *ecdsa.PrivateKey) error {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return err
+	}
+	return writePEM(path, "PRIVATE KEY", der, 0o600)
+}
+
+func writePEM(path, blockType string, der []byte, perm os.FileMode) error {
+	return os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), perm)
+}
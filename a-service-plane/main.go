@@ -5,12 +5,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"net/http"
 	"os"
 	"sync"
 	"time"
 
+	"github.com/Yusufzhafir/worlder-team-assignment/common/logging"
 	"github.com/spf13/cobra"
 )
 
@@ -41,8 +42,23 @@ type AggregatedStats struct {
 var rootCmd = &cobra.Command{
 	Use:   "service-cli",
 	Short: "CLI tool for managing API endpoints across multiple ports",
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		logger, _, err := logging.New(logging.Opts{Level: logLevel, Format: logFormat})
+		if err != nil {
+			return err
+		}
+		slog.SetDefault(logger)
+		return nil
+	},
 }
 
+var (
+	logLevel  string
+	logFormat string
+	// requestID is sent with every request of one invocation, so the services log it under one ID
+	requestID = logging.NewRequestID()
+)
+
 func main() {
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Println(err)
@@ -51,10 +67,14 @@ func main() {
 }
 
 func init() {
+	rootCmd.PersistentFlags().StringVar(&logLevel, "log-level", "info", "debug, info, warn or error")
+	rootCmd.PersistentFlags().StringVar(&logFormat, "log-format", logging.FormatText, "text or json")
+	rootCmd.AddCommand(logLevelCmd)
 	rootCmd.AddCommand(frequencyCmd)
 	rootCmd.AddCommand(startCmd)
 	rootCmd.AddCommand(stopCmd)
 	rootCmd.AddCommand(statsCmd)
+	rootCmd.AddCommand(certsCmd)
 }
 
 var frequencyCmd = &cobra.Command{
@@ -72,6 +92,19 @@ var frequencyCmd = &cobra.Command{
 	},
 }
 
+var logLevelCmd = &cobra.Command{
+	Use:   "log-level [level]",
+	Short: "Set the log level of all endpoints until they restart",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if _, err := logging.ParseLevel(args[0]); err != nil {
+			return err
+		}
+		makeRequestToAllPorts("PUT", "/api/v1/admin/log-level", logging.LevelRequest{Level: args[0]})
+		return nil
+	},
+}
+
 var startCmd = &cobra.Command{
 	Use:   "start",
 	Short: "Start all endpoints",
@@ -113,7 +146,7 @@ func makeRequestToAllPorts(method, endpoint string, payload interface{}) {
 			if payload != nil {
 				jsonData, err := json.Marshal(payload)
 				if err != nil {
-					log.Printf("Error marshaling payload for port %d: %v", p, err)
+					slog.Error("failed to marshal payload", "port", p, "error", err)
 					return
 				}
 				body = bytes.NewBuffer(jsonData)
@@ -121,11 +154,12 @@ func makeRequestToAllPorts(method, endpoint string, payload interface{}) {
 
 			req, err := http.NewRequest(method, url, body)
 			if err != nil {
-				log.Printf("Error creating request for port %d: %v", p, err)
+				slog.Error("failed to create request", "port", p, "error", err)
 				return
 			}
 
 			req.Header.Set("Accept", "application/json")
+			req.Header.Set(logging.RequestIDHeader, requestID)
 			if payload != nil {
 				req.Header.Set("Content-Type", "application/json")
 			}
@@ -133,7 +167,7 @@ func makeRequestToAllPorts(method, endpoint string, payload interface{}) {
 			client := &http.Client{Timeout: 10 * time.Second}
 			resp, err := client.Do(req)
 			if err != nil {
-				log.Printf("Error making request to port %d: %v", p, err)
+				slog.Error("request failed", "port", p, "error", err, "request_id", requestID)
 				return
 			}
 			defer resp.Body.Close()
@@ -164,28 +198,29 @@ func getAggregatedStats() {
 
 			req, err := http.NewRequest("GET", url, nil)
 			if err != nil {
-				log.Printf("Error creating request for port %d: %v", p, err)
+				slog.Error("failed to create request", "port", p, "error", err)
 				return
 			}
 
 			req.Header.Set("Accept", "application/json")
+			req.Header.Set(logging.RequestIDHeader, requestID)
 
 			client := &http.Client{Timeout: 10 * time.Second}
 			resp, err := client.Do(req)
 			if err != nil {
-				log.Printf("Error making request to port %d: %v", p, err)
+				slog.Error("request failed", "port", p, "error", err, "request_id", requestID)
 				return
 			}
 			defer resp.Body.Close()
 
 			if resp.StatusCode != 200 {
-				log.Printf("Port %d returned HTTP %d", p, resp.StatusCode)
+				slog.Warn("unexpected HTTP status", "port", p, "status", resp.StatusCode, "request_id", requestID)
 				return
 			}
 
 			var statsResp StatsResponse
 			if err := json.NewDecoder(resp.Body).Decode(&statsResp); err != nil {
-				log.Printf("Error decoding response from port %d: %v", p, err)
+				slog.Error("failed to decode response", "port", p, "error", err, "request_id", requestID)
 				return
 			}
 
@@ -0,0 +1,141 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	commonConfig "github.com/Yusufzhafir/worlder-team-assignment/common/config"
+	"github.com/Yusufzhafir/worlder-team-assignment/common/logging"
+	"github.com/Yusufzhafir/worlder-team-assignment/common/tracing"
+)
+
+// DefaultPath is read at start when it exists, relative to the working directory.
+const DefaultPath = "config/config.yaml"
+
+// Config is everything a-service reads at start. config.yaml next to this file
+// holds the defaults, go run ./a-service -print-defaults regenerates it.
+type Config struct {
+	HTTPAddr        string        `yaml:"http_addr" env:"HTTP_ADDR" usage:"REST listen address"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env:"SHUTDOWN_TIMEOUT" usage:"how long the trace flush may take"`
+	ServerAddr      string        `yaml:"server_addr" env:"SERVER_ADDR" usage:"b-service gRPC address"`
+	AuthToken       string        `yaml:"auth_token" env:"AUTH_TOKEN" secret:"true" usage:"bearer token sent to b-service, none when empty"`
+
+	Log       Log       `yaml:"log" env:"LOG_"`
+	Tracing   Tracing   `yaml:"tracing" env:"TRACING_"`
+	TLS       TLS       `yaml:"tls" env:"TLS_"`
+	Generator Generator `yaml:"generator" usage:"what the generator sends until POST /api/v1/config changes it"`
+}
+
+type Log struct {
+	Level  string `yaml:"level" env:"LEVEL" usage:"debug, info, warn or error, PUT /api/v1/admin/log-level changes it at runtime"`
+	Format string `yaml:"format" env:"FORMAT" usage:"text or json"`
+}
+
+type Tracing struct {
+	Exporter      string `yaml:"exporter" env:"EXPORTER" usage:"none, otlp, stdout or file"`
+	OTLPEndpoint  string `yaml:"otlp_endpoint" env:"OTLP_ENDPOINT" usage:"OTLP/gRPC collector, localhost:4317 when empty"`
+	OTLPInsecure  bool   `yaml:"otlp_insecure" env:"OTLP_INSECURE" usage:"talk to the collector without TLS"`
+	File          string `yaml:"file" env:"FILE" usage:"JSON lines file of the file exporter"`
+	SamplePercent int    `yaml:"sample_percent" env:"SAMPLE_PERCENT" usage:"share of new traces recorded"`
+}
+
+type TLS struct {
+	CAFile     string `yaml:"ca_file" env:"CA_FILE" usage:"dial b-service over TLS, trusting this CA"`
+	CertFile   string `yaml:"cert_file" env:"CERT_FILE" usage:"client certificate for b-service's mutual TLS"`
+	KeyFile    string `yaml:"key_file" env:"KEY_FILE"`
+	ServerName string `yaml:"server_name" env:"SERVER_NAME" usage:"overrides the name checked against b-service's certificate"`
+}
+
+type Generator struct {
+	SensorType string  `yaml:"sensor_type" env:"SENSOR_TYPE"`
+	Value      float64 `yaml:"value" env:"SENSOR_VALUE"`
+	ID1        string  `yaml:"id1" env:"ID1"`
+	ID2        int32   `yaml:"id2" env:"ID2"`
+	RateHz     float64 `yaml:"rate_hz" env:"RATE_HZ" usage:"readings per second"`
+	Mode       string  `yaml:"mode" env:"GENERATOR_MODE" usage:"unary or stream"`
+}
+
+// Interval is the time between readings at RateHz.
+func (g Generator) Interval() time.Duration {
+	return time.Duration(float64(time.Second) / g.RateHz)
+}
+
+func Default() Config {
+	return Config{
+		HTTPAddr:        ":9000",
+		ShutdownTimeout: 5 * time.Second,
+		ServerAddr:      "localhost:50051",
+		Log: Log{
+			Level:  "info",
+			Format: logging.FormatText,
+		},
+		Tracing: Tracing{
+			Exporter:      tracing.ExporterNone,
+			OTLPInsecure:  true,
+			File:          "./traces.jsonl",
+			SamplePercent: 100,
+		},
+		Generator: Generator{
+			SensorType: "TEMP",
+			Value:      10,
+			ID1:        "ABCDEFGH",
+			ID2:        1,
+			RateHz:     1,
+			Mode:       "unary",
+		},
+	}
+}
+
+// Load starts from Default and applies the config file, the environment and
+// args in that order. It returns commonConfig.ErrPrinted after -h or
+// -print-defaults.
+func Load(args []string) (Config, string, error) {
+	cfg := Default()
+	file, err := commonConfig.Load(&cfg, commonConfig.LoadOpts{Args: args, DefaultPath: DefaultPath})
+	if err != nil {
+		return cfg, file, err
+	}
+	return cfg, file, cfg.Validate()
+}
+
+// Validate reports every invalid field at once.
+func (c Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+	checkAddr := func(name, addr string) {
+		_, _, err := net.SplitHostPort(addr)
+		check(err == nil, "%s %q is not host:port", name, addr)
+	}
+
+	checkAddr("http_addr", c.HTTPAddr)
+	checkAddr("server_addr", c.ServerAddr)
+	check(c.ShutdownTimeout > 0, "shutdown_timeout must be positive")
+
+	_, err := logging.ParseLevel(c.Log.Level)
+	check(err == nil, "log.level: %v", err)
+	check(c.Log.Format == logging.FormatText || c.Log.Format == logging.FormatJSON, "log.format %q is not text or json", c.Log.Format)
+
+	switch c.Tracing.Exporter {
+	case tracing.ExporterNone, tracing.ExporterOTLP, tracing.ExporterStdout, tracing.ExporterFile:
+	default:
+		check(false, "tracing.exporter %q is not none, otlp, stdout or file", c.Tracing.Exporter)
+	}
+	check(c.Tracing.Exporter != tracing.ExporterFile || c.Tracing.File != "", "tracing.file is needed by the file exporter")
+	check(c.Tracing.SamplePercent >= 0 && c.Tracing.SamplePercent <= 100, "tracing.sample_percent must be 0..100")
+
+	check((c.TLS.CertFile == "") == (c.TLS.KeyFile == ""), "tls.cert_file and tls.key_file go together")
+	check(c.TLS.CertFile == "" || c.TLS.CAFile != "", "tls.cert_file needs tls.ca_file")
+
+	check(c.Generator.SensorType != "", "generator.sensor_type is needed")
+	check(c.Generator.ID1 != "", "generator.id1 is needed")
+	check(c.Generator.RateHz > 0 && c.Generator.RateHz <= 1e6, "generator.rate_hz must be in (0, 1000000]")
+	check(c.Generator.Mode == "unary" || c.Generator.Mode == "stream", "generator.mode %q is not unary or stream", c.Generator.Mode)
+
+	return errors.Join(errs...)
+}
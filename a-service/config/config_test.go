@@ -0,0 +1,68 @@
+package config
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	commonConfig "github.com/Yusufzhafir/worlder-team-assignment/common/config"
+)
+
+// config.yaml is what -print-defaults writes, regenerate it after changing a default.
+func TestDefaultFileIsCurrent(t *testing.T) {
+	want, err := commonConfig.Marshal(Default())
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	got, err := os.ReadFile("config.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(want) {
+		t.Fatal("config.yaml is stale, run go run ./a-service -print-defaults > a-service/config/config.yaml")
+	}
+}
+
+func TestLoad(t *testing.T) {
+	t.Setenv("SERVER_ADDR", "b-service:50051")
+	t.Setenv("RATE_HZ", "10")
+
+	cfg, _, err := Load([]string{"-config", "config.yaml", "-generator.mode", "stream"})
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.ServerAddr != "b-service:50051" || cfg.Generator.Mode != "stream" || cfg.Generator.Interval() != 100*time.Millisecond {
+		t.Fatalf("Load = %+v", cfg)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		change  func(c *Config)
+		wantErr string
+	}{
+		{name: "defaults", change: func(c *Config) {}},
+		{name: "bad server address", change: func(c *Config) { c.ServerAddr = "b-service" }, wantErr: "server_addr"},
+		{name: "cert without CA", change: func(c *Config) { c.TLS.CertFile, c.TLS.KeyFile = "a.pem", "a.key" }, wantErr: "tls.ca_file"},
+		{name: "zero rate", change: func(c *Config) { c.Generator.RateHz = 0 }, wantErr: "generator.rate_hz"},
+		{name: "bad mode", change: func(c *Config) { c.Generator.Mode = "batch" }, wantErr: "generator.mode"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Default()
+			tt.change(&cfg)
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Validate: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Validate = %v, want an error about %s", err, tt.wantErr)
+			}
+		})
+	}
+}
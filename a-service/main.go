@@ -2,8 +2,11 @@
 package main
 
 import (
+	"context"
+	"crypto/tls"
 	"errors"
-	"log"
+	"io/fs"
+	"log/slog"
 	"net/http"
 	"os"
 
@@ -11,9 +14,16 @@ import (
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 
+	"github.com/Yusufzhafir/worlder-team-assignment/a-service/config"
 	"github.com/Yusufzhafir/worlder-team-assignment/a-service/router"
+	"github.com/Yusufzhafir/worlder-team-assignment/a-service/router/admin"
 	"github.com/Yusufzhafir/worlder-team-assignment/a-service/router/generator"
 	"github.com/Yusufzhafir/worlder-team-assignment/a-service/usecase"
+	commonConfig "github.com/Yusufzhafir/worlder-team-assignment/common/config"
+	"github.com/Yusufzhafir/worlder-team-assignment/common/logging"
+	"github.com/Yusufzhafir/worlder-team-assignment/common/tlsconfig"
+	"github.com/Yusufzhafir/worlder-team-assignment/common/tracing"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/labstack/echo/otelecho"
 )
 
 //	@title			WORLDER TEAM ASSIGNMENT
@@ -59,43 +69,101 @@ import (
 // @authorizationUrl						https://example.com/oauth/authorize
 // @scope.admin							Grants read and write access to administrative information
 func main() {
-	err := godotenv.Load()
-	logger := log.Default()
-
+	// a .env file is optional, its variables never override the real environment
+	if err := godotenv.Load(); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		fatal("failed to read .env", "error", err)
+	}
+	// defaults, then config/config.yaml, then the environment, then flags
+	cfg, cfgFile, err := config.Load(os.Args[1:])
+	if errors.Is(err, commonConfig.ErrPrinted) {
+		return
+	}
+	if err != nil {
+		fatal("invalid config", "error", err)
+	}
+	// log.level can be changed at runtime through PUT /api/v1/admin/log-level
+	logger, logLevel, err := logging.New(logging.Opts{
+		Service: "a-service",
+		Level:   cfg.Log.Level,
+		Format:  cfg.Log.Format,
+	})
+	if err != nil {
+		fatal("invalid logging config", "error", err)
+	}
+	slog.SetDefault(logger)
+	if cfgFile != "" {
+		logger.Info("read config file", "path", cfgFile)
+	}
+	// traces start here and continue into b-service, tracing.exporter none records nothing
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Opts{
+		ServiceName: "a-service",
+		Exporter:    cfg.Tracing.Exporter,
+		Endpoint:    cfg.Tracing.OTLPEndpoint,
+		Insecure:    cfg.Tracing.OTLPInsecure,
+		File:        cfg.Tracing.File,
+		SampleRatio: float64(cfg.Tracing.SamplePercent) / 100,
+	})
 	if err != nil {
-		logger.Fatal("Error loading .env file")
+		fatal("Failed to set up tracing", "error", err)
 	}
+	defer func() {
+		shCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+		defer cancel()
+		if err := shutdownTracing(shCtx); err != nil {
+			logger.Error("failed to flush traces", "error", err)
+		}
+	}()
+
 	// Initialize data generator
-	var connString string
-	if val := os.Getenv("SERVER_ADDR"); val != "" {
-		connString = val
-	} else {
-		connString = "localhost:50051"
+	logger.Info("dialing b-service", "addr", cfg.ServerAddr)
+	// TLS towards b-service, client certificate only when b-service verifies clients
+	var tlsConfig *tls.Config
+	if cfg.TLS.CAFile != "" {
+		tlsConfig, err = tlsconfig.NewClientTLS(cfg.TLS.CAFile, cfg.TLS.CertFile, cfg.TLS.KeyFile, cfg.TLS.ServerName)
+		if err != nil {
+			fatal("Failed to load TLS config", "error", err)
+		}
+		logger.Info("dialing b-service over TLS")
 	}
-	logger.Print("connection string :", connString)
-	dg := usecase.NewDataGenerator(connString)
+	dg := usecase.NewDataGenerator(cfg.ServerAddr, cfg.AuthToken, tlsConfig)
+	dg.Config(usecase.GeneratorConfig{
+		Value: cfg.Generator.Value,
+		Type:  cfg.Generator.SensorType,
+		ID1:   cfg.Generator.ID1,
+		ID2:   cfg.Generator.ID2,
+		Mode:  cfg.Generator.Mode,
+	})
+	dg.SetFrequency(cfg.Generator.Interval())
 	err = dg.Connect()
 	if err != nil {
-		log.Fatalf("Failed to connect to gRPC server: %v", err)
+		fatal("Failed to connect to gRPC server", "error", err)
 	}
 	defer dg.Close()
 
 	// Initialize Echo
 	e := echo.New()
-	e.Use(middleware.Logger())
+	e.Use(otelecho.Middleware("a-service"))
+	e.Use(logging.EchoMiddleware(logger))
 	e.Use(middleware.Recover())
 	e.Use(middleware.CORS())
 
 	generatorRouter := generator.NewGeneratorRouter(&dg)
 	router.BindRouter(router.BindRouterOpts{
-		E:      e,
-		Router: generatorRouter,
+		E:           e,
+		Router:      generatorRouter,
+		AdminRouter: admin.NewAdminRouter(logLevel),
 	})
 
-	log.Println("Starting Microservice A on :9000")
+	logger.Info("Starting Microservice A", "addr", cfg.HTTPAddr)
 
-	if err = e.Start(":9000"); err != nil && !errors.Is(err, http.ErrServerClosed) {
-		log.Fatalf("crashed the server %v", err)
+	if err = e.Start(cfg.HTTPAddr); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		fatal("crashed the server", "error", err)
 	}
-	log.Println("servers stopped cleanly")
+	logger.Info("servers stopped cleanly")
+}
+
+// fatal logs msg at error level and exits, slog has no Fatal.
+func fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
 }
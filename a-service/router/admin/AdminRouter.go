@@ -0,0 +1,69 @@
+package admin
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	httpModels "github.com/Yusufzhafir/worlder-team-assignment/a-service/shared/model"
+	"github.com/Yusufzhafir/worlder-team-assignment/common/logging"
+	"github.com/labstack/echo/v4"
+)
+
+type AdminRouter interface {
+	GetLogLevel(ctx echo.Context) error
+	SetLogLevel(ctx echo.Context) error
+}
+
+type adminRouterImpl struct {
+	logLevel *slog.LevelVar
+}
+
+func NewAdminRouter(logLevel *slog.LevelVar) AdminRouter {
+	return &adminRouterImpl{
+		logLevel: logLevel,
+	}
+}
+
+// GetLogLevel godoc
+// @Summary Current log level
+// @Tags admin
+// @Produce json
+// @Success 200 {object} model.Envelope{data=logging.LevelRequest} "data: the level"
+// @Router /admin/log-level [get]
+func (a *adminRouterImpl) GetLogLevel(ctx echo.Context) error {
+	return ctx.JSON(http.StatusOK, httpModels.Body[logging.LevelRequest]{
+		Data: logging.LevelRequest{Level: a.logLevel.Level().String()},
+	})
+}
+
+// SetLogLevel godoc
+// @Summary Change the log level
+// @Description Takes effect at once and lasts until the process restarts, LOG_LEVEL applies again on the next start
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param body body logging.LevelRequest true "debug, info, warn or error"
+// @Success 200 {object} model.Envelope{data=logging.LevelRequest} "data: the new level"
+// @Failure 400 {object} model.Envelope{data=model.Empty} "error=true, message explains"
+// @Router /admin/log-level [put]
+func (a *adminRouterImpl) SetLogLevel(ctx echo.Context) error {
+	var body logging.LevelRequest
+	if err := ctx.Bind(&body); err != nil {
+		return ctx.JSON(http.StatusBadRequest, httpModels.Body[httpModels.Empty]{
+			Error:   true,
+			Message: fmt.Sprintf("Invalid request body: %v", err),
+		})
+	}
+	level, err := logging.SetLevel(ctx.Request().Context(), a.logLevel, body.Level)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, httpModels.Body[httpModels.Empty]{
+			Error:   true,
+			Message: err.Error(),
+		})
+	}
+	return ctx.JSON(http.StatusOK, httpModels.Body[logging.LevelRequest]{
+		Data:    logging.LevelRequest{Level: level.String()},
+		Message: "log level changed",
+	})
+}
@@ -2,7 +2,6 @@ package generator
 
 import (
 	"fmt"
-	"log"
 	"net/http"
 	"time"
 
@@ -65,7 +64,6 @@ func (g *generatorRouterImpl) Config(ctx echo.Context) error {
 // @Router /stats [get]
 func (g *generatorRouterImpl) GetStats(ctx echo.Context) error {
 	result := (*g.usecase).GetDetailedStats()
-	log.Default().Printf("output of stats %v", result)
 	return ctx.JSON(http.StatusOK, httpModels.Body[interface{}]{
 		Data:    result,
 		Error:   false,
@@ -127,7 +125,7 @@ func (g *generatorRouterImpl) SpamRequests(ctx echo.Context) error {
 			Message: fmt.Sprintf("Invalid request body: %v", err),
 		})
 	}
-	result := (*g.usecase).SpamRequests(requestConfig)
+	result := (*g.usecase).SpamRequests(ctx.Request().Context(), requestConfig)
 
 	return ctx.JSON(http.StatusOK, httpModels.Body[interface{}]{
 		Data:    result,
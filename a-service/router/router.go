@@ -2,6 +2,7 @@ package router
 
 import (
 	_ "github.com/Yusufzhafir/worlder-team-assignment/a-service/docs"
+	"github.com/Yusufzhafir/worlder-team-assignment/a-service/router/admin"
 	"github.com/Yusufzhafir/worlder-team-assignment/a-service/router/generator"
 	"github.com/labstack/echo/v4"
 	echoSwagger "github.com/swaggo/echo-swagger"
@@ -22,13 +23,20 @@ func bindGeneratorRoute(group *echo.Group, router generator.GeneratorRouter) {
 	group.POST("/spam", router.SpamRequests)
 }
 
+func bindAdminRoute(group *echo.Group, router admin.AdminRouter) {
+	group.GET("/admin/log-level", router.GetLogLevel)
+	group.PUT("/admin/log-level", router.SetLogLevel)
+}
+
 type BindRouterOpts struct {
-	Router generator.GeneratorRouter
-	E      *echo.Echo
+	Router      generator.GeneratorRouter
+	AdminRouter admin.AdminRouter
+	E           *echo.Echo
 }
 
 func BindRouter(opts BindRouterOpts) {
 	opts.E.GET("/swagger/*", echoSwagger.WrapHandler)
 	apiGroup := opts.E.Group("/api/v1")
 	bindGeneratorRoute(apiGroup, opts.Router)
+	bindAdminRoute(apiGroup, opts.AdminRouter)
 }
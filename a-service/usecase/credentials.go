@@ -0,0 +1,17 @@
+package usecase
+
+import "context"
+
+// tokenCredentials attaches "authorization: Bearer <token>" to every call.
+type tokenCredentials struct {
+	token string
+}
+
+func (c tokenCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + c.token}, nil
+}
+
+// RequireTransportSecurity is false so the token also works against a plaintext b-service in dev.
+func (c tokenCredentials) RequireTransportSecurity() bool {
+	return false
+}
@@ -2,16 +2,33 @@ package usecase
 
 import (
 	"context"
-	"log"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log/slog"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	"github.com/Yusufzhafir/worlder-team-assignment/common/logging"
 	pb "github.com/Yusufzhafir/worlder-team-assignment/common/protobuf"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 )
 
+var tracer = otel.Tracer("github.com/Yusufzhafir/worlder-team-assignment/a-service/usecase")
+
+// failureLogEvery samples the per-reading failure logs, an outage at a high
+// frequency would otherwise log every single reading.
+const failureLogEvery = 100
+
 type DataGenerator interface {
 	Connect() error
 	Close()
@@ -20,7 +37,7 @@ type DataGenerator interface {
 	SetFrequency(freq time.Duration)
 	IsRunning() bool
 	GetStats() (uint64, uint64)
-	SpamRequests(req SpamRequest) map[string]interface{}
+	SpamRequests(ctx context.Context, req SpamRequest) map[string]interface{}
 	Config(config GeneratorConfig)
 	GetDetailedStats() map[string]interface{}
 }
@@ -34,11 +51,25 @@ type DataGeneratorImpl struct {
 	startTime time.Time
 	// Configuration
 	serverAddr     string
+	authToken      string
+	tlsConfig      *tls.Config
 	sensorValue    float64
 	sensorType     string
 	id1            string
 	id2            int32
 	requestTimeout time.Duration
+	mode           string
+
+	// streamer carries readings when mode is modeStream
+	streamer *streamSender
+
+	// failureLog is sampled, it runs once per failed reading
+	failureLog *slog.Logger
+
+	// keyPrefix and keySeq make up the reading_key of every reading, so b-service
+	// stores a reading resent after a lost ack only once
+	keyPrefix string
+	keySeq    uint64
 
 	// Stats
 	totalSent   uint64
@@ -55,24 +86,45 @@ type SpamRequest struct {
 	ID2         int32   `json:"id2"`
 }
 
-func NewDataGenerator(serverAddr string) DataGenerator {
+const (
+	// modeUnary sends every reading as its own Readings call
+	modeUnary = "unary"
+	// modeStream pipelines readings over one IngestStream with sequence-numbered acks
+	modeStream = "stream"
+)
+
+// NewDataGenerator builds a generator for b-service at serverAddr. authToken is
+// sent as a bearer token on every call, an empty token sends none. A nil
+// tlsConfig dials without TLS.
+func NewDataGenerator(serverAddr string, authToken string, tlsConfig *tls.Config) DataGenerator {
 	return &DataGeneratorImpl{
 		serverAddr:     serverAddr,
+		authToken:      authToken,
+		tlsConfig:      tlsConfig,
 		frequency:      time.Second, // default: 1 req/sec
 		sensorValue:    10.0,
 		sensorType:     "TEMP",
 		id1:            "ABCDEFGH",
 		id2:            1,
 		requestTimeout: 1 * time.Second,
+		mode:           modeUnary,
+		failureLog:     logging.Sample(slog.Default(), failureLogEvery),
+		keyPrefix:      uuid.NewString(),
 	}
 }
 
+// nextReadingKey returns a reading_key no other reading of any generator carries.
+func (dg *DataGeneratorImpl) nextReadingKey() string {
+	return fmt.Sprintf("%s-%d", dg.keyPrefix, atomic.AddUint64(&dg.keySeq, 1))
+}
+
 type GeneratorConfig struct {
 	Value      float64 `json:"value"`
 	Type       string  `json:"type"`
 	ID1        string  `json:"id1"`
 	ID2        int32   `json:"id2"`
 	ServerAddr string  `json:"server_addr"`
+	Mode       string  `json:"mode" example:"stream"` // "unary" or "stream"
 }
 
 func (dg *DataGeneratorImpl) Config(config GeneratorConfig) {
@@ -90,6 +142,9 @@ func (dg *DataGeneratorImpl) Config(config GeneratorConfig) {
 	if config.ID2 != 0 {
 		dg.id2 = config.ID2
 	}
+	if config.Mode == modeUnary || config.Mode == modeStream {
+		dg.mode = config.Mode
+	}
 	dg.mu.Unlock()
 }
 
@@ -97,23 +152,39 @@ func (dg *DataGeneratorImpl) Connect() error {
 	dctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	conn, err := grpc.DialContext(
-		dctx,
-		dg.serverAddr,
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	creds := insecure.NewCredentials()
+	if dg.tlsConfig != nil {
+		creds = credentials.NewTLS(dg.tlsConfig)
+	}
+	opts := []grpc.DialOption{
+		grpc.WithTransportCredentials(creds),
 		grpc.WithBlock(),
 		grpc.WithDefaultCallOptions(grpc.WaitForReady(true)),
-	)
+		// sends the trace context of each call to b-service in its metadata
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+		// passes the request ID of each call on so both services log it
+		grpc.WithChainUnaryInterceptor(logging.UnaryClientInterceptor()),
+		grpc.WithChainStreamInterceptor(logging.StreamClientInterceptor()),
+	}
+	if dg.authToken != "" {
+		opts = append(opts, grpc.WithPerRPCCredentials(tokenCredentials{token: dg.authToken}))
+	}
+
+	conn, err := grpc.DialContext(dctx, dg.serverAddr, opts...)
 	if err != nil {
 		return err
 	}
 
 	dg.conn = conn
 	dg.client = pb.NewIngestServiceClient(conn)
+	dg.streamer = newStreamSender(dg.client, &dg.totalSent, &dg.totalFailed, dg.failureLog)
 	return nil
 }
 
 func (dg *DataGeneratorImpl) Close() {
+	if dg.streamer != nil {
+		dg.streamer.Close()
+	}
 	if dg.conn != nil {
 		dg.conn.Close()
 	}
@@ -173,8 +244,14 @@ func (dg *DataGeneratorImpl) GetDetailedStats() map[string]interface{} {
 	isRunning := dg.isRunning
 	startTime := dg.startTime
 	frequency := dg.frequency
+	mode := dg.mode
 	dg.mu.RUnlock()
 
+	var inFlight int
+	if dg.streamer != nil {
+		inFlight = dg.streamer.InFlight()
+	}
+
 	var uptimeSeconds float64
 	var overallRPS float64
 
@@ -192,6 +269,8 @@ func (dg *DataGeneratorImpl) GetDetailedStats() map[string]interface{} {
 		"Overall_RPS":       overallRPS,
 		"Is_Running":        isRunning,
 		"Configured_FreqMs": frequency.Milliseconds(),
+		"Mode":              mode,
+		"In_Flight":         inFlight,
 	}
 }
 
@@ -209,10 +288,10 @@ func (dg *DataGeneratorImpl) generateContinuously() {
 			// Update ticker frequency if changed
 			dg.mu.RLock()
 			newFreq := dg.frequency
-			log.Default().Printf("this is the current frequency %v", currentFreq)
 			dg.mu.RUnlock()
 
 			if newFreq != currentFreq {
+				slog.Debug("generator frequency changed", "from", currentFreq, "to", newFreq)
 				ticker.Stop()
 				ticker = time.NewTicker(newFreq)
 				currentFreq = newFreq
@@ -224,26 +303,61 @@ func (dg *DataGeneratorImpl) generateContinuously() {
 }
 
 func (dg *DataGeneratorImpl) sendSingleReading() {
-	ctx, cancel := context.WithTimeout(context.Background(), dg.requestTimeout)
-	defer cancel()
-
-	_, err := dg.client.Readings(ctx, &pb.SensorReading{
+	reading := &pb.SensorReading{
 		Value:       dg.sensorValue,
 		SensorType:  dg.sensorType,
 		Id1:         dg.id1,
 		Id2:         dg.id2,
 		TimestampMs: time.Now().UnixMilli(),
-	})
+		ReadingKey:  dg.nextReadingKey(),
+	}
+
+	dg.mu.RLock()
+	mode := dg.mode
+	dg.mu.RUnlock()
+
+	if mode == modeStream {
+		// sent/failed are counted when the ack for this reading arrives, a reading
+		// that hit a broken stream is still pending and gets resent
+		if err := dg.streamer.Send(reading); err != nil {
+			if errors.Is(err, errTooManyInFlight) {
+				atomic.AddUint64(&dg.totalFailed, 1)
+			}
+			dg.failureLog.Warn("failed to stream reading", "error", err)
+		}
+		return
+	}
+
+	// every generated reading is its own request, b-service logs it under the same ID
+	ctx := logging.WithRequestID(context.Background(), logging.NewRequestID())
+	ctx, span := tracer.Start(ctx, "DataGenerator.sendSingleReading", trace.WithAttributes(
+		attribute.String("sensor.id1", reading.GetId1()),
+		attribute.Int("sensor.id2", int(reading.GetId2())),
+	))
+	defer span.End()
+	ctx, cancel := context.WithTimeout(ctx, dg.requestTimeout)
+	defer cancel()
+
+	_, err := dg.client.Readings(ctx, reading)
 
 	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
 		atomic.AddUint64(&dg.totalFailed, 1)
-		log.Printf("Failed to send reading: %v", err)
+		dg.failureLog.WarnContext(ctx, "failed to send reading", "error", err)
 	} else {
 		atomic.AddUint64(&dg.totalSent, 1)
 	}
 }
 
-func (dg *DataGeneratorImpl) SpamRequests(req SpamRequest) map[string]interface{} {
+// SpamRequests runs under one span, with a child span per request so slow calls stand out.
+func (dg *DataGeneratorImpl) SpamRequests(ctx context.Context, req SpamRequest) map[string]interface{} {
+	ctx, span := tracer.Start(ctx, "DataGenerator.SpamRequests", trace.WithAttributes(
+		attribute.Int("spam.total", req.Total),
+		attribute.Int("spam.concurrency", req.Concurrency),
+	))
+	defer span.End()
+
 	timeout, _ := time.ParseDuration(req.Timeout)
 	if timeout == 0 {
 		timeout = 2 * time.Second
@@ -262,15 +376,23 @@ func (dg *DataGeneratorImpl) SpamRequests(req SpamRequest) map[string]interface{
 	worker := func() {
 		defer wg.Done()
 		for range jobs {
-			ctx, cancel := context.WithTimeout(context.Background(), timeout)
-			_, err := dg.client.Readings(ctx, &pb.SensorReading{
+			callCtx, callSpan := tracer.Start(ctx, "DataGenerator.SpamRequests.call")
+			// the REST request may finish first, the calls only honour their own timeout
+			callCtx, cancel := context.WithTimeout(context.WithoutCancel(callCtx), timeout)
+			_, err := dg.client.Readings(callCtx, &pb.SensorReading{
 				Value:       req.Value,
 				SensorType:  req.Type,
 				Id1:         req.ID1,
 				Id2:         req.ID2,
 				TimestampMs: time.Now().UnixMilli(),
+				ReadingKey:  dg.nextReadingKey(),
 			})
 			cancel()
+			if err != nil {
+				callSpan.RecordError(err)
+				callSpan.SetStatus(codes.Error, err.Error())
+			}
+			callSpan.End()
 
 			if err != nil {
 				atomic.AddUint64(&fail, 1)
@@ -288,6 +410,7 @@ func (dg *DataGeneratorImpl) SpamRequests(req SpamRequest) map[string]interface{
 
 	duration := time.Since(startTime)
 	rps := float64(req.Total) / duration.Seconds()
+	span.SetAttributes(attribute.Int64("spam.failed", int64(fail)))
 
 	return map[string]interface{}{
 		"total_requests":      req.Total,
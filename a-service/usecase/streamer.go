@@ -0,0 +1,184 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+
+	"github.com/Yusufzhafir/worlder-team-assignment/common/logging"
+	pb "github.com/Yusufzhafir/worlder-team-assignment/common/protobuf"
+)
+
+// maxInFlight bounds how many unacked readings a stream may hold before Send refuses more.
+const maxInFlight = 10000
+
+// errTooManyInFlight is the only Send error that means the reading was dropped.
+var errTooManyInFlight = errors.New("too many readings waiting for an ack")
+
+// streamSender keeps many readings in flight on a single IngestStream. Every
+// reading stays in pending until the server's cumulative ack covers its seq, so
+// when the stream breaks only that unacked tail is resent on the next stream.
+type streamSender struct {
+	client pb.IngestServiceClient
+
+	// sendMu serialises writes to the stream, which keeps seqs in order on the wire
+	sendMu sync.Mutex
+
+	mu      sync.Mutex
+	nextSeq uint64
+	pending []*pb.SequencedReading
+	stream  pb.IngestService_IngestStreamClient
+	cancel  context.CancelFunc
+	recvWg  sync.WaitGroup
+
+	sent   *uint64
+	failed *uint64
+	// nackLog is sampled, a misconfigured generator gets every reading refused
+	nackLog *slog.Logger
+}
+
+func newStreamSender(client pb.IngestServiceClient, sent *uint64, failed *uint64, nackLog *slog.Logger) *streamSender {
+	return &streamSender{
+		client:  client,
+		sent:    sent,
+		failed:  failed,
+		nackLog: nackLog,
+	}
+}
+
+// Send queues reading on the stream, opening (or reopening) the stream first if needed.
+// It returns once the reading is written to the stream, not once it is acked.
+// Unless the error is errTooManyInFlight the reading stays pending, it is resent
+// on the next stream and counted as sent or failed when its ack arrives.
+func (s *streamSender) Send(reading *pb.SensorReading) error {
+	s.sendMu.Lock()
+	defer s.sendMu.Unlock()
+
+	s.mu.Lock()
+	if len(s.pending) >= maxInFlight {
+		s.mu.Unlock()
+		return fmt.Errorf("%w: %d are pending", errTooManyInFlight, len(s.pending))
+	}
+	s.nextSeq++
+	item := &pb.SequencedReading{Seq: s.nextSeq, Reading: reading}
+	s.pending = append(s.pending, item)
+	stream := s.stream
+	s.mu.Unlock()
+
+	if stream == nil {
+		// reconnecting resends every pending reading, including this one
+		return s.reconnect()
+	}
+
+	if err := stream.Send(item); err != nil {
+		s.dropStream(stream)
+		return err
+	}
+	return nil
+}
+
+// reconnect opens a fresh stream and resends the unacked tail. Callers hold sendMu.
+func (s *streamSender) reconnect() error {
+	// one request ID per stream, b-service logs the stream under it when it ends
+	ctx, cancel := context.WithCancel(logging.WithRequestID(context.Background(), logging.NewRequestID()))
+	stream, err := s.client.IngestStream(ctx)
+	if err != nil {
+		cancel()
+		return err
+	}
+
+	s.mu.Lock()
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.stream = stream
+	s.cancel = cancel
+	tail := make([]*pb.SequencedReading, len(s.pending))
+	copy(tail, s.pending)
+	s.mu.Unlock()
+
+	s.recvWg.Add(1)
+	go s.receiveAcks(stream)
+
+	// the last pending reading is the one that triggered this reconnect
+	if resent := len(tail) - 1; resent > 0 {
+		slog.InfoContext(ctx, "ingest stream reconnected, resending unacked readings", "readings", resent)
+	}
+	for _, item := range tail {
+		if err := stream.Send(item); err != nil {
+			s.dropStream(stream)
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *streamSender) receiveAcks(stream pb.IngestService_IngestStreamClient) {
+	defer s.recvWg.Done()
+
+	for {
+		ack, err := stream.Recv()
+		if err != nil {
+			s.dropStream(stream)
+			return
+		}
+
+		nacked := make(map[uint64]bool, len(ack.GetNacks()))
+		for _, nack := range ack.GetNacks() {
+			nacked[nack.GetSeq()] = true
+			s.nackLog.WarnContext(stream.Context(), "reading was refused", "seq", nack.GetSeq(), "status", nack.GetStatus().String(), "reason", nack.GetReason())
+		}
+
+		s.mu.Lock()
+		resolved := 0
+		for resolved < len(s.pending) && s.pending[resolved].GetSeq() <= ack.GetCommittedSeq() {
+			if nacked[s.pending[resolved].GetSeq()] {
+				atomic.AddUint64(s.failed, 1)
+			} else {
+				atomic.AddUint64(s.sent, 1)
+			}
+			resolved++
+		}
+		s.pending = s.pending[resolved:]
+		s.mu.Unlock()
+	}
+}
+
+// dropStream forgets stream if it is still the current one, so the next Send reconnects.
+func (s *streamSender) dropStream(stream pb.IngestService_IngestStreamClient) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.stream != stream {
+		return
+	}
+	s.stream = nil
+	if s.cancel != nil {
+		s.cancel()
+		s.cancel = nil
+	}
+}
+
+// InFlight returns how many readings are still waiting for an ack.
+func (s *streamSender) InFlight() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.pending)
+}
+
+// Close half-closes the stream and waits for the server to ack what it already received.
+func (s *streamSender) Close() {
+	s.sendMu.Lock()
+	s.mu.Lock()
+	stream := s.stream
+	s.mu.Unlock()
+	if stream != nil {
+		_ = stream.CloseSend()
+	}
+	s.sendMu.Unlock()
+
+	s.recvWg.Wait()
+}
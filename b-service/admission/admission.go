@@ -0,0 +1,180 @@
+package admission
+
+import (
+	"context"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// RetryAfterKey is the trailer carrying how many milliseconds a refused caller should back off.
+const RetryAfterKey = "retry-after-ms"
+
+// Limits bounds one gRPC method. A stream holds its slot until it ends.
+type Limits struct {
+	MaxInFlight  int           // calls running at once
+	MaxQueue     int           // calls waiting for a slot, the rest are refused right away
+	QueueTimeout time.Duration // longest a call waits for a slot
+	RetryAfter   time.Duration // back-off suggested to refused callers
+}
+
+type Stats struct {
+	InFlight    int64  `json:"in_flight"`
+	Queued      int64  `json:"queued"`
+	MaxInFlight int    `json:"max_in_flight"`
+	MaxQueue    int    `json:"max_queue"`
+	Admitted    uint64 `json:"admitted"`
+	Rejected    uint64 `json:"rejected"`
+}
+
+// Controller admits calls to the limited methods and refuses the overflow with
+// codes.ResourceExhausted. Methods without limits are not counted.
+type Controller interface {
+	UnaryInterceptor() grpc.UnaryServerInterceptor
+	StreamInterceptor() grpc.StreamServerInterceptor
+	// Stats is keyed by full method name, e.g. /sensor.IngestService/Readings.
+	Stats() map[string]Stats
+}
+
+type limiter struct {
+	limits   Limits
+	slots    chan struct{}
+	inFlight atomic.Int64
+	queued   atomic.Int64
+	admitted atomic.Uint64
+	rejected atomic.Uint64
+}
+
+type ControllerImpl struct {
+	limiters map[string]*limiter
+}
+
+type ControllerOpts struct {
+	Limits map[string]Limits // keyed by full method name
+}
+
+func NewController(opts ControllerOpts) Controller {
+	c := &ControllerImpl{limiters: make(map[string]*limiter, len(opts.Limits))}
+	for method, limits := range opts.Limits {
+		if limits.MaxInFlight <= 0 {
+			// unlimited
+			continue
+		}
+		if limits.MaxQueue < 0 {
+			limits.MaxQueue = 0
+		}
+		if limits.QueueTimeout <= 0 {
+			limits.QueueTimeout = 100 * time.Millisecond
+		}
+		if limits.RetryAfter <= 0 {
+			limits.RetryAfter = limits.QueueTimeout
+		}
+		c.limiters[method] = &limiter{
+			limits: limits,
+			slots:  make(chan struct{}, limits.MaxInFlight),
+		}
+	}
+	return c
+}
+
+// acquire takes a slot, waiting in the queue if all of them are busy.
+func (l *limiter) acquire(ctx context.Context) error {
+	select {
+	case l.slots <- struct{}{}:
+		l.admit()
+		return nil
+	default:
+	}
+
+	if l.queued.Add(1) > int64(l.limits.MaxQueue) {
+		l.queued.Add(-1)
+		return l.refuse("too many calls queued")
+	}
+	defer l.queued.Add(-1)
+
+	timer := time.NewTimer(l.limits.QueueTimeout)
+	defer timer.Stop()
+
+	select {
+	case l.slots <- struct{}{}:
+		l.admit()
+		return nil
+	case <-timer.C:
+		return l.refuse("timed out waiting for a free slot")
+	case <-ctx.Done():
+		return status.FromContextError(ctx.Err()).Err()
+	}
+}
+
+func (l *limiter) admit() {
+	l.inFlight.Add(1)
+	l.admitted.Add(1)
+}
+
+func (l *limiter) release() {
+	l.inFlight.Add(-1)
+	<-l.slots
+}
+
+func (l *limiter) refuse(reason string) error {
+	l.rejected.Add(1)
+	return status.Errorf(codes.ResourceExhausted, "server is saturated: %s, retry after %s", reason, l.limits.RetryAfter)
+}
+
+func (l *limiter) retryAfter() metadata.MD {
+	return metadata.Pairs(RetryAfterKey, strconv.FormatInt(l.limits.RetryAfter.Milliseconds(), 10))
+}
+
+func (c *ControllerImpl) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		l, ok := c.limiters[info.FullMethod]
+		if !ok {
+			return handler(ctx, req)
+		}
+		if err := l.acquire(ctx); err != nil {
+			if status.Code(err) == codes.ResourceExhausted {
+				_ = grpc.SetTrailer(ctx, l.retryAfter())
+			}
+			return nil, err
+		}
+		defer l.release()
+		return handler(ctx, req)
+	}
+}
+
+func (c *ControllerImpl) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		l, ok := c.limiters[info.FullMethod]
+		if !ok {
+			return handler(srv, ss)
+		}
+		if err := l.acquire(ss.Context()); err != nil {
+			if status.Code(err) == codes.ResourceExhausted {
+				ss.SetTrailer(l.retryAfter())
+			}
+			return err
+		}
+		defer l.release()
+		return handler(srv, ss)
+	}
+}
+
+func (c *ControllerImpl) Stats() map[string]Stats {
+	stats := make(map[string]Stats, len(c.limiters))
+	for method, l := range c.limiters {
+		stats[method] = Stats{
+			InFlight:    l.inFlight.Load(),
+			Queued:      l.queued.Load(),
+			MaxInFlight: l.limits.MaxInFlight,
+			MaxQueue:    l.limits.MaxQueue,
+			Admitted:    l.admitted.Load(),
+			Rejected:    l.rejected.Load(),
+		}
+	}
+	return stats
+}
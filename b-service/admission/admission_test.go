@@ -0,0 +1,207 @@
+package admission
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const limitedMethod = "/sensor.IngestService/Readings"
+
+// trailerStream records the trailer a unary interceptor sets through grpc.SetTrailer.
+type trailerStream struct {
+	mu      sync.Mutex
+	trailer metadata.MD
+}
+
+func (s *trailerStream) Method() string                  { return limitedMethod }
+func (s *trailerStream) SetHeader(md metadata.MD) error  { return nil }
+func (s *trailerStream) SendHeader(md metadata.MD) error { return nil }
+func (s *trailerStream) SetTrailer(md metadata.MD) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.trailer = metadata.Join(s.trailer, md)
+	return nil
+}
+
+// serverStream records the trailer a stream interceptor sets.
+type serverStream struct {
+	grpc.ServerStream
+	ctx     context.Context
+	trailer metadata.MD
+}
+
+func (s *serverStream) Context() context.Context { return s.ctx }
+func (s *serverStream) SetTrailer(md metadata.MD) {
+	s.trailer = metadata.Join(s.trailer, md)
+}
+
+// holdSlot runs a unary call on method that keeps its slot until the returned func is called.
+func holdSlot(t *testing.T, c Controller, method string) func() {
+	t.Helper()
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = c.UnaryInterceptor()(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: method}, func(ctx context.Context, req any) (any, error) {
+			close(entered)
+			<-release
+			return nil, nil
+		})
+	}()
+	<-entered
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(release)
+			<-done
+		})
+	}
+}
+
+func TestUnaryInterceptor(t *testing.T) {
+	tests := []struct {
+		name   string
+		limits Limits
+		method string // limitedMethod when empty
+		// releaseAfter frees the held slot while the call waits, never when zero
+		releaseAfter time.Duration
+		cancelAfter  time.Duration
+		wantCode     codes.Code
+		wantMessage  string
+		wantRetryMs  string
+		wantRejected uint64
+		wantAdmitted uint64
+	}{
+		{
+			name:         "queue overflow is refused at once",
+			limits:       Limits{MaxInFlight: 1, MaxQueue: 0, QueueTimeout: time.Hour, RetryAfter: 250 * time.Millisecond},
+			wantCode:     codes.ResourceExhausted,
+			wantMessage:  "too many calls queued",
+			wantRetryMs:  "250",
+			wantRejected: 1,
+			wantAdmitted: 1,
+		},
+		{
+			name:         "queued call times out",
+			limits:       Limits{MaxInFlight: 1, MaxQueue: 1, QueueTimeout: 20 * time.Millisecond},
+			wantCode:     codes.ResourceExhausted,
+			wantMessage:  "timed out waiting for a free slot",
+			wantRetryMs:  "20",
+			wantRejected: 1,
+			wantAdmitted: 1,
+		},
+		{
+			name:         "queued call gets the freed slot",
+			limits:       Limits{MaxInFlight: 1, MaxQueue: 1, QueueTimeout: time.Second},
+			releaseAfter: 20 * time.Millisecond,
+			wantCode:     codes.OK,
+			wantAdmitted: 2,
+		},
+		{
+			name:         "caller gives up while queued",
+			limits:       Limits{MaxInFlight: 1, MaxQueue: 1, QueueTimeout: time.Second},
+			cancelAfter:  20 * time.Millisecond,
+			wantCode:     codes.Canceled,
+			wantAdmitted: 1,
+		},
+		{
+			name:     "methods without limits are not counted",
+			limits:   Limits{MaxInFlight: 1},
+			method:   "/sensor.SensorQueryService/GetSensorPaginated",
+			wantCode: codes.OK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewController(ControllerOpts{Limits: map[string]Limits{limitedMethod: tt.limits}})
+			method := tt.method
+			if method == "" {
+				method = limitedMethod
+			}
+			release := holdSlot(t, c, method)
+			defer release()
+			if tt.releaseAfter > 0 {
+				time.AfterFunc(tt.releaseAfter, release)
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tt.cancelAfter > 0 {
+				time.AfterFunc(tt.cancelAfter, cancel)
+			}
+			stream := &trailerStream{}
+			ctx = grpc.NewContextWithServerTransportStream(ctx, stream)
+
+			_, err := c.UnaryInterceptor()(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method}, func(ctx context.Context, req any) (any, error) {
+				return nil, nil
+			})
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("code = %s (%v), want %s", code, err, tt.wantCode)
+			}
+			if !strings.Contains(status.Convert(err).Message(), tt.wantMessage) {
+				t.Fatalf("message %q does not mention %q", status.Convert(err).Message(), tt.wantMessage)
+			}
+			if got := strings.Join(stream.trailer.Get(RetryAfterKey), ","); got != tt.wantRetryMs {
+				t.Fatalf("%s trailer = %q, want %q", RetryAfterKey, got, tt.wantRetryMs)
+			}
+
+			release()
+			stats := c.Stats()[limitedMethod]
+			if stats.Rejected != tt.wantRejected || stats.Admitted != tt.wantAdmitted || stats.InFlight != 0 || stats.Queued != 0 {
+				t.Fatalf("stats = %+v, want %d admitted and %d rejected", stats, tt.wantAdmitted, tt.wantRejected)
+			}
+		})
+	}
+}
+
+func TestStreamInterceptor(t *testing.T) {
+	c := NewController(ControllerOpts{Limits: map[string]Limits{limitedMethod: {MaxInFlight: 1, RetryAfter: time.Second}}})
+	release := holdSlot(t, c, limitedMethod)
+	defer release()
+
+	ss := &serverStream{ctx: context.Background()}
+	err := c.StreamInterceptor()(nil, ss, &grpc.StreamServerInfo{FullMethod: limitedMethod}, func(srv any, stream grpc.ServerStream) error {
+		t.Fatal("handler ran without a slot")
+		return nil
+	})
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("code = %s, want ResourceExhausted", status.Code(err))
+	}
+	if got := ss.trailer.Get(RetryAfterKey); len(got) != 1 || got[0] != "1000" {
+		t.Fatalf("%s trailer = %v, want [1000]", RetryAfterKey, got)
+	}
+
+	// a stream holds its slot until the handler returns
+	release()
+	entered := make(chan struct{})
+	finish := make(chan struct{})
+	go func() {
+		_ = c.StreamInterceptor()(nil, &serverStream{ctx: context.Background()}, &grpc.StreamServerInfo{FullMethod: limitedMethod}, func(srv any, stream grpc.ServerStream) error {
+			close(entered)
+			<-finish
+			return nil
+		})
+	}()
+	<-entered
+	if got := c.Stats()[limitedMethod].InFlight; got != 1 {
+		t.Fatalf("in flight = %d while the stream runs, want 1", got)
+	}
+	close(finish)
+}
+
+func TestNewControllerSkipsUnlimitedMethods(t *testing.T) {
+	c := NewController(ControllerOpts{Limits: map[string]Limits{limitedMethod: {MaxInFlight: 0}}})
+	if stats := c.Stats(); len(stats) != 0 {
+		t.Fatalf("stats = %v, want no limited methods", stats)
+	}
+}
@@ -0,0 +1,224 @@
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// maxPrincipalLen matches the principal column of sensor_readings.
+const maxPrincipalLen = 64
+
+type principalKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying the authenticated caller.
+func WithPrincipal(ctx context.Context, principal string) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFromContext returns the authenticated caller, if any.
+func PrincipalFromContext(ctx context.Context) (string, bool) {
+	principal, ok := ctx.Value(principalKey{}).(string)
+	return principal, ok && principal != ""
+}
+
+// Authenticator checks the "authorization: Bearer <token>" metadata of incoming
+// calls. The token is either one of the static tokens or an HS256 JWT whose sub
+// claim names the caller. Calls without a valid token fail with codes.Unauthenticated.
+type Authenticator interface {
+	UnaryInterceptor() grpc.UnaryServerInterceptor
+	StreamInterceptor() grpc.StreamServerInterceptor
+}
+
+type staticToken struct {
+	principal string
+	token     []byte
+}
+
+type AuthenticatorImpl struct {
+	tokens      []staticToken
+	jwtSecret   []byte
+	leeway      time.Duration
+	optionalExp bool
+	exempt      []string
+	now         func() time.Time
+}
+
+type AuthenticatorOpts struct {
+	Tokens      map[string]string // principal -> static bearer token
+	JWTSecret   []byte            // verifies HS256 JWTs, JWTs are refused when empty
+	Leeway      time.Duration     // clock skew allowed on exp and nbf
+	OptionalExp bool              // accept JWTs without an exp claim, which never expire
+	Exempt      []string          // services callable without a token, e.g. grpc.health.v1.Health
+}
+
+// NewAuthenticator fails when a static token's principal would not fit the
+// principal column.
+func NewAuthenticator(opts AuthenticatorOpts) (Authenticator, error) {
+	a := &AuthenticatorImpl{
+		jwtSecret:   opts.JWTSecret,
+		leeway:      opts.Leeway,
+		optionalExp: opts.OptionalExp,
+		now:         time.Now,
+	}
+	for _, service := range opts.Exempt {
+		a.exempt = append(a.exempt, "/"+service+"/")
+	}
+	for principal, token := range opts.Tokens {
+		if principal == "" || len(principal) > maxPrincipalLen {
+			return nil, fmt.Errorf("principal %q must be 1-%d characters", principal, maxPrincipalLen)
+		}
+		if token == "" {
+			return nil, fmt.Errorf("principal %q has an empty token", principal)
+		}
+		a.tokens = append(a.tokens, staticToken{principal: principal, token: []byte(token)})
+	}
+	return a, nil
+}
+
+func (a *AuthenticatorImpl) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if a.isExempt(info.FullMethod) {
+			return handler(ctx, req)
+		}
+		principal, err := a.authenticate(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return handler(WithPrincipal(ctx, principal), req)
+	}
+}
+
+func (a *AuthenticatorImpl) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if a.isExempt(info.FullMethod) {
+			return handler(srv, ss)
+		}
+		principal, err := a.authenticate(ss.Context())
+		if err != nil {
+			return err
+		}
+		return handler(srv, &contextStream{ServerStream: ss, ctx: WithPrincipal(ss.Context(), principal)})
+	}
+}
+
+func (a *AuthenticatorImpl) isExempt(fullMethod string) bool {
+	for _, prefix := range a.exempt {
+		if strings.HasPrefix(fullMethod, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// contextStream swaps in a context carrying values added by an interceptor.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
+
+func (a *AuthenticatorImpl) authenticate(ctx context.Context) (string, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return "", status.Error(codes.Unauthenticated, "missing authorization metadata")
+	}
+
+	scheme, token, ok := strings.Cut(values[0], " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return "", status.Error(codes.Unauthenticated, "authorization must be a bearer token")
+	}
+
+	if strings.Count(token, ".") == 2 {
+		principal, err := a.verifyJWT(token)
+		if err != nil {
+			return "", status.Errorf(codes.Unauthenticated, "invalid token: %v", err)
+		}
+		return principal, nil
+	}
+
+	for _, static := range a.tokens {
+		if subtle.ConstantTimeCompare(static.token, []byte(token)) == 1 {
+			return static.principal, nil
+		}
+	}
+	return "", status.Error(codes.Unauthenticated, "invalid token")
+}
+
+type jwtHeader struct {
+	Alg string `json:"alg"`
+}
+
+type jwtClaims struct {
+	Sub string `json:"sub"`
+	Exp int64  `json:"exp"`
+	Nbf int64  `json:"nbf"`
+}
+
+func (a *AuthenticatorImpl) verifyJWT(token string) (string, error) {
+	if len(a.jwtSecret) == 0 {
+		return "", errors.New("JWTs are not accepted")
+	}
+
+	parts := strings.Split(token, ".")
+	var header jwtHeader
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return "", fmt.Errorf("bad header: %w", err)
+	}
+	if header.Alg != "HS256" {
+		return "", fmt.Errorf("unsupported alg %q", header.Alg)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", fmt.Errorf("bad signature: %w", err)
+	}
+	mac := hmac.New(sha256.New, a.jwtSecret)
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return "", errors.New("signature mismatch")
+	}
+
+	var claims jwtClaims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return "", fmt.Errorf("bad claims: %w", err)
+	}
+	now := a.now()
+	if claims.Exp == 0 && !a.optionalExp {
+		return "", errors.New("token has no exp")
+	}
+	if claims.Exp != 0 && now.After(time.Unix(claims.Exp, 0).Add(a.leeway)) {
+		return "", errors.New("token has expired")
+	}
+	if claims.Nbf != 0 && now.Add(a.leeway).Before(time.Unix(claims.Nbf, 0)) {
+		return "", errors.New("token is not valid yet")
+	}
+	if claims.Sub == "" || len(claims.Sub) > maxPrincipalLen {
+		return "", fmt.Errorf("sub must be 1-%d characters", maxPrincipalLen)
+	}
+	return claims.Sub, nil
+}
+
+func decodeSegment(segment string, v any) error {
+	raw, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, v)
+}
@@ -0,0 +1,243 @@
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	testSecret = []byte("test-secret")
+	testNow    = time.Unix(1_700_000_000, 0)
+)
+
+// signJWT builds a token with the given header and claims, signed with secret.
+func signJWT(t *testing.T, header, claims map[string]any, secret []byte) string {
+	t.Helper()
+	encode := func(v any) string {
+		raw, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return base64.RawURLEncoding.EncodeToString(raw)
+	}
+	signed := encode(header) + "." + encode(claims)
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(signed))
+	return signed + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func hs256(t *testing.T, claims map[string]any) string {
+	return signJWT(t, map[string]any{"alg": "HS256", "typ": "JWT"}, claims, testSecret)
+}
+
+// unsigned drops the signature, as an alg none token has none.
+func unsigned(t *testing.T, header, claims map[string]any) string {
+	token := signJWT(t, header, claims, testSecret)
+	return token[:strings.LastIndex(token, ".")+1]
+}
+
+func newTestAuthenticator(t *testing.T, opts AuthenticatorOpts) *AuthenticatorImpl {
+	t.Helper()
+	a, err := NewAuthenticator(opts)
+	if err != nil {
+		t.Fatalf("NewAuthenticator: %v", err)
+	}
+	impl := a.(*AuthenticatorImpl)
+	impl.now = func() time.Time { return testNow }
+	return impl
+}
+
+func TestUnaryInterceptor(t *testing.T) {
+	defaultOpts := AuthenticatorOpts{
+		Tokens:    map[string]string{"a-service": "static-token"},
+		JWTSecret: testSecret,
+		Leeway:    30 * time.Second,
+		Exempt:    []string{"grpc.health.v1.Health"},
+	}
+	exp := testNow.Add(time.Hour).Unix()
+
+	tests := []struct {
+		name          string
+		opts          *AuthenticatorOpts // defaultOpts when nil
+		method        string             // /sensor.IngestService/Readings when empty
+		authorization string             // no metadata when empty
+		wantPrincipal string
+		wantCode      codes.Code
+	}{
+		{name: "static token", authorization: "Bearer static-token", wantPrincipal: "a-service"},
+		{name: "scheme is case insensitive", authorization: "bearer static-token", wantPrincipal: "a-service"},
+		{name: "unknown static token", authorization: "Bearer other-token", wantCode: codes.Unauthenticated},
+		{name: "missing metadata", wantCode: codes.Unauthenticated},
+		{name: "not a bearer token", authorization: "Basic static-token", wantCode: codes.Unauthenticated},
+		{name: "exempt method without token", method: "/grpc.health.v1.Health/Check", wantPrincipal: ""},
+		{name: "exempt prefix only matches whole services", method: "/grpc.health.v1.HealthX/Check", wantCode: codes.Unauthenticated},
+		{
+			name:          "valid JWT",
+			authorization: "Bearer " + hs256(t, map[string]any{"sub": "device-1", "exp": exp}),
+			wantPrincipal: "device-1",
+		},
+		{
+			name:          "bad signature",
+			authorization: "Bearer " + signJWT(t, map[string]any{"alg": "HS256"}, map[string]any{"sub": "device-1", "exp": exp}, []byte("other-secret")),
+			wantCode:      codes.Unauthenticated,
+		},
+		{
+			name:          "alg none",
+			authorization: "Bearer " + unsigned(t, map[string]any{"alg": "none"}, map[string]any{"sub": "device-1", "exp": exp}),
+			wantCode:      codes.Unauthenticated,
+		},
+		{
+			name:          "alg RS256",
+			authorization: "Bearer " + signJWT(t, map[string]any{"alg": "RS256"}, map[string]any{"sub": "device-1", "exp": exp}, testSecret),
+			wantCode:      codes.Unauthenticated,
+		},
+		{
+			name:          "expired within leeway",
+			authorization: "Bearer " + hs256(t, map[string]any{"sub": "device-1", "exp": testNow.Add(-20 * time.Second).Unix()}),
+			wantPrincipal: "device-1",
+		},
+		{
+			name:          "expired past leeway",
+			authorization: "Bearer " + hs256(t, map[string]any{"sub": "device-1", "exp": testNow.Add(-time.Minute).Unix()}),
+			wantCode:      codes.Unauthenticated,
+		},
+		{
+			name:          "not before within leeway",
+			authorization: "Bearer " + hs256(t, map[string]any{"sub": "device-1", "exp": exp, "nbf": testNow.Add(20 * time.Second).Unix()}),
+			wantPrincipal: "device-1",
+		},
+		{
+			name:          "not before past leeway",
+			authorization: "Bearer " + hs256(t, map[string]any{"sub": "device-1", "exp": exp, "nbf": testNow.Add(time.Minute).Unix()}),
+			wantCode:      codes.Unauthenticated,
+		},
+		{
+			name:          "missing exp",
+			authorization: "Bearer " + hs256(t, map[string]any{"sub": "device-1"}),
+			wantCode:      codes.Unauthenticated,
+		},
+		{
+			name:          "missing exp when optional",
+			opts:          &AuthenticatorOpts{JWTSecret: testSecret, OptionalExp: true},
+			authorization: "Bearer " + hs256(t, map[string]any{"sub": "device-1"}),
+			wantPrincipal: "device-1",
+		},
+		{
+			name:          "missing sub",
+			authorization: "Bearer " + hs256(t, map[string]any{"exp": exp}),
+			wantCode:      codes.Unauthenticated,
+		},
+		{
+			name:          "sub too long",
+			authorization: "Bearer " + hs256(t, map[string]any{"sub": strings.Repeat("x", maxPrincipalLen+1), "exp": exp}),
+			wantCode:      codes.Unauthenticated,
+		},
+		{
+			name:          "JWT without a secret",
+			opts:          &AuthenticatorOpts{Tokens: map[string]string{"a-service": "static-token"}},
+			authorization: "Bearer " + hs256(t, map[string]any{"sub": "device-1", "exp": exp}),
+			wantCode:      codes.Unauthenticated,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := defaultOpts
+			if tt.opts != nil {
+				opts = *tt.opts
+			}
+			a := newTestAuthenticator(t, opts)
+
+			ctx := context.Background()
+			if tt.authorization != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", tt.authorization))
+			}
+			method := tt.method
+			if method == "" {
+				method = "/sensor.IngestService/Readings"
+			}
+
+			var principal string
+			handler := func(ctx context.Context, req any) (any, error) {
+				principal, _ = PrincipalFromContext(ctx)
+				return nil, nil
+			}
+			_, err := a.UnaryInterceptor()(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method}, handler)
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("code = %s (%v), want %s", code, err, tt.wantCode)
+			}
+			if principal != tt.wantPrincipal {
+				t.Fatalf("principal = %q, want %q", principal, tt.wantPrincipal)
+			}
+		})
+	}
+}
+
+func TestNewAuthenticatorRejectsBadTokens(t *testing.T) {
+	tests := []struct {
+		name   string
+		tokens map[string]string
+	}{
+		{name: "principal too long", tokens: map[string]string{strings.Repeat("p", maxPrincipalLen+1): "token"}},
+		{name: "empty principal", tokens: map[string]string{"": "token"}},
+		{name: "empty token", tokens: map[string]string{"a-service": ""}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewAuthenticator(AuthenticatorOpts{Tokens: tt.tokens}); err == nil {
+				t.Fatal("NewAuthenticator accepted the tokens")
+			}
+		})
+	}
+
+	if _, err := NewAuthenticator(AuthenticatorOpts{Tokens: map[string]string{strings.Repeat("p", maxPrincipalLen): "token"}}); err != nil {
+		t.Fatalf("NewAuthenticator refused a %d character principal: %v", maxPrincipalLen, err)
+	}
+}
+
+func TestCallerFromContext(t *testing.T) {
+	withCert := func(ctx context.Context, cn string) context.Context {
+		cert := &x509.Certificate{Subject: pkix.Name{CommonName: cn}}
+		ctx = peer.NewContext(ctx, &peer.Peer{AuthInfo: credentials.TLSInfo{
+			State: tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}},
+		}})
+		return withClientCN(ctx)
+	}
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		want   string
+		wantOK bool
+	}{
+		{name: "anonymous", ctx: context.Background()},
+		{name: "token", ctx: WithPrincipal(context.Background(), "a-service"), want: "a-service", wantOK: true},
+		{name: "client certificate", ctx: withCert(context.Background(), "device-1"), want: "device-1", wantOK: true},
+		{name: "token wins over certificate", ctx: WithPrincipal(withCert(context.Background(), "device-1"), "a-service"), want: "a-service", wantOK: true},
+		{name: "CN too long", ctx: withCert(context.Background(), strings.Repeat("c", maxPrincipalLen+1))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := CallerFromContext(tt.ctx)
+			if got != tt.want || ok != tt.wantOK {
+				t.Fatalf("CallerFromContext = %q, %v, want %q, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
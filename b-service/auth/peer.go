@@ -0,0 +1,57 @@
+package auth
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+type clientCNKey struct{}
+
+// ClientCNFromContext returns the common name of the verified client certificate, if any.
+func ClientCNFromContext(ctx context.Context) (string, bool) {
+	cn, ok := ctx.Value(clientCNKey{}).(string)
+	return cn, ok && cn != ""
+}
+
+// CallerFromContext returns the principal of the bearer token, or else the CN of
+// the verified client certificate, so deployments that only use mutual TLS still
+// know who made a call. A CN too long for the principal column is ignored.
+func CallerFromContext(ctx context.Context) (string, bool) {
+	if principal, ok := PrincipalFromContext(ctx); ok {
+		return principal, true
+	}
+	if cn, ok := ClientCNFromContext(ctx); ok && len(cn) <= maxPrincipalLen {
+		return cn, true
+	}
+	return "", false
+}
+
+// withClientCN stores the CN of the client certificate verified during the TLS handshake.
+func withClientCN(ctx context.Context) context.Context {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ctx
+	}
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(info.State.VerifiedChains) == 0 || len(info.State.VerifiedChains[0]) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, clientCNKey{}, info.State.VerifiedChains[0][0].Subject.CommonName)
+}
+
+// ClientCertUnaryInterceptor makes the client certificate CN available through ClientCNFromContext.
+func ClientCertUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return handler(withClientCN(ctx), req)
+	}
+}
+
+// ClientCertStreamInterceptor makes the client certificate CN available through ClientCNFromContext.
+func ClientCertStreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &contextStream{ServerStream: ss, ctx: withClientCN(ss.Context())})
+	}
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Yusufzhafir/worlder-team-assignment/b-service/config"
+	"github.com/Yusufzhafir/worlder-team-assignment/b-service/migrate"
+	"github.com/Yusufzhafir/worlder-team-assignment/b-service/repository"
+	commonConfig "github.com/Yusufzhafir/worlder-team-assignment/common/config"
+	"github.com/Yusufzhafir/worlder-team-assignment/common/logging"
+)
+
+const migrateUsage = `usage: b-service migrate up [N] | down [N] | status [config flags]
+
+  up      apply N pending migrations, all of them when N is left out
+  down    revert the last N applied migrations, 1 when N is left out
+  status  list every migration and whether it is applied
+`
+
+// runMigrate is the migrate subcommand, it returns the exit code.
+func runMigrate(ctx context.Context, args []string) int {
+	if len(args) == 0 || (args[0] != "up" && args[0] != "down" && args[0] != "status") {
+		fmt.Fprint(os.Stderr, migrateUsage)
+		return 2
+	}
+	action, args := args[0], args[1:]
+	steps := 0
+	if action == "down" {
+		steps = 1
+	}
+	if action != "status" && len(args) > 0 {
+		if n, err := strconv.Atoi(args[0]); err == nil {
+			if n <= 0 {
+				fmt.Fprint(os.Stderr, migrateUsage)
+				return 2
+			}
+			steps, args = n, args[1:]
+		}
+	}
+
+	cfg, _, err := config.Load(args)
+	if errors.Is(err, commonConfig.ErrPrinted) {
+		return 0
+	}
+	if err != nil {
+		slog.Error("invalid config", "error", err)
+		return 1
+	}
+	logger, _, err := logging.New(logging.Opts{Service: "b-service", Level: cfg.Log.Level, Format: cfg.Log.Format})
+	if err != nil {
+		slog.Error("invalid logging config", "error", err)
+		return 1
+	}
+	slog.SetDefault(logger)
+
+	if cfg.Database.BackendName() == repository.BackendMemory {
+		logger.Error("the memory backend has no schema to migrate")
+		return 1
+	}
+	repo, db, err := openStorage(ctx, cfg.Database)
+	if err != nil {
+		logger.Error("Failed to connect DB", "backend", cfg.Database.BackendName(), "error", err)
+		return 1
+	}
+	defer repo.Close()
+	migrator, err := newMigrator(cfg.Database, db, logger)
+	if err != nil {
+		logger.Error("failed to load migrations", "error", err)
+		return 1
+	}
+
+	switch action {
+	case "up":
+		done, err := migrator.Up(ctx, steps)
+		if err != nil {
+			logger.Error("migrate up failed", "applied", len(done), "error", err)
+			return 1
+		}
+		if err := createHypertable(ctx, cfg.Database, db, logger); err != nil {
+			logger.Error("failed to create the hypertable", "error", err)
+			return 1
+		}
+		logger.Info("schema is up to date", "applied", len(done))
+	case "down":
+		done, err := migrator.Down(ctx, steps)
+		if err != nil {
+			logger.Error("migrate down failed", "reverted", len(done), "error", err)
+			return 1
+		}
+		logger.Info("reverted migrations", "reverted", len(done))
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			logger.Error("migrate status failed", "error", err)
+			return 1
+		}
+		printStatus(statuses)
+	}
+	return 0
+}
+
+func printStatus(statuses []migrate.Status) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATE\tAPPLIED AT")
+	for _, s := range statuses {
+		state, appliedAt := "pending", ""
+		switch {
+		case s.Dirty:
+			state = "dirty"
+		case s.Applied && s.Name == "":
+			state = "applied, unknown to this build"
+		case s.Applied:
+			state = "applied"
+		}
+		if s.Applied {
+			appliedAt = s.AppliedAt.Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "%04d\t%s\t%s\t%s\n", s.Version, s.Name, state, appliedAt)
+	}
+	_ = w.Flush()
+}
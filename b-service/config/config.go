@@ -0,0 +1,364 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/Yusufzhafir/worlder-team-assignment/b-service/live"
+	"github.com/Yusufzhafir/worlder-team-assignment/b-service/repository"
+	commonConfig "github.com/Yusufzhafir/worlder-team-assignment/common/config"
+	"github.com/Yusufzhafir/worlder-team-assignment/common/logging"
+	"github.com/Yusufzhafir/worlder-team-assignment/common/tracing"
+)
+
+// DefaultPath is read at start when it exists, relative to the working directory.
+const DefaultPath = "config/config.yaml"
+
+// Config is everything b-service reads at start. config.yaml next to this file
+// holds the defaults, go run ./b-service -print-defaults regenerates it.
+type Config struct {
+	GRPCAddr        string        `yaml:"grpc_addr" env:"GRPC_ADDR" usage:"gRPC listen address"`
+	HTTPAddr        string        `yaml:"http_addr" env:"HTTP_ADDR" usage:"REST listen address"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env:"SHUTDOWN_TIMEOUT" usage:"how long REST shutdown and the trace flush may take"`
+
+	Log        Log        `yaml:"log" env:"LOG_"`
+	Tracing    Tracing    `yaml:"tracing" env:"TRACING_"`
+	Database   Database   `yaml:"database"`
+	TLS        TLS        `yaml:"tls" env:"TLS_"`
+	Auth       Auth       `yaml:"auth"`
+	Ingest     Ingest     `yaml:"ingest" env:"INGEST_" usage:"micro-batching of unary Readings calls"`
+	Dedup      Dedup      `yaml:"dedup" env:"DEDUP_"`
+	Validation Validation `yaml:"validation" env:"VALIDATION_"`
+	Spool      Spool      `yaml:"spool" env:"SPOOL_" usage:"local write-ahead log for readings that arrive while the database is down"`
+	Watch      Watch      `yaml:"watch" env:"WATCH_" usage:"live feed behind WatchReadings, SSE and WebSocket"`
+	Admission  Admission  `yaml:"admission" env:"ADMISSION_" usage:"bounded in-flight calls per ingest method, 0 max_in_flight leaves a method unlimited"`
+	Health     Health     `yaml:"health" env:"HEALTH_"`
+	Metrics    Metrics    `yaml:"metrics" env:"METRICS_"`
+	WebSocket  WebSocket  `yaml:"websocket" env:"WS_"`
+}
+
+type Log struct {
+	Level       string `yaml:"level" env:"LEVEL" usage:"debug, info, warn or error, PUT /api/v1/admin/log-level changes it at runtime"`
+	Format      string `yaml:"format" env:"FORMAT" usage:"text or json"`
+	SampleEvery int    `yaml:"sample_every" env:"SAMPLE_EVERY" usage:"keep one in this many successful gRPC call logs"`
+}
+
+type Tracing struct {
+	Exporter      string `yaml:"exporter" env:"EXPORTER" usage:"none, otlp, stdout or file"`
+	OTLPEndpoint  string `yaml:"otlp_endpoint" env:"OTLP_ENDPOINT" usage:"OTLP/gRPC collector, localhost:4317 when empty"`
+	OTLPInsecure  bool   `yaml:"otlp_insecure" env:"OTLP_INSECURE" usage:"talk to the collector without TLS"`
+	File          string `yaml:"file" env:"FILE" usage:"JSON lines file of the file exporter"`
+	SamplePercent int    `yaml:"sample_percent" env:"SAMPLE_PERCENT" usage:"share of new traces recorded"`
+}
+
+type Database struct {
+	Backend    string `yaml:"backend" env:"DB_BACKEND" usage:"mysql, postgres, sqlite or memory, picked by the dsn scheme when empty"`
+	SQLitePath string `yaml:"sqlite_path" env:"SQLITE_PATH" usage:"database file of the sqlite backend"`
+
+	DSN string `yaml:"dsn" env:"DB_DSN" secret:"true" usage:"postgres:// URL or MySQL DSN, a MySQL one is built from the mysql_* fields when empty"`
+	// the fallback DSN, named like the variables of the MySQL image
+	Host     string `yaml:"mysql_host" env:"MYSQL_HOST" usage:"host:port of the fallback DSN"`
+	Name     string `yaml:"mysql_database" env:"MYSQL_DATABASE"`
+	User     string `yaml:"mysql_user" env:"MYSQL_USER"`
+	Password string `yaml:"mysql_password" env:"MYSQL_PASSWORD" secret:"true"`
+
+	MaxOpenConns    int           `yaml:"max_open_conns" env:"DB_MAX_OPEN_CONNS" usage:"0 is unlimited"`
+	MaxIdleConns    int           `yaml:"max_idle_conns" env:"DB_MAX_IDLE_CONNS"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime" env:"DB_CONN_MAX_LIFETIME" usage:"0 keeps connections forever"`
+	ConnMaxIdleTime time.Duration `yaml:"conn_max_idle_time" env:"DB_CONN_MAX_IDLE_TIME" usage:"0 keeps idle connections forever"`
+	ConnectTimeout  time.Duration `yaml:"connect_timeout" env:"DB_CONNECT_TIMEOUT" usage:"how long the first ping may take at start"`
+
+	MigrateOnStart     bool          `yaml:"migrate_on_start" env:"DB_MIGRATE_ON_START" usage:"apply pending schema migrations before serving"`
+	MigrateLockTimeout time.Duration `yaml:"migrate_lock_timeout" env:"DB_MIGRATE_LOCK_TIMEOUT" usage:"how long to wait while another replica migrates"`
+
+	Timescale      bool          `yaml:"timescale" env:"DB_TIMESCALE" usage:"make sensor_readings a TimescaleDB hypertable on ts when migrating, if the postgres server has the extension"`
+	TimescaleChunk time.Duration `yaml:"timescale_chunk" env:"DB_TIMESCALE_CHUNK" usage:"time range of one hypertable chunk"`
+}
+
+// BackendName is Backend, or when that is empty the backend the DSN names:
+// postgres for a postgres:// or postgresql:// URL, mysql for anything else.
+func (d Database) BackendName() string {
+	if d.Backend != "" {
+		return d.Backend
+	}
+	if strings.HasPrefix(d.DSN, "postgres://") || strings.HasPrefix(d.DSN, "postgresql://") {
+		return repository.BackendPostgres
+	}
+	return repository.BackendMySQL
+}
+
+// ConnString is DSN, or the DSN built from the mysql_* fields.
+func (d Database) ConnString() string {
+	if d.DSN != "" {
+		return d.DSN
+	}
+	return fmt.Sprintf("%s:%s@tcp(%s)/%s?parseTime=true&loc=Local", d.User, d.Password, d.Host, d.Name)
+}
+
+type TLS struct {
+	CertFile     string `yaml:"cert_file" env:"CERT_FILE" usage:"serve gRPC over TLS with this certificate"`
+	KeyFile      string `yaml:"key_file" env:"KEY_FILE"`
+	ClientCAFile string `yaml:"client_ca_file" env:"CLIENT_CA_FILE" usage:"require client certificates signed by this CA"`
+}
+
+type Auth struct {
+	Tokens     map[string]string `yaml:"tokens" env:"AUTH_TOKENS" secret:"true" usage:"static bearer tokens by principal, principal=token pairs in the environment"`
+	JWTSecret  string            `yaml:"jwt_secret" env:"JWT_SECRET" secret:"true" usage:"HS256 secret of bearer JWTs"`
+	JWTLeeway  time.Duration     `yaml:"jwt_leeway" env:"JWT_LEEWAY" usage:"clock skew allowed on exp and nbf"`
+	RequireExp bool              `yaml:"jwt_require_exp" env:"JWT_REQUIRE_EXP" usage:"refuse JWTs without exp"`
+}
+
+type Ingest struct {
+	PipelineEnabled bool          `yaml:"pipeline_enabled" env:"PIPELINE_ENABLED" usage:"false writes every reading on its own"`
+	BatchSize       int           `yaml:"batch_size" env:"BATCH_SIZE"`
+	FlushInterval   time.Duration `yaml:"flush_interval" env:"FLUSH_INTERVAL"`
+	Workers         int           `yaml:"workers" env:"WORKERS"`
+	QueueSize       int           `yaml:"queue_size" env:"QUEUE_SIZE"`
+	DrainTimeout    time.Duration `yaml:"drain_timeout" env:"DRAIN_TIMEOUT" usage:"how long shutdown waits for queued readings to flush"`
+}
+
+type Dedup struct {
+	NaturalKey     bool   `yaml:"natural_key" env:"NATURAL_KEY" usage:"treat readings with the same id1, id2, type and timestamp as one"`
+	ConflictPolicy string `yaml:"conflict_policy" env:"CONFLICT_POLICY" usage:"keep_first, keep_last or reject"`
+}
+
+type Validation struct {
+	ID2Min        int32         `yaml:"id2_min" env:"ID2_MIN"`
+	ID2Max        int32         `yaml:"id2_max" env:"ID2_MAX" usage:"id2 must lie in [id2_min, id2_max], both zero allow any"`
+	SensorTypes   []string      `yaml:"sensor_types" env:"SENSOR_TYPES" usage:"allowed sensor types after catalog normalization, empty allows any"`
+	MaxFutureSkew time.Duration `yaml:"max_future_skew" env:"MAX_FUTURE_SKEW" usage:"0 disables"`
+	MaxAge        time.Duration `yaml:"max_age" env:"MAX_AGE" usage:"0 disables"`
+	// the catalog is reloaded after every edit, this picks up edits made on other replicas
+	CatalogRefresh time.Duration `yaml:"catalog_refresh" env:"CATALOG_REFRESH" usage:"how often the sensor type catalog is reloaded, 0 only reloads after edits"`
+}
+
+type Spool struct {
+	Enabled        bool          `yaml:"enabled" env:"ENABLED" usage:"false fails readings while the database is down"`
+	Dir            string        `yaml:"dir" env:"DIR"`
+	SegmentBytes   int64         `yaml:"segment_bytes" env:"SEGMENT_BYTES"`
+	MaxBytes       int64         `yaml:"max_bytes" env:"MAX_BYTES"`
+	ReplayInterval time.Duration `yaml:"replay_interval" env:"REPLAY_INTERVAL"`
+	ReplayBatch    int           `yaml:"replay_batch" env:"REPLAY_BATCH"`
+}
+
+type Watch struct {
+	BufferSize     int    `yaml:"buffer_size" env:"BUFFER_SIZE"`
+	SlowConsumer   string `yaml:"slow_consumer" env:"SLOW_CONSUMER" usage:"drop or disconnect"`
+	MaxSubscribers int    `yaml:"max_subscribers" env:"MAX_SUBSCRIBERS"`
+}
+
+type Admission struct {
+	Readings       Limits `yaml:"readings" env:"READINGS_"`
+	ReadingsBatch  Limits `yaml:"readings_batch" env:"READINGS_BATCH_"`
+	StreamReadings Limits `yaml:"stream_readings" env:"STREAM_READINGS_"`
+	IngestStream   Limits `yaml:"ingest_stream" env:"INGEST_STREAM_"`
+}
+
+// Limits converts to admission.Limits.
+type Limits struct {
+	MaxInFlight  int           `yaml:"max_in_flight" env:"MAX_IN_FLIGHT"`
+	MaxQueue     int           `yaml:"max_queue" env:"MAX_QUEUE"`
+	QueueTimeout time.Duration `yaml:"queue_timeout" env:"QUEUE_TIMEOUT"`
+	RetryAfter   time.Duration `yaml:"retry_after" env:"RETRY_AFTER" usage:"queue_timeout when 0"`
+}
+
+type Health struct {
+	QueueSaturationPercent int           `yaml:"queue_saturation_percent" env:"QUEUE_SATURATION_PERCENT" usage:"not ready past this ingest queue or spool fill"`
+	CheckInterval          time.Duration `yaml:"check_interval" env:"CHECK_INTERVAL"`
+}
+
+type Metrics struct {
+	Enabled bool `yaml:"enabled" env:"ENABLED" usage:"serve Prometheus metrics on /metrics"`
+}
+
+type WebSocket struct {
+	AllowedOrigins []string `yaml:"allowed_origins" env:"ALLOWED_ORIGINS" usage:"origins allowed besides the service's own host, * allows any"`
+}
+
+func Default() Config {
+	return Config{
+		GRPCAddr:        ":50051",
+		HTTPAddr:        ":8080",
+		ShutdownTimeout: 5 * time.Second,
+		Log: Log{
+			Level:       "info",
+			Format:      logging.FormatText,
+			SampleEvery: 100,
+		},
+		Tracing: Tracing{
+			Exporter:      tracing.ExporterNone,
+			OTLPInsecure:  true,
+			File:          "./traces.jsonl",
+			SamplePercent: 100,
+		},
+		Database: Database{
+			SQLitePath:      "./data/sensors.db",
+			Host:            "localhost:3306",
+			MaxOpenConns:    64,
+			MaxIdleConns:    16,
+			ConnMaxLifetime: 30 * time.Minute,
+			ConnMaxIdleTime: 5 * time.Minute,
+			ConnectTimeout:  10 * time.Second,
+
+			MigrateLockTimeout: time.Minute,
+			TimescaleChunk:     24 * time.Hour,
+		},
+		Auth: Auth{
+			JWTLeeway:  30 * time.Second,
+			RequireExp: true,
+		},
+		Ingest: Ingest{
+			PipelineEnabled: true,
+			BatchSize:       500,
+			FlushInterval:   5 * time.Millisecond,
+			Workers:         4,
+			QueueSize:       10000,
+			DrainTimeout:    10 * time.Second,
+		},
+		Dedup: Dedup{
+			ConflictPolicy: string(repository.ConflictKeepFirst),
+		},
+		Validation: Validation{
+			MaxFutureSkew:  5 * time.Minute,
+			CatalogRefresh: 30 * time.Second,
+		},
+		Spool: Spool{
+			Enabled:        true,
+			Dir:            "./spool",
+			SegmentBytes:   16 << 20,
+			MaxBytes:       1 << 30,
+			ReplayInterval: time.Second,
+			ReplayBatch:    500,
+		},
+		Watch: Watch{
+			BufferSize:     256,
+			SlowConsumer:   string(live.PolicyDrop),
+			MaxSubscribers: 100,
+		},
+		Admission: Admission{
+			Readings:       Limits{MaxInFlight: 4096, MaxQueue: 4096, QueueTimeout: 200 * time.Millisecond},
+			ReadingsBatch:  Limits{MaxInFlight: 32, MaxQueue: 64, QueueTimeout: time.Second},
+			StreamReadings: Limits{MaxInFlight: 256, MaxQueue: 64, QueueTimeout: time.Second},
+			IngestStream:   Limits{MaxInFlight: 256, MaxQueue: 64, QueueTimeout: time.Second},
+		},
+		Health: Health{
+			QueueSaturationPercent: 90,
+			CheckInterval:          5 * time.Second,
+		},
+		Metrics: Metrics{
+			Enabled: true,
+		},
+	}
+}
+
+// Load starts from Default and applies the config file, the environment and
+// args in that order. It returns commonConfig.ErrPrinted after -h or
+// -print-defaults.
+func Load(args []string) (Config, string, error) {
+	cfg := Default()
+	file, err := commonConfig.Load(&cfg, commonConfig.LoadOpts{Args: args, DefaultPath: DefaultPath})
+	if err != nil {
+		return cfg, file, err
+	}
+	return cfg, file, cfg.Validate()
+}
+
+// Validate reports every invalid field at once.
+func (c Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+	checkAddr := func(name, addr string) {
+		_, _, err := net.SplitHostPort(addr)
+		check(err == nil, "%s %q is not host:port", name, addr)
+	}
+	checkLimits := func(name string, l Limits) {
+		check(l.MaxInFlight >= 0 && l.MaxQueue >= 0, "admission.%s limits must not be negative", name)
+		check(l.MaxInFlight == 0 || l.QueueTimeout > 0, "admission.%s.queue_timeout must be positive", name)
+	}
+
+	checkAddr("grpc_addr", c.GRPCAddr)
+	checkAddr("http_addr", c.HTTPAddr)
+	check(c.ShutdownTimeout > 0, "shutdown_timeout must be positive")
+
+	_, err := logging.ParseLevel(c.Log.Level)
+	check(err == nil, "log.level: %v", err)
+	check(c.Log.Format == logging.FormatText || c.Log.Format == logging.FormatJSON, "log.format %q is not text or json", c.Log.Format)
+	check(c.Log.SampleEvery >= 1, "log.sample_every must be at least 1")
+
+	switch c.Tracing.Exporter {
+	case tracing.ExporterNone, tracing.ExporterOTLP, tracing.ExporterStdout, tracing.ExporterFile:
+	default:
+		check(false, "tracing.exporter %q is not none, otlp, stdout or file", c.Tracing.Exporter)
+	}
+	check(c.Tracing.Exporter != tracing.ExporterFile || c.Tracing.File != "", "tracing.file is needed by the file exporter")
+	check(c.Tracing.SamplePercent >= 0 && c.Tracing.SamplePercent <= 100, "tracing.sample_percent must be 0..100")
+
+	switch c.Database.BackendName() {
+	case repository.BackendMySQL:
+		check(c.Database.DSN != "" || c.Database.Name != "", "database.dsn or database.mysql_database is needed")
+	case repository.BackendPostgres:
+		check(c.Database.DSN != "", "database.dsn is needed by the postgres backend")
+		check(c.Database.TimescaleChunk > 0, "database.timescale_chunk must be positive")
+	case repository.BackendSQLite:
+		check(repository.SQLiteAvailable, "database.backend sqlite needs a b-service built with CGO_ENABLED=1, this binary was built without cgo")
+		check(c.Database.SQLitePath != "", "database.sqlite_path is needed by the sqlite backend")
+	case repository.BackendMemory:
+	default:
+		check(false, "database.backend %q is not mysql, postgres, sqlite or memory", c.Database.Backend)
+	}
+	check(c.Database.MaxOpenConns >= 0 && c.Database.MaxIdleConns >= 0, "database pool sizes must not be negative")
+	check(c.Database.MaxOpenConns == 0 || c.Database.MaxIdleConns <= c.Database.MaxOpenConns, "database.max_idle_conns must not exceed max_open_conns")
+	check(c.Database.ConnectTimeout > 0, "database.connect_timeout must be positive")
+	check(c.Database.MigrateLockTimeout >= time.Second, "database.migrate_lock_timeout must be at least 1s")
+
+	check((c.TLS.CertFile == "") == (c.TLS.KeyFile == ""), "tls.cert_file and tls.key_file go together")
+	check(c.TLS.ClientCAFile == "" || c.TLS.CertFile != "", "tls.client_ca_file needs tls.cert_file")
+
+	check(c.Auth.JWTLeeway >= 0, "auth.jwt_leeway must not be negative")
+
+	if c.Ingest.PipelineEnabled {
+		check(c.Ingest.BatchSize > 0, "ingest.batch_size must be positive")
+		check(c.Ingest.FlushInterval > 0, "ingest.flush_interval must be positive")
+		check(c.Ingest.Workers > 0, "ingest.workers must be positive")
+		check(c.Ingest.QueueSize > 0, "ingest.queue_size must be positive")
+		check(c.Ingest.DrainTimeout > 0, "ingest.drain_timeout must be positive")
+	}
+
+	_, err = repository.ParseConflictPolicy(c.Dedup.ConflictPolicy)
+	check(err == nil, "dedup.conflict_policy: %v", err)
+
+	check(c.Validation.ID2Min <= c.Validation.ID2Max, "validation.id2_min must not exceed id2_max")
+	check(c.Validation.MaxFutureSkew >= 0 && c.Validation.MaxAge >= 0 && c.Validation.CatalogRefresh >= 0, "validation durations must not be negative")
+	for _, sensorType := range c.Validation.SensorTypes {
+		check(strings.TrimSpace(sensorType) != "", "validation.sensor_types has an empty entry")
+	}
+
+	if c.Spool.Enabled {
+		check(c.Spool.Dir != "", "spool.dir is needed")
+		check(c.Spool.SegmentBytes > 0 && c.Spool.MaxBytes >= c.Spool.SegmentBytes, "spool.max_bytes must hold at least one segment_bytes segment")
+		check(c.Spool.ReplayInterval > 0, "spool.replay_interval must be positive")
+		check(c.Spool.ReplayBatch > 0, "spool.replay_batch must be positive")
+	}
+
+	check(c.Watch.BufferSize > 0, "watch.buffer_size must be positive")
+	_, err = live.ParseSlowConsumerPolicy(c.Watch.SlowConsumer)
+	check(err == nil, "watch.slow_consumer: %v", err)
+	check(c.Watch.MaxSubscribers >= 0, "watch.max_subscribers must not be negative")
+
+	checkLimits("readings", c.Admission.Readings)
+	checkLimits("readings_batch", c.Admission.ReadingsBatch)
+	checkLimits("stream_readings", c.Admission.StreamReadings)
+	checkLimits("ingest_stream", c.Admission.IngestStream)
+
+	check(c.Health.QueueSaturationPercent > 0 && c.Health.QueueSaturationPercent <= 100, "health.queue_saturation_percent must be 1..100")
+	check(c.Health.CheckInterval > 0, "health.check_interval must be positive")
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,108 @@
+package config
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	repository "github.com/Yusufzhafir/worlder-team-assignment/b-service/repository"
+	commonConfig "github.com/Yusufzhafir/worlder-team-assignment/common/config"
+)
+
+// config.yaml is what -print-defaults writes, regenerate it after changing a default.
+func TestDefaultFileIsCurrent(t *testing.T) {
+	want, err := commonConfig.Marshal(Default())
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	got, err := os.ReadFile("config.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(want) {
+		t.Fatal("config.yaml is stale, run go run ./b-service -print-defaults > b-service/config/config.yaml")
+	}
+}
+
+func TestLoad(t *testing.T) {
+	t.Setenv("DB_DSN", "app:app@tcp(db:3306)/sensors")
+	t.Setenv("GRPC_ADDR", "0.0.0.0:50052")
+	t.Setenv("INGEST_BATCH_SIZE", "100")
+	t.Setenv("ADMISSION_READINGS_MAX_IN_FLIGHT", "10")
+	t.Setenv("AUTH_TOKENS", "a-service=secret")
+
+	cfg, file, err := Load([]string{"-config", "config.yaml", "-ingest.batch_size", "200", "-http_addr", ":8081"})
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if file != "config.yaml" {
+		t.Fatalf("file = %q, want config.yaml", file)
+	}
+	if cfg.GRPCAddr != "0.0.0.0:50052" || cfg.HTTPAddr != ":8081" {
+		t.Fatalf("addresses = %s %s", cfg.GRPCAddr, cfg.HTTPAddr)
+	}
+	if cfg.Ingest.BatchSize != 200 || cfg.Ingest.FlushInterval != 5*time.Millisecond {
+		t.Fatalf("ingest = %+v", cfg.Ingest)
+	}
+	if cfg.Admission.Readings.MaxInFlight != 10 || cfg.Admission.Readings.QueueTimeout != 200*time.Millisecond {
+		t.Fatalf("admission.readings = %+v", cfg.Admission.Readings)
+	}
+	if cfg.Auth.Tokens["a-service"] != "secret" || cfg.Database.ConnString() != "app:app@tcp(db:3306)/sensors" {
+		t.Fatalf("auth and database = %+v %+v", cfg.Auth, cfg.Database)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	valid := Default()
+	valid.Database.Name = "sensors"
+	sqliteErr := ""
+	if !repository.SQLiteAvailable {
+		sqliteErr = "built without cgo"
+	}
+	tests := []struct {
+		name    string
+		change  func(c *Config)
+		wantErr string
+	}{
+		{name: "defaults with a database", change: func(c *Config) {}},
+		{name: "no database", change: func(c *Config) { c.Database.Name = "" }, wantErr: "database.dsn"},
+		{name: "memory needs no database", change: func(c *Config) { c.Database.Backend, c.Database.Name = "memory", "" }},
+		{name: "sqlite", change: func(c *Config) { c.Database.Backend = "sqlite" }, wantErr: sqliteErr},
+		{name: "sqlite without a path", change: func(c *Config) { c.Database.Backend, c.Database.SQLitePath = "sqlite", "" }, wantErr: "database.sqlite_path"},
+		{name: "postgres by dsn scheme", change: func(c *Config) { c.Database.Name, c.Database.DSN = "", "postgres://app@db/sensors" }},
+		{name: "postgres without a dsn", change: func(c *Config) { c.Database.Backend = "postgres" }, wantErr: "needed by the postgres backend"},
+		{name: "unknown backend", change: func(c *Config) { c.Database.Backend = "oracle" }, wantErr: "database.backend"},
+		{name: "bad address", change: func(c *Config) { c.GRPCAddr = "50051" }, wantErr: "grpc_addr"},
+		{name: "bad level", change: func(c *Config) { c.Log.Level = "loud" }, wantErr: "log.level"},
+		{name: "idle above open", change: func(c *Config) { c.Database.MaxIdleConns = 100 }, wantErr: "max_idle_conns"},
+		{name: "key without cert", change: func(c *Config) { c.TLS.KeyFile = "key.pem" }, wantErr: "tls.cert_file"},
+		{name: "zero batch", change: func(c *Config) { c.Ingest.BatchSize = 0 }, wantErr: "ingest.batch_size"},
+		{name: "zero batch with the pipeline off", change: func(c *Config) {
+			c.Ingest.PipelineEnabled, c.Ingest.BatchSize = false, 0
+		}},
+		{name: "bad policy", change: func(c *Config) { c.Dedup.ConflictPolicy = "merge" }, wantErr: "dedup.conflict_policy"},
+		{name: "inverted id2 range", change: func(c *Config) { c.Validation.ID2Min = 5 }, wantErr: "id2_min"},
+		{name: "spool smaller than a segment", change: func(c *Config) { c.Spool.MaxBytes = 1 }, wantErr: "spool.max_bytes"},
+		{name: "admission without timeout", change: func(c *Config) { c.Admission.ReadingsBatch.QueueTimeout = 0 }, wantErr: "admission.readings_batch"},
+		{name: "later errors still reported", change: func(c *Config) {
+			c.Log.Format, c.Watch.SlowConsumer = "xml", "block"
+		}, wantErr: "watch.slow_consumer"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := valid
+			tt.change(&cfg)
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Validate: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Validate = %v, want an error about %s", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -24,40 +24,19 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/sensor": {
+        "/admin/admission": {
             "get": {
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Per method limits and current load of the admission control in front of the ingest RPCs",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "sensor"
-                ],
-                "summary": "List sensor readings (paginated)",
-                "parameters": [
-                    {
-                        "minimum": 1,
-                        "type": "integer",
-                        "default": 1,
-                        "description": "Page number",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "maximum": 500,
-                        "minimum": 1,
-                        "type": "integer",
-                        "default": 50,
-                        "description": "Page size",
-                        "name": "page_size",
-                        "in": "query"
-                    }
+                    "admin"
                 ],
+                "summary": "In-flight and queued gRPC ingest calls",
                 "responses": {
                     "200": {
-                        "description": "data: SensorPage",
+                        "description": "data: stats keyed by gRPC method",
                         "schema": {
                             "allOf": [
                                 {
@@ -67,15 +46,18 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/model.SensorPage"
+                                            "type": "object",
+                                            "additionalProperties": {
+                                                "$ref": "#/definitions/admission.Stats"
+                                            }
                                         }
                                     }
                                 }
                             ]
                         }
                     },
-                    "400": {
-                        "description": "error=true, message explains",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "allOf": [
                                 {
@@ -91,6 +73,37 @@ const docTemplate = `{
                                 }
                             ]
                         }
+                    }
+                }
+            }
+        },
+        "/admin/log-level": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Current log level",
+                "responses": {
+                    "200": {
+                        "description": "data: the level",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/logging.LevelRequest"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
                     },
                     "500": {
                         "description": "Internal Server Error",
@@ -111,11 +124,9 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/sensor/delete/ids": {
-            "delete": {
-                "description": "Delete sensor readings that match the specified ID combinations",
+            },
+            "put": {
+                "description": "Takes effect at once and lasts until the process restarts, LOG_LEVEL applies again on the next start",
                 "consumes": [
                     "application/json"
                 ],
@@ -123,23 +134,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "sensor"
+                    "admin"
                 ],
-                "summary": "Delete sensor readings by ID combinations",
+                "summary": "Change the log level",
                 "parameters": [
                     {
-                        "description": "ID combinations to delete",
-                        "name": "request",
+                        "description": "debug, info, warn or error",
+                        "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.DeleteByIDsRequest"
+                            "$ref": "#/definitions/logging.LevelRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "data: DeleteResponse",
+                        "description": "data: the new level",
                         "schema": {
                             "allOf": [
                                 {
@@ -149,7 +160,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/model.DeleteResponse"
+                                            "$ref": "#/definitions/logging.LevelRequest"
                                         }
                                     }
                                 }
@@ -195,51 +206,19 @@ const docTemplate = `{
                 }
             }
         },
-        "/sensor/delete/ids-time": {
-            "delete": {
-                "description": "Delete sensor readings that fall within the specified time range",
-                "consumes": [
-                    "application/json"
-                ],
+        "/admin/sensor-types": {
+            "get": {
+                "description": "Every type ordered by name, with its unit, plausible range, range policy and aliases.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "sensor"
-                ],
-                "summary": "Delete sensor readings by time range",
-                "parameters": [
-                    {
-                        "description": "Time range to delete",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/model.DeleteByIDAndTimesRequest"
-                        }
-                    }
+                    "admin"
                 ],
+                "summary": "List the sensor type catalog",
                 "responses": {
                     "200": {
-                        "description": "data: DeleteResponse",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/model.Envelope"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/model.DeleteResponse"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "400": {
-                        "description": "error=true, message explains",
+                        "description": "data: SensorType list",
                         "schema": {
                             "allOf": [
                                 {
@@ -249,7 +228,10 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/model.Empty"
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.SensorType"
+                                            }
                                         }
                                     }
                                 }
@@ -275,11 +257,9 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/sensor/delete/time": {
-            "delete": {
-                "description": "Delete sensor readings that fall within the specified time range",
+            },
+            "post": {
+                "description": "Readings sent with the name or an alias, in any case, are stored under the name. A value outside min and max is rejected with the reject policy and stored with outOfRange set with flag, the default.",
                 "consumes": [
                     "application/json"
                 ],
@@ -287,23 +267,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "sensor"
+                    "admin"
                 ],
-                "summary": "Delete sensor readings by time range",
+                "summary": "Add a sensor type to the catalog",
                 "parameters": [
                     {
-                        "description": "Time range to delete",
+                        "description": "Sensor type to add",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.DeleteByTimeRequest"
+                            "$ref": "#/definitions/model.CreateSensorTypeRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "data: DeleteResponse",
+                    "201": {
+                        "description": "data: SensorType",
                         "schema": {
                             "allOf": [
                                 {
@@ -313,7 +293,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/model.DeleteResponse"
+                                            "$ref": "#/definitions/model.SensorType"
                                         }
                                     }
                                 }
@@ -338,6 +318,24 @@ const docTemplate = `{
                             ]
                         }
                     },
+                    "409": {
+                        "description": "the name or an alias already names a type",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Empty"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -359,54 +357,28 @@ const docTemplate = `{
                 }
             }
         },
-        "/sensor/ids": {
+        "/admin/sensor-types/{name}": {
             "get": {
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "sensor"
+                    "admin"
                 ],
-                "summary": "List sensor readings filtered by ID combinations (paginated)",
+                "summary": "One sensor type of the catalog",
                 "parameters": [
-                    {
-                        "minimum": 1,
-                        "type": "integer",
-                        "default": 1,
-                        "description": "Page number",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "maximum": 500,
-                        "minimum": 1,
-                        "type": "integer",
-                        "default": 50,
-                        "description": "Page size",
-                        "name": "page_size",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "example": "0,1,2",
-                        "description": "Comma-separated ID1 values",
-                        "name": "id1",
-                        "in": "query"
-                    },
                     {
                         "type": "string",
-                        "example": "A,B,C",
-                        "description": "Comma-separated ID2 values",
-                        "name": "id2",
-                        "in": "query"
+                        "example": "temperature",
+                        "description": "Name of the type, in any case",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "data: SensorPage",
+                        "description": "data: SensorType",
                         "schema": {
                             "allOf": [
                                 {
@@ -416,15 +388,15 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/model.SensorPage"
+                                            "$ref": "#/definitions/model.SensorType"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
-                    "400": {
-                        "description": "error=true, message explains",
+                    "404": {
+                        "description": "the type is not in the catalog",
                         "schema": {
                             "allOf": [
                                 {
@@ -460,10 +432,9 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/sensor/ids-time": {
-            "get": {
+            },
+            "put": {
+                "description": "Replaces the unit, range, policy and aliases. Applies to readings ingested from now on, stored readings keep their outOfRange flag.",
                 "consumes": [
                     "application/json"
                 ],
@@ -471,61 +442,31 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "sensor"
+                    "admin"
                 ],
-                "summary": "List sensor readings filtered by ID combinations and time range (paginated)",
+                "summary": "Replace a sensor type",
                 "parameters": [
-                    {
-                        "minimum": 1,
-                        "type": "integer",
-                        "default": 1,
-                        "description": "Page number",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "maximum": 500,
-                        "minimum": 1,
-                        "type": "integer",
-                        "default": 50,
-                        "description": "Page size",
-                        "name": "page_size",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "example": "0,1,2",
-                        "description": "Comma-separated ID1 values",
-                        "name": "id1",
-                        "in": "query"
-                    },
                     {
                         "type": "string",
-                        "example": "A,B,C",
-                        "description": "Comma-separated ID2 values",
-                        "name": "id2",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "example": "2025-08-25T18:00:24.947000+07:00",
-                        "description": "Start time (RFC3339Nano)",
-                        "name": "from",
-                        "in": "query",
+                        "example": "temperature",
+                        "description": "Name of the type, in any case",
+                        "name": "name",
+                        "in": "path",
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "example": "2025-08-25T19:00:24.947000+07:00",
-                        "description": "End time (RFC3339Nano)",
-                        "name": "to",
-                        "in": "query",
-                        "required": true
+                        "description": "New definition",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.UpdateSensorTypeRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "data: SensorPage",
+                        "description": "data: SensorType",
                         "schema": {
                             "allOf": [
                                 {
@@ -535,7 +476,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/model.SensorPage"
+                                            "$ref": "#/definitions/model.SensorType"
                                         }
                                     }
                                 }
@@ -560,8 +501,8 @@ const docTemplate = `{
                             ]
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "the type is not in the catalog",
                         "schema": {
                             "allOf": [
                                 {
@@ -577,58 +518,67 @@ const docTemplate = `{
                                 }
                             ]
                         }
-                    }
-                }
-            }
-        },
-        "/sensor/time": {
-            "get": {
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "sensor"
-                ],
-                "summary": "List sensor readings filter by time(paginated)",
-                "parameters": [
-                    {
-                        "minimum": 1,
-                        "type": "integer",
-                        "default": 1,
-                        "description": "Page number",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "maximum": 500,
-                        "minimum": 1,
-                        "type": "integer",
-                        "default": 50,
-                        "description": "Page size",
-                        "name": "page_size",
-                        "in": "query"
                     },
-                    {
-                        "type": "string",
-                        "default": "2006-01-02T15:04:05.999999999+07:00",
-                        "description": "from time",
-                        "name": "from",
-                        "in": "query"
+                    "409": {
+                        "description": "an alias already names another type",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Empty"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
                     },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Empty"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Its name and aliases are no longer normalized or range checked. Stored readings are kept.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Remove a sensor type from the catalog",
+                "parameters": [
                     {
                         "type": "string",
-                        "default": "2006-01-02T16:04:05.999999999+07:00",
-                        "description": "to time",
-                        "name": "to",
-                        "in": "query"
+                        "example": "temperature",
+                        "description": "Name of the type, in any case",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "data: SensorPage",
+                        "description": "OK",
                         "schema": {
                             "allOf": [
                                 {
@@ -638,15 +588,15 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/model.SensorPage"
+                                            "$ref": "#/definitions/model.Empty"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
-                    "400": {
-                        "description": "error=true, message explains",
+                    "404": {
+                        "description": "the type is not in the catalog",
                         "schema": {
                             "allOf": [
                                 {
@@ -684,9 +634,58 @@ const docTemplate = `{
                 }
             }
         },
-        "/sensor/update/ids": {
-            "put": {
-                "description": "Update sensor readings that match the specified ID combinations",
+        "/admin/spool": {
+            "get": {
+                "description": "Readings written to disk while the database was unavailable, and how far the replayer has drained them",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Local spool depth and replay progress",
+                "responses": {
+                    "200": {
+                        "description": "data: spool.Stats",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/spool.Stats"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "spooling is disabled",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Empty"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/sensor": {
+            "get": {
                 "consumes": [
                     "application/json"
                 ],
@@ -696,21 +695,36 @@ const docTemplate = `{
                 "tags": [
                     "sensor"
                 ],
-                "summary": "Update sensor readings by ID combinations",
+                "summary": "List sensor readings (paginated)",
                 "parameters": [
                     {
-                        "description": "ID combinations and new values",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/model.UpdateByIDsRequest"
-                        }
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 500,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Page size",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Join the registered metadata of each sensor",
+                        "name": "with_metadata",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "data: UpdateResponse",
+                        "description": "data: SensorPage",
                         "schema": {
                             "allOf": [
                                 {
@@ -720,7 +734,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/model.UpdateResponse"
+                                            "$ref": "#/definitions/model.SensorPage"
                                         }
                                     }
                                 }
@@ -766,33 +780,74 @@ const docTemplate = `{
                 }
             }
         },
-        "/sensor/update/ids-time": {
-            "put": {
-                "description": "Update sensor readings that match both the ID combinations and time range",
-                "consumes": [
-                    "application/json"
-                ],
+        "/sensor/aggregate": {
+            "get": {
+                "description": "One series per sensor matching the ID combinations or the sensor type, with a point per bucket that has readings. Buckets are counted from the from time; at most 10000 per sensor.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "sensor"
                 ],
-                "summary": "Update sensor readings by ID combinations and time range",
+                "summary": "Downsample sensor readings into time buckets",
                 "parameters": [
                     {
-                        "description": "ID combinations, time range, and new values",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/model.UpdateByIDsAndTimeRequest"
-                        }
+                        "type": "string",
+                        "example": "A,B,C",
+                        "description": "Comma-separated ID1 values",
+                        "name": "id1",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "0,1,2",
+                        "description": "Comma-separated ID2 values",
+                        "name": "id2",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "temperature",
+                        "description": "Sensor type, instead of or besides the IDs",
+                        "name": "sensor_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2025-08-25T00:00:00+07:00",
+                        "description": "Start time (RFC3339Nano)",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "2025-08-26T00:00:00+07:00",
+                        "description": "End time (RFC3339Nano), exclusive",
+                        "name": "to",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "1m",
+                        "example": "1h",
+                        "description": "Bucket width, at least 1s",
+                        "name": "bucket",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "avg",
+                        "example": "avg,min,max",
+                        "description": "Comma-separated avg, min, max, sum, count, first, last, stddev",
+                        "name": "agg",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "data: UpdateResponse",
+                        "description": "data: AggregateResult",
                         "schema": {
                             "allOf": [
                                 {
@@ -802,7 +857,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/model.UpdateResponse"
+                                            "$ref": "#/definitions/model.AggregateResult"
                                         }
                                     }
                                 }
@@ -848,9 +903,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/sensor/update/time": {
-            "put": {
-                "description": "Update sensor readings that fall within the specified time range",
+        "/sensor/delete/ids": {
+            "delete": {
+                "description": "Delete sensor readings that match the specified ID combinations",
                 "consumes": [
                     "application/json"
                 ],
@@ -860,21 +915,21 @@ const docTemplate = `{
                 "tags": [
                     "sensor"
                 ],
-                "summary": "Update sensor readings by time range",
+                "summary": "Delete sensor readings by ID combinations",
                 "parameters": [
                     {
-                        "description": "Time range and new values",
+                        "description": "ID combinations to delete",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.UpdateByTimeRequest"
+                            "$ref": "#/definitions/model.DeleteByIDsRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "data: UpdateResponse",
+                        "description": "data: DeleteResponse",
                         "schema": {
                             "allOf": [
                                 {
@@ -884,7 +939,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/model.UpdateResponse"
+                                            "$ref": "#/definitions/model.DeleteResponse"
                                         }
                                     }
                                 }
@@ -929,47 +984,1794 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "model.DeleteByIDAndTimesRequest": {
-            "type": "object",
-            "required": [
-                "from_time",
-                "id_combinations",
-                "to_time"
-            ],
-            "properties": {
-                "from_time": {
-                    "type": "string",
-                    "example": "2025-08-25T18:00:24.947000+07:00"
-                },
-                "id_combinations": {
-                    "type": "array",
-                    "minItems": 1,
-                    "items": {
-                        "$ref": "#/definitions/model.IDCombination"
-                    }
-                },
-                "to_time": {
-                    "type": "string",
-                    "example": "2025-08-25T19:00:24.947000+07:00"
-                }
-            }
         },
-        "model.DeleteByIDsRequest": {
-            "type": "object",
-            "required": [
-                "id_combinations"
-            ],
-            "properties": {
-                "id_combinations": {
-                    "type": "array",
-                    "minItems": 1,
-                    "items": {
-                        "$ref": "#/definitions/model.IDCombination"
-                    }
-                }
+        "/sensor/delete/ids-time": {
+            "delete": {
+                "description": "Delete sensor readings that fall within the specified time range",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sensor"
+                ],
+                "summary": "Delete sensor readings by time range",
+                "parameters": [
+                    {
+                        "description": "Time range to delete",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.DeleteByIDAndTimesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "data: DeleteResponse",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.DeleteResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "error=true, message explains",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Empty"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Empty"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/sensor/delete/time": {
+            "delete": {
+                "description": "Delete sensor readings that fall within the specified time range",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sensor"
+                ],
+                "summary": "Delete sensor readings by time range",
+                "parameters": [
+                    {
+                        "description": "Time range to delete",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.DeleteByTimeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "data: DeleteResponse",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.DeleteResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "error=true, message explains",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Empty"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Empty"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/sensor/ids": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sensor"
+                ],
+                "summary": "List sensor readings filtered by ID combinations (paginated)",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 500,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Page size",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Join the registered metadata of each sensor",
+                        "name": "with_metadata",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "0,1,2",
+                        "description": "Comma-separated ID1 values",
+                        "name": "id1",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "A,B,C",
+                        "description": "Comma-separated ID2 values",
+                        "name": "id2",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "data: SensorPage",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.SensorPage"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "error=true, message explains",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Empty"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Empty"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/sensor/ids-time": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sensor"
+                ],
+                "summary": "List sensor readings filtered by ID combinations and time range (paginated)",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 500,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Page size",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Join the registered metadata of each sensor",
+                        "name": "with_metadata",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "0,1,2",
+                        "description": "Comma-separated ID1 values",
+                        "name": "id1",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "A,B,C",
+                        "description": "Comma-separated ID2 values",
+                        "name": "id2",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2025-08-25T18:00:24.947000+07:00",
+                        "description": "Start time (RFC3339Nano)",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "2025-08-25T19:00:24.947000+07:00",
+                        "description": "End time (RFC3339Nano)",
+                        "name": "to",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "data: SensorPage",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.SensorPage"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "error=true, message explains",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Empty"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Empty"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/sensor/stream": {
+            "get": {
+                "description": "Every stored reading matching the ID combinations is sent as a \"reading\" event with a SensorPayload as data. Without id1/id2 every reading is sent. An \"error\" event ends the stream when the client falls behind or the server shuts down.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "sensor"
+                ],
+                "summary": "Live feed of stored readings as Server-Sent Events",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "A,B,C",
+                        "description": "Comma-separated ID1 values",
+                        "name": "id1",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "0,1,2",
+                        "description": "Comma-separated ID2 values",
+                        "name": "id2",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "one per reading event",
+                        "schema": {
+                            "$ref": "#/definitions/model.SensorPayload"
+                        }
+                    },
+                    "400": {
+                        "description": "error=true, message explains",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Empty"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "503": {
+                        "description": "too many subscribers",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Empty"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/sensor/stream/ws": {
+            "get": {
+                "description": "Upgrades to a WebSocket and sends every stored reading matching the ID combinations as a JSON SensorPayload text message. Without id1/id2 every reading is sent. Messages from the client are ignored. The server closes the socket when the client falls behind or the server shuts down.",
+                "tags": [
+                    "sensor"
+                ],
+                "summary": "Live feed of stored readings over WebSocket",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "A,B,C",
+                        "description": "Comma-separated ID1 values",
+                        "name": "id1",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "0,1,2",
+                        "description": "Comma-separated ID2 values",
+                        "name": "id2",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "101": {
+                        "description": "one per message",
+                        "schema": {
+                            "$ref": "#/definitions/model.SensorPayload"
+                        }
+                    },
+                    "400": {
+                        "description": "error=true, message explains",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Empty"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "403": {
+                        "description": "Origin is neither the API's host nor in WS_ALLOWED_ORIGINS"
+                    },
+                    "503": {
+                        "description": "too many subscribers",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Empty"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/sensor/time": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sensor"
+                ],
+                "summary": "List sensor readings filter by time(paginated)",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 500,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Page size",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Join the registered metadata of each sensor",
+                        "name": "with_metadata",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "2006-01-02T15:04:05.999999999+07:00",
+                        "description": "from time",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "2006-01-02T16:04:05.999999999+07:00",
+                        "description": "to time",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "data: SensorPage",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.SensorPage"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "error=true, message explains",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Empty"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Empty"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/sensor/update/ids": {
+            "put": {
+                "description": "Update sensor readings that match the specified ID combinations",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sensor"
+                ],
+                "summary": "Update sensor readings by ID combinations",
+                "parameters": [
+                    {
+                        "description": "ID combinations and new values",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.UpdateByIDsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "data: UpdateResponse",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.UpdateResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "error=true, message explains",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Empty"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Empty"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/sensor/update/ids-time": {
+            "put": {
+                "description": "Update sensor readings that match both the ID combinations and time range",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sensor"
+                ],
+                "summary": "Update sensor readings by ID combinations and time range",
+                "parameters": [
+                    {
+                        "description": "ID combinations, time range, and new values",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.UpdateByIDsAndTimeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "data: UpdateResponse",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.UpdateResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "error=true, message explains",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Empty"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Empty"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/sensor/update/time": {
+            "put": {
+                "description": "Update sensor readings that fall within the specified time range",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sensor"
+                ],
+                "summary": "Update sensor readings by time range",
+                "parameters": [
+                    {
+                        "description": "Time range and new values",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.UpdateByTimeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "data: UpdateResponse",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.UpdateResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "error=true, message explains",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Empty"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Empty"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/sensors": {
+            "get": {
+                "description": "A sensor is a distinct id1/id2 pair. Each comes with its first and last timestamp, its latest value and type, its reading count and its ingest rate in readings per second over the last rate_minutes.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sensor"
+                ],
+                "summary": "List every sensor that has readings (paginated)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "minimum": 1,
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "maximum": 500,
+                        "minimum": 1,
+                        "default": 50,
+                        "description": "Page size",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "maximum": 1440,
+                        "minimum": 1,
+                        "default": 5,
+                        "description": "Ingest rate window in minutes",
+                        "name": "rate_minutes",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "data: SensorSummaryPage",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.SensorSummaryPage"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "error=true, message explains",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Empty"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Empty"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/sensors/registry": {
+            "get": {
+                "description": "Registered sensors ordered by id1 and id2, whether or not they have readings.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "registry"
+                ],
+                "summary": "List the sensor registry (paginated)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "minimum": 1,
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "maximum": 500,
+                        "minimum": 1,
+                        "default": 50,
+                        "description": "Page size",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "data: SensorMetadataPage",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.SensorMetadataPage"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "error=true, message explains",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Empty"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Empty"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Adds the metadata of one id1/id2 pair to the registry. enabled defaults to true.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "registry"
+                ],
+                "summary": "Register a sensor",
+                "parameters": [
+                    {
+                        "description": "Sensor to register",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.RegisterSensorRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "data: SensorMetadata",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.SensorMetadata"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "error=true, message explains",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Empty"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "409": {
+                        "description": "the sensor is already registered",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Empty"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Empty"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/sensors/registry/{id1}/{id2}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "registry"
+                ],
+                "summary": "Registered metadata of one sensor",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "ABC123",
+                        "description": "ID1",
+                        "name": "id1",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "example": 42,
+                        "description": "ID2",
+                        "name": "id2",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "data: SensorMetadata",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.SensorMetadata"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "error=true, message explains",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Empty"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "the sensor is not registered",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Empty"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Empty"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Every field is replaced, omitted ones are cleared and enabled becomes true.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "registry"
+                ],
+                "summary": "Replace the metadata of a registered sensor",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "ABC123",
+                        "description": "ID1",
+                        "name": "id1",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "example": 42,
+                        "description": "ID2",
+                        "name": "id2",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New metadata",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.UpdateSensorMetadataRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "data: SensorMetadata",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.SensorMetadata"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "error=true, message explains",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Empty"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "the sensor is not registered",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Empty"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Empty"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Only the metadata is removed, the readings of the sensor are kept.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "registry"
+                ],
+                "summary": "Remove a sensor from the registry",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "ABC123",
+                        "description": "ID1",
+                        "name": "id1",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "example": 42,
+                        "description": "ID2",
+                        "name": "id2",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Empty"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "error=true, message explains",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Empty"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "the sensor is not registered",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Empty"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Empty"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/sensors/{id1}/{id2}/latest": {
+            "get": {
+                "description": "The reading with the latest timestamp for the id1/id2 pair.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sensor"
+                ],
+                "summary": "Latest reading of one sensor",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "ABC123",
+                        "description": "ID1",
+                        "name": "id1",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "example": 42,
+                        "description": "ID2",
+                        "name": "id2",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "data: LatestReading",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.LatestReading"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "error=true, message explains",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Empty"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "the sensor has no readings",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Empty"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Empty"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "admission.Stats": {
+            "type": "object",
+            "properties": {
+                "admitted": {
+                    "type": "integer"
+                },
+                "in_flight": {
+                    "type": "integer"
+                },
+                "max_in_flight": {
+                    "type": "integer"
+                },
+                "max_queue": {
+                    "type": "integer"
+                },
+                "queued": {
+                    "type": "integer"
+                },
+                "rejected": {
+                    "type": "integer"
+                }
+            }
+        },
+        "logging.LevelRequest": {
+            "type": "object",
+            "properties": {
+                "level": {
+                    "type": "string",
+                    "example": "debug"
+                }
+            }
+        },
+        "model.AggregatePoint": {
+            "type": "object",
+            "properties": {
+                "avg": {
+                    "type": "number",
+                    "example": 23.5
+                },
+                "count": {
+                    "type": "integer",
+                    "example": 600
+                },
+                "first": {
+                    "type": "number",
+                    "example": 22.9
+                },
+                "last": {
+                    "type": "number",
+                    "example": 24.1
+                },
+                "max": {
+                    "type": "number",
+                    "example": 26.2
+                },
+                "min": {
+                    "type": "number",
+                    "example": 21
+                },
+                "stddev": {
+                    "type": "number",
+                    "example": 1.3
+                },
+                "sum": {
+                    "type": "number",
+                    "example": 14100
+                },
+                "timestampMs": {
+                    "description": "bucket start",
+                    "type": "integer",
+                    "example": 1724550000000
+                }
+            }
+        },
+        "model.AggregateResult": {
+            "type": "object",
+            "properties": {
+                "bucketMs": {
+                    "type": "integer",
+                    "example": 60000
+                },
+                "series": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.AggregateSeries"
+                    }
+                }
+            }
+        },
+        "model.AggregateSeries": {
+            "type": "object",
+            "properties": {
+                "id1": {
+                    "type": "string",
+                    "example": "ABC123"
+                },
+                "id2": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "points": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.AggregatePoint"
+                    }
+                }
+            }
+        },
+        "model.CreateSensorTypeRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "aliases": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "max": {
+                    "type": "number",
+                    "example": 150
+                },
+                "min": {
+                    "type": "number",
+                    "example": -50
+                },
+                "name": {
+                    "type": "string",
+                    "example": "temperature"
+                },
+                "rangePolicy": {
+                    "description": "flag when omitted",
+                    "type": "string",
+                    "example": "reject"
+                },
+                "unit": {
+                    "type": "string",
+                    "example": "C"
+                }
+            }
+        },
+        "model.DeleteByIDAndTimesRequest": {
+            "type": "object",
+            "required": [
+                "from_time",
+                "id_combinations",
+                "to_time"
+            ],
+            "properties": {
+                "from_time": {
+                    "type": "string",
+                    "example": "2025-08-25T18:00:24.947000+07:00"
+                },
+                "id_combinations": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/model.IDCombination"
+                    }
+                },
+                "to_time": {
+                    "type": "string",
+                    "example": "2025-08-25T19:00:24.947000+07:00"
+                }
+            }
+        },
+        "model.DeleteByIDsRequest": {
+            "type": "object",
+            "required": [
+                "id_combinations"
+            ],
+            "properties": {
+                "id_combinations": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/model.IDCombination"
+                    }
+                }
             }
         },
         "model.DeleteByTimeRequest": {
@@ -1032,6 +2834,166 @@ const docTemplate = `{
                 }
             }
         },
+        "model.LatestReading": {
+            "type": "object",
+            "properties": {
+                "createdAtMs": {
+                    "type": "integer",
+                    "example": 1724550000120
+                },
+                "id1": {
+                    "type": "string",
+                    "example": "ABC123"
+                },
+                "id2": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "outOfRange": {
+                    "description": "OutOfRange is set on readings stored outside the range of their sensor type",
+                    "type": "boolean"
+                },
+                "readingId": {
+                    "type": "integer",
+                    "example": 1001
+                },
+                "sensorType": {
+                    "type": "string",
+                    "example": "temperature"
+                },
+                "timestampMs": {
+                    "type": "integer",
+                    "example": 1724550000000
+                },
+                "value": {
+                    "type": "number",
+                    "example": 23.5
+                }
+            }
+        },
+        "model.RegisterSensorRequest": {
+            "type": "object",
+            "required": [
+                "id1",
+                "id2"
+            ],
+            "properties": {
+                "enabled": {
+                    "description": "true when omitted",
+                    "type": "boolean",
+                    "example": true
+                },
+                "expectedRate": {
+                    "type": "number",
+                    "example": 0.5
+                },
+                "id1": {
+                    "type": "string",
+                    "example": "ABC123"
+                },
+                "id2": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "labels": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "location": {
+                    "type": "string",
+                    "example": "plant 2, hall B"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "boiler inlet"
+                },
+                "owner": {
+                    "type": "string",
+                    "example": "facilities"
+                },
+                "unit": {
+                    "type": "string",
+                    "example": "C"
+                }
+            }
+        },
+        "model.SensorMetadata": {
+            "type": "object",
+            "properties": {
+                "createdAtMs": {
+                    "type": "integer",
+                    "example": 1724500000000
+                },
+                "enabled": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "expectedRate": {
+                    "description": "readings per second, 0 when unknown",
+                    "type": "number",
+                    "example": 0.5
+                },
+                "id1": {
+                    "type": "string",
+                    "example": "ABC123"
+                },
+                "id2": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "labels": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "location": {
+                    "type": "string",
+                    "example": "plant 2, hall B"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "boiler inlet"
+                },
+                "owner": {
+                    "type": "string",
+                    "example": "facilities"
+                },
+                "unit": {
+                    "type": "string",
+                    "example": "C"
+                },
+                "updatedAtMs": {
+                    "type": "integer",
+                    "example": 1724550000000
+                }
+            }
+        },
+        "model.SensorMetadataPage": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.SensorMetadata"
+                    }
+                },
+                "page": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "pageSize": {
+                    "type": "integer",
+                    "example": 50
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 12
+                }
+            }
+        },
         "model.SensorPage": {
             "type": "object",
             "properties": {
@@ -1066,6 +3028,18 @@ const docTemplate = `{
                     "type": "integer",
                     "example": 123
                 },
+                "outOfRange": {
+                    "description": "OutOfRange is set on readings stored outside the range of their sensor type",
+                    "type": "boolean"
+                },
+                "sensor": {
+                    "description": "Sensor is only set with with_metadata=true and a registered sensor",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.SensorMetadata"
+                        }
+                    ]
+                },
                 "sensorType": {
                     "type": "string",
                     "example": "temperature"
@@ -1080,6 +3054,113 @@ const docTemplate = `{
                 }
             }
         },
+        "model.SensorSummary": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer",
+                    "example": 86400
+                },
+                "firstTimestampMs": {
+                    "type": "integer",
+                    "example": 1724500000000
+                },
+                "id1": {
+                    "type": "string",
+                    "example": "ABC123"
+                },
+                "id2": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "ingestRate": {
+                    "description": "readings per second over the rate window",
+                    "type": "number",
+                    "example": 1.5
+                },
+                "lastTimestampMs": {
+                    "type": "integer",
+                    "example": 1724550000000
+                },
+                "latestValue": {
+                    "type": "number",
+                    "example": 23.5
+                },
+                "sensorType": {
+                    "description": "of the latest reading",
+                    "type": "string",
+                    "example": "temperature"
+                }
+            }
+        },
+        "model.SensorSummaryPage": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.SensorSummary"
+                    }
+                },
+                "page": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "pageSize": {
+                    "type": "integer",
+                    "example": 50
+                },
+                "rateMinutes": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 12
+                }
+            }
+        },
+        "model.SensorType": {
+            "type": "object",
+            "properties": {
+                "aliases": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "createdAtMs": {
+                    "type": "integer",
+                    "example": 1724500000000
+                },
+                "max": {
+                    "description": "null leaves the range open above",
+                    "type": "number",
+                    "example": 150
+                },
+                "min": {
+                    "description": "null leaves the range open below",
+                    "type": "number",
+                    "example": -50
+                },
+                "name": {
+                    "type": "string",
+                    "example": "temperature"
+                },
+                "rangePolicy": {
+                    "type": "string",
+                    "example": "flag"
+                },
+                "unit": {
+                    "type": "string",
+                    "example": "C"
+                },
+                "updatedAtMs": {
+                    "type": "integer",
+                    "example": 1724550000000
+                }
+            }
+        },
         "model.UpdateByIDsAndTimeRequest": {
             "type": "object",
             "required": [
@@ -1177,6 +3258,99 @@ const docTemplate = `{
                     "type": "integer"
                 }
             }
+        },
+        "model.UpdateSensorMetadataRequest": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "description": "true when omitted",
+                    "type": "boolean",
+                    "example": true
+                },
+                "expectedRate": {
+                    "type": "number",
+                    "example": 0.5
+                },
+                "labels": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "location": {
+                    "type": "string",
+                    "example": "plant 2, hall B"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "boiler inlet"
+                },
+                "owner": {
+                    "type": "string",
+                    "example": "facilities"
+                },
+                "unit": {
+                    "type": "string",
+                    "example": "C"
+                }
+            }
+        },
+        "model.UpdateSensorTypeRequest": {
+            "type": "object",
+            "properties": {
+                "aliases": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "max": {
+                    "type": "number",
+                    "example": 150
+                },
+                "min": {
+                    "type": "number",
+                    "example": -50
+                },
+                "rangePolicy": {
+                    "description": "flag when omitted",
+                    "type": "string",
+                    "example": "reject"
+                },
+                "unit": {
+                    "type": "string",
+                    "example": "C"
+                }
+            }
+        },
+        "spool.Stats": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "bytes": {
+                    "type": "integer"
+                },
+                "corrupted": {
+                    "type": "integer"
+                },
+                "dropped": {
+                    "type": "integer"
+                },
+                "max_bytes": {
+                    "type": "integer"
+                },
+                "pending_records": {
+                    "type": "integer"
+                },
+                "replayed": {
+                    "type": "integer"
+                },
+                "segments": {
+                    "type": "integer"
+                }
+            }
         }
     },
     "securityDefinitions": {
@@ -2,17 +2,25 @@ package grpc
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log"
+	"io"
+	"sort"
+	"time"
 
+	"github.com/Yusufzhafir/worlder-team-assignment/b-service/ingest"
+	"github.com/Yusufzhafir/worlder-team-assignment/b-service/metrics"
 	sensorUsecase "github.com/Yusufzhafir/worlder-team-assignment/b-service/usecase"
 	pb "github.com/Yusufzhafir/worlder-team-assignment/common/protobuf"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type ServerGRPC struct {
 	pb.UnimplementedIngestServiceServer
 	sensorUsecase *sensorUsecase.SensorUseCase
-	logger        *log.Logger
+	pipeline      ingest.Pipeline
+	metrics       metrics.Recorder
 }
 
 // SayHello implements helloworld.GreeterServer
@@ -25,29 +33,311 @@ func (s *ServerGRPC) Readings(ctx context.Context, in *pb.SensorReading) (*pb.St
 		}, fmt.Errorf("usecase is nil %v", usecase)
 	}
 
-	err := usecase.InsertSensor(ctx, in)
+	// refuse bad readings before they take a place in a micro-batch, with the
+	// violations as google.rpc.BadRequest details
+	if err := usecase.ValidateReading(in); err != nil {
+		return &pb.StreamAck{
+			Status: "rejected by validation",
+		}, err
+	}
+
+	var result *pb.ItemResult
+	var err error
+	if s.pipeline != nil {
+		// ack only once the micro-batch holding this reading is committed
+		result, err = s.pipeline.Submit(ctx, in)
+	} else {
+		result, err = usecase.InsertSensor(ctx, in)
+		if err != nil {
+			s.observeBatch(metrics.SourceReadings, 1, nil)
+		} else {
+			s.observeBatch(metrics.SourceReadings, 1, []*pb.ItemResult{result})
+		}
+	}
+
+	if err == nil {
+		switch result.GetStatus() {
+		case pb.ItemStatus_ITEM_STATUS_REJECTED_VALIDATION:
+			err = &sensorUsecase.ValidationError{Violations: result.GetViolations()}
+		case pb.ItemStatus_ITEM_STATUS_DUPLICATE_REJECTED:
+			err = status.Errorf(codes.AlreadyExists, "%s as reading_id %d", result.GetReason(), result.GetReadingId())
+		case pb.ItemStatus_ITEM_STATUS_DUPLICATE:
+			// a retry of something already stored, the producer can treat it as success
+			return &pb.StreamAck{
+				Status: fmt.Sprintf("duplicate of reading_id %d", result.GetReadingId()),
+			}, nil
+		}
+	}
 
 	if err != nil {
-		s.logger.Printf("WATDEFAK HAPPEND %v", err)
+		// the logging interceptor reports the failure with the call's request ID
 		return &pb.StreamAck{
 			Status: fmt.Sprintf("failed received id %v", in),
 		}, err
 	}
 
-	s.logger.Printf("Successfully saved HAPPEND %v", err)
 	return &pb.StreamAck{
-		Status: fmt.Sprintf("successfully received id %v", in),
+		Status:     fmt.Sprintf("successfully received id %v", in),
+		Durability: result.GetDurability(),
 	}, nil
 }
 
+// streamBatchSize is how many streamed readings are written per transaction.
+const streamBatchSize = 500
+
+// StreamReadings consumes a client stream, writing readings in batches of
+// streamBatchSize, and acks with the accepted/rejected totals once the client
+// closes its side of the stream. A storage failure ends the stream with
+// codes.Unavailable; the readings accepted before it stay stored, everything
+// sent after them has to be resent.
+func (s *ServerGRPC) StreamReadings(stream pb.IngestService_StreamReadingsServer) error {
+	usecase := *s.sensorUsecase
+
+	if usecase == nil {
+		return fmt.Errorf("usecase is nil %v", usecase)
+	}
+
+	ctx := stream.Context()
+	batch := make([]*pb.SensorReading, 0, streamBatchSize)
+	var accepted, rejected, spooled uint64
+
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		results, err := usecase.InsertSensorBatch(ctx, batch)
+		s.observeBatch(metrics.SourceStreamReadings, len(batch), results)
+		if err != nil {
+			return status.Errorf(codes.Unavailable, "failed to save readings after accepting %d: %v", accepted, err)
+		}
+		for _, result := range results {
+			if isRefused(result) {
+				rejected++
+				continue
+			}
+			accepted++
+			if result.GetDurability() == pb.Durability_DURABILITY_SPOOLED {
+				spooled++
+			}
+		}
+		batch = batch[:0]
+		return nil
+	}
+
+	for {
+		in, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			if err := flush(); err != nil {
+				return err
+			}
+			return stream.SendAndClose(&pb.StreamAck{
+				Status:   fmt.Sprintf("stream closed, accepted %d (spooled %d) rejected %d", accepted, spooled, rejected),
+				Accepted: accepted,
+				Rejected: rejected,
+				Spooled:  spooled,
+			})
+		}
+		if err != nil {
+			return err
+		}
+
+		batch = append(batch, in)
+		if len(batch) >= streamBatchSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// streamFlushInterval bounds how long a partial IngestStream batch waits for more readings.
+const streamFlushInterval = 10 * time.Millisecond
+
+// IngestStream is the pipelined ingest path. Readings arrive tagged with a
+// strictly increasing seq and are written in batches; after each batch the
+// server sends a cumulative ack for the highest seq received so far together with
+// nacks for the readings it refused. An item without a reading is nacked as
+// invalid without reaching the batch. A storage failure ends the stream with
+// codes.Unavailable so the producer reconnects and resends its unacked tail.
+func (s *ServerGRPC) IngestStream(stream pb.IngestService_IngestStreamServer) error {
+	usecase := *s.sensorUsecase
+
+	if usecase == nil {
+		return fmt.Errorf("usecase is nil %v", usecase)
+	}
+
+	ctx := stream.Context()
+	incoming := make(chan *pb.SequencedReading, streamBatchSize)
+	recvErr := make(chan error, 1)
+	go func() {
+		defer close(incoming)
+		for {
+			in, err := stream.Recv()
+			if err != nil {
+				recvErr <- err
+				return
+			}
+			select {
+			case incoming <- in:
+			case <-ctx.Done():
+				recvErr <- ctx.Err()
+				return
+			}
+		}
+	}()
+
+	batch := make([]*pb.SensorReading, 0, streamBatchSize)
+	seqs := make([]uint64, 0, streamBatchSize)
+	// nacks collects the items refused before batching, every seq up to lastSeq
+	// is either in batch or in nacks until the next ack
+	var nacks []*pb.Nack
+	var lastSeq, ackedSeq uint64
+
+	flush := func() error {
+		if lastSeq == ackedSeq {
+			return nil
+		}
+		ack := &pb.IngestAck{
+			CommittedSeq: lastSeq,
+			Durability:   pb.Durability_DURABILITY_COMMITTED,
+			Nacks:        nacks,
+		}
+		var results []*pb.ItemResult
+		if len(batch) > 0 {
+			var err error
+			results, err = usecase.InsertSensorBatch(ctx, batch)
+			s.observeBatch(metrics.SourceIngestStream, len(batch), results)
+			if err != nil {
+				return status.Errorf(codes.Unavailable, "failed to save readings up to seq %d: %v", seqs[len(seqs)-1], err)
+			}
+		}
+		for i, result := range results {
+			if result.GetDurability() == pb.Durability_DURABILITY_SPOOLED {
+				ack.Durability = pb.Durability_DURABILITY_SPOOLED
+			}
+			if isRefused(result) {
+				ack.Nacks = append(ack.Nacks, &pb.Nack{
+					Seq:        seqs[i],
+					Status:     result.GetStatus(),
+					Reason:     result.GetReason(),
+					Violations: result.GetViolations(),
+				})
+			}
+		}
+		sort.Slice(ack.Nacks, func(i, j int) bool { return ack.Nacks[i].GetSeq() < ack.Nacks[j].GetSeq() })
+		batch = batch[:0]
+		seqs = seqs[:0]
+		nacks = nil
+		ackedSeq = lastSeq
+		return stream.Send(ack)
+	}
+
+	ticker := time.NewTicker(streamFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case in, ok := <-incoming:
+			if !ok {
+				if err := flush(); err != nil {
+					return err
+				}
+				if err := <-recvErr; !errors.Is(err, io.EOF) {
+					return err
+				}
+				return nil
+			}
+			if in.GetSeq() <= lastSeq {
+				return status.Errorf(codes.InvalidArgument, "seq %d is not above the previous seq %d", in.GetSeq(), lastSeq)
+			}
+			lastSeq = in.GetSeq()
+			if in.GetReading() == nil {
+				invalid := &sensorUsecase.ValidationError{Violations: []*pb.FieldViolation{{Field: "reading", Description: "is required"}}}
+				nacks = append(nacks, &pb.Nack{
+					Seq:        in.GetSeq(),
+					Status:     pb.ItemStatus_ITEM_STATUS_REJECTED_VALIDATION,
+					Reason:     invalid.Error(),
+					Violations: invalid.Violations,
+				})
+			} else {
+				batch = append(batch, in.GetReading())
+				seqs = append(seqs, in.GetSeq())
+			}
+			if len(batch)+len(nacks) >= streamBatchSize {
+				if err := flush(); err != nil {
+					return err
+				}
+			}
+		case <-ticker.C:
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// maxBatchReadings caps a single ReadingsBatch request.
+const maxBatchReadings = 10000
+
+// ReadingsBatch stores every reading of the batch in one transaction and acks
+// with a result per reading, so producers can tell exactly what was kept.
+func (s *ServerGRPC) ReadingsBatch(ctx context.Context, in *pb.SensorReadingBatch) (*pb.BatchAck, error) {
+	usecase := *s.sensorUsecase
+
+	if usecase == nil {
+		return nil, fmt.Errorf("usecase is nil %v", usecase)
+	}
+
+	if len(in.GetReadings()) > maxBatchReadings {
+		return nil, status.Errorf(codes.InvalidArgument, "batch holds %d readings, at most %d are allowed", len(in.GetReadings()), maxBatchReadings)
+	}
+
+	results, err := usecase.InsertSensorBatch(ctx, in.GetReadings())
+	s.observeBatch(metrics.SourceReadingsBatch, len(in.GetReadings()), results)
+	if err != nil {
+		return nil, err
+	}
+
+	ack := &pb.BatchAck{Results: results}
+	for _, result := range results {
+		switch result.GetStatus() {
+		case pb.ItemStatus_ITEM_STATUS_STORED:
+			ack.Stored++
+		case pb.ItemStatus_ITEM_STATUS_REJECTED_VALIDATION:
+			ack.Rejected++
+		case pb.ItemStatus_ITEM_STATUS_DUPLICATE, pb.ItemStatus_ITEM_STATUS_DUPLICATE_REJECTED:
+			ack.Duplicates++
+		}
+	}
+	return ack, nil
+}
+
+// isRefused reports whether the reading was not stored and the producer should hear about it.
+// Plain duplicates are not refused, retrying something already stored is expected.
+func isRefused(result *pb.ItemResult) bool {
+	switch result.GetStatus() {
+	case pb.ItemStatus_ITEM_STATUS_REJECTED_VALIDATION, pb.ItemStatus_ITEM_STATUS_DUPLICATE_REJECTED:
+		return true
+	}
+	return false
+}
+
+func (s *ServerGRPC) observeBatch(source string, size int, results []*pb.ItemResult) {
+	if s.metrics != nil {
+		s.metrics.ObserveBatch(source, size, results)
+	}
+}
+
 type ServerGRPCOpts struct {
 	SensorUseCase *sensorUsecase.SensorUseCase
-	Logger        *log.Logger
+	Pipeline      ingest.Pipeline  // optional, Readings writes directly when nil
+	Metrics       metrics.Recorder // optional
 }
 
 func NewServerGRPC(opts ServerGRPCOpts) ServerGRPC {
 	return ServerGRPC{
 		sensorUsecase: opts.SensorUseCase,
-		logger:        opts.Logger,
+		pipeline:      opts.Pipeline,
+		metrics:       opts.Metrics,
 	}
 }
@@ -0,0 +1,353 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/Yusufzhafir/worlder-team-assignment/b-service/ingest"
+	repository "github.com/Yusufzhafir/worlder-team-assignment/b-service/repository"
+	"github.com/Yusufzhafir/worlder-team-assignment/b-service/repository/model"
+	sensorUsecase "github.com/Yusufzhafir/worlder-team-assignment/b-service/usecase"
+	pb "github.com/Yusufzhafir/worlder-team-assignment/common/protobuf"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// failingRepository accepts queries but fails every write, as an unreachable database would.
+type failingRepository struct {
+	repository.SensorRepository
+}
+
+var errDatabaseDown = errors.New("database is down")
+
+func (failingRepository) InsertReadingTx(ctx context.Context, r *model.SensorReadingInsert, policy repository.ConflictPolicy) (model.InsertOutcome, error) {
+	return model.InsertOutcome{}, errDatabaseDown
+}
+
+func (failingRepository) InsertReadingsBatchTx(ctx context.Context, rs []model.SensorReadingInsert, policy repository.ConflictPolicy) ([]model.InsertOutcome, error) {
+	return nil, errDatabaseDown
+}
+
+// newTestClient serves opts over an in-memory connection for the duration of the test.
+func newTestClient(t *testing.T, opts ServerGRPCOpts) pb.IngestServiceClient {
+	t.Helper()
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	ingestServer := NewServerGRPC(opts)
+	pb.RegisterIngestServiceServer(server, &ingestServer)
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("grpc.NewClient: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return pb.NewIngestServiceClient(conn)
+}
+
+func newTestUseCase(repo repository.SensorRepository, dedup sensorUsecase.DedupConfig) *sensorUsecase.SensorUseCase {
+	uc := sensorUsecase.NewSensorUseCase(&repo, dedup, nil, nil, nil, nil)
+	return &uc
+}
+
+func testReading(id1, key string) *pb.SensorReading {
+	return &pb.SensorReading{
+		Value:       21.5,
+		SensorType:  "temperature",
+		Id1:         id1,
+		Id2:         1,
+		TimestampMs: time.Now().UnixMilli(),
+		ReadingKey:  key,
+	}
+}
+
+// violatedFields returns the fields of the google.rpc.BadRequest carried by err.
+func violatedFields(err error) []string {
+	var fields []string
+	for _, detail := range status.Convert(err).Details() {
+		if badRequest, ok := detail.(*errdetails.BadRequest); ok {
+			for _, v := range badRequest.GetFieldViolations() {
+				fields = append(fields, v.GetField())
+			}
+		}
+	}
+	return fields
+}
+
+func TestReadings(t *testing.T) {
+	tests := []struct {
+		name       string
+		repo       repository.SensorRepository
+		dedup      sensorUsecase.DedupConfig
+		pipeline   bool
+		readings   []*pb.SensorReading // all but the last are sent first and must succeed
+		wantCode   codes.Code
+		wantFields []string
+	}{
+		{name: "stored", repo: repository.NewMemoryRepository(), readings: []*pb.SensorReading{testReading("A", "")}},
+		{name: "stored through the pipeline", repo: repository.NewMemoryRepository(), pipeline: true, readings: []*pb.SensorReading{testReading("A", "")}},
+		{name: "invalid", repo: repository.NewMemoryRepository(), readings: []*pb.SensorReading{testReading("a", "")}, wantCode: codes.InvalidArgument, wantFields: []string{"id1"}},
+		{name: "duplicate under keep_first", repo: repository.NewMemoryRepository(), readings: []*pb.SensorReading{testReading("A", "k1"), testReading("A", "k1")}},
+		{
+			name:     "duplicate under reject",
+			repo:     repository.NewMemoryRepository(),
+			dedup:    sensorUsecase.DedupConfig{Policy: repository.ConflictReject},
+			readings: []*pb.SensorReading{testReading("A", "k1"), testReading("A", "k1")},
+			wantCode: codes.AlreadyExists,
+		},
+		{name: "database down", repo: failingRepository{repository.NewMemoryRepository()}, readings: []*pb.SensorReading{testReading("A", "")}, wantCode: codes.Unknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := newTestUseCase(tt.repo, tt.dedup)
+			opts := ServerGRPCOpts{SensorUseCase: uc}
+			if tt.pipeline {
+				pipeline := ingest.NewPipeline(ingest.PipelineOpts{Flush: (*uc).InsertSensorBatch})
+				t.Cleanup(func() { _ = pipeline.Close(context.Background()) })
+				opts.Pipeline = pipeline
+			}
+			client := newTestClient(t, opts)
+			ctx := context.Background()
+
+			last := len(tt.readings) - 1
+			for _, r := range tt.readings[:last] {
+				if _, err := client.Readings(ctx, r); err != nil {
+					t.Fatalf("Readings before the one under test: %v", err)
+				}
+			}
+			ack, err := client.Readings(ctx, tt.readings[last])
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("Readings code = %v (%v), want %v", code, err, tt.wantCode)
+			}
+			if fields := violatedFields(err); len(fields) != len(tt.wantFields) || (len(fields) > 0 && fields[0] != tt.wantFields[0]) {
+				t.Fatalf("violated fields = %q, want %q", fields, tt.wantFields)
+			}
+			if tt.wantCode == codes.OK && last == 0 && ack.GetDurability() != pb.Durability_DURABILITY_COMMITTED {
+				t.Fatalf("durability = %v, want committed", ack.GetDurability())
+			}
+		})
+	}
+}
+
+func TestStreamReadings(t *testing.T) {
+	many := make([]*pb.SensorReading, streamBatchSize+1)
+	for i := range many {
+		many[i] = testReading("A", "")
+	}
+	tests := []struct {
+		name     string
+		repo     repository.SensorRepository
+		readings []*pb.SensorReading
+		wantCode codes.Code
+		want     *pb.StreamAck
+	}{
+		{
+			name:     "accepted and rejected",
+			repo:     repository.NewMemoryRepository(),
+			readings: []*pb.SensorReading{testReading("A", ""), testReading("a", ""), testReading("B", "")},
+			want:     &pb.StreamAck{Accepted: 2, Rejected: 1},
+		},
+		{
+			name:     "duplicates count as accepted",
+			repo:     repository.NewMemoryRepository(),
+			readings: []*pb.SensorReading{testReading("A", "k1"), testReading("A", "k1")},
+			want:     &pb.StreamAck{Accepted: 2},
+		},
+		{name: "more than one batch", repo: repository.NewMemoryRepository(), readings: many, want: &pb.StreamAck{Accepted: uint64(len(many))}},
+		{name: "empty stream", repo: repository.NewMemoryRepository(), want: &pb.StreamAck{}},
+		{name: "database down", repo: failingRepository{repository.NewMemoryRepository()}, readings: []*pb.SensorReading{testReading("A", "")}, wantCode: codes.Unavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, ServerGRPCOpts{SensorUseCase: newTestUseCase(tt.repo, sensorUsecase.DedupConfig{})})
+			stream, err := client.StreamReadings(context.Background())
+			if err != nil {
+				t.Fatalf("StreamReadings: %v", err)
+			}
+			for _, r := range tt.readings {
+				if err := stream.Send(r); err != nil {
+					t.Fatalf("Send: %v", err)
+				}
+			}
+			ack, err := stream.CloseAndRecv()
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("CloseAndRecv code = %v (%v), want %v", code, err, tt.wantCode)
+			}
+			if tt.want == nil {
+				return
+			}
+			if ack.GetAccepted() != tt.want.GetAccepted() || ack.GetRejected() != tt.want.GetRejected() || ack.GetSpooled() != tt.want.GetSpooled() {
+				t.Fatalf("ack = accepted %d rejected %d spooled %d, want %d %d %d",
+					ack.GetAccepted(), ack.GetRejected(), ack.GetSpooled(), tt.want.GetAccepted(), tt.want.GetRejected(), tt.want.GetSpooled())
+			}
+		})
+	}
+}
+
+func TestReadingsBatch(t *testing.T) {
+	client := newTestClient(t, ServerGRPCOpts{SensorUseCase: newTestUseCase(repository.NewMemoryRepository(), sensorUsecase.DedupConfig{})})
+	ctx := context.Background()
+
+	ack, err := client.ReadingsBatch(ctx, &pb.SensorReadingBatch{Readings: []*pb.SensorReading{
+		testReading("A", "k1"),
+		testReading("a", "k2"),
+		testReading("B", ""),
+		testReading("A", "k1"),
+	}})
+	if err != nil {
+		t.Fatalf("ReadingsBatch: %v", err)
+	}
+	wantStatus := []pb.ItemStatus{
+		pb.ItemStatus_ITEM_STATUS_STORED,
+		pb.ItemStatus_ITEM_STATUS_REJECTED_VALIDATION,
+		pb.ItemStatus_ITEM_STATUS_STORED,
+		pb.ItemStatus_ITEM_STATUS_DUPLICATE,
+	}
+	if len(ack.GetResults()) != len(wantStatus) {
+		t.Fatalf("got %d results, want %d", len(ack.GetResults()), len(wantStatus))
+	}
+	for i, result := range ack.GetResults() {
+		if result.GetStatus() != wantStatus[i] {
+			t.Fatalf("result %d status = %v, want %v", i, result.GetStatus(), wantStatus[i])
+		}
+	}
+	if ack.GetStored() != 2 || ack.GetRejected() != 1 || ack.GetDuplicates() != 1 {
+		t.Fatalf("ack = stored %d rejected %d duplicates %d, want 2 1 1", ack.GetStored(), ack.GetRejected(), ack.GetDuplicates())
+	}
+	if violations := ack.GetResults()[1].GetViolations(); len(violations) != 1 || violations[0].GetField() != "id1" {
+		t.Fatalf("violations = %v, want id1", violations)
+	}
+	if first, repeat := ack.GetResults()[0], ack.GetResults()[3]; first.GetReadingId() == 0 || repeat.GetReadingId() != first.GetReadingId() {
+		t.Fatalf("repeat reading_id = %d, want %d", repeat.GetReadingId(), first.GetReadingId())
+	}
+
+	full := make([]*pb.SensorReading, maxBatchReadings)
+	for i := range full {
+		full[i] = testReading("A", "")
+	}
+	if ack, err := client.ReadingsBatch(ctx, &pb.SensorReadingBatch{Readings: full}); err != nil || ack.GetStored() != maxBatchReadings {
+		t.Fatalf("ReadingsBatch at the cap = stored %d, %v, want %d", ack.GetStored(), err, maxBatchReadings)
+	}
+	over := append(full, testReading("A", ""))
+	if _, err := client.ReadingsBatch(ctx, &pb.SensorReadingBatch{Readings: over}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("ReadingsBatch above the cap = %v, want InvalidArgument", err)
+	}
+}
+
+func TestIngestStream(t *testing.T) {
+	type want struct {
+		committedSeq uint64
+		nacked       map[uint64]string // seq to the violated field
+		code         codes.Code
+	}
+	tests := []struct {
+		name  string
+		repo  repository.SensorRepository
+		items []*pb.SequencedReading
+		want  want
+	}{
+		{
+			name:  "cumulative ack",
+			repo:  repository.NewMemoryRepository(),
+			items: []*pb.SequencedReading{{Seq: 1, Reading: testReading("A", "")}, {Seq: 2, Reading: testReading("B", "")}, {Seq: 5, Reading: testReading("C", "")}},
+			want:  want{committedSeq: 5, nacked: map[uint64]string{}},
+		},
+		{
+			name: "nack for invalid and missing readings",
+			repo: repository.NewMemoryRepository(),
+			items: []*pb.SequencedReading{
+				{Seq: 1, Reading: testReading("A", "")},
+				{Seq: 2, Reading: testReading("a", "")},
+				{Seq: 3},
+				{Seq: 4, Reading: testReading("B", "")},
+			},
+			want: want{committedSeq: 4, nacked: map[uint64]string{2: "id1", 3: "reading"}},
+		},
+		{
+			name:  "only missing readings",
+			repo:  repository.NewMemoryRepository(),
+			items: []*pb.SequencedReading{{Seq: 1}, {Seq: 2}},
+			want:  want{committedSeq: 2, nacked: map[uint64]string{1: "reading", 2: "reading"}},
+		},
+		{
+			name:  "seq regression",
+			repo:  repository.NewMemoryRepository(),
+			items: []*pb.SequencedReading{{Seq: 2, Reading: testReading("A", "")}, {Seq: 2, Reading: testReading("A", "")}},
+			want:  want{code: codes.InvalidArgument},
+		},
+		{
+			name:  "database down",
+			repo:  failingRepository{repository.NewMemoryRepository()},
+			items: []*pb.SequencedReading{{Seq: 1, Reading: testReading("A", "")}},
+			want:  want{code: codes.Unavailable},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, ServerGRPCOpts{SensorUseCase: newTestUseCase(tt.repo, sensorUsecase.DedupConfig{})})
+			stream, err := client.IngestStream(context.Background())
+			if err != nil {
+				t.Fatalf("IngestStream: %v", err)
+			}
+			for _, item := range tt.items {
+				if err := stream.Send(item); err != nil {
+					t.Fatalf("Send: %v", err)
+				}
+			}
+			if err := stream.CloseSend(); err != nil {
+				t.Fatalf("CloseSend: %v", err)
+			}
+
+			var committedSeq uint64
+			nacked := map[uint64]string{}
+			for {
+				ack, err := stream.Recv()
+				if err != nil {
+					if errors.Is(err, io.EOF) {
+						err = nil
+					}
+					if code := status.Code(err); code != tt.want.code {
+						t.Fatalf("Recv code = %v (%v), want %v", code, err, tt.want.code)
+					}
+					break
+				}
+				if ack.GetCommittedSeq() <= committedSeq {
+					t.Fatalf("committed_seq went from %d to %d", committedSeq, ack.GetCommittedSeq())
+				}
+				committedSeq = ack.GetCommittedSeq()
+				for _, nack := range ack.GetNacks() {
+					if nack.GetStatus() != pb.ItemStatus_ITEM_STATUS_REJECTED_VALIDATION || len(nack.GetViolations()) == 0 {
+						t.Fatalf("nack = %v, want a validation rejection", nack)
+					}
+					nacked[nack.GetSeq()] = nack.GetViolations()[0].GetField()
+				}
+			}
+			if tt.want.code != codes.OK {
+				return
+			}
+			if committedSeq != tt.want.committedSeq {
+				t.Fatalf("committed_seq = %d, want %d", committedSeq, tt.want.committedSeq)
+			}
+			if len(nacked) != len(tt.want.nacked) {
+				t.Fatalf("nacked = %v, want %v", nacked, tt.want.nacked)
+			}
+			for seq, field := range tt.want.nacked {
+				if nacked[seq] != field {
+					t.Fatalf("nacked = %v, want %v", nacked, tt.want.nacked)
+				}
+			}
+		})
+	}
+}
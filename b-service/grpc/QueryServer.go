@@ -0,0 +1,617 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Yusufzhafir/worlder-team-assignment/b-service/live"
+	"github.com/Yusufzhafir/worlder-team-assignment/b-service/repository"
+	"github.com/Yusufzhafir/worlder-team-assignment/b-service/repository/model"
+	sensorUsecase "github.com/Yusufzhafir/worlder-team-assignment/b-service/usecase"
+	pb "github.com/Yusufzhafir/worlder-team-assignment/common/protobuf"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 500
+)
+
+// QueryServerGRPC serves SensorQueryService with the same use case as the REST sensor router.
+type QueryServerGRPC struct {
+	pb.UnimplementedSensorQueryServiceServer
+	sensorUsecase *sensorUsecase.SensorUseCase
+	feed          live.Hub
+}
+
+// readingFilter is the ids and time range shared by every SensorQueryService request.
+type readingFilter struct {
+	ids      []repository.IDCombination
+	from, to time.Time
+	hasTime  bool
+}
+
+func parseFilter(ids []*pb.IdCombination, fromMs, toMs int64) (readingFilter, error) {
+	filter := readingFilter{}
+	for _, id := range ids {
+		filter.ids = append(filter.ids, repository.IDCombination{
+			ID1: id.GetId1(),
+			ID2: int(id.GetId2()),
+		})
+	}
+
+	if fromMs == 0 && toMs == 0 {
+		return filter, nil
+	}
+	if fromMs == 0 || toMs == 0 {
+		return filter, status.Error(codes.InvalidArgument, "both from_ms and to_ms must be provided")
+	}
+	if fromMs >= toMs {
+		return filter, status.Error(codes.InvalidArgument, "from_ms must be before to_ms")
+	}
+	filter.from = time.UnixMilli(fromMs)
+	filter.to = time.UnixMilli(toMs)
+	filter.hasTime = true
+	return filter, nil
+}
+
+// usecaseError maps a use case failure to a status, so callers can tell an outage from a bug.
+func (s *QueryServerGRPC) usecaseError(op string, err error) error {
+	if repository.IsUnavailable(err) {
+		return status.Errorf(codes.Unavailable, "failed to %s: %v", op, err)
+	}
+	return status.Errorf(codes.Internal, "failed to %s: %v", op, err)
+}
+
+func toStoredReading(row model.SensorReading) *pb.StoredReading {
+	return &pb.StoredReading{
+		ReadingId:   row.ReadingID,
+		Value:       row.SensorValue,
+		SensorType:  row.SensorType,
+		Id1:         row.ID1,
+		Id2:         int32(row.ID2),
+		TimestampMs: row.TS.UnixMilli(),
+		CreatedAtMs: row.CreatedAt.UnixMilli(),
+		OutOfRange:  row.OutOfRange,
+	}
+}
+
+// ListReadings pages through readings, filtered like GET /sensor, /sensor/ids,
+// /sensor/time and /sensor/ids-time depending on which filters are set.
+func (s *QueryServerGRPC) ListReadings(ctx context.Context, in *pb.ListReadingsRequest) (*pb.ListReadingsResponse, error) {
+	usecase := *s.sensorUsecase
+	if usecase == nil {
+		return nil, fmt.Errorf("usecase is nil %v", usecase)
+	}
+
+	page := int(in.GetPage())
+	if page == 0 {
+		page = 1
+	}
+	size := int(in.GetPageSize())
+	if size == 0 {
+		size = defaultPageSize
+	}
+	if size > maxPageSize {
+		return nil, status.Errorf(codes.InvalidArgument, "page_size must be 1..%d", maxPageSize)
+	}
+	offset := (page - 1) * size
+
+	filter, err := parseFilter(in.GetIds(), in.GetFromMs(), in.GetToMs())
+	if err != nil {
+		return nil, err
+	}
+
+	var result sensorUsecase.PaginatedSensor
+	switch {
+	case len(filter.ids) > 0 && filter.hasTime:
+		result, err = usecase.GetSensorByIDsAndTime(ctx, &filter.ids, filter.from, filter.to, size, offset)
+	case len(filter.ids) > 0:
+		result, err = usecase.GetSensorByIDs(ctx, &filter.ids, size, offset)
+	case filter.hasTime:
+		result, err = usecase.GetSensorByTime(ctx, filter.from, filter.to, size, offset)
+	default:
+		result, err = usecase.GetSensorPaginated(ctx, size, offset)
+	}
+	if err != nil {
+		return nil, s.usecaseError("list readings", err)
+	}
+
+	resp := &pb.ListReadingsResponse{
+		Items:    make([]*pb.StoredReading, len(result.Data)),
+		Page:     uint32(page),
+		PageSize: uint32(size),
+		Total:    result.Count,
+	}
+	for i, row := range result.Data {
+		resp.Items[i] = toStoredReading(row)
+	}
+	if in.GetWithMetadata() {
+		if err := s.joinSensorMetadata(ctx, resp.Items); err != nil {
+			return nil, s.usecaseError("look up the sensor metadata", err)
+		}
+	}
+	return resp, nil
+}
+
+// joinSensorMetadata sets Sensor on every item whose sensor is registered.
+func (s *QueryServerGRPC) joinSensorMetadata(ctx context.Context, items []*pb.StoredReading) error {
+	if len(items) == 0 {
+		return nil
+	}
+	ids := make([]repository.IDCombination, len(items))
+	for i, item := range items {
+		ids[i] = repository.IDCombination{ID1: item.GetId1(), ID2: int(item.GetId2())}
+	}
+	sensors, err := (*s.sensorUsecase).LookupSensors(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for i, item := range items {
+		if sensor, ok := sensors[ids[i]]; ok {
+			item.Sensor = toSensorMetadata(sensor)
+		}
+	}
+	return nil
+}
+
+// UpdateReadings sets value and type of the matching readings, like the PUT /sensor/update routes.
+func (s *QueryServerGRPC) UpdateReadings(ctx context.Context, in *pb.UpdateReadingsRequest) (*pb.UpdateReadingsResponse, error) {
+	usecase := *s.sensorUsecase
+	if usecase == nil {
+		return nil, fmt.Errorf("usecase is nil %v", usecase)
+	}
+
+	filter, err := parseFilter(in.GetIds(), in.GetFromMs(), in.GetToMs())
+	if err != nil {
+		return nil, err
+	}
+	if in.GetSensorType() == "" {
+		return nil, status.Error(codes.InvalidArgument, "sensor_type is required")
+	}
+
+	var result sensorUsecase.MutatedResponse
+	switch {
+	case len(filter.ids) > 0 && filter.hasTime:
+		result, err = usecase.UpdateSensorByIdsAndTime(ctx, &filter.ids, filter.from, filter.to, in.GetSensorValue(), in.GetSensorType())
+	case len(filter.ids) > 0:
+		result, err = usecase.UpdateSensorByIds(ctx, &filter.ids, in.GetSensorValue(), in.GetSensorType())
+	case filter.hasTime:
+		result, err = usecase.UpdateSensorByTime(ctx, filter.from, filter.to, in.GetSensorValue(), in.GetSensorType())
+	default:
+		return nil, status.Error(codes.InvalidArgument, "at least one of ids and from_ms/to_ms must be provided")
+	}
+	if err != nil {
+		return nil, s.usecaseError("update readings", err)
+	}
+
+	return &pb.UpdateReadingsResponse{
+		UpdatedCount: result.Count,
+		Message:      result.Message,
+	}, nil
+}
+
+// DeleteReadings removes the matching readings, like the DELETE /sensor/delete routes.
+func (s *QueryServerGRPC) DeleteReadings(ctx context.Context, in *pb.DeleteReadingsRequest) (*pb.DeleteReadingsResponse, error) {
+	usecase := *s.sensorUsecase
+	if usecase == nil {
+		return nil, fmt.Errorf("usecase is nil %v", usecase)
+	}
+
+	filter, err := parseFilter(in.GetIds(), in.GetFromMs(), in.GetToMs())
+	if err != nil {
+		return nil, err
+	}
+
+	var result sensorUsecase.MutatedResponse
+	switch {
+	case len(filter.ids) > 0 && filter.hasTime:
+		result, err = usecase.DeleteSensorByIdsAndTime(ctx, &filter.ids, filter.from, filter.to)
+	case len(filter.ids) > 0:
+		result, err = usecase.DeleteSensorByIds(ctx, &filter.ids)
+	case filter.hasTime:
+		result, err = usecase.DeleteSensorByTime(ctx, filter.from, filter.to)
+	default:
+		return nil, status.Error(codes.InvalidArgument, "at least one of ids and from_ms/to_ms must be provided")
+	}
+	if err != nil {
+		return nil, s.usecaseError("delete readings", err)
+	}
+
+	return &pb.DeleteReadingsResponse{
+		DeletedCount: result.Count,
+		Message:      result.Message,
+	}, nil
+}
+
+var aggregateFuncs = map[pb.AggregateFunction]sensorUsecase.AggregateFunc{
+	pb.AggregateFunction_AGGREGATE_FUNCTION_AVG:    sensorUsecase.AggregateAvg,
+	pb.AggregateFunction_AGGREGATE_FUNCTION_MIN:    sensorUsecase.AggregateMin,
+	pb.AggregateFunction_AGGREGATE_FUNCTION_MAX:    sensorUsecase.AggregateMax,
+	pb.AggregateFunction_AGGREGATE_FUNCTION_SUM:    sensorUsecase.AggregateSum,
+	pb.AggregateFunction_AGGREGATE_FUNCTION_COUNT:  sensorUsecase.AggregateCount,
+	pb.AggregateFunction_AGGREGATE_FUNCTION_FIRST:  sensorUsecase.AggregateFirst,
+	pb.AggregateFunction_AGGREGATE_FUNCTION_LAST:   sensorUsecase.AggregateLast,
+	pb.AggregateFunction_AGGREGATE_FUNCTION_STDDEV: sensorUsecase.AggregateStddev,
+}
+
+func toAggregatePoint(p sensorUsecase.AggregatePoint, funcs []pb.AggregateFunction) *pb.AggregatePoint {
+	point := &pb.AggregatePoint{TimestampMs: p.Start.UnixMilli()}
+	for _, fn := range funcs {
+		value := p.Value(aggregateFuncs[fn])
+		switch fn {
+		case pb.AggregateFunction_AGGREGATE_FUNCTION_AVG:
+			point.Avg = &value
+		case pb.AggregateFunction_AGGREGATE_FUNCTION_MIN:
+			point.Min = &value
+		case pb.AggregateFunction_AGGREGATE_FUNCTION_MAX:
+			point.Max = &value
+		case pb.AggregateFunction_AGGREGATE_FUNCTION_SUM:
+			point.Sum = &value
+		case pb.AggregateFunction_AGGREGATE_FUNCTION_COUNT:
+			point.Count = &p.Count
+		case pb.AggregateFunction_AGGREGATE_FUNCTION_FIRST:
+			point.First = &value
+		case pb.AggregateFunction_AGGREGATE_FUNCTION_LAST:
+			point.Last = &value
+		case pb.AggregateFunction_AGGREGATE_FUNCTION_STDDEV:
+			point.Stddev = &value
+		}
+	}
+	return point
+}
+
+// AggregateReadings downsamples readings into time buckets, like GET /sensor/aggregate.
+func (s *QueryServerGRPC) AggregateReadings(ctx context.Context, in *pb.AggregateReadingsRequest) (*pb.AggregateReadingsResponse, error) {
+	usecase := *s.sensorUsecase
+	if usecase == nil {
+		return nil, fmt.Errorf("usecase is nil %v", usecase)
+	}
+
+	if in.GetFromMs() == 0 || in.GetToMs() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "both from_ms and to_ms must be provided")
+	}
+	filter, err := parseFilter(in.GetIds(), in.GetFromMs(), in.GetToMs())
+	if err != nil {
+		return nil, err
+	}
+
+	functions := in.GetFunctions()
+	if len(functions) == 0 {
+		functions = []pb.AggregateFunction{pb.AggregateFunction_AGGREGATE_FUNCTION_AVG}
+	}
+	funcs := make([]sensorUsecase.AggregateFunc, 0, len(functions))
+	for _, fn := range functions {
+		f, ok := aggregateFuncs[fn]
+		if !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "unknown aggregate function %s", fn)
+		}
+		funcs = append(funcs, f)
+	}
+
+	series, err := usecase.GetSensorAggregate(ctx, sensorUsecase.AggregateRequest{
+		IDs:        filter.ids,
+		SensorType: in.GetSensorType(),
+		From:       filter.from,
+		To:         filter.to,
+		Bucket:     time.Duration(in.GetBucketMs()) * time.Millisecond,
+		Funcs:      funcs,
+	})
+	if errors.Is(err, sensorUsecase.ErrInvalidAggregate) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err != nil {
+		return nil, s.usecaseError("aggregate readings", err)
+	}
+
+	resp := &pb.AggregateReadingsResponse{Series: make([]*pb.AggregateSeries, len(series))}
+	for i, sensor := range series {
+		points := make([]*pb.AggregatePoint, len(sensor.Points))
+		for j, p := range sensor.Points {
+			points[j] = toAggregatePoint(p, functions)
+		}
+		resp.Series[i] = &pb.AggregateSeries{Id1: sensor.ID1, Id2: int32(sensor.ID2), Points: points}
+	}
+	return resp, nil
+}
+
+// maxRateMinutes bounds the ingest rate window of ListSensors to a day.
+const maxRateMinutes = 24 * 60
+
+// ListSensors pages through the sensors that have readings, like GET /sensors.
+func (s *QueryServerGRPC) ListSensors(ctx context.Context, in *pb.ListSensorsRequest) (*pb.ListSensorsResponse, error) {
+	usecase := *s.sensorUsecase
+	if usecase == nil {
+		return nil, fmt.Errorf("usecase is nil %v", usecase)
+	}
+
+	page := int(in.GetPage())
+	if page == 0 {
+		page = 1
+	}
+	size := int(in.GetPageSize())
+	if size == 0 {
+		size = defaultPageSize
+	}
+	if size > maxPageSize {
+		return nil, status.Errorf(codes.InvalidArgument, "page_size must be 1..%d", maxPageSize)
+	}
+	if in.GetRateMinutes() > maxRateMinutes {
+		return nil, status.Errorf(codes.InvalidArgument, "rate_minutes must be 1..%d", maxRateMinutes)
+	}
+	rateWindow := time.Duration(in.GetRateMinutes()) * time.Minute
+
+	result, err := usecase.ListSensors(ctx, rateWindow, size, (page-1)*size)
+	if err != nil {
+		return nil, s.usecaseError("list sensors", err)
+	}
+
+	resp := &pb.ListSensorsResponse{
+		Items:    make([]*pb.SensorSummary, len(result.Data)),
+		Page:     uint32(page),
+		PageSize: uint32(size),
+		Total:    result.Count,
+	}
+	for i, sensor := range result.Data {
+		resp.Items[i] = &pb.SensorSummary{
+			Id1:              sensor.ID1,
+			Id2:              int32(sensor.ID2),
+			SensorType:       sensor.SensorType,
+			LatestValue:      sensor.LatestValue,
+			FirstTimestampMs: sensor.FirstTS.UnixMilli(),
+			LastTimestampMs:  sensor.LastTS.UnixMilli(),
+			Count:            sensor.Count,
+			IngestRate:       sensor.IngestRate,
+		}
+	}
+	return resp, nil
+}
+
+// GetLatestReading returns the newest reading of one sensor, like GET /sensors/{id1}/{id2}/latest.
+func (s *QueryServerGRPC) GetLatestReading(ctx context.Context, in *pb.GetLatestReadingRequest) (*pb.StoredReading, error) {
+	usecase := *s.sensorUsecase
+	if usecase == nil {
+		return nil, fmt.Errorf("usecase is nil %v", usecase)
+	}
+	if in.GetId() == nil {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	id := repository.IDCombination{ID1: in.GetId().GetId1(), ID2: int(in.GetId().GetId2())}
+	reading, err := usecase.GetLatestReading(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, status.Errorf(codes.NotFound, "sensor %s/%d has no readings", id.ID1, id.ID2)
+	}
+	if err != nil {
+		return nil, s.usecaseError("get the latest reading", err)
+	}
+	return toStoredReading(reading), nil
+}
+
+func toSensorMetadata(s model.Sensor) *pb.SensorMetadata {
+	enabled := s.Enabled
+	return &pb.SensorMetadata{
+		Id1:          s.ID1,
+		Id2:          int32(s.ID2),
+		Name:         s.Name,
+		Unit:         s.Unit,
+		Location:     s.Location,
+		Owner:        s.Owner,
+		ExpectedRate: s.ExpectedRate,
+		Enabled:      &enabled,
+		Labels:       s.Labels,
+		CreatedAtMs:  s.CreatedAt.UnixMilli(),
+		UpdatedAtMs:  s.UpdatedAt.UnixMilli(),
+	}
+}
+
+// fromSensorMetadata is the registry row in, an unset enabled meaning true.
+func fromSensorMetadata(in *pb.SensorMetadata) model.Sensor {
+	enabled := true
+	if in.Enabled != nil {
+		enabled = in.GetEnabled()
+	}
+	return model.Sensor{
+		ID1:          in.GetId1(),
+		ID2:          int(in.GetId2()),
+		Name:         in.GetName(),
+		Unit:         in.GetUnit(),
+		Location:     in.GetLocation(),
+		Owner:        in.GetOwner(),
+		ExpectedRate: in.GetExpectedRate(),
+		Enabled:      enabled,
+		Labels:       model.Labels(in.GetLabels()),
+	}
+}
+
+// registryError maps what the registry use case returns to a status.
+func (s *QueryServerGRPC) registryError(op string, id repository.IDCombination, err error) error {
+	switch {
+	case errors.Is(err, sensorUsecase.ErrInvalidSensor):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, repository.ErrNotFound):
+		return status.Errorf(codes.NotFound, "sensor %s/%d is not registered", id.ID1, id.ID2)
+	case errors.Is(err, repository.ErrAlreadyExists):
+		return status.Errorf(codes.AlreadyExists, "sensor %s/%d is already registered", id.ID1, id.ID2)
+	}
+	return s.usecaseError(op, err)
+}
+
+func (s *QueryServerGRPC) RegisterSensor(ctx context.Context, in *pb.RegisterSensorRequest) (*pb.SensorMetadata, error) {
+	usecase := *s.sensorUsecase
+	if usecase == nil {
+		return nil, fmt.Errorf("usecase is nil %v", usecase)
+	}
+	if in.GetSensor() == nil {
+		return nil, status.Error(codes.InvalidArgument, "sensor is required")
+	}
+
+	row := fromSensorMetadata(in.GetSensor())
+	sensor, err := usecase.RegisterSensor(ctx, row)
+	if err != nil {
+		return nil, s.registryError("register the sensor", repository.IDCombination{ID1: row.ID1, ID2: row.ID2}, err)
+	}
+	return toSensorMetadata(sensor), nil
+}
+
+func (s *QueryServerGRPC) GetSensor(ctx context.Context, in *pb.GetSensorRequest) (*pb.SensorMetadata, error) {
+	usecase := *s.sensorUsecase
+	if usecase == nil {
+		return nil, fmt.Errorf("usecase is nil %v", usecase)
+	}
+	if in.GetId() == nil {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	id := repository.IDCombination{ID1: in.GetId().GetId1(), ID2: int(in.GetId().GetId2())}
+	sensor, err := usecase.GetRegisteredSensor(ctx, id)
+	if err != nil {
+		return nil, s.registryError("get the sensor", id, err)
+	}
+	return toSensorMetadata(sensor), nil
+}
+
+func (s *QueryServerGRPC) ListRegisteredSensors(ctx context.Context, in *pb.ListRegisteredSensorsRequest) (*pb.ListRegisteredSensorsResponse, error) {
+	usecase := *s.sensorUsecase
+	if usecase == nil {
+		return nil, fmt.Errorf("usecase is nil %v", usecase)
+	}
+
+	page := int(in.GetPage())
+	if page == 0 {
+		page = 1
+	}
+	size := int(in.GetPageSize())
+	if size == 0 {
+		size = defaultPageSize
+	}
+	if size > maxPageSize {
+		return nil, status.Errorf(codes.InvalidArgument, "page_size must be 1..%d", maxPageSize)
+	}
+
+	result, err := usecase.ListRegisteredSensors(ctx, size, (page-1)*size)
+	if err != nil {
+		return nil, s.usecaseError("list the registered sensors", err)
+	}
+
+	resp := &pb.ListRegisteredSensorsResponse{
+		Items:    make([]*pb.SensorMetadata, len(result.Data)),
+		Page:     uint32(page),
+		PageSize: uint32(size),
+		Total:    result.Count,
+	}
+	for i, sensor := range result.Data {
+		resp.Items[i] = toSensorMetadata(sensor)
+	}
+	return resp, nil
+}
+
+func (s *QueryServerGRPC) UpdateSensor(ctx context.Context, in *pb.UpdateSensorRequest) (*pb.SensorMetadata, error) {
+	usecase := *s.sensorUsecase
+	if usecase == nil {
+		return nil, fmt.Errorf("usecase is nil %v", usecase)
+	}
+	if in.GetSensor() == nil {
+		return nil, status.Error(codes.InvalidArgument, "sensor is required")
+	}
+
+	row := fromSensorMetadata(in.GetSensor())
+	sensor, err := usecase.UpdateRegisteredSensor(ctx, row)
+	if err != nil {
+		return nil, s.registryError("update the sensor", repository.IDCombination{ID1: row.ID1, ID2: row.ID2}, err)
+	}
+	return toSensorMetadata(sensor), nil
+}
+
+func (s *QueryServerGRPC) DeleteSensor(ctx context.Context, in *pb.DeleteSensorRequest) (*pb.DeleteSensorResponse, error) {
+	usecase := *s.sensorUsecase
+	if usecase == nil {
+		return nil, fmt.Errorf("usecase is nil %v", usecase)
+	}
+	if in.GetId() == nil {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	id := repository.IDCombination{ID1: in.GetId().GetId1(), ID2: int(in.GetId().GetId2())}
+	if err := usecase.DeregisterSensor(ctx, id); err != nil {
+		return nil, s.registryError("delete the sensor", id, err)
+	}
+	return &pb.DeleteSensorResponse{}, nil
+}
+
+// DroppedKey is the WatchReadings trailer counting readings skipped for a slow subscriber.
+const DroppedKey = "dropped-readings"
+
+// WatchReadings streams readings matching in as they are stored, until the
+// client cancels, falls behind under the disconnect policy, or the server stops.
+func (s *QueryServerGRPC) WatchReadings(in *pb.WatchRequest, stream pb.SensorQueryService_WatchReadingsServer) error {
+	if s.feed == nil {
+		return status.Error(codes.Unimplemented, "live feed is disabled")
+	}
+
+	filter := live.Filter{
+		SensorTypes: in.GetSensorTypes(),
+		MinValue:    in.MinValue,
+		MaxValue:    in.MaxValue,
+	}
+	for _, id := range in.GetIds() {
+		filter.IDs = append(filter.IDs, live.ID{ID1: id.GetId1(), ID2: id.GetId2()})
+	}
+
+	var policy live.SlowConsumerPolicy
+	switch in.GetSlowConsumer() {
+	case pb.SlowConsumerPolicy_SLOW_CONSUMER_POLICY_DROP:
+		policy = live.PolicyDrop
+	case pb.SlowConsumerPolicy_SLOW_CONSUMER_POLICY_DISCONNECT:
+		policy = live.PolicyDisconnect
+	}
+
+	sub, err := s.feed.Subscribe(filter, policy)
+	if errors.Is(err, live.ErrHubClosed) {
+		return status.Error(codes.Unavailable, err.Error())
+	}
+	if err != nil {
+		return status.Error(codes.ResourceExhausted, err.Error())
+	}
+	defer func() {
+		sub.Close()
+		stream.SetTrailer(metadata.Pairs(DroppedKey, strconv.FormatUint(sub.Dropped(), 10)))
+	}()
+
+	ctx := stream.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case <-sub.Done():
+			switch err := sub.Err(); {
+			case errors.Is(err, live.ErrHubClosed):
+				return status.Error(codes.Unavailable, err.Error())
+			case err != nil:
+				return status.Error(codes.ResourceExhausted, err.Error())
+			}
+			return nil
+		case reading := <-sub.C():
+			if err := stream.Send(reading); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+type QueryServerGRPCOpts struct {
+	SensorUseCase *sensorUsecase.SensorUseCase
+	Feed          live.Hub // optional, WatchReadings is unimplemented when nil
+}
+
+func NewQueryServerGRPC(opts QueryServerGRPCOpts) QueryServerGRPC {
+	return QueryServerGRPC{
+		sensorUsecase: opts.SensorUseCase,
+		feed:          opts.Feed,
+	}
+}
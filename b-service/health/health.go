@@ -0,0 +1,220 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/Yusufzhafir/worlder-team-assignment/b-service/ingest"
+	"github.com/Yusufzhafir/worlder-team-assignment/b-service/spool"
+	grpcHealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+type Status string
+
+const (
+	StatusUp Status = "up"
+	// StatusDegraded still serves, e.g. readings are spooled while the database is down
+	StatusDegraded Status = "degraded"
+	StatusDown     Status = "down"
+)
+
+type Check struct {
+	Name   string `json:"name"`
+	Status Status `json:"status"`
+	Detail string `json:"detail,omitempty"`
+}
+
+type Report struct {
+	Status Status  `json:"status"`
+	Checks []Check `json:"checks"`
+}
+
+// Ready is false when any check is down.
+func (r Report) Ready() bool {
+	return r.Status != StatusDown
+}
+
+// Checker probes the database, the ingest queue and the spool, and mirrors the result into
+// the grpc.health.v1 service. After Shutdown every probe reports down.
+type Checker interface {
+	// Live is down only once shutdown has begun.
+	Live() Report
+	// Ready runs every check.
+	Ready(ctx context.Context) Report
+	// Start refreshes the gRPC serving status every interval until ctx ends.
+	Start(ctx context.Context)
+	Shutdown()
+}
+
+type CheckerImpl struct {
+	ping          func(ctx context.Context) error
+	pipeline      ingest.Pipeline
+	spool         spool.Spool
+	grpc          *grpcHealth.Server
+	services      []string
+	maxQueueRatio float64
+	interval      time.Duration
+	pingTimeout   time.Duration
+	logger        *slog.Logger
+	shuttingDown  atomic.Bool
+}
+
+type CheckerOpts struct {
+	Ping          func(ctx context.Context) error
+	Pipeline      ingest.Pipeline    // optional, nil when micro-batching is disabled
+	Spool         spool.Spool        // optional, a reachable spool turns a database outage into degraded
+	GRPC          *grpcHealth.Server // optional
+	Services      []string           // gRPC services reported besides the overall "" status
+	MaxQueueRatio float64            // queue or spool fill at which ingest counts as saturated
+	Interval      time.Duration      // how often the gRPC status is refreshed
+	PingTimeout   time.Duration      // upper bound of a database ping
+	Logger        *slog.Logger
+}
+
+func NewChecker(opts CheckerOpts) Checker {
+	if opts.MaxQueueRatio <= 0 || opts.MaxQueueRatio > 1 {
+		opts.MaxQueueRatio = 0.9
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = 5 * time.Second
+	}
+	if opts.PingTimeout <= 0 {
+		opts.PingTimeout = time.Second
+	}
+	if opts.Logger == nil {
+		opts.Logger = slog.Default()
+	}
+	return &CheckerImpl{
+		ping:          opts.Ping,
+		pipeline:      opts.Pipeline,
+		spool:         opts.Spool,
+		grpc:          opts.GRPC,
+		services:      opts.Services,
+		maxQueueRatio: opts.MaxQueueRatio,
+		interval:      opts.Interval,
+		pingTimeout:   opts.PingTimeout,
+		logger:        opts.Logger,
+	}
+}
+
+func (c *CheckerImpl) Live() Report {
+	if c.shuttingDown.Load() {
+		return Report{Status: StatusDown, Checks: []Check{c.shutdownCheck()}}
+	}
+	return Report{Status: StatusUp, Checks: []Check{}}
+}
+
+func (c *CheckerImpl) Ready(ctx context.Context) Report {
+	if c.shuttingDown.Load() {
+		return Report{Status: StatusDown, Checks: []Check{c.shutdownCheck()}}
+	}
+
+	checks := []Check{c.checkDatabase(ctx)}
+	if c.pipeline != nil {
+		checks = append(checks, c.checkQueue())
+	}
+	if c.spool != nil {
+		checks = append(checks, c.checkSpool())
+	}
+
+	report := Report{Status: StatusUp, Checks: checks}
+	for _, check := range checks {
+		if check.Status == StatusDown {
+			report.Status = StatusDown
+			break
+		}
+		if check.Status == StatusDegraded {
+			report.Status = StatusDegraded
+		}
+	}
+	return report
+}
+
+func (c *CheckerImpl) shutdownCheck() Check {
+	return Check{Name: "server", Status: StatusDown, Detail: "shutting down"}
+}
+
+func (c *CheckerImpl) checkDatabase(ctx context.Context) Check {
+	ctx, cancel := context.WithTimeout(ctx, c.pingTimeout)
+	defer cancel()
+
+	err := c.ping(ctx)
+	if err == nil {
+		return Check{Name: "database", Status: StatusUp}
+	}
+	if c.spool != nil {
+		stats := c.spool.Stats()
+		if float64(stats.Bytes) < c.maxQueueRatio*float64(stats.MaxBytes) {
+			return Check{Name: "database", Status: StatusDegraded, Detail: fmt.Sprintf("%v, readings are spooled", err)}
+		}
+	}
+	return Check{Name: "database", Status: StatusDown, Detail: err.Error()}
+}
+
+func (c *CheckerImpl) checkQueue() Check {
+	queued, capacity := c.pipeline.Depth()
+	detail := fmt.Sprintf("%d of %d queued", queued, capacity)
+	if capacity > 0 && float64(queued) >= c.maxQueueRatio*float64(capacity) {
+		return Check{Name: "ingest_queue", Status: StatusDown, Detail: detail}
+	}
+	return Check{Name: "ingest_queue", Status: StatusUp, Detail: detail}
+}
+
+// checkSpool is degraded once the spool is nearly full: readings then bypass it
+// while the database answers, and are refused while it does not.
+func (c *CheckerImpl) checkSpool() Check {
+	stats := c.spool.Stats()
+	detail := fmt.Sprintf("%d of %d bytes, %d readings pending", stats.Bytes, stats.MaxBytes, stats.PendingRecords)
+	if stats.MaxBytes > 0 && float64(stats.Bytes) >= c.maxQueueRatio*float64(stats.MaxBytes) {
+		return Check{Name: "spool", Status: StatusDegraded, Detail: detail}
+	}
+	return Check{Name: "spool", Status: StatusUp, Detail: detail}
+}
+
+func (c *CheckerImpl) Start(ctx context.Context) {
+	if c.grpc == nil {
+		return
+	}
+	c.refresh(ctx)
+	go func() {
+		ticker := time.NewTicker(c.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				c.refresh(ctx)
+			}
+		}
+	}()
+}
+
+func (c *CheckerImpl) refresh(ctx context.Context) {
+	if c.shuttingDown.Load() {
+		return
+	}
+	report := c.Ready(ctx)
+	status := healthpb.HealthCheckResponse_SERVING
+	if !report.Ready() {
+		status = healthpb.HealthCheckResponse_NOT_SERVING
+		c.logger.WarnContext(ctx, "not ready", "checks", report.Checks)
+	}
+	c.grpc.SetServingStatus("", status)
+	for _, service := range c.services {
+		c.grpc.SetServingStatus(service, status)
+	}
+}
+
+// Shutdown reports NOT_SERVING from now on, so load balancers stop routing before
+// the servers stop.
+func (c *CheckerImpl) Shutdown() {
+	c.shuttingDown.Store(true)
+	if c.grpc != nil {
+		c.grpc.Shutdown()
+	}
+}
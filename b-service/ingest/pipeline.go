@@ -0,0 +1,278 @@
+package ingest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/Yusufzhafir/worlder-team-assignment/b-service/auth"
+	pb "github.com/Yusufzhafir/worlder-team-assignment/common/protobuf"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/Yusufzhafir/worlder-team-assignment/b-service/ingest")
+
+var ErrPipelineClosed = errors.New("ingest pipeline is closed")
+
+// FlushFunc persists one batch of readings, returning one result per reading in order.
+type FlushFunc func(ctx context.Context, batch []*pb.SensorReading) ([]*pb.ItemResult, error)
+
+// Pipeline queues single readings in memory and writes them in batches.
+// Submit only returns once the batch holding the reading has been flushed.
+type Pipeline interface {
+	Submit(ctx context.Context, reading *pb.SensorReading) (*pb.ItemResult, error)
+	Close(ctx context.Context) error
+	// Depth reports how many readings wait in the queue and how many it holds.
+	Depth() (queued int, capacity int)
+}
+
+type flushOutcome struct {
+	result *pb.ItemResult
+	err    error
+}
+
+type pendingReading struct {
+	ctx     context.Context
+	reading *pb.SensorReading
+	done    chan flushOutcome
+}
+
+type PipelineImpl struct {
+	mu     sync.RWMutex
+	closed bool
+	queue  chan *pendingReading
+	wg     sync.WaitGroup
+
+	batchSize     int
+	flushInterval time.Duration
+	flushTimeout  time.Duration
+	flush         FlushFunc
+	logger        *slog.Logger
+}
+
+type PipelineOpts struct {
+	BatchSize     int           // flush once this many readings are queued
+	FlushInterval time.Duration // flush a partial batch after this long
+	Workers       int           // number of concurrent flushers
+	QueueSize     int           // readings buffered before Submit blocks
+	FlushTimeout  time.Duration // upper bound for a single batch write
+	Flush         FlushFunc
+	Logger        *slog.Logger
+}
+
+func NewPipeline(opts PipelineOpts) Pipeline {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 500
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = 5 * time.Millisecond
+	}
+	if opts.Workers <= 0 {
+		opts.Workers = 1
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = opts.BatchSize * opts.Workers * 2
+	}
+	if opts.FlushTimeout <= 0 {
+		opts.FlushTimeout = 10 * time.Second
+	}
+	if opts.Logger == nil {
+		opts.Logger = slog.Default()
+	}
+
+	p := &PipelineImpl{
+		queue:         make(chan *pendingReading, opts.QueueSize),
+		batchSize:     opts.BatchSize,
+		flushInterval: opts.FlushInterval,
+		flushTimeout:  opts.FlushTimeout,
+		flush:         opts.Flush,
+		logger:        opts.Logger,
+	}
+
+	for i := 0; i < opts.Workers; i++ {
+		p.wg.Add(1)
+		go p.worker()
+	}
+	return p
+}
+
+// Submit's span covers the wait in the queue and the batch write, which is a
+// separate span linked back to every reading it carried.
+func (p *PipelineImpl) Submit(ctx context.Context, reading *pb.SensorReading) (*pb.ItemResult, error) {
+	ctx, span := tracer.Start(ctx, "Pipeline.Submit")
+	result, err := p.submit(ctx, reading)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+	return result, err
+}
+
+func (p *PipelineImpl) submit(ctx context.Context, reading *pb.SensorReading) (*pb.ItemResult, error) {
+	pending := &pendingReading{
+		ctx:     ctx,
+		reading: reading,
+		done:    make(chan flushOutcome, 1),
+	}
+
+	p.mu.RLock()
+	if p.closed {
+		p.mu.RUnlock()
+		return nil, ErrPipelineClosed
+	}
+	select {
+	case p.queue <- pending:
+		p.mu.RUnlock()
+	case <-ctx.Done():
+		p.mu.RUnlock()
+		return nil, ctx.Err()
+	}
+
+	// the reading may still be committed if ctx expires while it is in flight
+	select {
+	case outcome := <-pending.done:
+		return outcome.result, outcome.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (p *PipelineImpl) Depth() (int, int) {
+	return len(p.queue), cap(p.queue)
+}
+
+// Close stops accepting readings and waits until every queued reading is flushed.
+func (p *PipelineImpl) Close(ctx context.Context) error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil
+	}
+	p.closed = true
+	close(p.queue)
+	p.mu.Unlock()
+
+	drained := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (p *PipelineImpl) worker() {
+	defer p.wg.Done()
+
+	batch := make([]*pendingReading, 0, p.batchSize)
+	timer := time.NewTimer(p.flushInterval)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		select {
+		case pending, ok := <-p.queue:
+			if !ok {
+				p.flushBatch(batch)
+				return
+			}
+			if len(batch) == 0 {
+				timer.Reset(p.flushInterval)
+			}
+			batch = append(batch, pending)
+			if len(batch) >= p.batchSize {
+				timer.Stop()
+				p.flushBatch(batch)
+				batch = batch[:0]
+			}
+		case <-timer.C:
+			p.flushBatch(batch)
+			batch = batch[:0]
+		}
+	}
+}
+
+func (p *PipelineImpl) flushBatch(batch []*pendingReading) {
+	if len(batch) == 0 {
+		return
+	}
+
+	// skip callers that already gave up, no one is waiting for their ack
+	live := make([]*pendingReading, 0, len(batch))
+	for _, pending := range batch {
+		if err := pending.ctx.Err(); err != nil {
+			pending.done <- flushOutcome{err: err}
+			continue
+		}
+		live = append(live, pending)
+	}
+
+	// the flush context carries the caller, so readings from different principals are written separately
+	byPrincipal := make(map[string][]*pendingReading)
+	for _, pending := range live {
+		principal, _ := auth.CallerFromContext(pending.ctx)
+		byPrincipal[principal] = append(byPrincipal[principal], pending)
+	}
+	for principal, group := range byPrincipal {
+		p.flushGroup(principal, group)
+	}
+}
+
+func (p *PipelineImpl) flushGroup(principal string, live []*pendingReading) {
+	readings := make([]*pb.SensorReading, len(live))
+	links := make([]trace.Link, 0, len(live))
+	for i, pending := range live {
+		readings[i] = pending.reading
+		links = append(links, trace.LinkFromContext(pending.ctx))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), p.flushTimeout)
+	defer cancel()
+	if principal != "" {
+		ctx = auth.WithPrincipal(ctx, principal)
+	}
+
+	// a batch serves many callers, so it is a trace of its own linked to each of them
+	ctx, span := tracer.Start(ctx, "Pipeline.Flush",
+		trace.WithNewRoot(),
+		trace.WithLinks(links...),
+		trace.WithAttributes(attribute.Int("batch.size", len(live))),
+	)
+	defer span.End()
+	flushSpan := trace.LinkFromContext(ctx)
+	for _, pending := range live {
+		trace.SpanFromContext(pending.ctx).AddEvent("batch flush started", trace.WithAttributes(
+			attribute.String("flush.trace_id", flushSpan.SpanContext.TraceID().String()),
+			attribute.Int("batch.size", len(live)),
+		))
+	}
+
+	results, err := p.flush(ctx, readings)
+	if err == nil && len(results) != len(live) {
+		err = fmt.Errorf("flush returned %d results for %d readings", len(results), len(live))
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		p.logger.ErrorContext(ctx, "failed to flush batch", "readings", len(readings), "error", err)
+		for _, pending := range live {
+			pending.done <- flushOutcome{err: err}
+		}
+		return
+	}
+	for i, pending := range live {
+		pending.done <- flushOutcome{result: results[i]}
+	}
+}
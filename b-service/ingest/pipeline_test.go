@@ -0,0 +1,249 @@
+package ingest
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Yusufzhafir/worlder-team-assignment/b-service/auth"
+	"github.com/Yusufzhafir/worlder-team-assignment/common/logging"
+	pb "github.com/Yusufzhafir/worlder-team-assignment/common/protobuf"
+)
+
+// flushRecorder is a FlushFunc that keeps every batch with the principal it was written under.
+type flushRecorder struct {
+	mu      sync.Mutex
+	batches []recordedBatch
+	err     error
+}
+
+type recordedBatch struct {
+	principal string
+	readings  []*pb.SensorReading
+}
+
+func (f *flushRecorder) flush(ctx context.Context, batch []*pb.SensorReading) ([]*pb.ItemResult, error) {
+	principal, _ := auth.PrincipalFromContext(ctx)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.batches = append(f.batches, recordedBatch{principal: principal, readings: batch})
+	if f.err != nil {
+		return nil, f.err
+	}
+	results := make([]*pb.ItemResult, len(batch))
+	for i, reading := range batch {
+		// the reading's id2 comes back as reading_id so callers can match their ack
+		results[i] = &pb.ItemResult{Index: uint32(i), Status: pb.ItemStatus_ITEM_STATUS_STORED, ReadingId: uint64(reading.GetId2())}
+	}
+	return results, nil
+}
+
+func (f *flushRecorder) sizes() []int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	sizes := make([]int, len(f.batches))
+	for i, batch := range f.batches {
+		sizes[i] = len(batch.readings)
+	}
+	return sizes
+}
+
+func newTestPipeline(f *flushRecorder, opts PipelineOpts) Pipeline {
+	opts.Flush = f.flush
+	opts.Logger = logging.Discard()
+	return NewPipeline(opts)
+}
+
+type submitted struct {
+	result *pb.ItemResult
+	err    error
+}
+
+// submitAll submits one reading per context concurrently, with id2 set to its index,
+// and waits for every ack.
+func submitAll(p Pipeline, ctxs []context.Context) []submitted {
+	out := make([]submitted, len(ctxs))
+	var wg sync.WaitGroup
+	for i, ctx := range ctxs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			out[i].result, out[i].err = p.Submit(ctx, &pb.SensorReading{Id1: "A", Id2: int32(i)})
+		}()
+	}
+	wg.Wait()
+	return out
+}
+
+func contexts(n int) []context.Context {
+	ctxs := make([]context.Context, n)
+	for i := range ctxs {
+		ctxs[i] = context.Background()
+	}
+	return ctxs
+}
+
+func TestFlushTriggers(t *testing.T) {
+	tests := []struct {
+		name        string
+		batchSize   int
+		interval    time.Duration
+		submit      int
+		wantBatches []int
+	}{
+		{name: "full batch flushes at once", batchSize: 3, interval: time.Hour, submit: 3, wantBatches: []int{3}},
+		{name: "partial batch flushes after the interval", batchSize: 100, interval: 20 * time.Millisecond, submit: 2, wantBatches: []int{2}},
+		{name: "remainder flushes after the interval", batchSize: 3, interval: 200 * time.Millisecond, submit: 5, wantBatches: []int{3, 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &flushRecorder{}
+			p := newTestPipeline(f, PipelineOpts{BatchSize: tt.batchSize, FlushInterval: tt.interval})
+			defer p.Close(context.Background())
+
+			for i, s := range submitAll(p, contexts(tt.submit)) {
+				if s.err != nil {
+					t.Fatalf("Submit %d: %v", i, s.err)
+				}
+				if s.result.GetReadingId() != uint64(i) {
+					t.Fatalf("Submit %d got the ack of reading %d", i, s.result.GetReadingId())
+				}
+			}
+			sizes := f.sizes()
+			sort.Sort(sort.Reverse(sort.IntSlice(sizes)))
+			if !equalInts(sizes, tt.wantBatches) {
+				t.Fatalf("flushed batches of %v, want %v", sizes, tt.wantBatches)
+			}
+		})
+	}
+}
+
+func TestFlushGroupsByPrincipal(t *testing.T) {
+	f := &flushRecorder{}
+	p := newTestPipeline(f, PipelineOpts{BatchSize: 4, FlushInterval: time.Hour})
+	defer p.Close(context.Background())
+
+	ctxs := []context.Context{
+		auth.WithPrincipal(context.Background(), "alice"),
+		auth.WithPrincipal(context.Background(), "bob"),
+		auth.WithPrincipal(context.Background(), "alice"),
+		context.Background(),
+	}
+	for i, s := range submitAll(p, ctxs) {
+		if s.err != nil {
+			t.Fatalf("Submit %d: %v", i, s.err)
+		}
+	}
+
+	got := map[string][]int{}
+	for _, batch := range f.batches {
+		if _, ok := got[batch.principal]; ok {
+			t.Fatalf("principal %q was flushed twice", batch.principal)
+		}
+		for _, reading := range batch.readings {
+			got[batch.principal] = append(got[batch.principal], int(reading.GetId2()))
+		}
+		sort.Ints(got[batch.principal])
+	}
+	want := map[string][]int{"alice": {0, 2}, "bob": {1}, "": {3}}
+	if len(got) != len(want) {
+		t.Fatalf("flushed %v, want %v", got, want)
+	}
+	for principal, ids := range want {
+		if !equalInts(got[principal], ids) {
+			t.Fatalf("principal %q flushed readings %v, want %v", principal, got[principal], ids)
+		}
+	}
+}
+
+func TestFlushErrors(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name      string
+		flushErr  error
+		ctxs      []context.Context
+		wantErrs  []error
+		wantFlush []int
+	}{
+		{
+			name:      "flush error fails every caller in the batch",
+			flushErr:  errors.New("database down"),
+			ctxs:      contexts(2),
+			wantErrs:  []error{errors.New("database down"), errors.New("database down")},
+			wantFlush: []int{2},
+		},
+		{
+			name:     "canceled caller is not flushed",
+			ctxs:     []context.Context{canceled},
+			wantErrs: []error{context.Canceled},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &flushRecorder{err: tt.flushErr}
+			p := newTestPipeline(f, PipelineOpts{BatchSize: len(tt.ctxs), FlushInterval: time.Hour})
+			defer p.Close(context.Background())
+
+			for i, s := range submitAll(p, tt.ctxs) {
+				if s.err == nil || s.err.Error() != tt.wantErrs[i].Error() {
+					t.Fatalf("Submit %d = %v, want %v", i, s.err, tt.wantErrs[i])
+				}
+			}
+			if sizes := f.sizes(); !equalInts(sizes, tt.wantFlush) {
+				t.Fatalf("flushed batches of %v, want %v", sizes, tt.wantFlush)
+			}
+		})
+	}
+}
+
+func TestCloseDrainsQueue(t *testing.T) {
+	f := &flushRecorder{}
+	p := newTestPipeline(f, PipelineOpts{BatchSize: 100, FlushInterval: time.Hour})
+
+	done := make(chan []submitted)
+	go func() { done <- submitAll(p, contexts(3)) }()
+
+	// the readings sit in a partial batch that only Close flushes
+	time.Sleep(50 * time.Millisecond)
+	if len(f.sizes()) != 0 {
+		t.Fatal("partial batch flushed before Close")
+	}
+
+	if err := p.Close(context.Background()); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	for i, s := range <-done {
+		if s.err != nil {
+			t.Fatalf("Submit %d: %v", i, s.err)
+		}
+	}
+	if sizes := f.sizes(); !equalInts(sizes, []int{3}) {
+		t.Fatalf("flushed batches of %v, want [3]", sizes)
+	}
+
+	if _, err := p.Submit(context.Background(), &pb.SensorReading{}); !errors.Is(err, ErrPipelineClosed) {
+		t.Fatalf("Submit after Close = %v, want ErrPipelineClosed", err)
+	}
+	if err := p.Close(context.Background()); err != nil {
+		t.Fatalf("second Close: %v", err)
+	}
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
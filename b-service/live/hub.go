@@ -0,0 +1,275 @@
+package live
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	pb "github.com/Yusufzhafir/worlder-team-assignment/common/protobuf"
+)
+
+var (
+	ErrTooManySubscribers = errors.New("too many live subscribers")
+	ErrSlowConsumer       = errors.New("subscriber fell behind and was disconnected")
+	ErrHubClosed          = errors.New("live feed is shutting down")
+)
+
+// SlowConsumerPolicy decides what happens to a subscriber whose buffer is full.
+type SlowConsumerPolicy string
+
+const (
+	// PolicyDrop skips readings the subscriber has no room for and counts them.
+	PolicyDrop SlowConsumerPolicy = "drop"
+	// PolicyDisconnect ends the subscription with ErrSlowConsumer.
+	PolicyDisconnect SlowConsumerPolicy = "disconnect"
+)
+
+func ParseSlowConsumerPolicy(policy string) (SlowConsumerPolicy, error) {
+	switch SlowConsumerPolicy(policy) {
+	case PolicyDrop, PolicyDisconnect:
+		return SlowConsumerPolicy(policy), nil
+	}
+	return "", fmt.Errorf("unknown slow consumer policy %q, expected drop or disconnect", policy)
+}
+
+// Filter selects the readings a subscriber receives. Empty fields match everything.
+type Filter struct {
+	IDs         []ID
+	SensorTypes []string
+	MinValue    *float64 // inclusive
+	MaxValue    *float64 // inclusive
+}
+
+type ID struct {
+	ID1 string
+	ID2 int32
+}
+
+func (f Filter) match(r *pb.SensorReading) bool {
+	if len(f.IDs) > 0 {
+		found := false
+		for _, id := range f.IDs {
+			if id.ID1 == r.GetId1() && id.ID2 == r.GetId2() {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(f.SensorTypes) > 0 {
+		found := false
+		for _, sensorType := range f.SensorTypes {
+			if sensorType == r.GetSensorType() {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if f.MinValue != nil && r.GetValue() < *f.MinValue {
+		return false
+	}
+	if f.MaxValue != nil && r.GetValue() > *f.MaxValue {
+		return false
+	}
+	return true
+}
+
+type Stats struct {
+	Subscribers  int    `json:"subscribers"`
+	Published    uint64 `json:"published"`
+	Delivered    uint64 `json:"delivered"`
+	Dropped      uint64 `json:"dropped"`
+	Disconnected uint64 `json:"disconnected"`
+}
+
+// Subscription receives matching readings on C until Done is closed.
+type Subscription interface {
+	C() <-chan *pb.SensorReading
+	// Done is closed when the hub ends the subscription, Err tells why.
+	Done() <-chan struct{}
+	Err() error
+	// Dropped counts readings skipped under PolicyDrop.
+	Dropped() uint64
+	Close()
+}
+
+// Hub fans stored readings out to live subscribers. Publish never blocks on a
+// subscriber, each has its own buffer and a slow consumer policy.
+type Hub interface {
+	Publish(readings ...*pb.SensorReading)
+	// Subscribe uses the hub's default policy when policy is empty.
+	Subscribe(filter Filter, policy SlowConsumerPolicy) (Subscription, error)
+	Stats() Stats
+	// Close ends every subscription with ErrHubClosed and refuses new ones, so
+	// streaming handlers return before the gRPC server drains.
+	Close()
+}
+
+type subscriber struct {
+	hub     *HubImpl
+	id      uint64
+	filter  Filter
+	policy  SlowConsumerPolicy
+	ch      chan *pb.SensorReading
+	done    chan struct{}
+	once    sync.Once
+	err     error
+	dropped atomic.Uint64
+}
+
+type HubImpl struct {
+	mu             sync.RWMutex
+	closed         bool
+	subscribers    map[uint64]*subscriber
+	nextID         uint64
+	bufferSize     int
+	policy         SlowConsumerPolicy
+	maxSubscribers int
+
+	published    atomic.Uint64
+	delivered    atomic.Uint64
+	dropped      atomic.Uint64
+	disconnected atomic.Uint64
+}
+
+type HubOpts struct {
+	BufferSize     int                // readings buffered per subscriber
+	Policy         SlowConsumerPolicy // default policy for subscribers that name none
+	MaxSubscribers int                // further Subscribe calls fail with ErrTooManySubscribers, 0 is unlimited
+}
+
+func NewHub(opts HubOpts) Hub {
+	if opts.BufferSize <= 0 {
+		opts.BufferSize = 256
+	}
+	if opts.Policy == "" {
+		opts.Policy = PolicyDrop
+	}
+	return &HubImpl{
+		subscribers:    make(map[uint64]*subscriber),
+		bufferSize:     opts.BufferSize,
+		policy:         opts.Policy,
+		maxSubscribers: opts.MaxSubscribers,
+	}
+}
+
+func (h *HubImpl) Publish(readings ...*pb.SensorReading) {
+	h.published.Add(uint64(len(readings)))
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for _, sub := range h.subscribers {
+		for _, r := range readings {
+			if sub.filter.match(r) && !h.deliver(sub, r) {
+				break
+			}
+		}
+	}
+}
+
+// deliver hands r to sub without blocking. It returns false once sub has ended.
+func (h *HubImpl) deliver(sub *subscriber, r *pb.SensorReading) bool {
+	select {
+	case <-sub.done:
+		return false
+	default:
+	}
+
+	select {
+	case sub.ch <- r:
+		h.delivered.Add(1)
+		return true
+	default:
+	}
+
+	if sub.policy == PolicyDisconnect {
+		h.disconnected.Add(1)
+		sub.end(ErrSlowConsumer)
+		return false
+	}
+	sub.dropped.Add(1)
+	h.dropped.Add(1)
+	return true
+}
+
+func (h *HubImpl) Subscribe(filter Filter, policy SlowConsumerPolicy) (Subscription, error) {
+	if policy == "" {
+		policy = h.policy
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return nil, ErrHubClosed
+	}
+	if h.maxSubscribers > 0 && len(h.subscribers) >= h.maxSubscribers {
+		return nil, ErrTooManySubscribers
+	}
+	h.nextID++
+	sub := &subscriber{
+		hub:    h,
+		id:     h.nextID,
+		filter: filter,
+		policy: policy,
+		ch:     make(chan *pb.SensorReading, h.bufferSize),
+		done:   make(chan struct{}),
+	}
+	h.subscribers[sub.id] = sub
+	return sub, nil
+}
+
+func (h *HubImpl) Stats() Stats {
+	h.mu.RLock()
+	subscribers := len(h.subscribers)
+	h.mu.RUnlock()
+	return Stats{
+		Subscribers:  subscribers,
+		Published:    h.published.Load(),
+		Delivered:    h.delivered.Load(),
+		Dropped:      h.dropped.Load(),
+		Disconnected: h.disconnected.Load(),
+	}
+}
+
+func (h *HubImpl) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.closed = true
+	for _, sub := range h.subscribers {
+		sub.end(ErrHubClosed)
+	}
+}
+
+// end closes done once. The data channel stays open, Publish may still hold it.
+func (s *subscriber) end(err error) {
+	s.once.Do(func() {
+		s.err = err
+		close(s.done)
+	})
+}
+
+func (s *subscriber) C() <-chan *pb.SensorReading { return s.ch }
+func (s *subscriber) Done() <-chan struct{}       { return s.done }
+func (s *subscriber) Dropped() uint64             { return s.dropped.Load() }
+
+func (s *subscriber) Err() error {
+	select {
+	case <-s.done:
+		return s.err
+	default:
+		return nil
+	}
+}
+
+func (s *subscriber) Close() {
+	s.end(nil)
+	s.hub.mu.Lock()
+	delete(s.hub.subscribers, s.id)
+	s.hub.mu.Unlock()
+}
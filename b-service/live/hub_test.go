@@ -0,0 +1,195 @@
+package live
+
+import (
+	"errors"
+	"testing"
+
+	pb "github.com/Yusufzhafir/worlder-team-assignment/common/protobuf"
+)
+
+func readings(n int) []*pb.SensorReading {
+	out := make([]*pb.SensorReading, n)
+	for i := range out {
+		out[i] = &pb.SensorReading{Id1: "A", Id2: int32(i), SensorType: "TEMP", Value: float64(i)}
+	}
+	return out
+}
+
+// drain takes whatever is buffered on sub without blocking.
+func drain(sub Subscription) []*pb.SensorReading {
+	var got []*pb.SensorReading
+	for {
+		select {
+		case r := <-sub.C():
+			got = append(got, r)
+		default:
+			return got
+		}
+	}
+}
+
+func isDone(sub Subscription) bool {
+	select {
+	case <-sub.Done():
+		return true
+	default:
+		return false
+	}
+}
+
+func TestSlowConsumerPolicy(t *testing.T) {
+	tests := []struct {
+		name         string
+		hubPolicy    SlowConsumerPolicy
+		subPolicy    SlowConsumerPolicy
+		wantReceived int
+		wantDropped  uint64
+		wantErr      error
+		wantStats    Stats
+	}{
+		{
+			name:         "drop skips what does not fit",
+			hubPolicy:    PolicyDrop,
+			wantReceived: 2,
+			wantDropped:  3,
+			wantStats:    Stats{Subscribers: 2, Published: 5, Delivered: 4, Dropped: 3},
+		},
+		{
+			name:         "disconnect ends the subscription",
+			hubPolicy:    PolicyDisconnect,
+			wantReceived: 2,
+			wantErr:      ErrSlowConsumer,
+			wantStats:    Stats{Subscribers: 2, Published: 5, Delivered: 4, Disconnected: 1},
+		},
+		{
+			name:         "subscriber policy overrides the hub default",
+			hubPolicy:    PolicyDrop,
+			subPolicy:    PolicyDisconnect,
+			wantReceived: 2,
+			wantErr:      ErrSlowConsumer,
+			wantStats:    Stats{Subscribers: 2, Published: 5, Delivered: 4, Disconnected: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hub := NewHub(HubOpts{BufferSize: 2, Policy: tt.hubPolicy})
+			slow, err := hub.Subscribe(Filter{}, tt.subPolicy)
+			if err != nil {
+				t.Fatalf("Subscribe: %v", err)
+			}
+			// a subscriber filtering down to what fits is never affected by the slow one
+			fast, err := hub.Subscribe(Filter{IDs: []ID{{ID1: "A", ID2: 0}, {ID1: "A", ID2: 4}}}, "")
+			if err != nil {
+				t.Fatalf("Subscribe: %v", err)
+			}
+
+			hub.Publish(readings(5)...)
+
+			got := drain(slow)
+			if len(got) != tt.wantReceived {
+				t.Fatalf("slow subscriber received %d readings, want %d", len(got), tt.wantReceived)
+			}
+			for i, r := range got {
+				if r.GetId2() != int32(i) {
+					t.Fatalf("reading %d has id2 %d, the oldest ones should be kept", i, r.GetId2())
+				}
+			}
+			if slow.Dropped() != tt.wantDropped {
+				t.Fatalf("Dropped = %d, want %d", slow.Dropped(), tt.wantDropped)
+			}
+			if isDone(slow) != (tt.wantErr != nil) || !errors.Is(slow.Err(), tt.wantErr) {
+				t.Fatalf("done = %v, Err = %v, want %v", isDone(slow), slow.Err(), tt.wantErr)
+			}
+			if fastGot := drain(fast); len(fastGot) != 2 || isDone(fast) {
+				t.Fatalf("fast subscriber received %d readings, done %v", len(fastGot), isDone(fast))
+			}
+			if stats := hub.Stats(); stats != tt.wantStats {
+				t.Fatalf("stats = %+v, want %+v", stats, tt.wantStats)
+			}
+
+			// once drained, a kept subscription gets the next reading and an ended one does not
+			hub.Publish(readings(1)...)
+			wantNext := 1
+			if tt.wantErr != nil {
+				wantNext = 0
+			}
+			if got := len(drain(slow)); got != wantNext {
+				t.Fatalf("slow subscriber received %d readings after catching up, want %d", got, wantNext)
+			}
+		})
+	}
+}
+
+func TestFilter(t *testing.T) {
+	low, high := 1.0, 3.0
+	tests := []struct {
+		name    string
+		filter  Filter
+		wantIDs []int32
+	}{
+		{name: "empty matches everything", filter: Filter{}, wantIDs: []int32{0, 1, 2, 3, 4}},
+		{name: "ids", filter: Filter{IDs: []ID{{ID1: "A", ID2: 1}, {ID1: "B", ID2: 2}}}, wantIDs: []int32{1}},
+		{name: "sensor types", filter: Filter{SensorTypes: []string{"HUMIDITY"}}},
+		{name: "value range is inclusive", filter: Filter{MinValue: &low, MaxValue: &high}, wantIDs: []int32{1, 2, 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hub := NewHub(HubOpts{})
+			sub, err := hub.Subscribe(tt.filter, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			hub.Publish(readings(5)...)
+			got := drain(sub)
+			if len(got) != len(tt.wantIDs) {
+				t.Fatalf("received %d readings, want %v", len(got), tt.wantIDs)
+			}
+			for i, r := range got {
+				if r.GetId2() != tt.wantIDs[i] {
+					t.Fatalf("reading %d has id2 %d, want %d", i, r.GetId2(), tt.wantIDs[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSubscribeLimitsAndClose(t *testing.T) {
+	hub := NewHub(HubOpts{MaxSubscribers: 1})
+	sub, err := hub.Subscribe(Filter{}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := hub.Subscribe(Filter{}, ""); !errors.Is(err, ErrTooManySubscribers) {
+		t.Fatalf("second Subscribe = %v, want ErrTooManySubscribers", err)
+	}
+
+	// closing a subscription frees its place
+	sub.Close()
+	if !isDone(sub) || sub.Err() != nil {
+		t.Fatalf("closed subscription: done %v, Err %v", isDone(sub), sub.Err())
+	}
+	sub, err = hub.Subscribe(Filter{}, "")
+	if err != nil {
+		t.Fatalf("Subscribe after Close: %v", err)
+	}
+
+	hub.Close()
+	if !errors.Is(sub.Err(), ErrHubClosed) {
+		t.Fatalf("Err after hub Close = %v, want ErrHubClosed", sub.Err())
+	}
+	if _, err := hub.Subscribe(Filter{}, ""); !errors.Is(err, ErrHubClosed) {
+		t.Fatalf("Subscribe after hub Close = %v, want ErrHubClosed", err)
+	}
+}
+
+func TestParseSlowConsumerPolicy(t *testing.T) {
+	for _, policy := range []string{"drop", "disconnect"} {
+		if got, err := ParseSlowConsumerPolicy(policy); err != nil || string(got) != policy {
+			t.Fatalf("ParseSlowConsumerPolicy(%q) = %q, %v", policy, got, err)
+		}
+	}
+	if _, err := ParseSlowConsumerPolicy("block"); err == nil {
+		t.Fatal("ParseSlowConsumerPolicy accepted block")
+	}
+}
@@ -3,30 +3,50 @@ package main
 import (
 	"context"
 	"errors"
-	"fmt"
-	"log"
+	"io/fs"
+	"log/slog"
 	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
-	"time"
 
 	//internal
+	"github.com/Yusufzhafir/worlder-team-assignment/b-service/admission"
+	"github.com/Yusufzhafir/worlder-team-assignment/b-service/auth"
+	"github.com/Yusufzhafir/worlder-team-assignment/b-service/config"
 	_ "github.com/Yusufzhafir/worlder-team-assignment/b-service/docs"
 	grpcServer "github.com/Yusufzhafir/worlder-team-assignment/b-service/grpc"
+	"github.com/Yusufzhafir/worlder-team-assignment/b-service/health"
+	"github.com/Yusufzhafir/worlder-team-assignment/b-service/ingest"
+	"github.com/Yusufzhafir/worlder-team-assignment/b-service/live"
+	"github.com/Yusufzhafir/worlder-team-assignment/b-service/metrics"
 	sensorRepository "github.com/Yusufzhafir/worlder-team-assignment/b-service/repository"
+	"github.com/Yusufzhafir/worlder-team-assignment/b-service/repository/model"
 	httpRouter "github.com/Yusufzhafir/worlder-team-assignment/b-service/router"
+	adminRouter "github.com/Yusufzhafir/worlder-team-assignment/b-service/router/admin"
+	healthRouter "github.com/Yusufzhafir/worlder-team-assignment/b-service/router/health"
 	sensorRouter "github.com/Yusufzhafir/worlder-team-assignment/b-service/router/sensor"
+	"github.com/Yusufzhafir/worlder-team-assignment/b-service/spool"
 	sensorUsecase "github.com/Yusufzhafir/worlder-team-assignment/b-service/usecase"
+	commonConfig "github.com/Yusufzhafir/worlder-team-assignment/common/config"
+	"github.com/Yusufzhafir/worlder-team-assignment/common/logging"
 	pb "github.com/Yusufzhafir/worlder-team-assignment/common/protobuf"
+	"github.com/Yusufzhafir/worlder-team-assignment/common/tlsconfig"
+	"github.com/Yusufzhafir/worlder-team-assignment/common/tracing"
 	"golang.org/x/sync/errgroup"
 
 	//external
-	_ "github.com/go-sql-driver/mysql"
-	"github.com/jmoiron/sqlx"
 	"github.com/joho/godotenv"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	grpcHealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+
+	"go.opentelemetry.io/contrib/instrumentation/github.com/labstack/echo/otelecho"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc/filters"
 
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
@@ -77,72 +97,306 @@ import (
 // @authorizationUrl						https://example.com/oauth/authorize
 // @scope.admin							Grants read and write access to administrative information
 
-var (
-	port = 50051
-)
-
 func main() {
 	// ---- shared ctx (CTRL+C to stop) ----
 	rootCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
-	logger := log.Default()
-	//load environment variable
-	err := godotenv.Load()
+	// a .env file is optional, its variables never override the real environment
+	if err := godotenv.Load(); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		fatal("failed to read .env", "error", err)
+	}
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		os.Exit(runMigrate(rootCtx, os.Args[2:]))
+	}
+	// defaults, then config/config.yaml, then the environment, then flags
+	cfg, cfgFile, err := config.Load(os.Args[1:])
+	if errors.Is(err, commonConfig.ErrPrinted) {
+		return
+	}
 	if err != nil {
-		logger.Fatal("Error loading .env file")
+		fatal("invalid config", "error", err)
 	}
-	dbName := os.Getenv("MYSQL_DATABASE")
-	dbUser := os.Getenv("MYSQL_USER")
-	dbPassword := os.Getenv("MYSQL_PASSWORD")
-
-	grpcLis, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
-
+	// log.level can be changed at runtime through PUT /api/v1/admin/log-level
+	logger, logLevel, err := logging.New(logging.Opts{
+		Service: "b-service",
+		Level:   cfg.Log.Level,
+		Format:  cfg.Log.Format,
+	})
+	if err != nil {
+		fatal("invalid logging config", "error", err)
+	}
+	slog.SetDefault(logger)
+	if cfgFile != "" {
+		logger.Info("read config file", "path", cfgFile)
+	}
+	// spans go nowhere unless tracing.exporter is otlp, stdout or file
+	shutdownTracing, err := tracing.Setup(rootCtx, tracing.Opts{
+		ServiceName: "b-service",
+		Exporter:    cfg.Tracing.Exporter,
+		Endpoint:    cfg.Tracing.OTLPEndpoint,
+		Insecure:    cfg.Tracing.OTLPInsecure,
+		File:        cfg.Tracing.File,
+		SampleRatio: float64(cfg.Tracing.SamplePercent) / 100,
+	})
 	if err != nil {
-		logger.Fatalf("failed to listen: %v", err)
+		fatal("failed to set up tracing", "error", err)
 	}
 
-	var connString string
-	if val := os.Getenv("DB_DSN"); val != "" {
-		connString = val
-	} else {
-		connString = fmt.Sprintf("%s:%s@tcp(localhost:3306)/%s?parseTime=true&loc=Local", dbUser, dbPassword, dbName)
+	grpcLis, err := net.Listen("tcp", cfg.GRPCAddr)
+
+	if err != nil {
+		fatal("failed to listen", "error", err)
 	}
-	db, err := sqlx.Connect("mysql", connString)
 
+	repo, db, err := openStorage(rootCtx, cfg.Database)
 	if err != nil {
-		logger.Fatalf("Failed to connect DB %v", err)
-		return
+		fatal("Failed to connect DB", "backend", cfg.Database.BackendName(), "error", err)
+	}
+	defer repo.Close()
+	// replicas starting together take turns through the migration lock
+	if cfg.Database.MigrateOnStart && db != nil {
+		migrator, err := newMigrator(cfg.Database, db, logger)
+		if err != nil {
+			fatal("failed to load migrations", "error", err)
+		}
+		if _, err := migrator.Up(rootCtx, 0); err != nil {
+			fatal("failed to migrate the schema", "error", err)
+		}
+		if err := createHypertable(rootCtx, cfg.Database, db, logger); err != nil {
+			fatal("failed to create the hypertable", "error", err)
+		}
 	}
 
+	// both were checked by cfg.Validate
+	conflictPolicy, _ := sensorRepository.ParseConflictPolicy(cfg.Dedup.ConflictPolicy)
+	watchPolicy, _ := live.ParseSlowConsumerPolicy(cfg.Watch.SlowConsumer)
+
 	//initiate stuff
-	repoObj := sensorRepository.NewSensorRepository()
-	useCaseObj := sensorUsecase.NewSensorUseCase(
-		db,
+	repoObj := sensorRepository.NewTracedRepository(repo, cfg.Database.BackendName())
+
+	// local write-ahead log for readings that arrive while the database is down, spool.enabled=false fails them instead
+	var useCaseObj sensorUsecase.SensorUseCase
+	var readingSpool spool.Spool
+	if cfg.Spool.Enabled {
+		readingSpool, err = spool.NewSpool(spool.SpoolOpts{
+			Dir:            cfg.Spool.Dir,
+			SegmentBytes:   cfg.Spool.SegmentBytes,
+			MaxBytes:       cfg.Spool.MaxBytes,
+			ReplayInterval: cfg.Spool.ReplayInterval,
+			ReplayBatch:    cfg.Spool.ReplayBatch,
+			// useCaseObj is set below, before the replayer starts
+			Replay: func(ctx context.Context, rows []model.SensorReadingInsert) error {
+				return useCaseObj.ReplaySpooled(ctx, rows)
+			},
+			Ping:        repo.Ping,
+			IsRetryable: sensorRepository.IsUnavailable,
+			Logger:      logger,
+		})
+		if err != nil {
+			fatal("failed to open spool", "error", err)
+		}
+	}
+
+	// live feed of stored readings for WatchReadings subscribers
+	feed := live.NewHub(live.HubOpts{
+		BufferSize:     cfg.Watch.BufferSize,
+		Policy:         watchPolicy,
+		MaxSubscribers: cfg.Watch.MaxSubscribers,
+	})
+
+	// sensor type catalog, normalizing types and checking value ranges on ingest
+	catalog := sensorUsecase.NewSensorTypeCatalog(sensorUsecase.SensorTypeCatalogOpts{
+		Repo:            &repoObj,
+		RefreshInterval: cfg.Validation.CatalogRefresh,
+		Logger:          logger,
+	})
+	catalog.Start(rootCtx)
+
+	useCaseObj = sensorUsecase.NewSensorUseCase(
 		&repoObj,
+		sensorUsecase.DedupConfig{
+			NaturalKey: cfg.Dedup.NaturalKey,
+			Policy:     conflictPolicy,
+		},
+		readingSpool,
+		sensorUsecase.NewReadingValidator(sensorUsecase.ReadingValidatorOpts{
+			ID2Min:        cfg.Validation.ID2Min,
+			ID2Max:        cfg.Validation.ID2Max,
+			SensorTypes:   cfg.Validation.SensorTypes,
+			MaxFutureSkew: cfg.Validation.MaxFutureSkew,
+			MaxAge:        cfg.Validation.MaxAge,
+		}),
+		feed,
+		catalog,
 	)
+	if readingSpool != nil {
+		readingSpool.Start()
+	}
+
+	// micro-batching for unary Readings, ingest.pipeline_enabled=false writes each reading directly
+	var pipeline ingest.Pipeline
+	// recorder is set below, once every queue it reads exists
+	var recorder metrics.Recorder
+	if cfg.Ingest.PipelineEnabled {
+		pipeline = ingest.NewPipeline(ingest.PipelineOpts{
+			BatchSize:     cfg.Ingest.BatchSize,
+			FlushInterval: cfg.Ingest.FlushInterval,
+			Workers:       cfg.Ingest.Workers,
+			QueueSize:     cfg.Ingest.QueueSize,
+			Flush: func(ctx context.Context, batch []*pb.SensorReading) ([]*pb.ItemResult, error) {
+				results, err := useCaseObj.InsertSensorBatch(ctx, batch)
+				if recorder != nil {
+					recorder.ObserveBatch(metrics.SourcePipeline, len(batch), results)
+				}
+				return results, err
+			},
+			Logger: logger,
+		})
+	}
+
+	// bounded in-flight calls per ingest method, the overflow gets codes.ResourceExhausted
+	admissionCtl := admission.NewController(admission.ControllerOpts{
+		Limits: map[string]admission.Limits{
+			pb.IngestService_Readings_FullMethodName:       admission.Limits(cfg.Admission.Readings),
+			pb.IngestService_ReadingsBatch_FullMethodName:  admission.Limits(cfg.Admission.ReadingsBatch),
+			pb.IngestService_StreamReadings_FullMethodName: admission.Limits(cfg.Admission.StreamReadings),
+			pb.IngestService_IngestStream_FullMethodName:   admission.Limits(cfg.Admission.IngestStream),
+		},
+	})
+
+	// one log line per call with its request ID, outermost so refused calls are logged too
+	unaryInterceptors := []grpc.UnaryServerInterceptor{logging.UnaryServerInterceptor(logger, cfg.Log.SampleEvery)}
+	streamInterceptors := []grpc.StreamServerInterceptor{logging.StreamServerInterceptor(logger)}
+	serverOpts := []grpc.ServerOption{
+		// picks up the trace context a-service sends in the call metadata
+		grpc.StatsHandler(otelgrpc.NewServerHandler(otelgrpc.WithFilter(filters.Not(filters.HealthCheck())))),
+	}
+
+	// Prometheus /metrics, ahead of auth and admission so refused calls are counted too
+	if cfg.Metrics.Enabled {
+		recorder = metrics.NewRecorder(metrics.RecorderOpts{
+			DB:        sqlHandle(db),
+			Pipeline:  pipeline,
+			Spool:     readingSpool,
+			Admission: admissionCtl,
+			Feed:      feed,
+		})
+		unaryInterceptors = append(unaryInterceptors, recorder.UnaryInterceptor())
+		streamInterceptors = append(streamInterceptors, recorder.StreamInterceptor())
+	}
+
+	// TLS on the gRPC listener, with client certificates verified when tls.client_ca_file is set
+	if cfg.TLS.CertFile != "" {
+		clientCAFile := cfg.TLS.ClientCAFile
+		tlsConfig, err := tlsconfig.NewServerTLS(cfg.TLS.CertFile, cfg.TLS.KeyFile, clientCAFile)
+		if err != nil {
+			fatal("failed to load TLS config", "error", err)
+		}
+		serverOpts = append(serverOpts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+		if clientCAFile != "" {
+			unaryInterceptors = append(unaryInterceptors, auth.ClientCertUnaryInterceptor())
+			streamInterceptors = append(streamInterceptors, auth.ClientCertStreamInterceptor())
+			logger.Info("gRPC serves mutual TLS, client certificates are required")
+		} else {
+			logger.Info("gRPC serves TLS")
+		}
+	}
+
+	// bearer tokens on every gRPC call, checked before a call takes an admission slot
+	if len(cfg.Auth.Tokens) > 0 || cfg.Auth.JWTSecret != "" {
+		authenticator, err := auth.NewAuthenticator(auth.AuthenticatorOpts{
+			Tokens:      cfg.Auth.Tokens,
+			JWTSecret:   []byte(cfg.Auth.JWTSecret),
+			Leeway:      cfg.Auth.JWTLeeway,
+			OptionalExp: !cfg.Auth.RequireExp,
+			// probes and tooling connect without credentials
+			Exempt: []string{
+				healthpb.Health_ServiceDesc.ServiceName,
+				"grpc.reflection.v1.ServerReflection",
+				"grpc.reflection.v1alpha.ServerReflection",
+			},
+		})
+		if err != nil {
+			fatal("invalid AUTH_TOKENS", "error", err)
+		}
+		unaryInterceptors = append(unaryInterceptors, authenticator.UnaryInterceptor())
+		streamInterceptors = append(streamInterceptors, authenticator.StreamInterceptor())
+	} else {
+		logger.Warn("AUTH_TOKENS and JWT_SECRET are empty, gRPC calls are not authenticated")
+	}
+	unaryInterceptors = append(unaryInterceptors, admissionCtl.UnaryInterceptor())
+	streamInterceptors = append(streamInterceptors, admissionCtl.StreamInterceptor())
 
 	//grpc server
-	grpcSrv := grpc.NewServer()
+	serverOpts = append(serverOpts,
+		grpc.ChainUnaryInterceptor(unaryInterceptors...),
+		grpc.ChainStreamInterceptor(streamInterceptors...),
+	)
+	grpcSrv := grpc.NewServer(serverOpts...)
 	myServer := grpcServer.NewServerGRPC(grpcServer.ServerGRPCOpts{
 		SensorUseCase: &useCaseObj,
-		Logger:        logger,
+		Pipeline:      pipeline,
+		Metrics:       recorder,
 	})
 	pb.RegisterIngestServiceServer(grpcSrv, &myServer)
+	queryServer := grpcServer.NewQueryServerGRPC(grpcServer.QueryServerGRPCOpts{
+		SensorUseCase: &useCaseObj,
+		Feed:          feed,
+	})
+	pb.RegisterSensorQueryServiceServer(grpcSrv, &queryServer)
+
+	// grpc.health.v1 mirrors /readyz, reflection lets grpcurl discover the services
+	healthSrv := grpcHealth.NewServer()
+	healthpb.RegisterHealthServer(grpcSrv, healthSrv)
+	reflection.Register(grpcSrv)
+	healthChecker := health.NewChecker(health.CheckerOpts{
+		Ping:          repo.Ping,
+		Pipeline:      pipeline,
+		Spool:         readingSpool,
+		GRPC:          healthSrv,
+		Services:      []string{pb.IngestService_ServiceDesc.ServiceName, pb.SensorQueryService_ServiceDesc.ServiceName},
+		MaxQueueRatio: float64(cfg.Health.QueueSaturationPercent) / 100,
+		Interval:      cfg.Health.CheckInterval,
+		Logger:        logger,
+	})
+	healthChecker.Start(rootCtx)
 
 	//http server
 	e := echo.New()
 
 	// Middleware
-	e.Use(middleware.Logger())
+	e.Use(otelecho.Middleware("b-service", otelecho.WithSkipper(func(c echo.Context) bool {
+		switch c.Path() {
+		case "/metrics", "/healthz", "/readyz":
+			return true
+		}
+		return false
+	})))
+	e.Use(logging.EchoMiddleware(logger, "/metrics", "/healthz", "/readyz"))
 	e.Use(middleware.Recover())
+	var metricsHandler http.Handler
+	if recorder != nil {
+		e.Use(recorder.Middleware())
+		metricsHandler = recorder.Handler()
+	}
 	e.GET("/swagger/*", echoSwagger.WrapHandler)
 
 	//http routers
-	sensorRouter := sensorRouter.NewSensorRouter(&useCaseObj)
+	sensorRouter := sensorRouter.NewSensorRouter(&useCaseObj, feed, cfg.WebSocket.AllowedOrigins)
+	healthRouter := healthRouter.NewHealthRouter(healthChecker)
+	adminRouter := adminRouter.NewAdminRouter(adminRouter.AdminRouterOpts{
+		Spool:         readingSpool,
+		Admission:     admissionCtl,
+		LogLevel:      logLevel,
+		SensorUsecase: &useCaseObj,
+	})
 	httpRouter.BindRouter(httpRouter.BindRouterOpts{
 		E:            e,
 		SensorRouter: &sensorRouter,
+		AdminRouter:  &adminRouter,
+		HealthRouter: &healthRouter,
+		Metrics:      metricsHandler,
 	})
 
 	// ---- run both ----
@@ -150,28 +404,53 @@ func main() {
 
 	// gRPC server
 	g.Go(func() error {
-		log.Printf("gRPC server listening at %s", grpcLis.Addr())
+		logger.Info("gRPC server listening", "addr", grpcLis.Addr().String())
 		// stop gRPC when context is canceled
+		stopped := make(chan struct{})
 		go func() {
+			defer close(stopped)
 			<-ctx.Done()
-			log.Println("stopping gRPC...")
+			healthChecker.Shutdown()
+			// WatchReadings streams never end on their own
+			feed.Close()
+			logger.Info("stopping gRPC")
 			grpcSrv.GracefulStop()
+			if pipeline != nil {
+				// in-flight Readings calls are done, flush whatever is still queued
+				logger.Info("draining ingest pipeline")
+				shCtx, cancel := context.WithTimeout(context.Background(), cfg.Ingest.DrainTimeout)
+				defer cancel()
+				if err := pipeline.Close(shCtx); err != nil {
+					logger.Error("failed to drain ingest pipeline", "error", err)
+				}
+			}
+			if readingSpool != nil {
+				// whatever is still spooled is replayed on the next start
+				if err := readingSpool.Close(); err != nil {
+					logger.Error("failed to close spool", "error", err)
+				}
+			}
 		}()
-		return grpcSrv.Serve(grpcLis)
+		if err := grpcSrv.Serve(grpcLis); err != nil {
+			return err
+		}
+		// Serve returns nil once GracefulStop is called, wait for the drain to finish
+		<-stopped
+		return nil
 	})
 
 	// REST server
 	g.Go(func() error {
-		log.Printf("REST server listening at localhost:8080")
+		logger.Info("REST server listening", "addr", cfg.HTTPAddr)
 		// graceful shutdown
 		go func() {
 			<-ctx.Done()
-			log.Println("stopping REST...")
-			shCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			logger.Info("stopping REST")
+			shCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
 			defer cancel()
 			_ = e.Shutdown(shCtx)
 		}()
-		err := e.Start(":8080")
+		err := e.Start(cfg.HTTPAddr)
 		if err != nil && !errors.Is(err, http.ErrServerClosed) {
 			return err
 		}
@@ -180,7 +459,18 @@ func main() {
 
 	// wait until one server errors or context canceled
 	if err := g.Wait(); err != nil && !errors.Is(err, context.Canceled) {
-		log.Fatalf("server error: %v", err)
+		fatal("server error", "error", err)
+	}
+	shCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+	if err := shutdownTracing(shCtx); err != nil {
+		logger.Error("failed to flush traces", "error", err)
 	}
-	log.Println("servers stopped cleanly")
+	logger.Info("servers stopped cleanly")
+}
+
+// fatal logs msg at error level and exits, slog has no Fatal.
+func fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
 }
@@ -0,0 +1,191 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Yusufzhafir/worlder-team-assignment/b-service/admission"
+	"github.com/Yusufzhafir/worlder-team-assignment/b-service/ingest"
+	"github.com/Yusufzhafir/worlder-team-assignment/b-service/live"
+	"github.com/Yusufzhafir/worlder-team-assignment/b-service/spool"
+	pb "github.com/Yusufzhafir/worlder-team-assignment/common/protobuf"
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// Batch sources passed to ObserveBatch.
+const (
+	SourcePipeline       = "pipeline"
+	SourceReadings       = "readings"
+	SourceReadingsBatch  = "readings_batch"
+	SourceStreamReadings = "stream_readings"
+	SourceIngestStream   = "ingest_stream"
+)
+
+// Recorder collects gRPC, REST and ingest metrics and serves them in the
+// Prometheus text format, together with gauges read from the queues on scrape.
+type Recorder interface {
+	UnaryInterceptor() grpc.UnaryServerInterceptor
+	StreamInterceptor() grpc.StreamServerInterceptor
+	// Middleware times REST requests per route template, not per raw path.
+	Middleware() echo.MiddlewareFunc
+	// ObserveBatch records one write of size readings and the status of each
+	// result. results is nil when the whole batch failed.
+	ObserveBatch(source string, size int, results []*pb.ItemResult)
+	Handler() http.Handler
+}
+
+type RecorderImpl struct {
+	registry    *prometheus.Registry
+	grpcHandled *prometheus.CounterVec
+	grpcLatency *prometheus.HistogramVec
+	httpLatency *prometheus.HistogramVec
+	batchSize   *prometheus.HistogramVec
+	batchFailed *prometheus.CounterVec
+	readings    *prometheus.CounterVec
+}
+
+type RecorderOpts struct {
+	DB        *sql.DB              // optional, exports the connection pool stats
+	Pipeline  ingest.Pipeline      // optional, nil when micro-batching is disabled
+	Spool     spool.Spool          // optional, nil when spooling is disabled
+	Admission admission.Controller // optional
+	Feed      live.Hub             // optional
+	Buckets   []float64            // latency buckets in seconds, defaults to 0.5ms..16s
+}
+
+func NewRecorder(opts RecorderOpts) Recorder {
+	if len(opts.Buckets) == 0 {
+		opts.Buckets = prometheus.ExponentialBuckets(0.0005, 2, 16)
+	}
+
+	r := &RecorderImpl{
+		registry: prometheus.NewRegistry(),
+		grpcHandled: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "grpc_server_handled_total",
+			Help: "gRPC calls completed, by method and status code.",
+		}, []string{"method", "code"}),
+		grpcLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "grpc_server_handling_seconds",
+			Help:    "Time spent handling gRPC calls, by method and status code. Streams are timed until they end.",
+			Buckets: opts.Buckets,
+		}, []string{"method", "code"}),
+		httpLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "Time spent handling REST requests, by method, route and status code.",
+			Buckets: opts.Buckets,
+		}, []string{"method", "route", "code"}),
+		batchSize: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "ingest_batch_size",
+			Help:    "Readings written per database batch, by ingest path.",
+			Buckets: prometheus.ExponentialBuckets(1, 4, 8),
+		}, []string{"source"}),
+		batchFailed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "ingest_batch_failures_total",
+			Help: "Batches that could neither be stored nor spooled, by ingest path.",
+		}, []string{"source"}),
+		readings: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "ingest_readings_total",
+			Help: "Readings written, by ingest path and item status.",
+		}, []string{"source", "status"}),
+	}
+
+	r.registry.MustRegister(
+		r.grpcHandled,
+		r.grpcLatency,
+		r.httpLatency,
+		r.batchSize,
+		r.batchFailed,
+		r.readings,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		&queueCollector{
+			pipeline:  opts.Pipeline,
+			spool:     opts.Spool,
+			admission: opts.Admission,
+			feed:      opts.Feed,
+		},
+	)
+	if opts.DB != nil {
+		r.registry.MustRegister(collectors.NewDBStatsCollector(opts.DB, "sensor"))
+	}
+	return r
+}
+
+func (r *RecorderImpl) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		r.observeCall(info.FullMethod, start, err)
+		return resp, err
+	}
+}
+
+func (r *RecorderImpl) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		r.observeCall(info.FullMethod, start, err)
+		return err
+	}
+}
+
+func (r *RecorderImpl) observeCall(method string, start time.Time, err error) {
+	code := status.Code(err).String()
+	r.grpcHandled.WithLabelValues(method, code).Inc()
+	r.grpcLatency.WithLabelValues(method, code).Observe(time.Since(start).Seconds())
+}
+
+func (r *RecorderImpl) Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+			err := next(c)
+
+			// the error handler has not written the response yet
+			code := c.Response().Status
+			if err != nil {
+				code = http.StatusInternalServerError
+				var httpErr *echo.HTTPError
+				if errors.As(err, &httpErr) {
+					code = httpErr.Code
+				}
+			}
+			route := c.Path()
+			if route == "" {
+				route = "unmatched"
+			}
+			r.httpLatency.WithLabelValues(c.Request().Method, route, strconv.Itoa(code)).Observe(time.Since(start).Seconds())
+			return err
+		}
+	}
+}
+
+func (r *RecorderImpl) ObserveBatch(source string, size int, results []*pb.ItemResult) {
+	r.batchSize.WithLabelValues(source).Observe(float64(size))
+	if results == nil {
+		r.batchFailed.WithLabelValues(source).Inc()
+		return
+	}
+	for _, result := range results {
+		r.readings.WithLabelValues(source, statusLabel(result.GetStatus())).Inc()
+	}
+}
+
+// statusLabel turns ITEM_STATUS_DUPLICATE_REJECTED into duplicate_rejected.
+func statusLabel(s pb.ItemStatus) string {
+	return strings.ToLower(strings.TrimPrefix(s.String(), "ITEM_STATUS_"))
+}
+
+func (r *RecorderImpl) Handler() http.Handler {
+	return promhttp.HandlerFor(r.registry, promhttp.HandlerOpts{Registry: r.registry})
+}
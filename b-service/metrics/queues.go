@@ -0,0 +1,98 @@
+package metrics
+
+import (
+	"github.com/Yusufzhafir/worlder-team-assignment/b-service/admission"
+	"github.com/Yusufzhafir/worlder-team-assignment/b-service/ingest"
+	"github.com/Yusufzhafir/worlder-team-assignment/b-service/live"
+	"github.com/Yusufzhafir/worlder-team-assignment/b-service/spool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	pipelineQueuedDesc   = prometheus.NewDesc("ingest_pipeline_queued", "Readings waiting in the micro-batching queue.", nil, nil)
+	pipelineCapacityDesc = prometheus.NewDesc("ingest_pipeline_capacity", "Readings the micro-batching queue holds.", nil, nil)
+
+	spoolActiveDesc    = prometheus.NewDesc("spool_active", "1 while readings are written to the spool instead of the database.", nil, nil)
+	spoolSegmentsDesc  = prometheus.NewDesc("spool_segments", "Spool segment files on disk.", nil, nil)
+	spoolBytesDesc     = prometheus.NewDesc("spool_bytes", "Bytes held by the spool.", nil, nil)
+	spoolMaxBytesDesc  = prometheus.NewDesc("spool_max_bytes", "Bytes the spool may hold before refusing readings.", nil, nil)
+	spoolPendingDesc   = prometheus.NewDesc("spool_pending_records", "Spooled readings not replayed yet.", nil, nil)
+	spoolReplayedDesc  = prometheus.NewDesc("spool_replayed_total", "Spooled readings replayed into the database.", nil, nil)
+	spoolDroppedDesc   = prometheus.NewDesc("spool_dropped_total", "Spooled readings the database refused on replay.", nil, nil)
+	spoolCorruptedDesc = prometheus.NewDesc("spool_corrupted_total", "Spool records skipped because they could not be decoded.", nil, nil)
+
+	admissionInFlightDesc = prometheus.NewDesc("admission_in_flight", "Calls holding an admission slot.", []string{"method"}, nil)
+	admissionQueuedDesc   = prometheus.NewDesc("admission_queued", "Calls waiting for an admission slot.", []string{"method"}, nil)
+	admissionMaxDesc      = prometheus.NewDesc("admission_max_in_flight", "Admission slots per method.", []string{"method"}, nil)
+	admissionAdmittedDesc = prometheus.NewDesc("admission_admitted_total", "Calls given an admission slot.", []string{"method"}, nil)
+	admissionRejectedDesc = prometheus.NewDesc("admission_rejected_total", "Calls refused with ResourceExhausted.", []string{"method"}, nil)
+
+	liveSubscribersDesc  = prometheus.NewDesc("live_subscribers", "Open WatchReadings, SSE and WebSocket subscriptions.", nil, nil)
+	livePublishedDesc    = prometheus.NewDesc("live_published_total", "Readings published to the live feed.", nil, nil)
+	liveDeliveredDesc    = prometheus.NewDesc("live_delivered_total", "Readings handed to subscribers.", nil, nil)
+	liveDroppedDesc      = prometheus.NewDesc("live_dropped_total", "Readings skipped for slow subscribers.", nil, nil)
+	liveDisconnectedDesc = prometheus.NewDesc("live_disconnected_total", "Subscribers ended for falling behind.", nil, nil)
+)
+
+// queueCollector reads the queue gauges when scraped instead of tracking them on the hot path.
+type queueCollector struct {
+	pipeline  ingest.Pipeline
+	spool     spool.Spool
+	admission admission.Controller
+	feed      live.Hub
+}
+
+func (q *queueCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range []*prometheus.Desc{
+		pipelineQueuedDesc, pipelineCapacityDesc,
+		spoolActiveDesc, spoolSegmentsDesc, spoolBytesDesc, spoolMaxBytesDesc,
+		spoolPendingDesc, spoolReplayedDesc, spoolDroppedDesc, spoolCorruptedDesc,
+		admissionInFlightDesc, admissionQueuedDesc, admissionMaxDesc, admissionAdmittedDesc, admissionRejectedDesc,
+		liveSubscribersDesc, livePublishedDesc, liveDeliveredDesc, liveDroppedDesc, liveDisconnectedDesc,
+	} {
+		ch <- desc
+	}
+}
+
+func (q *queueCollector) Collect(ch chan<- prometheus.Metric) {
+	if q.pipeline != nil {
+		queued, capacity := q.pipeline.Depth()
+		ch <- prometheus.MustNewConstMetric(pipelineQueuedDesc, prometheus.GaugeValue, float64(queued))
+		ch <- prometheus.MustNewConstMetric(pipelineCapacityDesc, prometheus.GaugeValue, float64(capacity))
+	}
+
+	if q.spool != nil {
+		stats := q.spool.Stats()
+		active := 0.0
+		if stats.Active {
+			active = 1
+		}
+		ch <- prometheus.MustNewConstMetric(spoolActiveDesc, prometheus.GaugeValue, active)
+		ch <- prometheus.MustNewConstMetric(spoolSegmentsDesc, prometheus.GaugeValue, float64(stats.Segments))
+		ch <- prometheus.MustNewConstMetric(spoolBytesDesc, prometheus.GaugeValue, float64(stats.Bytes))
+		ch <- prometheus.MustNewConstMetric(spoolMaxBytesDesc, prometheus.GaugeValue, float64(stats.MaxBytes))
+		ch <- prometheus.MustNewConstMetric(spoolPendingDesc, prometheus.GaugeValue, float64(stats.PendingRecords))
+		ch <- prometheus.MustNewConstMetric(spoolReplayedDesc, prometheus.CounterValue, float64(stats.Replayed))
+		ch <- prometheus.MustNewConstMetric(spoolDroppedDesc, prometheus.CounterValue, float64(stats.Dropped))
+		ch <- prometheus.MustNewConstMetric(spoolCorruptedDesc, prometheus.CounterValue, float64(stats.Corrupted))
+	}
+
+	if q.admission != nil {
+		for method, stats := range q.admission.Stats() {
+			ch <- prometheus.MustNewConstMetric(admissionInFlightDesc, prometheus.GaugeValue, float64(stats.InFlight), method)
+			ch <- prometheus.MustNewConstMetric(admissionQueuedDesc, prometheus.GaugeValue, float64(stats.Queued), method)
+			ch <- prometheus.MustNewConstMetric(admissionMaxDesc, prometheus.GaugeValue, float64(stats.MaxInFlight), method)
+			ch <- prometheus.MustNewConstMetric(admissionAdmittedDesc, prometheus.CounterValue, float64(stats.Admitted), method)
+			ch <- prometheus.MustNewConstMetric(admissionRejectedDesc, prometheus.CounterValue, float64(stats.Rejected), method)
+		}
+	}
+
+	if q.feed != nil {
+		stats := q.feed.Stats()
+		ch <- prometheus.MustNewConstMetric(liveSubscribersDesc, prometheus.GaugeValue, float64(stats.Subscribers))
+		ch <- prometheus.MustNewConstMetric(livePublishedDesc, prometheus.CounterValue, float64(stats.Published))
+		ch <- prometheus.MustNewConstMetric(liveDeliveredDesc, prometheus.CounterValue, float64(stats.Delivered))
+		ch <- prometheus.MustNewConstMetric(liveDroppedDesc, prometheus.CounterValue, float64(stats.Dropped))
+		ch <- prometheus.MustNewConstMetric(liveDisconnectedDesc, prometheus.CounterValue, float64(stats.Disconnected))
+	}
+}
@@ -0,0 +1,467 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+//go:embed mysql/*.sql postgres/*.sql sqlite/*.sql
+var files embed.FS
+
+// Each dialect's migrations are numbered on their own.
+var (
+	MySQL, _    = fs.Sub(files, "mysql")
+	Postgres, _ = fs.Sub(files, "postgres")
+	SQLite, _   = fs.Sub(files, "sqlite")
+)
+
+var (
+	ErrDirty       = errors.New("a migration failed halfway")
+	ErrLockTimeout = errors.New("timed out waiting for the migration lock")
+)
+
+// lockName is the advisory lock held while migrating, replicas starting
+// together wait for each other instead of racing on the same DDL.
+const lockName = "b-service.schema_migrations"
+
+// Dialect is what the migrator needs to know about one kind of database.
+type Dialect struct {
+	Name        string
+	Source      fs.FS // the dialect's migrations
+	createTable string
+	now         string // current time with sub-second precision
+	bindType    int    // sqlx bind type the migrator's ? placeholders are rebound to
+	// lock serializes migrators on different connections, the returned func releases it
+	lock func(ctx context.Context, conn *sql.Conn, timeout time.Duration) (func(), error)
+}
+
+var MySQLDialect = Dialect{
+	Name:   "mysql",
+	Source: MySQL,
+	createTable: `
+CREATE TABLE IF NOT EXISTS schema_migrations (
+  version     BIGINT NOT NULL,
+  name        VARCHAR(255) NOT NULL,
+  dirty       BOOLEAN NOT NULL DEFAULT FALSE,
+  applied_at  TIMESTAMP(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+  PRIMARY KEY (version)
+) ENGINE=InnoDB
+`,
+	now:  "CURRENT_TIMESTAMP(6)",
+	lock: mysqlLock,
+}
+
+var PostgresDialect = Dialect{
+	Name:   "postgres",
+	Source: Postgres,
+	createTable: `
+CREATE TABLE IF NOT EXISTS schema_migrations (
+  version     BIGINT NOT NULL PRIMARY KEY,
+  name        VARCHAR(255) NOT NULL,
+  dirty       BOOLEAN NOT NULL DEFAULT FALSE,
+  applied_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+)
+`,
+	now:      "now()",
+	bindType: sqlx.DOLLAR,
+	lock:     postgresLock,
+}
+
+// SQLiteDialect takes no lock, SQLite already lets one writer at a time at the
+// file and busy_timeout makes the others wait.
+var SQLiteDialect = Dialect{
+	Name:   "sqlite",
+	Source: SQLite,
+	createTable: `
+CREATE TABLE IF NOT EXISTS schema_migrations (
+  version     INTEGER NOT NULL PRIMARY KEY,
+  name        TEXT NOT NULL,
+  dirty       BOOLEAN NOT NULL DEFAULT FALSE,
+  applied_at  TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+)
+`,
+	now:  "strftime('%Y-%m-%d %H:%M:%f', 'now')",
+	lock: func(context.Context, *sql.Conn, time.Duration) (func(), error) { return func() {}, nil },
+}
+
+// postgresLock polls pg_try_advisory_lock, pg_advisory_lock would wait past the timeout.
+func postgresLock(ctx context.Context, conn *sql.Conn, timeout time.Duration) (func(), error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		var got bool
+		if err := conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock(hashtext($1))`, lockName).Scan(&got); err != nil {
+			return nil, fmt.Errorf("failed to take the migration lock: %w", err)
+		}
+		if got {
+			return func() {
+				_, _ = conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock(hashtext($1))`, lockName)
+			}, nil
+		}
+		if time.Now().After(deadline) {
+			return nil, ErrLockTimeout
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(500 * time.Millisecond):
+		}
+	}
+}
+
+func mysqlLock(ctx context.Context, conn *sql.Conn, timeout time.Duration) (func(), error) {
+	var got sql.NullInt64
+	if err := conn.QueryRowContext(ctx, `SELECT GET_LOCK(?, ?)`, lockName, int(timeout.Seconds())).Scan(&got); err != nil {
+		return nil, fmt.Errorf("failed to take the migration lock: %w", err)
+	}
+	if !got.Valid || got.Int64 != 1 {
+		return nil, ErrLockTimeout
+	}
+	return func() {
+		// the lock goes with the session anyway, ctx may be canceled by now
+		_, _ = conn.ExecContext(context.Background(), `SELECT RELEASE_LOCK(?)`, lockName)
+	}, nil
+}
+
+// Migration is one numbered schema change, read from a pair of files named
+// NNNN_name.up.sql and NNNN_name.down.sql.
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status is a known migration and whether the database has it.
+type Status struct {
+	Migration
+	Applied   bool
+	Dirty     bool
+	AppliedAt time.Time
+}
+
+// Migrator applies and reverts migrations, recording them in schema_migrations.
+// Statements run one by one outside a transaction, as MySQL commits DDL as it
+// goes anyway, so a migration that fails halfway stays marked dirty and blocks
+// further runs until someone repairs the schema by hand and deletes its row.
+type Migrator interface {
+	// Up applies up to steps pending migrations in version order, all of them when steps is 0.
+	Up(ctx context.Context, steps int) ([]Migration, error)
+	// Down reverts the last steps applied migrations, newest first.
+	Down(ctx context.Context, steps int) ([]Migration, error)
+	Status(ctx context.Context) ([]Status, error)
+}
+
+type MigratorOpts struct {
+	DB          *sql.DB
+	Dialect     Dialect       // MySQLDialect when left zero
+	Source      fs.FS         // migration files, the dialect's when nil
+	LockTimeout time.Duration // how long to wait for another replica's migrations, 1m when 0
+	Logger      *slog.Logger  // slog.Default() when nil
+}
+
+type MigratorImpl struct {
+	db          *sql.DB
+	dialect     Dialect
+	migrations  []Migration
+	lockTimeout time.Duration
+	logger      *slog.Logger
+}
+
+func NewMigrator(opts MigratorOpts) (Migrator, error) {
+	if opts.Dialect.Name == "" {
+		opts.Dialect = MySQLDialect
+	}
+	if opts.Source == nil {
+		opts.Source = opts.Dialect.Source
+	}
+	if opts.LockTimeout <= 0 {
+		opts.LockTimeout = time.Minute
+	}
+	if opts.Logger == nil {
+		opts.Logger = slog.Default()
+	}
+	migrations, err := Load(opts.Source)
+	if err != nil {
+		return nil, err
+	}
+	return &MigratorImpl{
+		db:          opts.DB,
+		dialect:     opts.Dialect,
+		migrations:  migrations,
+		lockTimeout: opts.LockTimeout,
+		logger:      opts.Logger,
+	}, nil
+}
+
+// Load reads the migrations in source, sorted by version. Every version needs
+// both an up and a down file.
+func Load(source fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(source, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list migrations: %w", err)
+	}
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || path.Ext(name) != ".sql" {
+			continue
+		}
+		base := strings.TrimSuffix(name, ".sql")
+		direction := path.Ext(base)
+		base = strings.TrimSuffix(base, direction)
+		number, label, ok := strings.Cut(base, "_")
+		version, err := strconv.ParseInt(number, 10, 64)
+		if !ok || err != nil || version <= 0 || label == "" || (direction != ".up" && direction != ".down") {
+			return nil, fmt.Errorf("migration file %s is not named NNNN_name.up.sql or NNNN_name.down.sql", name)
+		}
+
+		body, err := fs.ReadFile(source, name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", name, err)
+		}
+		m := byVersion[version]
+		if m == nil {
+			m = &Migration{Version: version, Name: label}
+			byVersion[version] = m
+		}
+		if m.Name != label {
+			return nil, fmt.Errorf("migration %d is named both %s and %s", version, m.Name, label)
+		}
+		if direction == ".up" {
+			m.Up = string(body)
+		} else {
+			m.Down = string(body)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s needs both an up and a down file", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// Statements splits a migration into the statements it runs one by one, so
+// the DSN needs no multiStatements. A statement ends with a semicolon at the
+// end of a line, lines starting with -- are comments.
+func Statements(script string) []string {
+	var statements []string
+	var current strings.Builder
+	for _, line := range strings.Split(script, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		current.WriteString(line)
+		current.WriteString("\n")
+		if strings.HasSuffix(trimmed, ";") {
+			statements = append(statements, strings.TrimSuffix(strings.TrimSpace(current.String()), ";"))
+			current.Reset()
+		}
+	}
+	if rest := strings.TrimSpace(current.String()); rest != "" {
+		statements = append(statements, rest)
+	}
+	return statements
+}
+
+type appliedRow struct {
+	dirty     bool
+	appliedAt time.Time
+}
+
+// pending picks the migrations Up would apply, in version order.
+func pending(migrations []Migration, applied map[int64]appliedRow, steps int) []Migration {
+	var out []Migration
+	for _, m := range migrations {
+		if _, ok := applied[m.Version]; ok {
+			continue
+		}
+		if steps > 0 && len(out) == steps {
+			break
+		}
+		out = append(out, m)
+	}
+	return out
+}
+
+// revertible picks the migrations Down would revert, newest first. Applied
+// versions this binary does not know are an error, it has no down file for them.
+func revertible(migrations []Migration, applied map[int64]appliedRow, steps int) ([]Migration, error) {
+	known := make(map[int64]Migration, len(migrations))
+	for _, m := range migrations {
+		known[m.Version] = m
+	}
+	versions := make([]int64, 0, len(applied))
+	for version := range applied {
+		versions = append(versions, version)
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i] > versions[j] })
+
+	var out []Migration
+	for _, version := range versions {
+		if steps > 0 && len(out) == steps {
+			break
+		}
+		m, ok := known[version]
+		if !ok {
+			return nil, fmt.Errorf("applied migration %d is unknown to this build, revert it with the build that added it", version)
+		}
+		out = append(out, m)
+	}
+	return out, nil
+}
+
+func (m *MigratorImpl) bind(query string) string {
+	return sqlx.Rebind(m.dialect.bindType, query)
+}
+
+func (m *MigratorImpl) Up(ctx context.Context, steps int) ([]Migration, error) {
+	var done []Migration
+	err := m.locked(ctx, func(conn *sql.Conn, applied map[int64]appliedRow) error {
+		for _, migration := range pending(m.migrations, applied, steps) {
+			start := time.Now()
+			if _, err := conn.ExecContext(ctx, m.bind(`INSERT INTO schema_migrations (version, name, dirty) VALUES (?, ?, TRUE)`), migration.Version, migration.Name); err != nil {
+				return fmt.Errorf("failed to record migration %d: %w", migration.Version, err)
+			}
+			if err := run(ctx, conn, migration.Up); err != nil {
+				return fmt.Errorf("migration %d_%s failed, schema_migrations marks it dirty: %w", migration.Version, migration.Name, err)
+			}
+			if _, err := conn.ExecContext(ctx, m.bind(`UPDATE schema_migrations SET dirty = FALSE, applied_at = `+m.dialect.now+` WHERE version = ?`), migration.Version); err != nil {
+				return fmt.Errorf("failed to record migration %d: %w", migration.Version, err)
+			}
+			m.logger.InfoContext(ctx, "applied migration", "version", migration.Version, "name", migration.Name, "took", time.Since(start))
+			done = append(done, migration)
+		}
+		return nil
+	})
+	return done, err
+}
+
+func (m *MigratorImpl) Down(ctx context.Context, steps int) ([]Migration, error) {
+	var done []Migration
+	err := m.locked(ctx, func(conn *sql.Conn, applied map[int64]appliedRow) error {
+		toRevert, err := revertible(m.migrations, applied, steps)
+		if err != nil {
+			return err
+		}
+		for _, migration := range toRevert {
+			start := time.Now()
+			if _, err := conn.ExecContext(ctx, m.bind(`UPDATE schema_migrations SET dirty = TRUE WHERE version = ?`), migration.Version); err != nil {
+				return fmt.Errorf("failed to record migration %d: %w", migration.Version, err)
+			}
+			if err := run(ctx, conn, migration.Down); err != nil {
+				return fmt.Errorf("reverting migration %d_%s failed, schema_migrations marks it dirty: %w", migration.Version, migration.Name, err)
+			}
+			if _, err := conn.ExecContext(ctx, m.bind(`DELETE FROM schema_migrations WHERE version = ?`), migration.Version); err != nil {
+				return fmt.Errorf("failed to record migration %d: %w", migration.Version, err)
+			}
+			m.logger.InfoContext(ctx, "reverted migration", "version", migration.Version, "name", migration.Name, "took", time.Since(start))
+			done = append(done, migration)
+		}
+		return nil
+	})
+	return done, err
+}
+
+func (m *MigratorImpl) Status(ctx context.Context) ([]Status, error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	if _, err := conn.ExecContext(ctx, m.dialect.createTable); err != nil {
+		return nil, fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+	applied, err := readApplied(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		row, ok := applied[migration.Version]
+		statuses = append(statuses, Status{Migration: migration, Applied: ok, Dirty: row.dirty, AppliedAt: row.appliedAt})
+		delete(applied, migration.Version)
+	}
+	// versions applied by a newer build
+	for version, row := range applied {
+		statuses = append(statuses, Status{Migration: Migration{Version: version}, Applied: true, Dirty: row.dirty, AppliedAt: row.appliedAt})
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Version < statuses[j].Version })
+	return statuses, nil
+}
+
+// locked runs fn on one connection holding the dialect's lock, with
+// schema_migrations created and no dirty migration in it.
+func (m *MigratorImpl) locked(ctx context.Context, fn func(conn *sql.Conn, applied map[int64]appliedRow) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	release, err := m.dialect.lock(ctx, conn, m.lockTimeout)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	if _, err := conn.ExecContext(ctx, m.dialect.createTable); err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+	applied, err := readApplied(ctx, conn)
+	if err != nil {
+		return err
+	}
+	for version, row := range applied {
+		if row.dirty {
+			return fmt.Errorf("%w: repair migration %d by hand, then delete its schema_migrations row", ErrDirty, version)
+		}
+	}
+	return fn(conn, applied)
+}
+
+func readApplied(ctx context.Context, conn *sql.Conn) (map[int64]appliedRow, error) {
+	rows, err := conn.QueryContext(ctx, `SELECT version, dirty, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int64]appliedRow)
+	for rows.Next() {
+		var version int64
+		var row appliedRow
+		if err := rows.Scan(&version, &row.dirty, &row.appliedAt); err != nil {
+			return nil, err
+		}
+		applied[version] = row
+	}
+	return applied, rows.Err()
+}
+
+func run(ctx context.Context, conn *sql.Conn, script string) error {
+	for _, statement := range Statements(script) {
+		if _, err := conn.ExecContext(ctx, statement); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestLoadEmbedded(t *testing.T) {
+	for _, dialect := range []Dialect{MySQLDialect, PostgresDialect, SQLiteDialect} {
+		t.Run(dialect.Name, func(t *testing.T) {
+			migrations, err := Load(dialect.Source)
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+			if len(migrations) == 0 {
+				t.Fatal("no migrations")
+			}
+			for i, m := range migrations {
+				if m.Version != int64(i+1) {
+					t.Fatalf("migration %d has version %d, versions should count up from 1", i, m.Version)
+				}
+				if len(Statements(m.Up)) == 0 || len(Statements(m.Down)) == 0 {
+					t.Fatalf("migration %d_%s has an empty script", m.Version, m.Name)
+				}
+			}
+		})
+	}
+}
+
+func TestMigratorSQLite(t *testing.T) {
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "migrate.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	ctx := context.Background()
+	migrator, err := NewMigrator(MigratorOpts{DB: db, Dialect: SQLiteDialect, Logger: slog.New(slog.NewTextHandler(io.Discard, nil))})
+	if err != nil {
+		t.Fatalf("NewMigrator: %v", err)
+	}
+
+	done, err := migrator.Up(ctx, 0)
+	if err != nil || len(done) == 0 {
+		t.Fatalf("Up = %d migrations, %v", len(done), err)
+	}
+	if _, err := db.ExecContext(ctx, `INSERT INTO sensor_readings (sensor_value, sensor_type, id1, id2, ts) VALUES (1, 'TEMP', 'A', 1, ?)`, time.Now().UTC()); err != nil {
+		t.Fatalf("insert after Up: %v", err)
+	}
+	if _, err := db.ExecContext(ctx, `INSERT INTO sensor_readings (sensor_value, sensor_type, id1, id2, ts) VALUES (1, 'TEMP', 'lower', 1, ?)`, time.Now().UTC()); err == nil {
+		t.Fatal("id1 check accepted lowercase")
+	}
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	for _, s := range statuses {
+		if !s.Applied || s.Dirty || s.AppliedAt.IsZero() {
+			t.Fatalf("status after Up = %+v", s)
+		}
+	}
+
+	if done, err := migrator.Down(ctx, 0); err != nil || len(done) != len(statuses) {
+		t.Fatalf("Down = %d migrations, %v", len(done), err)
+	}
+	if _, err := db.ExecContext(ctx, `SELECT 1 FROM sensor_readings`); err == nil {
+		t.Fatal("sensor_readings survived Down")
+	}
+}
+
+func TestLoad(t *testing.T) {
+	file := func(body string) *fstest.MapFile { return &fstest.MapFile{Data: []byte(body)} }
+	tests := []struct {
+		name    string
+		files   fstest.MapFS
+		want    []int64
+		wantErr string
+	}{
+		{name: "sorted by version", files: fstest.MapFS{
+			"0010_b.up.sql": file("B"), "0010_b.down.sql": file("b"),
+			"0002_a.up.sql": file("A"), "0002_a.down.sql": file("a"),
+			"README.md": file("ignored"),
+		}, want: []int64{2, 10}},
+		{name: "missing down", files: fstest.MapFS{"0001_a.up.sql": file("A")}, wantErr: "both an up and a down"},
+		{name: "bad name", files: fstest.MapFS{"first.up.sql": file("A")}, wantErr: "is not named"},
+		{name: "bad direction", files: fstest.MapFS{"0001_a.sideways.sql": file("A")}, wantErr: "is not named"},
+		{name: "two names", files: fstest.MapFS{"0001_a.up.sql": file("A"), "0001_b.down.sql": file("b")}, wantErr: "named both"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			migrations, err := Load(tt.files)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Load = %v, want an error containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+			var versions []int64
+			for _, m := range migrations {
+				versions = append(versions, m.Version)
+			}
+			if !reflect.DeepEqual(versions, tt.want) {
+				t.Fatalf("versions = %v, want %v", versions, tt.want)
+			}
+		})
+	}
+}
+
+func TestStatements(t *testing.T) {
+	script := `-- comment
+CREATE TABLE t (
+  a INT -- trailing comments stay with their line
+);
+
+SET @x := 'a;b';
+PREPARE s FROM @x;
+DO 0`
+	want := []string{
+		"CREATE TABLE t (\n  a INT -- trailing comments stay with their line\n)",
+		"SET @x := 'a;b'",
+		"PREPARE s FROM @x",
+		"DO 0",
+	}
+	if got := Statements(script); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Statements = %q, want %q", got, want)
+	}
+}
+
+func TestPlan(t *testing.T) {
+	migrations := []Migration{{Version: 1}, {Version: 2}, {Version: 3}, {Version: 4}}
+	applied := map[int64]appliedRow{1: {}, 3: {}}
+
+	versions := func(ms []Migration) []int64 {
+		var out []int64
+		for _, m := range ms {
+			out = append(out, m.Version)
+		}
+		return out
+	}
+	if got := versions(pending(migrations, applied, 0)); !reflect.DeepEqual(got, []int64{2, 4}) {
+		t.Fatalf("pending = %v, want [2 4]", got)
+	}
+	if got := versions(pending(migrations, applied, 1)); !reflect.DeepEqual(got, []int64{2}) {
+		t.Fatalf("pending 1 = %v, want [2]", got)
+	}
+
+	down, err := revertible(migrations, applied, 1)
+	if err != nil || !reflect.DeepEqual(versions(down), []int64{3}) {
+		t.Fatalf("revertible 1 = %v %v, want [3]", versions(down), err)
+	}
+	down, err = revertible(migrations, applied, 0)
+	if err != nil || !reflect.DeepEqual(versions(down), []int64{3, 1}) {
+		t.Fatalf("revertible = %v %v, want [3 1]", versions(down), err)
+	}
+	if _, err := revertible(migrations, map[int64]appliedRow{9: {}}, 1); err == nil {
+		t.Fatal("revertible accepted a version it has no down file for")
+	}
+}
@@ -0,0 +1,17 @@
+-- The table as it was first deployed. IF NOT EXISTS adopts databases created
+-- by db-service/schema.sql before migrations existed.
+CREATE TABLE IF NOT EXISTS sensor_readings (
+  reading_id    BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  sensor_value  DOUBLE NOT NULL,
+  sensor_type   VARCHAR(32) NOT NULL,
+  id1           CHAR(8) NOT NULL,
+  id2           INT NOT NULL,
+  ts            TIMESTAMP(6) NOT NULL,
+  created_at    TIMESTAMP(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+
+  PRIMARY KEY (reading_id),
+  KEY idx_ids_ts (id1, id2, ts),
+  KEY idx_ts (ts),
+  KEY idx_type_ts (sensor_type, ts),
+  CONSTRAINT chk_id1_uppercase CHECK (id1 REGEXP '^[A-Z0-9]{1,8}$')
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
@@ -0,0 +1 @@
+ALTER TABLE sensor_readings DROP INDEX uk_reading_key, DROP COLUMN reading_key;
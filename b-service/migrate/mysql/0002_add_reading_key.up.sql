@@ -0,0 +1,10 @@
+-- idempotency key, client supplied or "id1:id2:timestamp_ms"; NULL keys never conflict.
+-- MySQL has no ADD COLUMN IF NOT EXISTS, tables from schema.sql already have it.
+SET @ddl := IF(
+  (SELECT COUNT(*) FROM information_schema.columns
+   WHERE table_schema = DATABASE() AND table_name = 'sensor_readings' AND column_name = 'reading_key') = 0,
+  'ALTER TABLE sensor_readings ADD COLUMN reading_key VARCHAR(64) NULL, ADD UNIQUE KEY uk_reading_key (reading_key)',
+  'DO 0');
+PREPARE stmt FROM @ddl;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
@@ -0,0 +1,9 @@
+-- authenticated gRPC caller that ingested the reading
+SET @ddl := IF(
+  (SELECT COUNT(*) FROM information_schema.columns
+   WHERE table_schema = DATABASE() AND table_name = 'sensor_readings' AND column_name = 'principal') = 0,
+  'ALTER TABLE sensor_readings ADD COLUMN principal VARCHAR(64) NULL',
+  'DO 0');
+PREPARE stmt FROM @ddl;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
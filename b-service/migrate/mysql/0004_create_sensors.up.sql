@@ -0,0 +1,18 @@
+-- Registry of known sensors, one row per id1/id2 whether or not it has readings.
+-- labels is a JSON object of strings, expected_rate is readings per second with 0 for unknown.
+CREATE TABLE IF NOT EXISTS sensors (
+  id1            CHAR(8) NOT NULL,
+  id2            INT NOT NULL,
+  name           VARCHAR(128) NOT NULL DEFAULT '',
+  unit           VARCHAR(32) NOT NULL DEFAULT '',
+  location       VARCHAR(128) NOT NULL DEFAULT '',
+  owner          VARCHAR(64) NOT NULL DEFAULT '',
+  expected_rate  DOUBLE NOT NULL DEFAULT 0,
+  enabled        BOOLEAN NOT NULL DEFAULT TRUE,
+  labels         JSON NOT NULL,
+  created_at     TIMESTAMP(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+  updated_at     TIMESTAMP(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+
+  PRIMARY KEY (id1, id2),
+  CONSTRAINT chk_sensors_id1_uppercase CHECK (id1 REGEXP '^[A-Z0-9]{1,8}$')
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
@@ -0,0 +1,24 @@
+-- Catalog of sensor types. Every name and alias of a type is a row of
+-- sensor_type_aliases, lowercased, so no two types can claim the same one.
+-- min_value and max_value bound the plausible values, NULL leaves a side open.
+CREATE TABLE IF NOT EXISTS sensor_types (
+  name          VARCHAR(32) NOT NULL,
+  unit          VARCHAR(32) NOT NULL DEFAULT '',
+  min_value     DOUBLE NULL,
+  max_value     DOUBLE NULL,
+  range_policy  VARCHAR(8) NOT NULL DEFAULT 'flag',
+  created_at    TIMESTAMP(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+  updated_at    TIMESTAMP(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+
+  PRIMARY KEY (name),
+  CONSTRAINT chk_range_policy CHECK (range_policy IN ('flag', 'reject'))
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+CREATE TABLE IF NOT EXISTS sensor_type_aliases (
+  alias  VARCHAR(32) NOT NULL,
+  name   VARCHAR(32) NOT NULL,
+
+  PRIMARY KEY (alias),
+  KEY idx_name (name),
+  CONSTRAINT fk_sensor_type_aliases_name FOREIGN KEY (name) REFERENCES sensor_types (name) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
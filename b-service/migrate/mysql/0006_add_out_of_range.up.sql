@@ -0,0 +1,2 @@
+-- set on readings stored outside the plausible range of their sensor type
+ALTER TABLE sensor_readings ADD COLUMN out_of_range BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,21 @@
+-- The whole MySQL schema up to its 0003 in one step, Postgres databases start here.
+-- reading_id is BY DEFAULT so batch inserts can COPY ids taken from its sequence.
+-- uk_reading_key is named because upserts refer to it, CreateHypertable widens it to (reading_key, ts).
+CREATE TABLE IF NOT EXISTS sensor_readings (
+  reading_id    BIGINT GENERATED BY DEFAULT AS IDENTITY,
+  sensor_value  DOUBLE PRECISION NOT NULL,
+  sensor_type   VARCHAR(32) NOT NULL,
+  id1           VARCHAR(8) NOT NULL,
+  id2           INTEGER NOT NULL,
+  ts            TIMESTAMPTZ NOT NULL,
+  created_at    TIMESTAMPTZ NOT NULL DEFAULT now(),
+  reading_key   VARCHAR(64) NULL,
+  principal     VARCHAR(64) NULL,
+
+  CONSTRAINT sensor_readings_pkey PRIMARY KEY (reading_id),
+  CONSTRAINT uk_reading_key UNIQUE (reading_key),
+  CONSTRAINT chk_id1_uppercase CHECK (id1 ~ '^[A-Z0-9]{1,8}$')
+);
+CREATE INDEX IF NOT EXISTS idx_ids_ts ON sensor_readings (id1, id2, ts);
+CREATE INDEX IF NOT EXISTS idx_ts ON sensor_readings (ts);
+CREATE INDEX IF NOT EXISTS idx_type_ts ON sensor_readings (sensor_type, ts);
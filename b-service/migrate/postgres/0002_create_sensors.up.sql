@@ -0,0 +1,18 @@
+-- Registry of known sensors, one row per id1/id2 whether or not it has readings.
+-- labels is a JSON object of strings, expected_rate is readings per second with 0 for unknown.
+CREATE TABLE IF NOT EXISTS sensors (
+  id1            VARCHAR(8) NOT NULL,
+  id2            INTEGER NOT NULL,
+  name           VARCHAR(128) NOT NULL DEFAULT '',
+  unit           VARCHAR(32) NOT NULL DEFAULT '',
+  location       VARCHAR(128) NOT NULL DEFAULT '',
+  owner          VARCHAR(64) NOT NULL DEFAULT '',
+  expected_rate  DOUBLE PRECISION NOT NULL DEFAULT 0,
+  enabled        BOOLEAN NOT NULL DEFAULT TRUE,
+  labels         JSONB NOT NULL DEFAULT '{}',
+  created_at     TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at     TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+  CONSTRAINT sensors_pkey PRIMARY KEY (id1, id2),
+  CONSTRAINT chk_sensors_id1_uppercase CHECK (id1 ~ '^[A-Z0-9]{1,8}$')
+);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS sensor_type_aliases;
+DROP TABLE IF EXISTS sensor_types;
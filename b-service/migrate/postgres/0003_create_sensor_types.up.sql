@@ -0,0 +1,23 @@
+-- Catalog of sensor types. Every name and alias of a type is a row of
+-- sensor_type_aliases, lowercased, so no two types can claim the same one.
+-- min_value and max_value bound the plausible values, NULL leaves a side open.
+CREATE TABLE IF NOT EXISTS sensor_types (
+  name          VARCHAR(32) NOT NULL,
+  unit          VARCHAR(32) NOT NULL DEFAULT '',
+  min_value     DOUBLE PRECISION NULL,
+  max_value     DOUBLE PRECISION NULL,
+  range_policy  VARCHAR(8) NOT NULL DEFAULT 'flag',
+  created_at    TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at    TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+  CONSTRAINT sensor_types_pkey PRIMARY KEY (name),
+  CONSTRAINT chk_range_policy CHECK (range_policy IN ('flag', 'reject'))
+);
+
+CREATE TABLE IF NOT EXISTS sensor_type_aliases (
+  alias  VARCHAR(32) NOT NULL,
+  name   VARCHAR(32) NOT NULL REFERENCES sensor_types (name) ON DELETE CASCADE,
+
+  CONSTRAINT sensor_type_aliases_pkey PRIMARY KEY (alias)
+);
+CREATE INDEX IF NOT EXISTS idx_sensor_type_aliases_name ON sensor_type_aliases (name);
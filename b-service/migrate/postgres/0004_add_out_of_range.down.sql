@@ -0,0 +1 @@
+ALTER TABLE sensor_readings DROP COLUMN IF EXISTS out_of_range;
@@ -0,0 +1,17 @@
+-- The whole MySQL schema up to its 0003 in one step, SQLite databases start here.
+-- Timestamps are stored as UTC text, which sorts like the time it holds.
+CREATE TABLE IF NOT EXISTS sensor_readings (
+  reading_id    INTEGER PRIMARY KEY AUTOINCREMENT,
+  sensor_value  REAL NOT NULL,
+  sensor_type   TEXT NOT NULL,
+  id1           TEXT NOT NULL CHECK (length(id1) BETWEEN 1 AND 8 AND id1 NOT GLOB '*[^A-Z0-9]*'),
+  id2           INTEGER NOT NULL,
+  ts            TIMESTAMP NOT NULL,
+  created_at    TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  reading_key   TEXT NULL,
+  principal     TEXT NULL
+);
+CREATE UNIQUE INDEX IF NOT EXISTS uk_reading_key ON sensor_readings (reading_key);
+CREATE INDEX IF NOT EXISTS idx_ids_ts ON sensor_readings (id1, id2, ts);
+CREATE INDEX IF NOT EXISTS idx_ts ON sensor_readings (ts);
+CREATE INDEX IF NOT EXISTS idx_type_ts ON sensor_readings (sensor_type, ts);
@@ -0,0 +1,17 @@
+-- Registry of known sensors, one row per id1/id2 whether or not it has readings.
+-- labels is a JSON object of strings, expected_rate is readings per second with 0 for unknown.
+CREATE TABLE IF NOT EXISTS sensors (
+  id1            TEXT NOT NULL CHECK (length(id1) BETWEEN 1 AND 8 AND id1 NOT GLOB '*[^A-Z0-9]*'),
+  id2            INTEGER NOT NULL,
+  name           TEXT NOT NULL DEFAULT '',
+  unit           TEXT NOT NULL DEFAULT '',
+  location       TEXT NOT NULL DEFAULT '',
+  owner          TEXT NOT NULL DEFAULT '',
+  expected_rate  REAL NOT NULL DEFAULT 0,
+  enabled        BOOLEAN NOT NULL DEFAULT TRUE,
+  labels         TEXT NOT NULL DEFAULT '{}',
+  created_at     TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at     TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+  PRIMARY KEY (id1, id2)
+);
@@ -0,0 +1,18 @@
+-- Catalog of sensor types. Every name and alias of a type is a row of
+-- sensor_type_aliases, lowercased, so no two types can claim the same one.
+-- min_value and max_value bound the plausible values, NULL leaves a side open.
+CREATE TABLE IF NOT EXISTS sensor_types (
+  name          TEXT NOT NULL PRIMARY KEY,
+  unit          TEXT NOT NULL DEFAULT '',
+  min_value     REAL NULL,
+  max_value     REAL NULL,
+  range_policy  TEXT NOT NULL DEFAULT 'flag' CHECK (range_policy IN ('flag', 'reject')),
+  created_at    TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at    TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS sensor_type_aliases (
+  alias  TEXT NOT NULL PRIMARY KEY,
+  name   TEXT NOT NULL REFERENCES sensor_types (name) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_sensor_type_aliases_name ON sensor_type_aliases (name);
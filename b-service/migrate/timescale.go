@@ -0,0 +1,65 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// TimescaleDB wants every unique constraint of a hypertable to include the
+// partitioning column, so reading_key only conflicts with a key at the same ts
+// afterwards. Natural keys carry the timestamp anyway.
+var widenConstraintsDDL = []string{
+	`ALTER TABLE sensor_readings DROP CONSTRAINT sensor_readings_pkey, ADD CONSTRAINT sensor_readings_pkey PRIMARY KEY (reading_id, ts)`,
+	`ALTER TABLE sensor_readings DROP CONSTRAINT uk_reading_key, ADD CONSTRAINT uk_reading_key UNIQUE (reading_key, ts)`,
+}
+
+// idx_ts from 0001 already covers what create_hypertable's default index would.
+const createHypertableSQL = `
+SELECT create_hypertable('sensor_readings', 'ts',
+  chunk_time_interval => make_interval(secs => $1),
+  migrate_data => true,
+  create_default_indexes => false)
+`
+
+// CreateHypertable turns the Postgres sensor_readings into a TimescaleDB
+// hypertable on ts, with chunks of chunkInterval. It reports false without
+// changing anything when the server has no timescaledb extension to install,
+// and true when the table is a hypertable already. Postgres runs DDL in
+// transactions, so a failure leaves the table as it was.
+func CreateHypertable(ctx context.Context, db *sql.DB, chunkInterval time.Duration) (bool, error) {
+	var available bool
+	if err := db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM pg_available_extensions WHERE name = 'timescaledb')`).Scan(&available); err != nil {
+		return false, fmt.Errorf("failed to look for timescaledb: %w", err)
+	}
+	if !available {
+		return false, nil
+	}
+	if _, err := db.ExecContext(ctx, `CREATE EXTENSION IF NOT EXISTS timescaledb`); err != nil {
+		return false, fmt.Errorf("failed to create the timescaledb extension: %w", err)
+	}
+
+	var exists bool
+	if err := db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM timescaledb_information.hypertables WHERE hypertable_name = 'sensor_readings')`).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to list hypertables: %w", err)
+	}
+	if exists {
+		return true, nil
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = tx.Rollback() }()
+	for _, statement := range widenConstraintsDDL {
+		if _, err := tx.ExecContext(ctx, statement); err != nil {
+			return false, fmt.Errorf("failed to prepare sensor_readings for a hypertable: %w", err)
+		}
+	}
+	if _, err := tx.ExecContext(ctx, createHypertableSQL, chunkInterval.Seconds()); err != nil {
+		return false, fmt.Errorf("failed to create the sensor_readings hypertable: %w", err)
+	}
+	return true, tx.Commit()
+}
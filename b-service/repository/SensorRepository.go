@@ -3,13 +3,17 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
+	"errors"
 	"fmt"
+	"net"
 	"strings"
 	"time"
 
 	model "github.com/Yusufzhafir/worlder-team-assignment/b-service/repository/model"
 
 	"github.com/jmoiron/sqlx"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type IDCombination struct {
@@ -17,113 +21,127 @@ type IDCombination struct {
 	ID2 int    `db:"id2"`
 }
 
+// ConflictPolicy decides what happens when a reading_key is already stored.
+type ConflictPolicy string
+
+const (
+	ConflictKeepFirst ConflictPolicy = "keep_first" // keep the stored row, drop the new value
+	ConflictKeepLast  ConflictPolicy = "keep_last"  // overwrite the stored row with the new value
+	ConflictReject    ConflictPolicy = "reject"     // keep the stored row and refuse the new value
+)
+
+func ParseConflictPolicy(policy string) (ConflictPolicy, error) {
+	switch ConflictPolicy(policy) {
+	case ConflictKeepFirst, ConflictKeepLast, ConflictReject:
+		return ConflictPolicy(policy), nil
+	}
+	return "", fmt.Errorf("unknown conflict policy %q, expected keep_first, keep_last or reject", policy)
+}
+
+// SensorRepository stores sensor_readings, the sensors registry and the sensor
+// type catalog. Each backend owns its connection, callers never see it.
 type SensorRepository interface {
-	InsertReadingTx(ctx context.Context, db *sqlx.DB, r *model.SensorReadingInsert) (uint64, error)
-	InsertReadingsBatchTx(ctx context.Context, db *sqlx.DB, rs []model.SensorReadingInsert) (int64, error)
+	InsertReadingTx(ctx context.Context, r *model.SensorReadingInsert, policy ConflictPolicy) (model.InsertOutcome, error)
+	InsertReadingsBatchTx(ctx context.Context, rs []model.SensorReadingInsert, policy ConflictPolicy) ([]model.InsertOutcome, error)
 
 	// Select by time (already implemented)
-	SelectByTime(ctx context.Context, db *sqlx.DB, startTime, stopTime time.Time, limit int, offset int) ([]model.SensorReading, error)
-	SelectCountByTime(ctx context.Context, db *sqlx.DB, startTime, stopTime time.Time) (int64, error)
+	SelectByTime(ctx context.Context, startTime, stopTime time.Time, limit int, offset int) ([]model.SensorReading, error)
+	SelectCountByTime(ctx context.Context, startTime, stopTime time.Time) (int64, error)
 
 	// Select by ID combinations
-	SelectByIDs(ctx context.Context, db *sqlx.DB, ids []IDCombination, limit int, offset int) ([]model.SensorReading, error)
-	SelectCountByIDs(ctx context.Context, db *sqlx.DB, ids []IDCombination) (int64, error)
+	SelectByIDs(ctx context.Context, ids []IDCombination, limit int, offset int) ([]model.SensorReading, error)
+	SelectCountByIDs(ctx context.Context, ids []IDCombination) (int64, error)
 
 	// Select by ID combinations and time
-	SelectByIDsAndTime(ctx context.Context, db *sqlx.DB, ids []IDCombination, startTime, stopTime time.Time, limit int, offset int) ([]model.SensorReading, error)
-	SelectCountByIDsAndTime(ctx context.Context, db *sqlx.DB, ids []IDCombination, startTime, stopTime time.Time) (int64, error)
+	SelectByIDsAndTime(ctx context.Context, ids []IDCombination, startTime, stopTime time.Time, limit int, offset int) ([]model.SensorReading, error)
+	SelectCountByIDsAndTime(ctx context.Context, ids []IDCombination, startTime, stopTime time.Time) (int64, error)
 
 	// Delete operations
-	DeleteByTime(ctx context.Context, db *sqlx.DB, startTime, stopTime time.Time) (int64, error)
-	DeleteByIDs(ctx context.Context, db *sqlx.DB, ids []IDCombination) (int64, error)
-	DeleteByIDsAndTime(ctx context.Context, db *sqlx.DB, ids []IDCombination, startTime, stopTime time.Time) (int64, error)
+	DeleteByTime(ctx context.Context, startTime, stopTime time.Time) (int64, error)
+	DeleteByIDs(ctx context.Context, ids []IDCombination) (int64, error)
+	DeleteByIDsAndTime(ctx context.Context, ids []IDCombination, startTime, stopTime time.Time) (int64, error)
 
 	// Update operations
-	UpdateByTime(ctx context.Context, db *sqlx.DB, startTime, stopTime time.Time, sensorValue float64, sensorType string) (int64, error)
-	UpdateByIDs(ctx context.Context, db *sqlx.DB, ids []IDCombination, sensorValue float64, sensorType string) (int64, error)
-	UpdateByIDsAndTime(ctx context.Context, db *sqlx.DB, ids []IDCombination, startTime, stopTime time.Time, sensorValue float64, sensorType string) (int64, error)
+	UpdateByTime(ctx context.Context, startTime, stopTime time.Time, sensorValue float64, sensorType string) (int64, error)
+	UpdateByIDs(ctx context.Context, ids []IDCombination, sensorValue float64, sensorType string) (int64, error)
+	UpdateByIDsAndTime(ctx context.Context, ids []IDCombination, startTime, stopTime time.Time, sensorValue float64, sensorType string) (int64, error)
 
 	//default pagination
-	SelectSensorDataPaginated(ctx context.Context, db *sqlx.DB, limit, offset int) ([]model.SensorReading, error)
-	SelectCountPagination(ctx context.Context, db *sqlx.DB) (int64, error)
+	SelectSensorDataPaginated(ctx context.Context, limit, offset int) ([]model.SensorReading, error)
+	SelectCountPagination(ctx context.Context) (int64, error)
+
+	// Downsampling
+	SelectAggregate(ctx context.Context, q AggregateQuery) ([]model.AggregateBucket, error)
+
+	// Per sensor, a sensor being a distinct (id1, id2)
+	SelectSensorSummaries(ctx context.Context, since time.Time, limit, offset int) ([]model.SensorSummary, error)
+	SelectCountSensors(ctx context.Context) (int64, error)
+	SelectLatestReading(ctx context.Context, id IDCombination) (model.SensorReading, error)
+
+	// Sensor registry, the sensors table keyed by (id1, id2)
+	InsertSensor(ctx context.Context, s *model.Sensor) error
+	SelectSensor(ctx context.Context, id IDCombination) (model.Sensor, error)
+	SelectSensorsByIDs(ctx context.Context, ids []IDCombination) ([]model.Sensor, error)
+	SelectRegisteredSensors(ctx context.Context, limit, offset int) ([]model.Sensor, error)
+	SelectCountRegisteredSensors(ctx context.Context) (int64, error)
+	UpdateSensor(ctx context.Context, s *model.Sensor) error
+	DeleteSensor(ctx context.Context, id IDCombination) error
+
+	// Sensor type catalog, the sensor_types table keyed by name and its aliases
+	SelectSensorTypes(ctx context.Context) ([]model.SensorType, error)
+	InsertSensorType(ctx context.Context, t *model.SensorType) error
+	UpdateSensorType(ctx context.Context, t *model.SensorType) error
+	DeleteSensorType(ctx context.Context, name string) error
+
+	// Ping checks the backend answers, Close releases it.
+	Ping(ctx context.Context) error
+	Close() error
 }
 
-type SensorRepositoryImpl struct {
-}
+// Backend names, the value of database.backend.
+const (
+	BackendMySQL    = "mysql"
+	BackendPostgres = "postgres"
+	BackendSQLite   = "sqlite"
+	BackendMemory   = "memory"
+)
 
-func NewSensorRepository() SensorRepository {
-	return &SensorRepositoryImpl{}
+// sqlReadings holds the queries every SQL backend runs alike, the backends
+// embed it and add their own inserts. Queries are written with ? and rebound
+// for the driver. Times are bound in UTC, SQLite compares them as text.
+type sqlReadings struct {
+	db        *sqlx.DB
+	bucketSQL string             // numbers the bucket of a reading, see SelectAggregate
+	system    attribute.KeyValue // db.system.name of the transaction spans
 }
 
-const insertReadingSQL = `
-INSERT INTO sensor_readings (sensor_value, sensor_type, id1, id2, ts)
-VALUES (:sensor_value, :sensor_type, :id1, :id2, :ts)
-`
-
-func (sensorRepo *SensorRepositoryImpl) InsertReadingTx(ctx context.Context, db *sqlx.DB, r *model.SensorReadingInsert) (uint64, error) {
-	tx, err := db.BeginTxx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
-	if err != nil {
-		return 0, err
-	}
-	defer func() {
-		if err != nil {
-			_ = tx.Rollback()
-		}
-	}()
-
-	res, err := tx.NamedExecContext(ctx, insertReadingSQL, r)
-	if err != nil {
-		return 0, err
-	}
-
-	id, err := res.LastInsertId()
-	if err != nil {
-		return 0, err
-	}
+func (repo *sqlReadings) Ping(ctx context.Context) error {
+	return repo.db.PingContext(ctx)
+}
 
-	if err = tx.Commit(); err != nil {
-		return 0, err
-	}
-	return uint64(id), nil
+func (repo *sqlReadings) Close() error {
+	return repo.db.Close()
 }
 
-func (sensorRepo *SensorRepositoryImpl) InsertReadingsBatchTx(ctx context.Context, db *sqlx.DB, rs []model.SensorReadingInsert) (int64, error) {
-	if len(rs) == 0 {
-		return 0, nil
+// IsUnavailable reports whether err means the database could not be reached or
+// gave up on the statement, as opposed to refusing the data itself. Only the
+// former is worth retrying later with the same rows.
+func IsUnavailable(err error) bool {
+	if unavailable, ok := mysqlUnavailable(err); ok {
+		return unavailable
 	}
-
-	tx, err := db.BeginTxx(ctx, nil)
-	if err != nil {
-		return 0, err
+	if unavailable, ok := postgresUnavailable(err); ok {
+		return unavailable
 	}
-	defer func() {
-		if err != nil {
-			_ = tx.Rollback()
-		}
-	}()
-
-	stmt, err := tx.PrepareNamedContext(ctx, insertReadingSQL)
-	if err != nil {
-		return 0, err
+	if unavailable, ok := sqliteUnavailable(err); ok {
+		return unavailable
 	}
-	defer stmt.Close()
 
-	var affected int64
-	for _, r := range rs {
-		res, execErr := stmt.ExecContext(ctx, r)
-		if execErr != nil {
-			err = execErr
-			return affected, err
-		}
-		if n, _ := res.RowsAffected(); n > 0 {
-			affected += n
-		}
-	}
-
-	if err = tx.Commit(); err != nil {
-		return affected, err
-	}
-	return affected, nil
+	var netErr net.Error
+	return errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, sql.ErrConnDone) ||
+		errors.Is(err, context.DeadlineExceeded) ||
+		errors.As(err, &netErr)
 }
 
 type selectSensorPageArgs struct {
@@ -132,15 +150,14 @@ type selectSensorPageArgs struct {
 }
 
 const selectSensorsDataPaginated = `
-SELECT sensor_value, sensor_type, id1, id2, ts
+SELECT sensor_value, sensor_type, id1, id2, ts, out_of_range
 FROM sensor_readings
 ORDER BY ts
 LIMIT :limit OFFSET :offset
 `
 
-func (repo *SensorRepositoryImpl) SelectSensorDataPaginated(
+func (repo *sqlReadings) SelectSensorDataPaginated(
 	ctx context.Context,
-	db *sqlx.DB,
 	limit, offset int,
 ) ([]model.SensorReading, error) {
 	if limit <= 0 {
@@ -155,7 +172,7 @@ func (repo *SensorRepositoryImpl) SelectSensorDataPaginated(
 		Offset: offset,
 	}
 
-	rows, err := db.NamedQuery(selectSensorsDataPaginated, args)
+	rows, err := repo.db.NamedQueryContext(ctx, selectSensorsDataPaginated, args)
 	if err != nil {
 		return nil, err
 	}
@@ -180,12 +197,11 @@ SELECT count(id1) AS cnt
 FROM sensor_readings
 `
 
-func (repo *SensorRepositoryImpl) SelectCountPagination(
+func (repo *sqlReadings) SelectCountPagination(
 	ctx context.Context,
-	db *sqlx.DB,
 ) (int64, error) {
 	var result CountResult
-	if err := db.GetContext(ctx, &result, selectCountPaginated); err != nil {
+	if err := repo.db.GetContext(ctx, &result, selectCountPaginated); err != nil {
 		return 0, err
 	}
 
@@ -193,7 +209,7 @@ func (repo *SensorRepositoryImpl) SelectCountPagination(
 }
 
 const selectSensorsDataByTimePaginated = `
-SELECT sensor_value, sensor_type, id1, id2, ts
+SELECT sensor_value, sensor_type, id1, id2, ts, out_of_range
 FROM sensor_readings
 WHERE ts >= :ts_start AND ts < :ts_stop
 ORDER BY ts
@@ -207,9 +223,8 @@ type SelectByTimePageArgs struct {
 	Offset  int       `db:"offset"`
 }
 
-func (repo *SensorRepositoryImpl) SelectByTime(
+func (repo *sqlReadings) SelectByTime(
 	ctx context.Context,
-	db *sqlx.DB,
 	startTime, stopTime time.Time,
 	limit, offset int,
 ) ([]model.SensorReading, error) {
@@ -221,13 +236,13 @@ func (repo *SensorRepositoryImpl) SelectByTime(
 	}
 
 	args := SelectByTimePageArgs{
-		TsStart: startTime,
-		TsStop:  stopTime,
+		TsStart: startTime.UTC(),
+		TsStop:  stopTime.UTC(),
 		Limit:   limit,
 		Offset:  offset,
 	}
 
-	rows, err := db.NamedQuery(selectSensorsDataByTimePaginated, args)
+	rows, err := repo.db.NamedQueryContext(ctx, selectSensorsDataByTimePaginated, args)
 	if err != nil {
 		return nil, err
 	}
@@ -257,13 +272,12 @@ type CountResult struct {
 	Cnt int64 `db:"cnt"`
 }
 
-func (repo *SensorRepositoryImpl) SelectCountByTime(
+func (repo *sqlReadings) SelectCountByTime(
 	ctx context.Context,
-	db *sqlx.DB,
 	startTime, stopTime time.Time,
 ) (int64, error) {
 	var result CountResult
-	if err := db.GetContext(ctx, &result, selectCountSensorsDataByTimePaginated, startTime, stopTime); err != nil {
+	if err := repo.db.GetContext(ctx, &result, repo.db.Rebind(selectCountSensorsDataByTimePaginated), startTime.UTC(), stopTime.UTC()); err != nil {
 		return 0, err
 	}
 
@@ -288,9 +302,8 @@ func buildIDCondition(ids []IDCombination) (string, []interface{}) {
 }
 
 // Select by ID combinations
-func (repo *SensorRepositoryImpl) SelectByIDs(
+func (repo *sqlReadings) SelectByIDs(
 	ctx context.Context,
-	db *sqlx.DB,
 	ids []IDCombination,
 	limit, offset int,
 ) ([]model.SensorReading, error) {
@@ -307,7 +320,7 @@ func (repo *SensorRepositoryImpl) SelectByIDs(
 
 	idCondition, args := buildIDCondition(ids)
 	query := fmt.Sprintf(`
-SELECT sensor_value, sensor_type, id1, id2, ts
+SELECT sensor_value, sensor_type, id1, id2, ts, out_of_range
 FROM sensor_readings
 WHERE %s
 ORDER BY ts
@@ -316,7 +329,7 @@ LIMIT ? OFFSET ?
 
 	args = append(args, limit, offset)
 
-	rows, err := db.QueryContext(ctx, query, args...)
+	rows, err := repo.db.QueryContext(ctx, repo.db.Rebind(query), args...)
 	if err != nil {
 		return nil, err
 	}
@@ -325,7 +338,7 @@ LIMIT ? OFFSET ?
 	var readings []model.SensorReading
 	for rows.Next() {
 		var r model.SensorReading
-		if err := rows.Scan(&r.SensorValue, &r.SensorType, &r.ID1, &r.ID2, &r.TS); err != nil {
+		if err := rows.Scan(&r.SensorValue, &r.SensorType, &r.ID1, &r.ID2, &r.TS, &r.OutOfRange); err != nil {
 			return nil, err
 		}
 		readings = append(readings, r)
@@ -336,9 +349,8 @@ LIMIT ? OFFSET ?
 	return readings, nil
 }
 
-func (repo *SensorRepositoryImpl) SelectCountByIDs(
+func (repo *sqlReadings) SelectCountByIDs(
 	ctx context.Context,
-	db *sqlx.DB,
 	ids []IDCombination,
 ) (int64, error) {
 	if len(ids) == 0 {
@@ -353,7 +365,7 @@ WHERE %s
 `, idCondition)
 
 	var result CountResult
-	if err := db.GetContext(ctx, &result, query, args...); err != nil {
+	if err := repo.db.GetContext(ctx, &result, repo.db.Rebind(query), args...); err != nil {
 		return 0, err
 	}
 
@@ -361,9 +373,8 @@ WHERE %s
 }
 
 // Select by ID combinations and time
-func (repo *SensorRepositoryImpl) SelectByIDsAndTime(
+func (repo *sqlReadings) SelectByIDsAndTime(
 	ctx context.Context,
-	db *sqlx.DB,
 	ids []IDCombination,
 	startTime, stopTime time.Time,
 	limit, offset int,
@@ -381,16 +392,16 @@ func (repo *SensorRepositoryImpl) SelectByIDsAndTime(
 
 	idCondition, args := buildIDCondition(ids)
 	query := fmt.Sprintf(`
-SELECT sensor_value, sensor_type, id1, id2, ts
+SELECT sensor_value, sensor_type, id1, id2, ts, out_of_range
 FROM sensor_readings
 WHERE %s AND ts >= ? AND ts < ?
 ORDER BY ts
 LIMIT ? OFFSET ?
 `, idCondition)
 
-	args = append(args, startTime, stopTime, limit, offset)
+	args = append(args, startTime.UTC(), stopTime.UTC(), limit, offset)
 
-	rows, err := db.QueryContext(ctx, query, args...)
+	rows, err := repo.db.QueryContext(ctx, repo.db.Rebind(query), args...)
 	if err != nil {
 		return nil, err
 	}
@@ -399,7 +410,7 @@ LIMIT ? OFFSET ?
 	var readings []model.SensorReading
 	for rows.Next() {
 		var r model.SensorReading
-		if err := rows.Scan(&r.SensorValue, &r.SensorType, &r.ID1, &r.ID2, &r.TS); err != nil {
+		if err := rows.Scan(&r.SensorValue, &r.SensorType, &r.ID1, &r.ID2, &r.TS, &r.OutOfRange); err != nil {
 			return nil, err
 		}
 		readings = append(readings, r)
@@ -410,9 +421,8 @@ LIMIT ? OFFSET ?
 	return readings, nil
 }
 
-func (repo *SensorRepositoryImpl) SelectCountByIDsAndTime(
+func (repo *sqlReadings) SelectCountByIDsAndTime(
 	ctx context.Context,
-	db *sqlx.DB,
 	ids []IDCombination,
 	startTime, stopTime time.Time,
 ) (int64, error) {
@@ -427,10 +437,10 @@ FROM sensor_readings
 WHERE %s AND ts >= ? AND ts < ?
 `, idCondition)
 
-	args = append(args, startTime, stopTime)
+	args = append(args, startTime.UTC(), stopTime.UTC())
 
 	var result CountResult
-	if err := db.GetContext(ctx, &result, query, args...); err != nil {
+	if err := repo.db.GetContext(ctx, &result, repo.db.Rebind(query), args...); err != nil {
 		return 0, err
 	}
 
@@ -438,14 +448,13 @@ WHERE %s AND ts >= ? AND ts < ?
 }
 
 // Delete operations
-func (repo *SensorRepositoryImpl) DeleteByTime(
+func (repo *sqlReadings) DeleteByTime(
 	ctx context.Context,
-	db *sqlx.DB,
 	startTime, stopTime time.Time,
 ) (int64, error) {
 	query := `DELETE FROM sensor_readings WHERE ts >= ? AND ts < ?`
 
-	result, err := db.ExecContext(ctx, query, startTime, stopTime)
+	result, err := repo.db.ExecContext(ctx, repo.db.Rebind(query), startTime.UTC(), stopTime.UTC())
 	if err != nil {
 		return 0, err
 	}
@@ -458,9 +467,8 @@ func (repo *SensorRepositoryImpl) DeleteByTime(
 	return affected, nil
 }
 
-func (repo *SensorRepositoryImpl) DeleteByIDs(
+func (repo *sqlReadings) DeleteByIDs(
 	ctx context.Context,
-	db *sqlx.DB,
 	ids []IDCombination,
 ) (int64, error) {
 	if len(ids) == 0 {
@@ -470,7 +478,7 @@ func (repo *SensorRepositoryImpl) DeleteByIDs(
 	idCondition, args := buildIDCondition(ids)
 	query := fmt.Sprintf(`DELETE FROM sensor_readings WHERE %s`, idCondition)
 
-	result, err := db.ExecContext(ctx, query, args...)
+	result, err := repo.db.ExecContext(ctx, repo.db.Rebind(query), args...)
 	if err != nil {
 		return 0, err
 	}
@@ -483,9 +491,8 @@ func (repo *SensorRepositoryImpl) DeleteByIDs(
 	return affected, nil
 }
 
-func (repo *SensorRepositoryImpl) DeleteByIDsAndTime(
+func (repo *sqlReadings) DeleteByIDsAndTime(
 	ctx context.Context,
-	db *sqlx.DB,
 	ids []IDCombination,
 	startTime, stopTime time.Time,
 ) (int64, error) {
@@ -496,9 +503,9 @@ func (repo *SensorRepositoryImpl) DeleteByIDsAndTime(
 	idCondition, args := buildIDCondition(ids)
 	query := fmt.Sprintf(`DELETE FROM sensor_readings WHERE %s AND ts >= ? AND ts < ?`, idCondition)
 
-	args = append(args, startTime, stopTime)
+	args = append(args, startTime.UTC(), stopTime.UTC())
 
-	result, err := db.ExecContext(ctx, query, args...)
+	result, err := repo.db.ExecContext(ctx, repo.db.Rebind(query), args...)
 	if err != nil {
 		return 0, err
 	}
@@ -512,16 +519,15 @@ func (repo *SensorRepositoryImpl) DeleteByIDsAndTime(
 }
 
 // Update operations
-func (repo *SensorRepositoryImpl) UpdateByTime(
+func (repo *sqlReadings) UpdateByTime(
 	ctx context.Context,
-	db *sqlx.DB,
 	startTime, stopTime time.Time,
 	sensorValue float64,
 	sensorType string,
 ) (int64, error) {
 	query := `UPDATE sensor_readings SET sensor_value = ?, sensor_type = ? WHERE ts >= ? AND ts < ?`
 
-	result, err := db.ExecContext(ctx, query, sensorValue, sensorType, startTime, stopTime)
+	result, err := repo.db.ExecContext(ctx, repo.db.Rebind(query), sensorValue, sensorType, startTime.UTC(), stopTime.UTC())
 	if err != nil {
 		return 0, err
 	}
@@ -534,9 +540,8 @@ func (repo *SensorRepositoryImpl) UpdateByTime(
 	return affected, nil
 }
 
-func (repo *SensorRepositoryImpl) UpdateByIDs(
+func (repo *sqlReadings) UpdateByIDs(
 	ctx context.Context,
-	db *sqlx.DB,
 	ids []IDCombination,
 	sensorValue float64,
 	sensorType string,
@@ -551,7 +556,7 @@ func (repo *SensorRepositoryImpl) UpdateByIDs(
 	// Prepend the update values to the args
 	args = append([]interface{}{sensorValue, sensorType}, args...)
 
-	result, err := db.ExecContext(ctx, query, args...)
+	result, err := repo.db.ExecContext(ctx, repo.db.Rebind(query), args...)
 	if err != nil {
 		return 0, err
 	}
@@ -564,9 +569,8 @@ func (repo *SensorRepositoryImpl) UpdateByIDs(
 	return affected, nil
 }
 
-func (repo *SensorRepositoryImpl) UpdateByIDsAndTime(
+func (repo *sqlReadings) UpdateByIDsAndTime(
 	ctx context.Context,
-	db *sqlx.DB,
 	ids []IDCombination,
 	startTime, stopTime time.Time,
 	sensorValue float64,
@@ -581,9 +585,9 @@ func (repo *SensorRepositoryImpl) UpdateByIDsAndTime(
 
 	// Prepend the update values and append time values
 	args = append([]interface{}{sensorValue, sensorType}, args...)
-	args = append(args, startTime, stopTime)
+	args = append(args, startTime.UTC(), stopTime.UTC())
 
-	result, err := db.ExecContext(ctx, query, args...)
+	result, err := repo.db.ExecContext(ctx, repo.db.Rebind(query), args...)
 	if err != nil {
 		return 0, err
 	}
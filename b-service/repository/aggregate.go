@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	model "github.com/Yusufzhafir/worlder-team-assignment/b-service/repository/model"
+)
+
+// AggregateQuery picks the readings SelectAggregate downsamples: those of IDs
+// and of SensorType, whichever are set, with Start <= ts < Stop. Buckets are
+// Bucket wide and counted from Start.
+type AggregateQuery struct {
+	IDs        []IDCombination
+	SensorType string
+	Start      time.Time
+	Stop       time.Time
+	Bucket     time.Duration
+	// FirstLast also selects the earliest and latest value of every bucket,
+	// which costs a sort of each sensor's readings.
+	FirstLast bool
+}
+
+// Each backend numbers buckets with its own expression over ts, taking the
+// query start and the bucket width in milliseconds as arguments. Integer
+// division floors here since ts is never before the start.
+const (
+	mysqlBucketSQL    = `TIMESTAMPDIFF(MICROSECOND, ?, ts) DIV (? * 1000)`
+	postgresBucketSQL = `CAST(FLOOR(EXTRACT(EPOCH FROM ts - CAST(? AS TIMESTAMPTZ)) * 1000 / CAST(? AS BIGINT)) AS BIGINT)`
+	// julianday is a fraction of days, rounded to whole milliseconds first so
+	// a reading on a bucket boundary does not fall into the previous bucket
+	sqliteBucketSQL = `CAST(ROUND((julianday(ts) - julianday(?)) * 86400000) AS INTEGER) / ?`
+)
+
+// The readings of the query with their bucket, grouped per sensor and bucket
+// by aggregateSQL. idx_ids_ts serves the ids and range, idx_type_ts the type.
+const aggregateReadingsSQL = `
+SELECT id1, id2, sensor_value, ts, reading_id, %s AS bucket
+FROM sensor_readings
+WHERE %s AND ts >= ? AND ts < ?
+`
+
+// Window functions pick the first and last value of each bucket, every row of
+// a bucket carries the same pair so MIN below reads it back.
+const aggregateFirstLastSQL = `
+SELECT id1, id2, sensor_value, bucket,
+  FIRST_VALUE(sensor_value) OVER (PARTITION BY id1, id2, bucket ORDER BY ts, reading_id) AS first_reading,
+  FIRST_VALUE(sensor_value) OVER (PARTITION BY id1, id2, bucket ORDER BY ts DESC, reading_id DESC) AS last_reading
+FROM (%s) readings
+`
+
+// SQLite has no standard deviation, so every backend sums the squares and the
+// caller derives it.
+const aggregateSQL = `
+SELECT id1, id2, bucket,
+  COUNT(*) AS cnt,
+  SUM(sensor_value) AS total,
+  SUM(sensor_value * sensor_value) AS total_squares,
+  MIN(sensor_value) AS min_value,
+  MAX(sensor_value) AS max_value%s
+FROM (%s) bucketed
+GROUP BY id1, id2, bucket
+ORDER BY id1, id2, bucket
+`
+
+// SelectAggregate returns the non-empty buckets of every matching sensor,
+// ordered by id1, id2 and bucket.
+func (repo *sqlReadings) SelectAggregate(ctx context.Context, q AggregateQuery) ([]model.AggregateBucket, error) {
+	if q.Bucket <= 0 {
+		return nil, fmt.Errorf("bucket width must be positive, got %s", q.Bucket)
+	}
+
+	args := []interface{}{q.Start.UTC(), q.Bucket.Milliseconds()}
+	conditions := make([]string, 0, 2)
+	if len(q.IDs) > 0 {
+		idCondition, idArgs := buildIDCondition(q.IDs)
+		conditions = append(conditions, idCondition)
+		args = append(args, idArgs...)
+	}
+	if q.SensorType != "" {
+		conditions = append(conditions, "sensor_type = ?")
+		args = append(args, q.SensorType)
+	}
+	if len(conditions) == 0 {
+		conditions = append(conditions, "1=1")
+	}
+	args = append(args, q.Start.UTC(), q.Stop.UTC())
+
+	readings := fmt.Sprintf(aggregateReadingsSQL, repo.bucketSQL, strings.Join(conditions, " AND "))
+	firstLast := ""
+	if q.FirstLast {
+		readings = fmt.Sprintf(aggregateFirstLastSQL, readings)
+		firstLast = `,
+  MIN(first_reading) AS first_reading,
+  MIN(last_reading) AS last_reading`
+	}
+	query := fmt.Sprintf(aggregateSQL, firstLast, readings)
+
+	var buckets []model.AggregateBucket
+	if err := repo.db.SelectContext(ctx, &buckets, repo.db.Rebind(query), args...); err != nil {
+		return nil, err
+	}
+	return buckets, nil
+}
@@ -0,0 +1,616 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Yusufzhafir/worlder-team-assignment/b-service/migrate"
+	model "github.com/Yusufzhafir/worlder-team-assignment/b-service/repository/model"
+
+	embeddedpostgres "github.com/fergusstrange/embedded-postgres"
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestMemoryConformance(t *testing.T) {
+	testConformance(t, func(t *testing.T) SensorRepository { return NewMemoryRepository() })
+}
+
+func TestSQLiteConformance(t *testing.T) {
+	testConformance(t, func(t *testing.T) SensorRepository {
+		db := openMigrated(t, "sqlite3", SQLiteDSN(filepath.Join(t.TempDir(), "sensors.db")), migrate.SQLiteDialect)
+		return NewSQLiteRepository(db)
+	})
+}
+
+// conformanceTables are emptied before every case that runs on a shared
+// database, tables that reference another come before it.
+var conformanceTables = []string{"sensor_readings", "sensors", "sensor_type_aliases", "sensor_types"}
+
+// TestMySQLConformance needs a scratch database, its tables are emptied before
+// every case: TEST_MYSQL_DSN=user:pass@tcp(localhost:3306)/scratch?parseTime=true
+func TestMySQLConformance(t *testing.T) {
+	dsn := os.Getenv("TEST_MYSQL_DSN")
+	if dsn == "" {
+		t.Skip("TEST_MYSQL_DSN is not set")
+	}
+	testConformance(t, func(t *testing.T) SensorRepository {
+		db := openMigrated(t, "mysql", dsn, migrate.MySQLDialect)
+		for _, table := range conformanceTables {
+			if _, err := db.Exec(`DELETE FROM ` + table); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return NewMySQLRepository(db)
+	})
+}
+
+// TestPostgresConformance runs against TEST_POSTGRES_DSN, emptied before every
+// case, or with TEST_EMBEDDED_POSTGRES=1 against a throwaway server whose
+// binaries are downloaded once to ~/.embedded-postgres-go.
+func TestPostgresConformance(t *testing.T) {
+	dsn := os.Getenv("TEST_POSTGRES_DSN")
+	if dsn == "" && os.Getenv("TEST_EMBEDDED_POSTGRES") == "" {
+		t.Skip("neither TEST_POSTGRES_DSN nor TEST_EMBEDDED_POSTGRES is set")
+	}
+	if dsn == "" {
+		pgConfig := embeddedpostgres.DefaultConfig().
+			Port(54329).
+			Database("sensors").
+			RuntimePath(t.TempDir()).
+			Logger(io.Discard)
+		server := embeddedpostgres.NewDatabase(pgConfig)
+		if err := server.Start(); err != nil {
+			t.Fatalf("start embedded postgres: %v", err)
+		}
+		t.Cleanup(func() { _ = server.Stop() })
+		dsn = pgConfig.GetConnectionURL() + "?sslmode=disable"
+	}
+	testConformance(t, func(t *testing.T) SensorRepository {
+		db := openMigrated(t, "postgres", dsn, migrate.PostgresDialect)
+		if _, err := db.Exec(`TRUNCATE ` + strings.Join(conformanceTables, ", ")); err != nil {
+			t.Fatal(err)
+		}
+		return NewPostgresRepository(db)
+	})
+}
+
+func openMigrated(t *testing.T, driverName, dsn string, dialect migrate.Dialect) *sqlx.DB {
+	t.Helper()
+	db, err := sqlx.Open(driverName, dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	migrator, err := migrate.NewMigrator(migrate.MigratorOpts{DB: db.DB, Dialect: dialect, Logger: slog.New(slog.NewTextHandler(io.Discard, nil))})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrator.Up(context.Background(), 0); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return db
+}
+
+var base = time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func reading(id1 string, id2 int, offset time.Duration, value float64) model.SensorReadingInsert {
+	return model.SensorReadingInsert{SensorValue: value, SensorType: "TEMP", ID1: id1, ID2: id2, TS: base.Add(offset)}
+}
+
+func keyed(r model.SensorReadingInsert, key string) model.SensorReadingInsert {
+	r.ReadingKey = &key
+	return r
+}
+
+// seed stores readings of A/1 at 0..4s, B/2 at 1.5s and 3.5s, and one of A/1 at 10s
+// written in another zone.
+func seed(t *testing.T, repo SensorRepository) {
+	t.Helper()
+	rows := []model.SensorReadingInsert{
+		reading("A", 1, 4*time.Second, 4), reading("A", 1, 0, 0), reading("B", 2, 1500*time.Millisecond, 21),
+		reading("A", 1, 2*time.Second, 2), reading("A", 1, time.Second, 1), reading("A", 1, 3*time.Second, 3),
+		reading("B", 2, 3500*time.Millisecond, 23),
+	}
+	other := reading("A", 1, 10*time.Second, 10)
+	other.TS = other.TS.In(time.FixedZone("WIB", 7*3600))
+	rows = append(rows, other)
+	if _, err := repo.InsertReadingsBatchTx(context.Background(), rows, ConflictKeepFirst); err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+}
+
+// values lists what rs hold, sensor values are unique in seed.
+func values(rs []model.SensorReading) []float64 {
+	out := make([]float64, 0, len(rs))
+	for _, r := range rs {
+		out = append(out, r.SensorValue)
+	}
+	return out
+}
+
+func sameValues(t *testing.T, what string, got []model.SensorReading, want ...float64) {
+	t.Helper()
+	gotValues := values(got)
+	if len(gotValues) != len(want) {
+		t.Fatalf("%s = %v, want %v", what, gotValues, want)
+	}
+	for i := range want {
+		if gotValues[i] != want[i] {
+			t.Fatalf("%s = %v, want %v", what, gotValues, want)
+		}
+	}
+}
+
+// testConformance is what every SensorRepository backend has to agree on.
+// newRepo returns an empty repository.
+func testConformance(t *testing.T, newRepo func(t *testing.T) SensorRepository) {
+	ctx := context.Background()
+	a1 := []IDCombination{{ID1: "A", ID2: 1}}
+
+	t.Run("ping", func(t *testing.T) {
+		if err := newRepo(t).Ping(ctx); err != nil {
+			t.Fatalf("Ping: %v", err)
+		}
+	})
+
+	t.Run("plain inserts get new ids", func(t *testing.T) {
+		repo := newRepo(t)
+		first, err := repo.InsertReadingTx(ctx, &model.SensorReadingInsert{SensorValue: 1, SensorType: "TEMP", ID1: "A", ID2: 1, TS: base}, ConflictKeepFirst)
+		if err != nil {
+			t.Fatalf("InsertReadingTx: %v", err)
+		}
+		outcomes, err := repo.InsertReadingsBatchTx(ctx, []model.SensorReadingInsert{reading("A", 1, 0, 1), reading("A", 1, 0, 1)}, ConflictKeepFirst)
+		if err != nil {
+			t.Fatalf("InsertReadingsBatchTx: %v", err)
+		}
+		ids := map[uint64]bool{first.ReadingID: true}
+		for _, o := range outcomes {
+			if o.Duplicate || o.ReadingID == 0 || ids[o.ReadingID] {
+				t.Fatalf("outcomes = %+v after id %d, want new ids", outcomes, first.ReadingID)
+			}
+			ids[o.ReadingID] = true
+		}
+		if n, _ := repo.SelectCountPagination(ctx); n != 3 {
+			t.Fatalf("count = %d, want 3", n)
+		}
+	})
+
+	for _, tt := range []struct {
+		policy    ConflictPolicy
+		wantValue float64
+	}{
+		{policy: ConflictKeepFirst, wantValue: 1},
+		{policy: ConflictKeepLast, wantValue: 2},
+		{policy: ConflictReject, wantValue: 1},
+	} {
+		t.Run("duplicate key with "+string(tt.policy), func(t *testing.T) {
+			repo := newRepo(t)
+			first, err := repo.InsertReadingTx(ctx, ptr(keyed(reading("A", 1, 0, 1), "k1")), tt.policy)
+			if err != nil || first.Duplicate {
+				t.Fatalf("first insert = %+v, %v", first, err)
+			}
+			again, err := repo.InsertReadingTx(ctx, ptr(keyed(reading("A", 1, 0, 2), "k1")), tt.policy)
+			if err != nil || !again.Duplicate || again.ReadingID != first.ReadingID {
+				t.Fatalf("second insert = %+v, %v, want a duplicate of %d", again, err, first.ReadingID)
+			}
+
+			// a batch mixing the stored key, a new key and a plain row
+			outcomes, err := repo.InsertReadingsBatchTx(ctx, []model.SensorReadingInsert{
+				reading("B", 2, 0, 5), keyed(reading("A", 1, 0, 2), "k1"), keyed(reading("A", 1, time.Second, 3), "k2"),
+			}, tt.policy)
+			if err != nil {
+				t.Fatalf("InsertReadingsBatchTx: %v", err)
+			}
+			if outcomes[0].Duplicate || !outcomes[1].Duplicate || outcomes[1].ReadingID != first.ReadingID || outcomes[2].Duplicate {
+				t.Fatalf("batch outcomes = %+v", outcomes)
+			}
+			if outcomes[0].ReadingID == 0 || outcomes[2].ReadingID == 0 || outcomes[0].ReadingID == outcomes[2].ReadingID {
+				t.Fatalf("batch outcomes = %+v, want new ids", outcomes)
+			}
+
+			rows, err := repo.SelectByIDs(ctx, a1, 10, 0)
+			if err != nil {
+				t.Fatalf("SelectByIDs: %v", err)
+			}
+			sameValues(t, "A/1", rows, tt.wantValue, 3)
+		})
+	}
+
+	t.Run("select by time", func(t *testing.T) {
+		repo := newRepo(t)
+		seed(t, repo)
+		rows, err := repo.SelectByTime(ctx, base.Add(time.Second), base.Add(3*time.Second), 10, 0)
+		if err != nil {
+			t.Fatalf("SelectByTime: %v", err)
+		}
+		if len(rows) != 3 || !rows[0].TS.Equal(base.Add(time.Second)) || !rows[2].TS.Equal(base.Add(2*time.Second)) {
+			t.Fatalf("SelectByTime = %+v, want [1s, 3s) in ts order", rows)
+		}
+		if n, err := repo.SelectCountByTime(ctx, base.Add(time.Second), base.Add(3*time.Second)); err != nil || n != 3 {
+			t.Fatalf("SelectCountByTime = %d, %v, want 3", n, err)
+		}
+
+		// bounds in another zone mean the same instants
+		zone := time.FixedZone("EST", -5*3600)
+		rows, err = repo.SelectByTime(ctx, base.Add(4*time.Second).In(zone), base.Add(11*time.Second).In(zone), 10, 0)
+		if err != nil {
+			t.Fatalf("SelectByTime: %v", err)
+		}
+		sameValues(t, "SelectByTime in another zone", rows, 4, 10)
+	})
+
+	t.Run("pagination", func(t *testing.T) {
+		repo := newRepo(t)
+		seed(t, repo)
+		rows, err := repo.SelectSensorDataPaginated(ctx, 3, 0)
+		if err != nil {
+			t.Fatalf("SelectSensorDataPaginated: %v", err)
+		}
+		sameValues(t, "first page", rows[:1], 0)
+		if len(rows) != 3 {
+			t.Fatalf("first page has %d rows, want 3", len(rows))
+		}
+		rows, err = repo.SelectSensorDataPaginated(ctx, 3, 6)
+		if err != nil {
+			t.Fatalf("SelectSensorDataPaginated: %v", err)
+		}
+		sameValues(t, "last page", rows, 4, 10)
+		rows, err = repo.SelectSensorDataPaginated(ctx, 3, 100)
+		if err != nil || len(rows) != 0 {
+			t.Fatalf("page past the end = %+v, %v", rows, err)
+		}
+		rows, err = repo.SelectSensorDataPaginated(ctx, 0, -1)
+		if err != nil || len(rows) != 8 {
+			t.Fatalf("default page has %d rows, %v, want all 8", len(rows), err)
+		}
+		if n, err := repo.SelectCountPagination(ctx); err != nil || n != 8 {
+			t.Fatalf("SelectCountPagination = %d, %v, want 8", n, err)
+		}
+	})
+
+	t.Run("select by ids", func(t *testing.T) {
+		repo := newRepo(t)
+		seed(t, repo)
+		rows, err := repo.SelectByIDs(ctx, []IDCombination{{ID1: "B", ID2: 2}, {ID1: "A", ID2: 9}}, 10, 0)
+		if err != nil {
+			t.Fatalf("SelectByIDs: %v", err)
+		}
+		sameValues(t, "SelectByIDs", rows, 21, 23)
+		rows, err = repo.SelectByIDs(ctx, a1, 2, 1)
+		if err != nil {
+			t.Fatalf("SelectByIDs: %v", err)
+		}
+		sameValues(t, "SelectByIDs page", rows, 1, 2)
+		if n, err := repo.SelectCountByIDs(ctx, a1); err != nil || n != 6 {
+			t.Fatalf("SelectCountByIDs = %d, %v, want 6", n, err)
+		}
+
+		rows, err = repo.SelectByIDsAndTime(ctx, a1, base.Add(time.Second), base.Add(10*time.Second), 10, 0)
+		if err != nil {
+			t.Fatalf("SelectByIDsAndTime: %v", err)
+		}
+		sameValues(t, "SelectByIDsAndTime", rows, 1, 2, 3, 4)
+		if n, err := repo.SelectCountByIDsAndTime(ctx, a1, base.Add(time.Second), base.Add(10*time.Second)); err != nil || n != 4 {
+			t.Fatalf("SelectCountByIDsAndTime = %d, %v, want 4", n, err)
+		}
+
+		if rows, err := repo.SelectByIDs(ctx, nil, 10, 0); err != nil || len(rows) != 0 {
+			t.Fatalf("SelectByIDs without ids = %+v, %v", rows, err)
+		}
+		if n, err := repo.SelectCountByIDs(ctx, nil); err != nil || n != 0 {
+			t.Fatalf("SelectCountByIDs without ids = %d, %v", n, err)
+		}
+	})
+
+	t.Run("update", func(t *testing.T) {
+		repo := newRepo(t)
+		seed(t, repo)
+		if n, err := repo.UpdateByIDsAndTime(ctx, a1, base, base.Add(2*time.Second), 100, "HUMIDITY"); err != nil || n != 2 {
+			t.Fatalf("UpdateByIDsAndTime = %d, %v, want 2", n, err)
+		}
+		if n, err := repo.UpdateByIDs(ctx, []IDCombination{{ID1: "B", ID2: 2}}, 200, "PRESSURE"); err != nil || n != 2 {
+			t.Fatalf("UpdateByIDs = %d, %v, want 2", n, err)
+		}
+		if n, err := repo.UpdateByTime(ctx, base.Add(10*time.Second), base.Add(11*time.Second), 300, "TEMP"); err != nil || n != 1 {
+			t.Fatalf("UpdateByTime = %d, %v, want 1", n, err)
+		}
+		rows, err := repo.SelectSensorDataPaginated(ctx, 100, 0)
+		if err != nil {
+			t.Fatalf("SelectSensorDataPaginated: %v", err)
+		}
+		sameValues(t, "after updates", rows, 100, 100, 200, 2, 3, 200, 4, 300)
+		if rows[0].SensorType != "HUMIDITY" || rows[2].SensorType != "PRESSURE" {
+			t.Fatalf("types after updates = %+v", rows)
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		repo := newRepo(t)
+		seed(t, repo)
+		if n, err := repo.DeleteByIDsAndTime(ctx, a1, base, base.Add(2*time.Second)); err != nil || n != 2 {
+			t.Fatalf("DeleteByIDsAndTime = %d, %v, want 2", n, err)
+		}
+		if n, err := repo.DeleteByTime(ctx, base.Add(3*time.Second), base.Add(4*time.Second)); err != nil || n != 2 {
+			t.Fatalf("DeleteByTime = %d, %v, want 2", n, err)
+		}
+		if n, err := repo.DeleteByIDs(ctx, []IDCombination{{ID1: "B", ID2: 2}}); err != nil || n != 1 {
+			t.Fatalf("DeleteByIDs = %d, %v, want 1", n, err)
+		}
+		rows, err := repo.SelectSensorDataPaginated(ctx, 100, 0)
+		if err != nil {
+			t.Fatalf("SelectSensorDataPaginated: %v", err)
+		}
+		sameValues(t, "after deletes", rows, 2, 4, 10)
+		if n, err := repo.DeleteByIDs(ctx, nil); err != nil || n != 0 {
+			t.Fatalf("DeleteByIDs without ids = %d, %v", n, err)
+		}
+	})
+
+	t.Run("aggregate", func(t *testing.T) {
+		repo := newRepo(t)
+		seed(t, repo)
+		humid := reading("C", 3, time.Second, 50)
+		humid.SensorType = "HUMIDITY"
+		if _, err := repo.InsertReadingTx(ctx, &humid, ConflictKeepFirst); err != nil {
+			t.Fatalf("InsertReadingTx: %v", err)
+		}
+
+		// a reading on a bucket boundary starts the next bucket
+		buckets, err := repo.SelectAggregate(ctx, AggregateQuery{IDs: a1, Start: base, Stop: base.Add(12 * time.Second), Bucket: 2 * time.Second, FirstLast: true})
+		if err != nil {
+			t.Fatalf("SelectAggregate: %v", err)
+		}
+		want := []model.AggregateBucket{
+			{ID1: "A", ID2: 1, Bucket: 0, Count: 2, Sum: 1, SumOfSquares: 1, Min: 0, Max: 1, First: 0, Last: 1},
+			{ID1: "A", ID2: 1, Bucket: 1, Count: 2, Sum: 5, SumOfSquares: 13, Min: 2, Max: 3, First: 2, Last: 3},
+			{ID1: "A", ID2: 1, Bucket: 2, Count: 1, Sum: 4, SumOfSquares: 16, Min: 4, Max: 4, First: 4, Last: 4},
+			{ID1: "A", ID2: 1, Bucket: 5, Count: 1, Sum: 10, SumOfSquares: 100, Min: 10, Max: 10, First: 10, Last: 10},
+		}
+		if len(buckets) != len(want) {
+			t.Fatalf("SelectAggregate = %+v, want %+v", buckets, want)
+		}
+		for i := range want {
+			if buckets[i] != want[i] {
+				t.Fatalf("bucket %d = %+v, want %+v", i, buckets[i], want[i])
+			}
+		}
+
+		// by type, from a start in another zone that is not on a whole second
+		zone := time.FixedZone("EST", -5*3600)
+		buckets, err = repo.SelectAggregate(ctx, AggregateQuery{SensorType: "TEMP", Start: base.Add(500 * time.Millisecond).In(zone), Stop: base.Add(4 * time.Second), Bucket: 2 * time.Second})
+		if err != nil {
+			t.Fatalf("SelectAggregate by type: %v", err)
+		}
+		var got []string
+		for _, b := range buckets {
+			got = append(got, fmt.Sprintf("%s/%d#%d:%d", b.ID1, b.ID2, b.Bucket, b.Count))
+		}
+		if want := "[A/1#0:2 A/1#1:1 B/2#0:1 B/2#1:1]"; fmt.Sprint(got) != want {
+			t.Fatalf("SelectAggregate by type = %v, want %s", got, want)
+		}
+	})
+
+	t.Run("sensor summaries", func(t *testing.T) {
+		repo := newRepo(t)
+		seed(t, repo)
+		if n, err := repo.SelectCountSensors(ctx); err != nil || n != 2 {
+			t.Fatalf("SelectCountSensors = %d, %v, want 2", n, err)
+		}
+
+		summaries, err := repo.SelectSensorSummaries(ctx, base.Add(3*time.Second), 10, 0)
+		if err != nil {
+			t.Fatalf("SelectSensorSummaries: %v", err)
+		}
+		if len(summaries) != 2 {
+			t.Fatalf("SelectSensorSummaries = %+v, want A/1 and B/2", summaries)
+		}
+		a, b := summaries[0], summaries[1]
+		if a.ID1 != "A" || a.ID2 != 1 || a.Count != 6 || a.RecentCount != 3 || a.LatestValue != 10 || a.SensorType != "TEMP" ||
+			!a.FirstTS.Equal(base) || !a.LastTS.Equal(base.Add(10*time.Second)) {
+			t.Fatalf("A/1 summary = %+v", a)
+		}
+		if b.ID1 != "B" || b.ID2 != 2 || b.Count != 2 || b.RecentCount != 1 || b.LatestValue != 23 ||
+			!b.FirstTS.Equal(base.Add(1500*time.Millisecond)) || !b.LastTS.Equal(base.Add(3500*time.Millisecond)) {
+			t.Fatalf("B/2 summary = %+v", b)
+		}
+
+		summaries, err = repo.SelectSensorSummaries(ctx, base, 1, 1)
+		if err != nil || len(summaries) != 1 || summaries[0].ID1 != "B" {
+			t.Fatalf("second page = %+v, %v, want B/2", summaries, err)
+		}
+	})
+
+	t.Run("latest reading", func(t *testing.T) {
+		repo := newRepo(t)
+		seed(t, repo)
+		latest, err := repo.SelectLatestReading(ctx, IDCombination{ID1: "A", ID2: 1})
+		if err != nil {
+			t.Fatalf("SelectLatestReading: %v", err)
+		}
+		if latest.SensorValue != 10 || !latest.TS.Equal(base.Add(10*time.Second)) || latest.ReadingID == 0 || latest.CreatedAt.IsZero() {
+			t.Fatalf("SelectLatestReading = %+v, want the reading at 10s", latest)
+		}
+		if _, err := repo.SelectLatestReading(ctx, IDCombination{ID1: "A", ID2: 9}); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("SelectLatestReading of an unknown sensor = %v, want ErrNotFound", err)
+		}
+	})
+
+	t.Run("sensor registry", func(t *testing.T) {
+		repo := newRepo(t)
+		registered := base.Add(-time.Hour)
+		sensor := model.Sensor{
+			ID1: "A", ID2: 1, Name: "boiler inlet", Unit: "C", Location: "plant 2", Owner: "ops",
+			ExpectedRate: 0.5, Enabled: true, Labels: model.Labels{"line": "3"},
+			CreatedAt: registered, UpdatedAt: registered,
+		}
+		if err := repo.InsertSensor(ctx, &sensor); err != nil {
+			t.Fatalf("InsertSensor: %v", err)
+		}
+		if err := repo.InsertSensor(ctx, &sensor); !errors.Is(err, ErrAlreadyExists) {
+			t.Fatalf("InsertSensor of a registered id = %v, want ErrAlreadyExists", err)
+		}
+		if err := repo.InsertSensor(ctx, &model.Sensor{ID1: "B", ID2: 2, CreatedAt: registered, UpdatedAt: registered}); err != nil {
+			t.Fatalf("InsertSensor without labels: %v", err)
+		}
+
+		got, err := repo.SelectSensor(ctx, IDCombination{ID1: "A", ID2: 1})
+		if err != nil {
+			t.Fatalf("SelectSensor: %v", err)
+		}
+		if got.Name != "boiler inlet" || got.ExpectedRate != 0.5 || !got.Enabled || got.Labels["line"] != "3" || !got.CreatedAt.Equal(registered) {
+			t.Fatalf("SelectSensor = %+v, want what was inserted", got)
+		}
+		if _, err := repo.SelectSensor(ctx, IDCombination{ID1: "A", ID2: 9}); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("SelectSensor of an unknown sensor = %v, want ErrNotFound", err)
+		}
+
+		sensor.Name, sensor.Enabled, sensor.Labels, sensor.UpdatedAt = "boiler outlet", false, model.Labels{}, base
+		if err := repo.UpdateSensor(ctx, &sensor); err != nil {
+			t.Fatalf("UpdateSensor: %v", err)
+		}
+		if err := repo.UpdateSensor(ctx, &sensor); err != nil {
+			t.Fatalf("UpdateSensor with the same values: %v", err)
+		}
+		if err := repo.UpdateSensor(ctx, &model.Sensor{ID1: "A", ID2: 9}); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("UpdateSensor of an unknown sensor = %v, want ErrNotFound", err)
+		}
+
+		page, err := repo.SelectRegisteredSensors(ctx, 10, 0)
+		if err != nil || len(page) != 2 {
+			t.Fatalf("SelectRegisteredSensors = %+v, %v, want 2 sensors", page, err)
+		}
+		if a := page[0]; a.ID1 != "A" || a.Name != "boiler outlet" || a.Enabled || len(a.Labels) != 0 || !a.UpdatedAt.Equal(base) || !a.CreatedAt.Equal(registered) {
+			t.Fatalf("A/1 after update = %+v", a)
+		}
+		if count, err := repo.SelectCountRegisteredSensors(ctx); err != nil || count != 2 {
+			t.Fatalf("SelectCountRegisteredSensors = %d, %v, want 2", count, err)
+		}
+		byIDs, err := repo.SelectSensorsByIDs(ctx, []IDCombination{{ID1: "B", ID2: 2}, {ID1: "C", ID2: 3}})
+		if err != nil || len(byIDs) != 1 || byIDs[0].ID1 != "B" {
+			t.Fatalf("SelectSensorsByIDs = %+v, %v, want only B/2", byIDs, err)
+		}
+
+		if err := repo.DeleteSensor(ctx, IDCombination{ID1: "A", ID2: 1}); err != nil {
+			t.Fatalf("DeleteSensor: %v", err)
+		}
+		if err := repo.DeleteSensor(ctx, IDCombination{ID1: "A", ID2: 1}); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("DeleteSensor twice = %v, want ErrNotFound", err)
+		}
+	})
+
+	t.Run("sensor type catalog", func(t *testing.T) {
+		repo := newRepo(t)
+		created := base.Add(-time.Hour)
+		temperature := model.SensorType{
+			Name: "temperature", Unit: "C", MinValue: ptr(-50.0), MaxValue: ptr(150.0), RangePolicy: "reject",
+			Aliases: []string{"temp", "t"}, CreatedAt: created, UpdatedAt: created,
+		}
+		if err := repo.InsertSensorType(ctx, &temperature); err != nil {
+			t.Fatalf("InsertSensorType: %v", err)
+		}
+		for _, tt := range []struct {
+			name string
+			st   model.SensorType
+		}{
+			{name: "taken name", st: model.SensorType{Name: "temperature"}},
+			{name: "name taken as an alias", st: model.SensorType{Name: "temp"}},
+			{name: "alias taken as a name", st: model.SensorType{Name: "humidity", Aliases: []string{"temperature"}}},
+			{name: "taken alias", st: model.SensorType{Name: "humidity", Aliases: []string{"rh", "t"}}},
+		} {
+			tt.st.RangePolicy, tt.st.CreatedAt, tt.st.UpdatedAt = "flag", created, created
+			if err := repo.InsertSensorType(ctx, &tt.st); !errors.Is(err, ErrAlreadyExists) {
+				t.Fatalf("InsertSensorType with a %s = %v, want ErrAlreadyExists", tt.name, err)
+			}
+		}
+		humidity := model.SensorType{Name: "humidity", Unit: "%", RangePolicy: "flag", Aliases: []string{"rh"}, CreatedAt: created, UpdatedAt: created}
+		if err := repo.InsertSensorType(ctx, &humidity); err != nil {
+			t.Fatalf("InsertSensorType after refused ones: %v", err)
+		}
+
+		temperature.MaxValue, temperature.RangePolicy, temperature.Aliases, temperature.UpdatedAt = nil, "flag", []string{"temp"}, base
+		if err := repo.UpdateSensorType(ctx, &temperature); err != nil {
+			t.Fatalf("UpdateSensorType: %v", err)
+		}
+		if err := repo.UpdateSensorType(ctx, &temperature); err != nil {
+			t.Fatalf("UpdateSensorType with the same values: %v", err)
+		}
+		if err := repo.UpdateSensorType(ctx, &model.SensorType{Name: "pressure", RangePolicy: "flag"}); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("UpdateSensorType of an unknown type = %v, want ErrNotFound", err)
+		}
+		humidity.Aliases = []string{"rh", "temp"}
+		if err := repo.UpdateSensorType(ctx, &humidity); !errors.Is(err, ErrAlreadyExists) {
+			t.Fatalf("UpdateSensorType with an alias of another type = %v, want ErrAlreadyExists", err)
+		}
+
+		types, err := repo.SelectSensorTypes(ctx)
+		if err != nil || len(types) != 2 {
+			t.Fatalf("SelectSensorTypes = %+v, %v, want 2 types", types, err)
+		}
+		h, temp := types[0], types[1]
+		if h.Name != "humidity" || len(h.Aliases) != 1 || h.Aliases[0] != "rh" {
+			t.Fatalf("humidity after a refused update = %+v", h)
+		}
+		if temp.Unit != "C" || temp.MinValue == nil || *temp.MinValue != -50 || temp.MaxValue != nil || temp.RangePolicy != "flag" ||
+			len(temp.Aliases) != 1 || temp.Aliases[0] != "temp" || !temp.CreatedAt.Equal(created) || !temp.UpdatedAt.Equal(base) {
+			t.Fatalf("temperature after update = %+v", temp)
+		}
+
+		if err := repo.DeleteSensorType(ctx, "temperature"); err != nil {
+			t.Fatalf("DeleteSensorType: %v", err)
+		}
+		if err := repo.DeleteSensorType(ctx, "temperature"); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("DeleteSensorType twice = %v, want ErrNotFound", err)
+		}
+		reused := model.SensorType{Name: "temp", RangePolicy: "flag", CreatedAt: base, UpdatedAt: base}
+		if err := repo.InsertSensorType(ctx, &reused); err != nil {
+			t.Fatalf("InsertSensorType with an alias of a deleted type: %v", err)
+		}
+	})
+
+	t.Run("out of range flag is stored", func(t *testing.T) {
+		repo := newRepo(t)
+		flagged := reading("A", 1, 0, 900)
+		flagged.OutOfRange = true
+		if _, err := repo.InsertReadingsBatchTx(ctx, []model.SensorReadingInsert{flagged, reading("A", 1, time.Second, 1)}, ConflictKeepFirst); err != nil {
+			t.Fatalf("InsertReadingsBatchTx: %v", err)
+		}
+		got, err := repo.SelectByIDs(ctx, a1, 10, 0)
+		if err != nil || len(got) != 2 || !got[0].OutOfRange || got[1].OutOfRange {
+			t.Fatalf("SelectByIDs = %+v, %v, want only the first flagged", got, err)
+		}
+		latest, err := repo.SelectLatestReading(ctx, IDCombination{ID1: "A", ID2: 1})
+		if err != nil || latest.OutOfRange {
+			t.Fatalf("SelectLatestReading = %+v, %v, want the unflagged reading", latest, err)
+		}
+	})
+
+	t.Run("deleted keys can be stored again", func(t *testing.T) {
+		repo := newRepo(t)
+		first, err := repo.InsertReadingTx(ctx, ptr(keyed(reading("A", 1, 0, 1), "k1")), ConflictKeepFirst)
+		if err != nil {
+			t.Fatalf("InsertReadingTx: %v", err)
+		}
+		if _, err := repo.DeleteByIDs(ctx, a1); err != nil {
+			t.Fatalf("DeleteByIDs: %v", err)
+		}
+		again, err := repo.InsertReadingTx(ctx, ptr(keyed(reading("A", 1, 0, 2), "k1")), ConflictKeepFirst)
+		if err != nil || again.Duplicate || again.ReadingID == first.ReadingID {
+			t.Fatalf("insert after delete = %+v, %v", again, err)
+		}
+	})
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,661 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	model "github.com/Yusufzhafir/worlder-team-assignment/b-service/repository/model"
+)
+
+// MemoryRepositoryImpl keeps readings, the sensor registry and the sensor type
+// catalog in process memory, for tests and demos. Nothing survives a restart and
+// every query scans all rows.
+type MemoryRepositoryImpl struct {
+	mu          sync.RWMutex
+	rows        []*memoryRow // in insert order
+	byKey       map[string]*memoryRow
+	lastID      uint64
+	sensors     map[IDCombination]model.Sensor
+	sensorTypes map[string]model.SensorType
+	typeAliases map[string]string // lowercase name or alias to the name owning it
+}
+
+type memoryRow struct {
+	reading    model.SensorReading
+	readingKey *string
+	principal  *string
+}
+
+func NewMemoryRepository() SensorRepository {
+	return &MemoryRepositoryImpl{
+		byKey:       make(map[string]*memoryRow),
+		sensors:     make(map[IDCombination]model.Sensor),
+		sensorTypes: make(map[string]model.SensorType),
+		typeAliases: make(map[string]string),
+	}
+}
+
+func (repo *MemoryRepositoryImpl) Ping(ctx context.Context) error {
+	return nil
+}
+
+func (repo *MemoryRepositoryImpl) Close() error {
+	return nil
+}
+
+func (repo *MemoryRepositoryImpl) InsertReadingTx(ctx context.Context, r *model.SensorReadingInsert, policy ConflictPolicy) (model.InsertOutcome, error) {
+	outcomes, err := repo.InsertReadingsBatchTx(ctx, []model.SensorReadingInsert{*r}, policy)
+	if err != nil {
+		return model.InsertOutcome{}, err
+	}
+	return outcomes[0], nil
+}
+
+func (repo *MemoryRepositoryImpl) InsertReadingsBatchTx(ctx context.Context, rs []model.SensorReadingInsert, policy ConflictPolicy) ([]model.InsertOutcome, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	outcomes := make([]model.InsertOutcome, len(rs))
+	for i, r := range rs {
+		reading := model.SensorReading{SensorValue: r.SensorValue, SensorType: r.SensorType, ID1: r.ID1, ID2: r.ID2, TS: r.TS, OutOfRange: r.OutOfRange}
+		if r.ReadingKey != nil {
+			if stored, ok := repo.byKey[*r.ReadingKey]; ok {
+				outcomes[i] = model.InsertOutcome{ReadingID: stored.reading.ReadingID, Duplicate: true}
+				if policy == ConflictKeepLast {
+					reading.ReadingID, reading.CreatedAt = stored.reading.ReadingID, stored.reading.CreatedAt
+					stored.reading, stored.principal = reading, r.Principal
+				}
+				continue
+			}
+		}
+
+		repo.lastID++
+		reading.ReadingID, reading.CreatedAt = repo.lastID, time.Now()
+		row := &memoryRow{reading: reading, readingKey: r.ReadingKey, principal: r.Principal}
+		repo.rows = append(repo.rows, row)
+		if r.ReadingKey != nil {
+			repo.byKey[*r.ReadingKey] = row
+		}
+		outcomes[i].ReadingID = repo.lastID
+	}
+	return outcomes, nil
+}
+
+// memoryFilter matches rows on ids and a [start, stop) range, both optional.
+type memoryFilter struct {
+	ids         []IDCombination
+	start, stop *time.Time
+}
+
+func (f memoryFilter) match(r *model.SensorReading) bool {
+	if f.start != nil && (r.TS.Before(*f.start) || !r.TS.Before(*f.stop)) {
+		return false
+	}
+	if f.ids == nil {
+		return true
+	}
+	for _, id := range f.ids {
+		if id.ID1 == r.ID1 && id.ID2 == r.ID2 {
+			return true
+		}
+	}
+	return false
+}
+
+func timeRange(startTime, stopTime time.Time) memoryFilter {
+	return memoryFilter{start: &startTime, stop: &stopTime}
+}
+
+// selectPage returns the matching readings ordered by ts, with the columns the
+// SQL backends select.
+func (repo *MemoryRepositoryImpl) selectPage(ctx context.Context, f memoryFilter, limit, offset int) ([]model.SensorReading, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = 100
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	var matched []model.SensorReading
+	for _, row := range repo.rows {
+		if f.match(&row.reading) {
+			r := row.reading
+			matched = append(matched, model.SensorReading{SensorValue: r.SensorValue, SensorType: r.SensorType, ID1: r.ID1, ID2: r.ID2, TS: r.TS, OutOfRange: r.OutOfRange})
+		}
+	}
+	sort.SliceStable(matched, func(i, j int) bool { return matched[i].TS.Before(matched[j].TS) })
+	if offset >= len(matched) {
+		return nil, nil
+	}
+	return matched[offset:min(offset+limit, len(matched))], nil
+}
+
+func (repo *MemoryRepositoryImpl) count(ctx context.Context, f memoryFilter) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	var n int64
+	for _, row := range repo.rows {
+		if f.match(&row.reading) {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (repo *MemoryRepositoryImpl) delete(ctx context.Context, f memoryFilter) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	kept := repo.rows[:0]
+	var n int64
+	for _, row := range repo.rows {
+		if !f.match(&row.reading) {
+			kept = append(kept, row)
+			continue
+		}
+		if row.readingKey != nil {
+			delete(repo.byKey, *row.readingKey)
+		}
+		n++
+	}
+	clear(repo.rows[len(kept):])
+	repo.rows = kept
+	return n, nil
+}
+
+func (repo *MemoryRepositoryImpl) update(ctx context.Context, f memoryFilter, sensorValue float64, sensorType string) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	var n int64
+	for _, row := range repo.rows {
+		if f.match(&row.reading) {
+			row.reading.SensorValue, row.reading.SensorType = sensorValue, sensorType
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (repo *MemoryRepositoryImpl) SelectByTime(ctx context.Context, startTime, stopTime time.Time, limit int, offset int) ([]model.SensorReading, error) {
+	return repo.selectPage(ctx, timeRange(startTime, stopTime), limit, offset)
+}
+
+func (repo *MemoryRepositoryImpl) SelectCountByTime(ctx context.Context, startTime, stopTime time.Time) (int64, error) {
+	return repo.count(ctx, timeRange(startTime, stopTime))
+}
+
+func (repo *MemoryRepositoryImpl) SelectByIDs(ctx context.Context, ids []IDCombination, limit int, offset int) ([]model.SensorReading, error) {
+	if len(ids) == 0 {
+		return []model.SensorReading{}, nil
+	}
+	return repo.selectPage(ctx, memoryFilter{ids: ids}, limit, offset)
+}
+
+func (repo *MemoryRepositoryImpl) SelectCountByIDs(ctx context.Context, ids []IDCombination) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	return repo.count(ctx, memoryFilter{ids: ids})
+}
+
+func (repo *MemoryRepositoryImpl) SelectByIDsAndTime(ctx context.Context, ids []IDCombination, startTime, stopTime time.Time, limit int, offset int) ([]model.SensorReading, error) {
+	if len(ids) == 0 {
+		return []model.SensorReading{}, nil
+	}
+	f := timeRange(startTime, stopTime)
+	f.ids = ids
+	return repo.selectPage(ctx, f, limit, offset)
+}
+
+func (repo *MemoryRepositoryImpl) SelectCountByIDsAndTime(ctx context.Context, ids []IDCombination, startTime, stopTime time.Time) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	f := timeRange(startTime, stopTime)
+	f.ids = ids
+	return repo.count(ctx, f)
+}
+
+func (repo *MemoryRepositoryImpl) DeleteByTime(ctx context.Context, startTime, stopTime time.Time) (int64, error) {
+	return repo.delete(ctx, timeRange(startTime, stopTime))
+}
+
+func (repo *MemoryRepositoryImpl) DeleteByIDs(ctx context.Context, ids []IDCombination) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	return repo.delete(ctx, memoryFilter{ids: ids})
+}
+
+func (repo *MemoryRepositoryImpl) DeleteByIDsAndTime(ctx context.Context, ids []IDCombination, startTime, stopTime time.Time) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	f := timeRange(startTime, stopTime)
+	f.ids = ids
+	return repo.delete(ctx, f)
+}
+
+func (repo *MemoryRepositoryImpl) UpdateByTime(ctx context.Context, startTime, stopTime time.Time, sensorValue float64, sensorType string) (int64, error) {
+	return repo.update(ctx, timeRange(startTime, stopTime), sensorValue, sensorType)
+}
+
+func (repo *MemoryRepositoryImpl) UpdateByIDs(ctx context.Context, ids []IDCombination, sensorValue float64, sensorType string) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	return repo.update(ctx, memoryFilter{ids: ids}, sensorValue, sensorType)
+}
+
+func (repo *MemoryRepositoryImpl) UpdateByIDsAndTime(ctx context.Context, ids []IDCombination, startTime, stopTime time.Time, sensorValue float64, sensorType string) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	f := timeRange(startTime, stopTime)
+	f.ids = ids
+	return repo.update(ctx, f, sensorValue, sensorType)
+}
+
+func (repo *MemoryRepositoryImpl) SelectSensorDataPaginated(ctx context.Context, limit, offset int) ([]model.SensorReading, error) {
+	return repo.selectPage(ctx, memoryFilter{}, limit, offset)
+}
+
+func (repo *MemoryRepositoryImpl) SelectCountPagination(ctx context.Context) (int64, error) {
+	return repo.count(ctx, memoryFilter{})
+}
+
+type memoryBucketKey struct {
+	id1    string
+	id2    int
+	bucket int64
+}
+
+func (repo *MemoryRepositoryImpl) SelectAggregate(ctx context.Context, q AggregateQuery) ([]model.AggregateBucket, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if q.Bucket <= 0 {
+		return nil, fmt.Errorf("bucket width must be positive, got %s", q.Bucket)
+	}
+	f := timeRange(q.Start, q.Stop)
+	if len(q.IDs) > 0 {
+		f.ids = q.IDs
+	}
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	// rows are in insert order, so the first reading at a ts has the lowest id as in SQL
+	buckets := make(map[memoryBucketKey]*model.AggregateBucket)
+	firstTS := make(map[memoryBucketKey]time.Time)
+	lastTS := make(map[memoryBucketKey]time.Time)
+	for _, row := range repo.rows {
+		r := &row.reading
+		if !f.match(r) || (q.SensorType != "" && r.SensorType != q.SensorType) {
+			continue
+		}
+		key := memoryBucketKey{id1: r.ID1, id2: r.ID2, bucket: int64(r.TS.Sub(q.Start) / q.Bucket)}
+		b, ok := buckets[key]
+		if !ok {
+			b = &model.AggregateBucket{ID1: r.ID1, ID2: r.ID2, Bucket: key.bucket, Min: r.SensorValue, Max: r.SensorValue}
+			buckets[key] = b
+		}
+		b.Count++
+		b.Sum += r.SensorValue
+		b.SumOfSquares += r.SensorValue * r.SensorValue
+		b.Min = min(b.Min, r.SensorValue)
+		b.Max = max(b.Max, r.SensorValue)
+		if q.FirstLast {
+			if !ok || r.TS.Before(firstTS[key]) {
+				b.First, firstTS[key] = r.SensorValue, r.TS
+			}
+			if !ok || !r.TS.Before(lastTS[key]) {
+				b.Last, lastTS[key] = r.SensorValue, r.TS
+			}
+		}
+	}
+
+	result := make([]model.AggregateBucket, 0, len(buckets))
+	for _, b := range buckets {
+		result = append(result, *b)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if a.ID1 != b.ID1 {
+			return a.ID1 < b.ID1
+		}
+		if a.ID2 != b.ID2 {
+			return a.ID2 < b.ID2
+		}
+		return a.Bucket < b.Bucket
+	})
+	return result, nil
+}
+
+// summaries returns every sensor's summary in id order, under the read lock.
+func (repo *MemoryRepositoryImpl) summaries(since time.Time) []model.SensorSummary {
+	byID := make(map[IDCombination]*model.SensorSummary)
+	for _, row := range repo.rows {
+		r := &row.reading
+		id := IDCombination{ID1: r.ID1, ID2: r.ID2}
+		s, ok := byID[id]
+		if !ok {
+			s = &model.SensorSummary{ID1: r.ID1, ID2: r.ID2, FirstTS: r.TS, LastTS: r.TS, SensorType: r.SensorType, LatestValue: r.SensorValue}
+			byID[id] = s
+		}
+		s.Count++
+		if !r.TS.Before(since) {
+			s.RecentCount++
+		}
+		// rows are in id order, so ties keep the lowest id first and the highest last
+		if r.TS.Before(s.FirstTS) {
+			s.FirstTS = r.TS
+		}
+		if !r.TS.Before(s.LastTS) {
+			s.LastTS, s.SensorType, s.LatestValue = r.TS, r.SensorType, r.SensorValue
+		}
+	}
+
+	result := make([]model.SensorSummary, 0, len(byID))
+	for _, s := range byID {
+		result = append(result, *s)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].ID1 != result[j].ID1 {
+			return result[i].ID1 < result[j].ID1
+		}
+		return result[i].ID2 < result[j].ID2
+	})
+	return result
+}
+
+func (repo *MemoryRepositoryImpl) SelectSensorSummaries(ctx context.Context, since time.Time, limit, offset int) ([]model.SensorSummary, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = 100
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	summaries := repo.summaries(since)
+	if offset >= len(summaries) {
+		return nil, nil
+	}
+	return summaries[offset:min(offset+limit, len(summaries))], nil
+}
+
+func (repo *MemoryRepositoryImpl) SelectCountSensors(ctx context.Context) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	return int64(len(repo.summaries(time.Time{}))), nil
+}
+
+func (repo *MemoryRepositoryImpl) SelectLatestReading(ctx context.Context, id IDCombination) (model.SensorReading, error) {
+	if err := ctx.Err(); err != nil {
+		return model.SensorReading{}, err
+	}
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	var latest *model.SensorReading
+	for _, row := range repo.rows {
+		r := &row.reading
+		if r.ID1 == id.ID1 && r.ID2 == id.ID2 && (latest == nil || !r.TS.Before(latest.TS)) {
+			latest = r
+		}
+	}
+	if latest == nil {
+		return model.SensorReading{}, ErrNotFound
+	}
+	return *latest, nil
+}
+
+// copySensor keeps callers from sharing the stored labels map.
+func copySensor(s model.Sensor) model.Sensor {
+	labels := make(model.Labels, len(s.Labels))
+	for k, v := range s.Labels {
+		labels[k] = v
+	}
+	s.Labels = labels
+	return s
+}
+
+func (repo *MemoryRepositoryImpl) InsertSensor(ctx context.Context, s *model.Sensor) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	id := IDCombination{ID1: s.ID1, ID2: s.ID2}
+	if _, ok := repo.sensors[id]; ok {
+		return ErrAlreadyExists
+	}
+	repo.sensors[id] = copySensor(*s)
+	return nil
+}
+
+func (repo *MemoryRepositoryImpl) SelectSensor(ctx context.Context, id IDCombination) (model.Sensor, error) {
+	if err := ctx.Err(); err != nil {
+		return model.Sensor{}, err
+	}
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	sensor, ok := repo.sensors[id]
+	if !ok {
+		return model.Sensor{}, ErrNotFound
+	}
+	return copySensor(sensor), nil
+}
+
+func (repo *MemoryRepositoryImpl) SelectSensorsByIDs(ctx context.Context, ids []IDCombination) ([]model.Sensor, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	sensors := make([]model.Sensor, 0, len(ids))
+	seen := make(map[IDCombination]bool, len(ids))
+	for _, id := range ids {
+		if sensor, ok := repo.sensors[id]; ok && !seen[id] {
+			seen[id] = true
+			sensors = append(sensors, copySensor(sensor))
+		}
+	}
+	sortSensors(sensors)
+	return sensors, nil
+}
+
+func (repo *MemoryRepositoryImpl) SelectRegisteredSensors(ctx context.Context, limit, offset int) ([]model.Sensor, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = 100
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	sensors := make([]model.Sensor, 0, len(repo.sensors))
+	for _, sensor := range repo.sensors {
+		sensors = append(sensors, copySensor(sensor))
+	}
+	sortSensors(sensors)
+	if offset >= len(sensors) {
+		return nil, nil
+	}
+	return sensors[offset:min(offset+limit, len(sensors))], nil
+}
+
+func sortSensors(sensors []model.Sensor) {
+	sort.Slice(sensors, func(i, j int) bool {
+		if sensors[i].ID1 != sensors[j].ID1 {
+			return sensors[i].ID1 < sensors[j].ID1
+		}
+		return sensors[i].ID2 < sensors[j].ID2
+	})
+}
+
+func (repo *MemoryRepositoryImpl) SelectCountRegisteredSensors(ctx context.Context) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	return int64(len(repo.sensors)), nil
+}
+
+func (repo *MemoryRepositoryImpl) UpdateSensor(ctx context.Context, s *model.Sensor) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	id := IDCombination{ID1: s.ID1, ID2: s.ID2}
+	stored, ok := repo.sensors[id]
+	if !ok {
+		return ErrNotFound
+	}
+	updated := copySensor(*s)
+	updated.CreatedAt = stored.CreatedAt
+	repo.sensors[id] = updated
+	return nil
+}
+
+func (repo *MemoryRepositoryImpl) DeleteSensor(ctx context.Context, id IDCombination) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if _, ok := repo.sensors[id]; !ok {
+		return ErrNotFound
+	}
+	delete(repo.sensors, id)
+	return nil
+}
+
+func (repo *MemoryRepositoryImpl) SelectSensorTypes(ctx context.Context) ([]model.SensorType, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	types := make([]model.SensorType, 0, len(repo.sensorTypes))
+	for _, t := range repo.sensorTypes {
+		t.Aliases = append([]string{}, t.Aliases...)
+		types = append(types, t)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i].Name < types[j].Name })
+	return types, nil
+}
+
+func (repo *MemoryRepositoryImpl) InsertSensorType(ctx context.Context, t *model.SensorType) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if _, ok := repo.sensorTypes[t.Name]; ok {
+		return ErrAlreadyExists
+	}
+	return repo.storeSensorType(*t)
+}
+
+func (repo *MemoryRepositoryImpl) UpdateSensorType(ctx context.Context, t *model.SensorType) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	stored, ok := repo.sensorTypes[t.Name]
+	if !ok {
+		return ErrNotFound
+	}
+	updated := *t
+	updated.CreatedAt = stored.CreatedAt
+	return repo.storeSensorType(updated)
+}
+
+func (repo *MemoryRepositoryImpl) DeleteSensorType(ctx context.Context, name string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if _, ok := repo.sensorTypes[name]; !ok {
+		return ErrNotFound
+	}
+	delete(repo.sensorTypes, name)
+	for alias, owner := range repo.typeAliases {
+		if owner == name {
+			delete(repo.typeAliases, alias)
+		}
+	}
+	return nil
+}
+
+// storeSensorType replaces t and its aliases, unless another type holds one of
+// them. Callers hold the write lock.
+func (repo *MemoryRepositoryImpl) storeSensorType(t model.SensorType) error {
+	aliases := append([]string{strings.ToLower(t.Name)}, t.Aliases...)
+	for _, alias := range aliases {
+		if owner, ok := repo.typeAliases[alias]; ok && owner != t.Name {
+			return ErrAlreadyExists
+		}
+	}
+	for alias, owner := range repo.typeAliases {
+		if owner == t.Name {
+			delete(repo.typeAliases, alias)
+		}
+	}
+	for _, alias := range aliases {
+		repo.typeAliases[alias] = t.Name
+	}
+	t.Aliases = append([]string{}, t.Aliases...)
+	repo.sensorTypes[t.Name] = t
+	return nil
+}
@@ -0,0 +1,44 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// Labels are free-form key/value pairs, stored as a JSON object.
+type Labels map[string]string
+
+// Value encodes the labels as JSON text, which the JSON and JSONB columns accept.
+func (l Labels) Value() (driver.Value, error) {
+	if l == nil {
+		return "{}", nil
+	}
+	b, err := json.Marshal(map[string]string(l))
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// Scan decodes the JSON object MySQL and Postgres return as bytes and SQLite as text.
+func (l *Labels) Scan(src any) error {
+	var b []byte
+	switch v := src.(type) {
+	case nil:
+		*l = Labels{}
+		return nil
+	case []byte:
+		b = v
+	case string:
+		b = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into Labels", src)
+	}
+	labels := Labels{}
+	if err := json.Unmarshal(b, &labels); err != nil {
+		return fmt.Errorf("labels are not a JSON object of strings: %w", err)
+	}
+	*l = labels
+	return nil
+}
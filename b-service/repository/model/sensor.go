@@ -9,8 +9,9 @@ type SensorReading struct {
 	SensorType  string    `db:"sensor_type"`
 	ID1         string    `db:"id1"`
 	ID2         int       `db:"id2"`
-	TS          time.Time `db:"ts"`         // TIMESTAMP(6)
-	CreatedAt   time.Time `db:"created_at"` // TIMESTAMP(6)
+	TS          time.Time `db:"ts"`           // TIMESTAMP(6)
+	OutOfRange  bool      `db:"out_of_range"` // outside the range of its sensor type when stored
+	CreatedAt   time.Time `db:"created_at"`   // TIMESTAMP(6)
 }
 
 // Insert DTO (omit auto fields)
@@ -20,4 +21,66 @@ type SensorReadingInsert struct {
 	ID1         string    `db:"id1"`
 	ID2         int       `db:"id2"`
 	TS          time.Time `db:"ts"`
+	ReadingKey  *string   `db:"reading_key"` // nil stores NULL, which never conflicts
+	Principal   *string   `db:"principal"`   // authenticated caller, nil when auth is disabled
+	OutOfRange  bool      `db:"out_of_range"`
+}
+
+// Result of writing one reading
+type InsertOutcome struct {
+	ReadingID uint64
+	Duplicate bool // reading_key was already stored; what was kept depends on the conflict policy
+}
+
+// One bucket of one sensor's readings, as aggregated by SelectAggregate
+type AggregateBucket struct {
+	ID1          string  `db:"id1"`
+	ID2          int     `db:"id2"`
+	Bucket       int64   `db:"bucket"` // n for the bucket starting n widths after the query start
+	Count        int64   `db:"cnt"`
+	Sum          float64 `db:"total"`
+	SumOfSquares float64 `db:"total_squares"`
+	Min          float64 `db:"min_value"`
+	Max          float64 `db:"max_value"`
+	First        float64 `db:"first_reading"` // earliest and latest value, only selected on request
+	Last         float64 `db:"last_reading"`
+}
+
+// What sensor_readings holds for one (id1, id2)
+type SensorSummary struct {
+	ID1         string    `db:"id1"`
+	ID2         int       `db:"id2"`
+	SensorType  string    `db:"sensor_type"`  // of the latest reading
+	LatestValue float64   `db:"sensor_value"` // of the latest reading
+	FirstTS     time.Time `db:"first_ts"`
+	LastTS      time.Time `db:"last_ts"`
+	Count       int64     `db:"cnt"`
+	RecentCount int64     `db:"recent"` // readings at or after the query's since
+}
+
+// Row of the sensors registry, keyed by (id1, id2)
+type Sensor struct {
+	ID1          string    `db:"id1"`
+	ID2          int       `db:"id2"`
+	Name         string    `db:"name"`
+	Unit         string    `db:"unit"`
+	Location     string    `db:"location"`
+	Owner        string    `db:"owner"`
+	ExpectedRate float64   `db:"expected_rate"` // readings per second, 0 when unknown
+	Enabled      bool      `db:"enabled"`
+	Labels       Labels    `db:"labels"`
+	CreatedAt    time.Time `db:"created_at"`
+	UpdatedAt    time.Time `db:"updated_at"`
+}
+
+// Row of the sensor type catalog, keyed by its canonical name
+type SensorType struct {
+	Name        string    `db:"name"`
+	Unit        string    `db:"unit"`
+	MinValue    *float64  `db:"min_value"` // nil leaves the range open below
+	MaxValue    *float64  `db:"max_value"` // nil leaves the range open above
+	RangePolicy string    `db:"range_policy"`
+	Aliases     []string  `db:"-"` // lowercase, other than the name itself
+	CreatedAt   time.Time `db:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at"`
 }
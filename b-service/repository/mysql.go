@@ -0,0 +1,251 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	model "github.com/Yusufzhafir/worlder-team-assignment/b-service/repository/model"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jmoiron/sqlx"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+)
+
+// MySQLRepositoryImpl is the MySQL backend, upserting through ON DUPLICATE KEY UPDATE.
+type MySQLRepositoryImpl struct {
+	sqlReadings
+}
+
+func NewMySQLRepository(db *sqlx.DB) SensorRepository {
+	return &MySQLRepositoryImpl{sqlReadings{db: db, bucketSQL: mysqlBucketSQL, system: semconv.DBSystemNameMySQL}}
+}
+
+const insertReadingSQL = `
+INSERT INTO sensor_readings (sensor_value, sensor_type, id1, id2, ts, reading_key, principal, out_of_range)
+VALUES (:sensor_value, :sensor_type, :id1, :id2, :ts, :reading_key, :principal, :out_of_range)
+`
+
+// LAST_INSERT_ID(reading_id) makes LastInsertId report the existing row on a conflict,
+// and since the row is left unchanged RowsAffected is 0 instead of 1.
+const upsertKeepFirstSQL = insertReadingSQL + `ON DUPLICATE KEY UPDATE reading_id = LAST_INSERT_ID(reading_id)
+`
+
+// RowsAffected is 2 when the stored row changed and 0 when the values were identical.
+const upsertKeepLastSQL = insertReadingSQL + `AS incoming
+ON DUPLICATE KEY UPDATE
+  reading_id = LAST_INSERT_ID(reading_id),
+  sensor_value = incoming.sensor_value,
+  sensor_type = incoming.sensor_type,
+  id1 = incoming.id1,
+  id2 = incoming.id2,
+  ts = incoming.ts,
+  principal = incoming.principal,
+  out_of_range = incoming.out_of_range
+`
+
+const selectIDsByKeysSQL = `
+SELECT reading_key, reading_id
+FROM sensor_readings
+WHERE reading_key IN (?)
+`
+
+// upsertSQL picks the statement for keyed rows. Reject behaves like keep_first
+// in SQL, callers are the ones refusing the duplicate.
+func upsertSQL(policy ConflictPolicy) string {
+	if policy == ConflictKeepLast {
+		return upsertKeepLastSQL
+	}
+	return upsertKeepFirstSQL
+}
+
+func isDuplicateKey(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == 1062
+}
+
+// mysqlUnavailable is IsUnavailable for MySQL errors, ok is false for any other error.
+func mysqlUnavailable(err error) (unavailable, ok bool) {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		switch mysqlErr.Number {
+		case 1040, 1053, 1205, 1213, 1290, 1836: // too many connections, shutdown, lock wait timeout, deadlock, read only
+			return true, true
+		}
+		return false, true
+	}
+	if errors.Is(err, mysql.ErrInvalidConn) {
+		return true, true
+	}
+	return false, false
+}
+
+type keyedID struct {
+	ReadingKey string `db:"reading_key"`
+	ReadingID  uint64 `db:"reading_id"`
+}
+
+func selectIDsByKeys(ctx context.Context, tx *sqlx.Tx, keys []string) (map[string]uint64, error) {
+	query, args, err := sqlx.In(selectIDsByKeysSQL, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []keyedID
+	if err := tx.SelectContext(ctx, &rows, tx.Rebind(query), args...); err != nil {
+		return nil, err
+	}
+
+	ids := make(map[string]uint64, len(rows))
+	for _, row := range rows {
+		ids[row.ReadingKey] = row.ReadingID
+	}
+	return ids, nil
+}
+
+func (sensorRepo *MySQLRepositoryImpl) InsertReadingTx(ctx context.Context, r *model.SensorReadingInsert, policy ConflictPolicy) (model.InsertOutcome, error) {
+	tx, err := beginTx(ctx, sensorRepo.db, semconv.DBSystemNameMySQL)
+	if err != nil {
+		return model.InsertOutcome{}, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	query := insertReadingSQL
+	if r.ReadingKey != nil && policy != ConflictReject {
+		query = upsertSQL(policy)
+	}
+
+	res, err := tx.NamedExecContext(ctx, query, r)
+	if err != nil {
+		if r.ReadingKey == nil || !isDuplicateKey(err) {
+			return model.InsertOutcome{}, err
+		}
+		// reject policy: nothing was written, report the row that holds the key
+		var existing map[string]uint64
+		existing, err = selectIDsByKeys(ctx, tx, []string{*r.ReadingKey})
+		if err != nil {
+			return model.InsertOutcome{}, err
+		}
+		if err = commitTx(ctx, tx, semconv.DBSystemNameMySQL); err != nil {
+			return model.InsertOutcome{}, err
+		}
+		return model.InsertOutcome{ReadingID: existing[*r.ReadingKey], Duplicate: true}, nil
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return model.InsertOutcome{}, err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return model.InsertOutcome{}, err
+	}
+
+	if err = commitTx(ctx, tx, semconv.DBSystemNameMySQL); err != nil {
+		return model.InsertOutcome{}, err
+	}
+	return model.InsertOutcome{ReadingID: uint64(id), Duplicate: affected != 1}, nil
+}
+
+// maxRowsPerInsert keeps a multi-row INSERT well under MySQL's 65535 placeholder limit.
+const maxRowsPerInsert = 1000
+
+// InsertReadingsBatchTx writes rs in one transaction and returns an outcome per row
+// in input order. Rows without a reading_key go through a plain multi-row INSERT,
+// which is a "simple insert" for InnoDB, so its auto-increment values are
+// consecutive starting at LastInsertId. Keyed rows are checked against what is
+// already stored, upserted according to policy, then their ids are read back.
+// reading_key values are expected to be unique within rs.
+func (sensorRepo *MySQLRepositoryImpl) InsertReadingsBatchTx(ctx context.Context, rs []model.SensorReadingInsert, policy ConflictPolicy) ([]model.InsertOutcome, error) {
+	outcomes := make([]model.InsertOutcome, len(rs))
+	if len(rs) == 0 {
+		return outcomes, nil
+	}
+
+	tx, err := beginTx(ctx, sensorRepo.db, semconv.DBSystemNameMySQL)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	plain := make([]model.SensorReadingInsert, 0, len(rs))
+	plainIndex := make([]int, 0, len(rs))
+	keys := make([]string, 0)
+	keyedIndex := make([]int, 0)
+	for i := range rs {
+		if rs[i].ReadingKey == nil {
+			plain = append(plain, rs[i])
+			plainIndex = append(plainIndex, i)
+			continue
+		}
+		keys = append(keys, *rs[i].ReadingKey)
+		keyedIndex = append(keyedIndex, i)
+	}
+
+	for start := 0; start < len(plain); start += maxRowsPerInsert {
+		end := min(start+maxRowsPerInsert, len(plain))
+
+		// sqlx expands the VALUES tuple once per element of the slice
+		res, execErr := tx.NamedExecContext(ctx, insertReadingSQL, plain[start:end])
+		if execErr != nil {
+			err = execErr
+			return nil, err
+		}
+		firstID, idErr := res.LastInsertId()
+		if idErr != nil {
+			err = idErr
+			return nil, err
+		}
+		for i := start; i < end; i++ {
+			outcomes[plainIndex[i]].ReadingID = uint64(firstID) + uint64(i-start)
+		}
+	}
+
+	if len(keyedIndex) > 0 {
+		var existing map[string]uint64
+		existing, err = selectIDsByKeys(ctx, tx, keys)
+		if err != nil {
+			return nil, err
+		}
+
+		toWrite := make([]model.SensorReadingInsert, 0, len(keyedIndex))
+		for _, i := range keyedIndex {
+			if _, ok := existing[*rs[i].ReadingKey]; ok {
+				outcomes[i].Duplicate = true
+				if policy != ConflictKeepLast {
+					continue
+				}
+			}
+			toWrite = append(toWrite, rs[i])
+		}
+
+		// still an upsert, a concurrent batch may have stored the same key since the select
+		for start := 0; start < len(toWrite); start += maxRowsPerInsert {
+			end := min(start+maxRowsPerInsert, len(toWrite))
+			if _, err = tx.NamedExecContext(ctx, upsertSQL(policy), toWrite[start:end]); err != nil {
+				return nil, err
+			}
+		}
+
+		var stored map[string]uint64
+		stored, err = selectIDsByKeys(ctx, tx, keys)
+		if err != nil {
+			return nil, err
+		}
+		for _, i := range keyedIndex {
+			outcomes[i].ReadingID = stored[*rs[i].ReadingKey]
+		}
+	}
+
+	if err = commitTx(ctx, tx, semconv.DBSystemNameMySQL); err != nil {
+		return nil, err
+	}
+	return outcomes, nil
+}
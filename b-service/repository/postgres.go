@@ -0,0 +1,282 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	model "github.com/Yusufzhafir/worlder-team-assignment/b-service/repository/model"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+)
+
+// PostgresRepositoryImpl is the PostgreSQL backend, for a plain or a TimescaleDB
+// sensor_readings (see migrate.CreateHypertable). Reads and updates are the
+// shared queries with $n placeholders, batches are written with COPY.
+type PostgresRepositoryImpl struct {
+	sqlReadings
+}
+
+// NewPostgresRepository expects db opened with the lib/pq driver, named "postgres".
+func NewPostgresRepository(db *sqlx.DB) SensorRepository {
+	return &PostgresRepositoryImpl{sqlReadings{db: db, bucketSQL: postgresBucketSQL, system: semconv.DBSystemNamePostgreSQL}}
+}
+
+// Every statement returns the row's id and whether it inserted it. ON CONFLICT
+// names uk_reading_key rather than its columns, which a hypertable widens to
+// (reading_key, ts).
+const pgInsertReadingSQL = insertReadingSQL + `RETURNING reading_id, TRUE AS inserted
+`
+
+const pgUpsertKeepFirstSQL = insertReadingSQL + `ON CONFLICT ON CONSTRAINT uk_reading_key DO NOTHING
+RETURNING reading_id, TRUE AS inserted
+`
+
+// xmax is 0 on a row version this statement inserted, and set on one it updated.
+const pgUpsertKeepLastSQL = insertReadingSQL + `ON CONFLICT ON CONSTRAINT uk_reading_key DO UPDATE SET
+  sensor_value = EXCLUDED.sensor_value,
+  sensor_type = EXCLUDED.sensor_type,
+  id1 = EXCLUDED.id1,
+  id2 = EXCLUDED.id2,
+  ts = EXCLUDED.ts,
+  principal = EXCLUDED.principal,
+  out_of_range = EXCLUDED.out_of_range
+RETURNING reading_id, xmax = 0 AS inserted
+`
+
+// COPY cannot report ids, so a batch takes them from the identity sequence first.
+const pgNextIDsSQL = `
+SELECT nextval(pg_get_serial_sequence('sensor_readings', 'reading_id'))
+FROM generate_series(1, $1)
+`
+
+// Keyed rows are copied to a staging table and moved over with one INSERT,
+// which can do what COPY cannot: skip or update conflicting keys.
+const pgCreateIncomingSQL = `
+CREATE TEMPORARY TABLE sensor_readings_incoming (LIKE sensor_readings INCLUDING DEFAULTS) ON COMMIT DROP
+`
+
+const pgMoveIncomingSQL = `
+INSERT INTO sensor_readings (reading_id, sensor_value, sensor_type, id1, id2, ts, reading_key, principal, out_of_range)
+SELECT reading_id, sensor_value, sensor_type, id1, id2, ts, reading_key, principal, out_of_range
+FROM sensor_readings_incoming
+`
+
+var pgCopyColumns = []string{"reading_id", "sensor_value", "sensor_type", "id1", "id2", "ts", "reading_key", "principal", "out_of_range"}
+
+// pgMoveIncoming picks the statement moving staged rows, reject behaves like
+// keep_first in SQL as it does for MySQL.
+func pgMoveIncoming(policy ConflictPolicy) string {
+	if policy == ConflictKeepLast {
+		return pgMoveIncomingSQL + `ON CONFLICT ON CONSTRAINT uk_reading_key DO UPDATE SET
+  sensor_value = EXCLUDED.sensor_value,
+  sensor_type = EXCLUDED.sensor_type,
+  id1 = EXCLUDED.id1,
+  id2 = EXCLUDED.id2,
+  ts = EXCLUDED.ts,
+  principal = EXCLUDED.principal,
+  out_of_range = EXCLUDED.out_of_range
+`
+	}
+	return pgMoveIncomingSQL + `ON CONFLICT ON CONSTRAINT uk_reading_key DO NOTHING
+`
+}
+
+// postgresUnavailable is IsUnavailable for Postgres errors, ok is false for any other error.
+func postgresUnavailable(err error) (unavailable, ok bool) {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return false, false
+	}
+	switch pqErr.Code.Class() {
+	case "08", "53", "57": // connection exception, insufficient resources, operator intervention
+		return true, true
+	}
+	switch pqErr.Code {
+	case "40001", "40P01", "55P03", "25006": // serialization failure, deadlock, lock not available, read only
+		return true, true
+	}
+	return false, true
+}
+
+func postgresUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
+
+type pgInserted struct {
+	ReadingID uint64 `db:"reading_id"`
+	Inserted  bool   `db:"inserted"`
+}
+
+func (sensorRepo *PostgresRepositoryImpl) InsertReadingTx(ctx context.Context, r *model.SensorReadingInsert, policy ConflictPolicy) (model.InsertOutcome, error) {
+	tx, err := beginTx(ctx, sensorRepo.db, semconv.DBSystemNamePostgreSQL)
+	if err != nil {
+		return model.InsertOutcome{}, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	query := pgInsertReadingSQL
+	if r.ReadingKey != nil {
+		query = pgUpsertKeepFirstSQL
+		if policy == ConflictKeepLast {
+			query = pgUpsertKeepLastSQL
+		}
+	}
+
+	var row pgInserted
+	var rows *sqlx.Rows
+	rows, err = sqlx.NamedQueryContext(ctx, tx, query, r)
+	if err != nil {
+		return model.InsertOutcome{}, err
+	}
+	found := rows.Next()
+	if found {
+		err = rows.StructScan(&row)
+	}
+	if closeErr := rows.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = rows.Err()
+	}
+	if err != nil {
+		return model.InsertOutcome{}, err
+	}
+
+	if !found {
+		// DO NOTHING returns no row, report the one that holds the key
+		var existing map[string]uint64
+		existing, err = selectIDsByKeys(ctx, tx, []string{*r.ReadingKey})
+		if err != nil {
+			return model.InsertOutcome{}, err
+		}
+		row = pgInserted{ReadingID: existing[*r.ReadingKey]}
+	}
+
+	if err = commitTx(ctx, tx, semconv.DBSystemNamePostgreSQL); err != nil {
+		return model.InsertOutcome{}, err
+	}
+	return model.InsertOutcome{ReadingID: row.ReadingID, Duplicate: !row.Inserted}, nil
+}
+
+// InsertReadingsBatchTx writes rs in one transaction and returns an outcome per
+// row in input order. ids are taken from the sequence up front. Without keyed
+// rows the batch is one COPY into sensor_readings; otherwise the stored keys
+// are looked up, the rows still to write are copied to a staging table and
+// upserted from there according to policy, and the keyed ids are read back.
+// reading_key values are expected to be unique within rs.
+func (sensorRepo *PostgresRepositoryImpl) InsertReadingsBatchTx(ctx context.Context, rs []model.SensorReadingInsert, policy ConflictPolicy) ([]model.InsertOutcome, error) {
+	outcomes := make([]model.InsertOutcome, len(rs))
+	if len(rs) == 0 {
+		return outcomes, nil
+	}
+
+	tx, err := beginTx(ctx, sensorRepo.db, semconv.DBSystemNamePostgreSQL)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	var ids []uint64
+	if err = tx.SelectContext(ctx, &ids, pgNextIDsSQL, len(rs)); err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0)
+	keyedIndex := make([]int, 0)
+	for i := range rs {
+		if rs[i].ReadingKey != nil {
+			keys = append(keys, *rs[i].ReadingKey)
+			keyedIndex = append(keyedIndex, i)
+		}
+	}
+
+	if len(keyedIndex) == 0 {
+		if err = pgCopy(ctx, tx, "sensor_readings", rs, ids, nil); err != nil {
+			return nil, err
+		}
+		for i := range rs {
+			outcomes[i].ReadingID = ids[i]
+		}
+		if err = commitTx(ctx, tx, semconv.DBSystemNamePostgreSQL); err != nil {
+			return nil, err
+		}
+		return outcomes, nil
+	}
+
+	var existing map[string]uint64
+	existing, err = selectIDsByKeys(ctx, tx, keys)
+	if err != nil {
+		return nil, err
+	}
+	skip := make([]bool, len(rs))
+	for _, i := range keyedIndex {
+		if _, ok := existing[*rs[i].ReadingKey]; ok {
+			outcomes[i].Duplicate = true
+			skip[i] = policy != ConflictKeepLast
+		}
+	}
+
+	if _, err = tx.ExecContext(ctx, pgCreateIncomingSQL); err != nil {
+		return nil, err
+	}
+	if err = pgCopy(ctx, tx, "sensor_readings_incoming", rs, ids, skip); err != nil {
+		return nil, err
+	}
+	// still an upsert, a concurrent batch may have stored the same key since the select
+	if _, err = tx.ExecContext(ctx, pgMoveIncoming(policy)); err != nil {
+		return nil, err
+	}
+
+	var stored map[string]uint64
+	stored, err = selectIDsByKeys(ctx, tx, keys)
+	if err != nil {
+		return nil, err
+	}
+	for i := range rs {
+		if rs[i].ReadingKey != nil {
+			outcomes[i].ReadingID = stored[*rs[i].ReadingKey]
+		} else {
+			outcomes[i].ReadingID = ids[i]
+		}
+	}
+
+	if err = commitTx(ctx, tx, semconv.DBSystemNamePostgreSQL); err != nil {
+		return nil, err
+	}
+	return outcomes, nil
+}
+
+// pgCopy streams rs into table with COPY FROM STDIN, row i with id ids[i],
+// leaving out the rows skip marks.
+func pgCopy(ctx context.Context, tx *sqlx.Tx, table string, rs []model.SensorReadingInsert, ids []uint64, skip []bool) error {
+	stmt, err := tx.PrepareContext(ctx, pq.CopyIn(table, pgCopyColumns...))
+	if err != nil {
+		return err
+	}
+	for i, r := range rs {
+		if skip != nil && skip[i] {
+			continue
+		}
+		if _, err := stmt.ExecContext(ctx, int64(ids[i]), r.SensorValue, r.SensorType, r.ID1, r.ID2, r.TS, r.ReadingKey, r.Principal, r.OutOfRange); err != nil {
+			_ = stmt.Close()
+			return err
+		}
+	}
+	// an Exec without arguments ends the COPY
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		_ = stmt.Close()
+		return err
+	}
+	return stmt.Close()
+}
@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	model "github.com/Yusufzhafir/worlder-team-assignment/b-service/repository/model"
+)
+
+// ErrAlreadyExists is returned when a row with the same key is already stored.
+var ErrAlreadyExists = errors.New("already exists")
+
+// isUniqueViolation reports whether err is a primary or unique key conflict on any backend.
+func isUniqueViolation(err error) bool {
+	return isDuplicateKey(err) || postgresUniqueViolation(err) || sqliteUniqueViolation(err)
+}
+
+const sensorColumns = `id1, id2, name, unit, location, owner, expected_rate, enabled, labels, created_at, updated_at`
+
+const insertSensorSQL = `
+INSERT INTO sensors (` + sensorColumns + `)
+VALUES (:id1, :id2, :name, :unit, :location, :owner, :expected_rate, :enabled, :labels, :created_at, :updated_at)
+`
+
+const selectSensorSQL = `
+SELECT ` + sensorColumns + `
+FROM sensors
+WHERE id1 = ? AND id2 = ?
+`
+
+const selectRegisteredSensorsSQL = `
+SELECT ` + sensorColumns + `
+FROM sensors
+ORDER BY id1, id2
+LIMIT ? OFFSET ?
+`
+
+const selectCountRegisteredSensorsSQL = `
+SELECT COUNT(*) AS cnt
+FROM sensors
+`
+
+// created_at is left as it was registered.
+const updateSensorSQL = `
+UPDATE sensors
+SET name = :name, unit = :unit, location = :location, owner = :owner,
+  expected_rate = :expected_rate, enabled = :enabled, labels = :labels, updated_at = :updated_at
+WHERE id1 = :id1 AND id2 = :id2
+`
+
+const deleteSensorSQL = `DELETE FROM sensors WHERE id1 = ? AND id2 = ?`
+
+// InsertSensor registers s, or returns ErrAlreadyExists when its id1/id2 is taken.
+func (repo *sqlReadings) InsertSensor(ctx context.Context, s *model.Sensor) error {
+	row := *s
+	row.CreatedAt = row.CreatedAt.UTC()
+	row.UpdatedAt = row.UpdatedAt.UTC()
+	_, err := repo.db.NamedExecContext(ctx, insertSensorSQL, row)
+	if err != nil && isUniqueViolation(err) {
+		return ErrAlreadyExists
+	}
+	return err
+}
+
+// SelectSensor returns the registered sensor id, or ErrNotFound.
+func (repo *sqlReadings) SelectSensor(ctx context.Context, id IDCombination) (model.Sensor, error) {
+	var sensor model.Sensor
+	err := repo.db.GetContext(ctx, &sensor, repo.db.Rebind(selectSensorSQL), id.ID1, id.ID2)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.Sensor{}, ErrNotFound
+	}
+	return sensor, err
+}
+
+// SelectSensorsByIDs returns the registered sensors among ids, unregistered ones are left out.
+func (repo *sqlReadings) SelectSensorsByIDs(ctx context.Context, ids []IDCombination) ([]model.Sensor, error) {
+	if len(ids) == 0 {
+		return []model.Sensor{}, nil
+	}
+
+	idCondition, args := buildIDCondition(ids)
+	query := fmt.Sprintf(`
+SELECT %s
+FROM sensors
+WHERE %s
+ORDER BY id1, id2
+`, sensorColumns, idCondition)
+
+	var sensors []model.Sensor
+	if err := repo.db.SelectContext(ctx, &sensors, repo.db.Rebind(query), args...); err != nil {
+		return nil, err
+	}
+	return sensors, nil
+}
+
+// SelectRegisteredSensors pages through the registry in id order.
+func (repo *sqlReadings) SelectRegisteredSensors(ctx context.Context, limit, offset int) ([]model.Sensor, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	var sensors []model.Sensor
+	if err := repo.db.SelectContext(ctx, &sensors, repo.db.Rebind(selectRegisteredSensorsSQL), limit, offset); err != nil {
+		return nil, err
+	}
+	return sensors, nil
+}
+
+func (repo *sqlReadings) SelectCountRegisteredSensors(ctx context.Context) (int64, error) {
+	var result CountResult
+	if err := repo.db.GetContext(ctx, &result, selectCountRegisteredSensorsSQL); err != nil {
+		return 0, err
+	}
+	return result.Cnt, nil
+}
+
+// UpdateSensor replaces everything but the ids and created_at of the registered
+// sensor s, or returns ErrNotFound.
+func (repo *sqlReadings) UpdateSensor(ctx context.Context, s *model.Sensor) error {
+	row := *s
+	row.UpdatedAt = row.UpdatedAt.UTC()
+	res, err := repo.db.NamedExecContext(ctx, updateSensorSQL, row)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		// MySQL counts a row whose values did not change as unaffected
+		_, err = repo.SelectSensor(ctx, IDCombination{ID1: s.ID1, ID2: s.ID2})
+		return err
+	}
+	return nil
+}
+
+// DeleteSensor removes id from the registry, its readings are kept. Returns
+// ErrNotFound when id is not registered.
+func (repo *sqlReadings) DeleteSensor(ctx context.Context, id IDCombination) error {
+	res, err := repo.db.ExecContext(ctx, repo.db.Rebind(deleteSensorSQL), id.ID1, id.ID2)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
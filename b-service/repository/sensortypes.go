@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"context"
+	"strings"
+
+	model "github.com/Yusufzhafir/worlder-team-assignment/b-service/repository/model"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const sensorTypeColumns = `name, unit, min_value, max_value, range_policy, created_at, updated_at`
+
+const selectSensorTypesSQL = `
+SELECT ` + sensorTypeColumns + `
+FROM sensor_types
+ORDER BY name
+`
+
+const selectSensorTypeAliasesSQL = `
+SELECT alias, name
+FROM sensor_type_aliases
+ORDER BY name, alias
+`
+
+const insertSensorTypeSQL = `
+INSERT INTO sensor_types (` + sensorTypeColumns + `)
+VALUES (:name, :unit, :min_value, :max_value, :range_policy, :created_at, :updated_at)
+`
+
+// created_at is left as it was created.
+const updateSensorTypeSQL = `
+UPDATE sensor_types
+SET unit = :unit, min_value = :min_value, max_value = :max_value, range_policy = :range_policy, updated_at = :updated_at
+WHERE name = :name
+`
+
+const selectCountSensorTypeSQL = `SELECT COUNT(*) AS cnt FROM sensor_types WHERE name = ?`
+
+const insertSensorTypeAliasSQL = `INSERT INTO sensor_type_aliases (alias, name) VALUES (?, ?)`
+
+const deleteSensorTypeAliasesSQL = `DELETE FROM sensor_type_aliases WHERE name = ?`
+
+const deleteSensorTypeSQL = `DELETE FROM sensor_types WHERE name = ?`
+
+type sensorTypeAlias struct {
+	Alias string `db:"alias"`
+	Name  string `db:"name"`
+}
+
+// SelectSensorTypes returns the whole catalog ordered by name, each type with its aliases.
+func (repo *sqlReadings) SelectSensorTypes(ctx context.Context) ([]model.SensorType, error) {
+	types := []model.SensorType{}
+	if err := repo.db.SelectContext(ctx, &types, selectSensorTypesSQL); err != nil {
+		return nil, err
+	}
+	var aliases []sensorTypeAlias
+	if err := repo.db.SelectContext(ctx, &aliases, selectSensorTypeAliasesSQL); err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]*model.SensorType, len(types))
+	for i := range types {
+		types[i].Aliases = []string{}
+		byName[types[i].Name] = &types[i]
+	}
+	for _, alias := range aliases {
+		// the lowercased name is stored as an alias too, to keep it unique
+		if t, ok := byName[alias.Name]; ok && alias.Alias != strings.ToLower(alias.Name) {
+			t.Aliases = append(t.Aliases, alias.Alias)
+		}
+	}
+	return types, nil
+}
+
+// InsertSensorType adds t to the catalog, or returns ErrAlreadyExists when its
+// name or one of its aliases is taken by any type.
+func (repo *sqlReadings) InsertSensorType(ctx context.Context, t *model.SensorType) error {
+	tx, err := beginTx(ctx, repo.db, repo.system)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	row := *t
+	row.CreatedAt = row.CreatedAt.UTC()
+	row.UpdatedAt = row.UpdatedAt.UTC()
+	if _, err = tx.NamedExecContext(ctx, insertSensorTypeSQL, row); err != nil {
+		return sensorTypeConflict(err)
+	}
+	if err = insertSensorTypeAliases(ctx, tx, t); err != nil {
+		return err
+	}
+	return commitTx(ctx, tx, repo.system)
+}
+
+// UpdateSensorType replaces everything but the name and created_at of t, its
+// aliases included. Returns ErrNotFound when t is not in the catalog, or
+// ErrAlreadyExists when one of its aliases belongs to another type.
+func (repo *sqlReadings) UpdateSensorType(ctx context.Context, t *model.SensorType) error {
+	tx, err := beginTx(ctx, repo.db, repo.system)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	row := *t
+	row.UpdatedAt = row.UpdatedAt.UTC()
+	if _, err = tx.NamedExecContext(ctx, updateSensorTypeSQL, row); err != nil {
+		return err
+	}
+	// MySQL counts a row whose values did not change as unaffected, so count it instead
+	var found CountResult
+	if err = tx.GetContext(ctx, &found, tx.Rebind(selectCountSensorTypeSQL), t.Name); err != nil {
+		return err
+	}
+	if found.Cnt == 0 {
+		err = ErrNotFound
+		return err
+	}
+	if _, err = tx.ExecContext(ctx, tx.Rebind(deleteSensorTypeAliasesSQL), t.Name); err != nil {
+		return err
+	}
+	if err = insertSensorTypeAliases(ctx, tx, t); err != nil {
+		return err
+	}
+	return commitTx(ctx, tx, repo.system)
+}
+
+// DeleteSensorType removes name and its aliases from the catalog, or returns
+// ErrNotFound. Readings already stored under it are kept.
+func (repo *sqlReadings) DeleteSensorType(ctx context.Context, name string) error {
+	tx, err := beginTx(ctx, repo.db, repo.system)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	if _, err = tx.ExecContext(ctx, tx.Rebind(deleteSensorTypeAliasesSQL), name); err != nil {
+		return err
+	}
+	res, err := tx.ExecContext(ctx, tx.Rebind(deleteSensorTypeSQL), name)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		err = ErrNotFound
+		return err
+	}
+	return commitTx(ctx, tx, repo.system)
+}
+
+// insertSensorTypeAliases stores the lowercased name of t and its aliases.
+func insertSensorTypeAliases(ctx context.Context, tx *sqlx.Tx, t *model.SensorType) error {
+	query := tx.Rebind(insertSensorTypeAliasSQL)
+	for _, alias := range append([]string{strings.ToLower(t.Name)}, t.Aliases...) {
+		if _, err := tx.ExecContext(ctx, query, alias, t.Name); err != nil {
+			return sensorTypeConflict(err)
+		}
+	}
+	return nil
+}
+
+func sensorTypeConflict(err error) error {
+	if isUniqueViolation(err) {
+		return ErrAlreadyExists
+	}
+	return err
+}
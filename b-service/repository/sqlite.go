@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"net/url"
+
+	model "github.com/Yusufzhafir/worlder-team-assignment/b-service/repository/model"
+
+	"github.com/jmoiron/sqlx"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+)
+
+// SQLiteRepositoryImpl is the SQLite backend, for a single node or local runs.
+// Its connections open every transaction with BEGIN IMMEDIATE (see SQLiteDSN),
+// so looking a reading_key up and then writing it cannot race another writer.
+type SQLiteRepositoryImpl struct {
+	sqlReadings
+}
+
+// NewSQLiteRepository expects db opened with the sqlite3 driver on SQLiteDSN.
+func NewSQLiteRepository(db *sqlx.DB) SensorRepository {
+	return &SQLiteRepositoryImpl{sqlReadings{db: db, bucketSQL: sqliteBucketSQL, system: semconv.DBSystemNameSQLite}}
+}
+
+// SQLiteDSN is the sqlite3 DSN of the database file at path. Writers queue on
+// the file lock for up to 5s instead of failing straight away, and WAL lets
+// readers carry on while they do. Times are read back in the local zone, as
+// from MySQL.
+func SQLiteDSN(path string) string {
+	params := url.Values{}
+	params.Set("_txlock", "immediate")
+	params.Set("_busy_timeout", "5000")
+	params.Set("_journal_mode", "WAL")
+	params.Set("_loc", "auto")
+	return "file:" + path + "?" + params.Encode()
+}
+
+const sqliteUpdateByKeySQL = `
+UPDATE sensor_readings
+SET sensor_value = :sensor_value, sensor_type = :sensor_type, id1 = :id1, id2 = :id2, ts = :ts, principal = :principal, out_of_range = :out_of_range
+WHERE reading_key = :reading_key
+`
+
+func (sensorRepo *SQLiteRepositoryImpl) InsertReadingTx(ctx context.Context, r *model.SensorReadingInsert, policy ConflictPolicy) (model.InsertOutcome, error) {
+	outcomes, err := sensorRepo.InsertReadingsBatchTx(ctx, []model.SensorReadingInsert{*r}, policy)
+	if err != nil {
+		return model.InsertOutcome{}, err
+	}
+	return outcomes[0], nil
+}
+
+// InsertReadingsBatchTx writes rs in one transaction and returns an outcome per
+// row in input order. SQLite has a single writer, so the stored keys are looked
+// up first and every row then goes through one prepared statement, which is
+// as fast as a multi-row INSERT here and gives each row its own LastInsertId.
+func (sensorRepo *SQLiteRepositoryImpl) InsertReadingsBatchTx(ctx context.Context, rs []model.SensorReadingInsert, policy ConflictPolicy) ([]model.InsertOutcome, error) {
+	outcomes := make([]model.InsertOutcome, len(rs))
+	if len(rs) == 0 {
+		return outcomes, nil
+	}
+
+	tx, err := beginTx(ctx, sensorRepo.db, semconv.DBSystemNameSQLite)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	keys := make([]string, 0)
+	for i := range rs {
+		if rs[i].ReadingKey != nil {
+			keys = append(keys, *rs[i].ReadingKey)
+		}
+	}
+	existing := map[string]uint64{}
+	if len(keys) > 0 {
+		existing, err = selectIDsByKeys(ctx, tx, keys)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	insert, err := tx.PrepareNamedContext(ctx, insertReadingSQL)
+	if err != nil {
+		return nil, err
+	}
+	defer insert.Close()
+
+	for i := range rs {
+		row := rs[i]
+		row.TS = row.TS.UTC()
+		if row.ReadingKey != nil {
+			if id, ok := existing[*row.ReadingKey]; ok {
+				outcomes[i] = model.InsertOutcome{ReadingID: id, Duplicate: true}
+				if policy == ConflictKeepLast {
+					if _, err = tx.NamedExecContext(ctx, sqliteUpdateByKeySQL, row); err != nil {
+						return nil, err
+					}
+				}
+				continue
+			}
+		}
+
+		res, execErr := insert.ExecContext(ctx, row)
+		if execErr != nil {
+			err = execErr
+			return nil, err
+		}
+		id, idErr := res.LastInsertId()
+		if idErr != nil {
+			err = idErr
+			return nil, err
+		}
+		outcomes[i].ReadingID = uint64(id)
+		if row.ReadingKey != nil {
+			// a later row of rs may repeat the key
+			existing[*row.ReadingKey] = uint64(id)
+		}
+	}
+
+	if err = commitTx(ctx, tx, semconv.DBSystemNameSQLite); err != nil {
+		return nil, err
+	}
+	return outcomes, nil
+}
@@ -0,0 +1,31 @@
+//go:build cgo
+
+package repository
+
+import (
+	"errors"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// SQLiteAvailable reports whether this binary carries the sqlite3 driver, which needs cgo.
+const SQLiteAvailable = true
+
+// sqliteUnavailable is IsUnavailable for SQLite errors, ok is false for any other error.
+func sqliteUnavailable(err error) (unavailable, ok bool) {
+	var sqliteErr sqlite3.Error
+	if !errors.As(err, &sqliteErr) {
+		return false, false
+	}
+	switch sqliteErr.Code {
+	case sqlite3.ErrBusy, sqlite3.ErrLocked, sqlite3.ErrIoErr, sqlite3.ErrFull, sqlite3.ErrReadonly: // still locked after busy_timeout, disk trouble
+		return true, true
+	}
+	return false, true
+}
+
+func sqliteUniqueViolation(err error) bool {
+	var sqliteErr sqlite3.Error
+	return errors.As(err, &sqliteErr) &&
+		(sqliteErr.ExtendedCode == sqlite3.ErrConstraintPrimaryKey || sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique)
+}
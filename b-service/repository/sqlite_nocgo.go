@@ -0,0 +1,15 @@
+//go:build !cgo
+
+package repository
+
+// SQLiteAvailable reports whether this binary carries the sqlite3 driver, which needs cgo.
+const SQLiteAvailable = false
+
+// sqliteUnavailable never matches without cgo, the sqlite3 driver is only a stub then.
+func sqliteUnavailable(error) (unavailable, ok bool) {
+	return false, false
+}
+
+func sqliteUniqueViolation(error) bool {
+	return false
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	model "github.com/Yusufzhafir/worlder-team-assignment/b-service/repository/model"
+)
+
+// ErrNotFound is returned when the row asked for does not exist.
+var ErrNotFound = errors.New("not found")
+
+// The page of sensors is counted in one pass over idx_ids_ts, then the first and
+// latest reading of each are single index lookups. Joining the rows rather than
+// selecting MIN(ts) keeps ts a column, which SQLite only returns as a time then.
+const selectSensorSummariesSQL = `
+SELECT s.id1, s.id2, s.cnt, s.recent, f.ts AS first_ts, l.ts AS last_ts, l.sensor_type, l.sensor_value
+FROM (
+  SELECT id1, id2, COUNT(*) AS cnt, SUM(CASE WHEN ts >= ? THEN 1 ELSE 0 END) AS recent
+  FROM sensor_readings
+  GROUP BY id1, id2
+  ORDER BY id1, id2
+  LIMIT ? OFFSET ?
+) s
+JOIN sensor_readings f ON f.reading_id = (
+  SELECT reading_id FROM sensor_readings WHERE id1 = s.id1 AND id2 = s.id2 ORDER BY ts, reading_id LIMIT 1
+)
+JOIN sensor_readings l ON l.reading_id = (
+  SELECT reading_id FROM sensor_readings WHERE id1 = s.id1 AND id2 = s.id2 ORDER BY ts DESC, reading_id DESC LIMIT 1
+)
+ORDER BY s.id1, s.id2
+`
+
+const selectCountSensorsSQL = `
+SELECT COUNT(*) AS cnt
+FROM (SELECT DISTINCT id1, id2 FROM sensor_readings) sensors
+`
+
+const selectLatestReadingSQL = `
+SELECT reading_id, sensor_value, sensor_type, id1, id2, ts, out_of_range, created_at
+FROM sensor_readings
+WHERE id1 = ? AND id2 = ?
+ORDER BY ts DESC, reading_id DESC
+LIMIT 1
+`
+
+// SelectSensorSummaries pages through the distinct (id1, id2) of sensor_readings
+// in id order. RecentCount counts the readings with ts >= since.
+func (repo *sqlReadings) SelectSensorSummaries(ctx context.Context, since time.Time, limit, offset int) ([]model.SensorSummary, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	var summaries []model.SensorSummary
+	if err := repo.db.SelectContext(ctx, &summaries, repo.db.Rebind(selectSensorSummariesSQL), since.UTC(), limit, offset); err != nil {
+		return nil, err
+	}
+	return summaries, nil
+}
+
+func (repo *sqlReadings) SelectCountSensors(ctx context.Context) (int64, error) {
+	var result CountResult
+	if err := repo.db.GetContext(ctx, &result, selectCountSensorsSQL); err != nil {
+		return 0, err
+	}
+	return result.Cnt, nil
+}
+
+// SelectLatestReading returns the reading of id with the latest ts, or ErrNotFound.
+func (repo *sqlReadings) SelectLatestReading(ctx context.Context, id IDCombination) (model.SensorReading, error) {
+	var reading model.SensorReading
+	err := repo.db.GetContext(ctx, &reading, repo.db.Rebind(selectLatestReadingSQL), id.ID1, id.ID2)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.SensorReading{}, ErrNotFound
+	}
+	return reading, err
+}
@@ -0,0 +1,304 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	model "github.com/Yusufzhafir/worlder-team-assignment/b-service/repository/model"
+	"github.com/jmoiron/sqlx"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/Yusufzhafir/worlder-team-assignment/b-service/repository")
+
+// startSpan opens a client span for one repository call against sensor_readings.
+func (t *tracedRepository) startSpan(ctx context.Context, name string, operation string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return t.startCollectionSpan(ctx, "sensor_readings", name, operation, attrs...)
+}
+
+// startRegistrySpan is startSpan for a call against the sensors registry.
+func (t *tracedRepository) startRegistrySpan(ctx context.Context, name string, operation string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return t.startCollectionSpan(ctx, "sensors", name, operation, attrs...)
+}
+
+// startCatalogSpan is startSpan for a call against the sensor type catalog.
+func (t *tracedRepository) startCatalogSpan(ctx context.Context, name string, operation string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return t.startCollectionSpan(ctx, "sensor_types", name, operation, attrs...)
+}
+
+func (t *tracedRepository) startCollectionSpan(ctx context.Context, collection, name, operation string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	attrs = append(attrs,
+		t.system,
+		semconv.DBOperationName(operation),
+		semconv.DBCollectionName(collection),
+	)
+	return tracer.Start(ctx, "SensorRepository."+name, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+}
+
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// beginTx and commitTx get their own spans, a slow COMMIT is the database flushing its log.
+func beginTx(ctx context.Context, db *sqlx.DB, system attribute.KeyValue) (*sqlx.Tx, error) {
+	ctx, span := tracer.Start(ctx, "BEGIN", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(system))
+	tx, err := db.BeginTxx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+	endSpan(span, err)
+	return tx, err
+}
+
+func commitTx(ctx context.Context, tx *sqlx.Tx, system attribute.KeyValue) error {
+	_, span := tracer.Start(ctx, "COMMIT", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(system))
+	err := tx.Commit()
+	endSpan(span, err)
+	return err
+}
+
+type tracedRepository struct {
+	next   SensorRepository
+	system attribute.KeyValue
+}
+
+// NewTracedRepository wraps next so every call becomes a span under the caller's.
+// backend is the database.backend value, recorded as db.system.name.
+func NewTracedRepository(next SensorRepository, backend string) SensorRepository {
+	system := semconv.DBSystemNameKey.String(backend)
+	if backend == BackendPostgres {
+		system = semconv.DBSystemNamePostgreSQL
+	}
+	return &tracedRepository{next: next, system: system}
+}
+
+func (t *tracedRepository) Ping(ctx context.Context) error {
+	return t.next.Ping(ctx)
+}
+
+func (t *tracedRepository) Close() error {
+	return t.next.Close()
+}
+
+func (t *tracedRepository) InsertReadingTx(ctx context.Context, r *model.SensorReadingInsert, policy ConflictPolicy) (model.InsertOutcome, error) {
+	ctx, span := t.startSpan(ctx, "InsertReadingTx", "INSERT", attribute.String("dedup.policy", string(policy)))
+	outcome, err := t.next.InsertReadingTx(ctx, r, policy)
+	endSpan(span, err)
+	return outcome, err
+}
+
+func (t *tracedRepository) InsertReadingsBatchTx(ctx context.Context, rs []model.SensorReadingInsert, policy ConflictPolicy) ([]model.InsertOutcome, error) {
+	ctx, span := t.startSpan(ctx, "InsertReadingsBatchTx", "INSERT", semconv.DBOperationBatchSize(len(rs)), attribute.String("dedup.policy", string(policy)))
+	outcomes, err := t.next.InsertReadingsBatchTx(ctx, rs, policy)
+	endSpan(span, err)
+	return outcomes, err
+}
+
+func (t *tracedRepository) SelectByTime(ctx context.Context, startTime, stopTime time.Time, limit int, offset int) ([]model.SensorReading, error) {
+	ctx, span := t.startSpan(ctx, "SelectByTime", "SELECT")
+	rows, err := t.next.SelectByTime(ctx, startTime, stopTime, limit, offset)
+	endSpan(span, err)
+	return rows, err
+}
+
+func (t *tracedRepository) SelectCountByTime(ctx context.Context, startTime, stopTime time.Time) (int64, error) {
+	ctx, span := t.startSpan(ctx, "SelectCountByTime", "SELECT")
+	count, err := t.next.SelectCountByTime(ctx, startTime, stopTime)
+	endSpan(span, err)
+	return count, err
+}
+
+func (t *tracedRepository) SelectByIDs(ctx context.Context, ids []IDCombination, limit int, offset int) ([]model.SensorReading, error) {
+	ctx, span := t.startSpan(ctx, "SelectByIDs", "SELECT")
+	rows, err := t.next.SelectByIDs(ctx, ids, limit, offset)
+	endSpan(span, err)
+	return rows, err
+}
+
+func (t *tracedRepository) SelectCountByIDs(ctx context.Context, ids []IDCombination) (int64, error) {
+	ctx, span := t.startSpan(ctx, "SelectCountByIDs", "SELECT")
+	count, err := t.next.SelectCountByIDs(ctx, ids)
+	endSpan(span, err)
+	return count, err
+}
+
+func (t *tracedRepository) SelectByIDsAndTime(ctx context.Context, ids []IDCombination, startTime, stopTime time.Time, limit int, offset int) ([]model.SensorReading, error) {
+	ctx, span := t.startSpan(ctx, "SelectByIDsAndTime", "SELECT")
+	rows, err := t.next.SelectByIDsAndTime(ctx, ids, startTime, stopTime, limit, offset)
+	endSpan(span, err)
+	return rows, err
+}
+
+func (t *tracedRepository) SelectCountByIDsAndTime(ctx context.Context, ids []IDCombination, startTime, stopTime time.Time) (int64, error) {
+	ctx, span := t.startSpan(ctx, "SelectCountByIDsAndTime", "SELECT")
+	count, err := t.next.SelectCountByIDsAndTime(ctx, ids, startTime, stopTime)
+	endSpan(span, err)
+	return count, err
+}
+
+func (t *tracedRepository) DeleteByTime(ctx context.Context, startTime, stopTime time.Time) (int64, error) {
+	ctx, span := t.startSpan(ctx, "DeleteByTime", "DELETE")
+	affected, err := t.next.DeleteByTime(ctx, startTime, stopTime)
+	endSpan(span, err)
+	return affected, err
+}
+
+func (t *tracedRepository) DeleteByIDs(ctx context.Context, ids []IDCombination) (int64, error) {
+	ctx, span := t.startSpan(ctx, "DeleteByIDs", "DELETE")
+	affected, err := t.next.DeleteByIDs(ctx, ids)
+	endSpan(span, err)
+	return affected, err
+}
+
+func (t *tracedRepository) DeleteByIDsAndTime(ctx context.Context, ids []IDCombination, startTime, stopTime time.Time) (int64, error) {
+	ctx, span := t.startSpan(ctx, "DeleteByIDsAndTime", "DELETE")
+	affected, err := t.next.DeleteByIDsAndTime(ctx, ids, startTime, stopTime)
+	endSpan(span, err)
+	return affected, err
+}
+
+func (t *tracedRepository) UpdateByTime(ctx context.Context, startTime, stopTime time.Time, sensorValue float64, sensorType string) (int64, error) {
+	ctx, span := t.startSpan(ctx, "UpdateByTime", "UPDATE")
+	affected, err := t.next.UpdateByTime(ctx, startTime, stopTime, sensorValue, sensorType)
+	endSpan(span, err)
+	return affected, err
+}
+
+func (t *tracedRepository) UpdateByIDs(ctx context.Context, ids []IDCombination, sensorValue float64, sensorType string) (int64, error) {
+	ctx, span := t.startSpan(ctx, "UpdateByIDs", "UPDATE")
+	affected, err := t.next.UpdateByIDs(ctx, ids, sensorValue, sensorType)
+	endSpan(span, err)
+	return affected, err
+}
+
+func (t *tracedRepository) UpdateByIDsAndTime(ctx context.Context, ids []IDCombination, startTime, stopTime time.Time, sensorValue float64, sensorType string) (int64, error) {
+	ctx, span := t.startSpan(ctx, "UpdateByIDsAndTime", "UPDATE")
+	affected, err := t.next.UpdateByIDsAndTime(ctx, ids, startTime, stopTime, sensorValue, sensorType)
+	endSpan(span, err)
+	return affected, err
+}
+
+func (t *tracedRepository) SelectSensorDataPaginated(ctx context.Context, limit, offset int) ([]model.SensorReading, error) {
+	ctx, span := t.startSpan(ctx, "SelectSensorDataPaginated", "SELECT")
+	rows, err := t.next.SelectSensorDataPaginated(ctx, limit, offset)
+	endSpan(span, err)
+	return rows, err
+}
+
+func (t *tracedRepository) SelectCountPagination(ctx context.Context) (int64, error) {
+	ctx, span := t.startSpan(ctx, "SelectCountPagination", "SELECT")
+	count, err := t.next.SelectCountPagination(ctx)
+	endSpan(span, err)
+	return count, err
+}
+
+func (t *tracedRepository) SelectAggregate(ctx context.Context, q AggregateQuery) ([]model.AggregateBucket, error) {
+	ctx, span := t.startSpan(ctx, "SelectAggregate", "SELECT", attribute.Int64("aggregate.bucket_ms", q.Bucket.Milliseconds()))
+	buckets, err := t.next.SelectAggregate(ctx, q)
+	endSpan(span, err)
+	return buckets, err
+}
+
+func (t *tracedRepository) SelectSensorSummaries(ctx context.Context, since time.Time, limit, offset int) ([]model.SensorSummary, error) {
+	ctx, span := t.startSpan(ctx, "SelectSensorSummaries", "SELECT")
+	summaries, err := t.next.SelectSensorSummaries(ctx, since, limit, offset)
+	endSpan(span, err)
+	return summaries, err
+}
+
+func (t *tracedRepository) SelectCountSensors(ctx context.Context) (int64, error) {
+	ctx, span := t.startSpan(ctx, "SelectCountSensors", "SELECT")
+	count, err := t.next.SelectCountSensors(ctx)
+	endSpan(span, err)
+	return count, err
+}
+
+func (t *tracedRepository) SelectLatestReading(ctx context.Context, id IDCombination) (model.SensorReading, error) {
+	ctx, span := t.startSpan(ctx, "SelectLatestReading", "SELECT")
+	reading, err := t.next.SelectLatestReading(ctx, id)
+	endSpan(span, err)
+	return reading, err
+}
+
+func (t *tracedRepository) InsertSensor(ctx context.Context, s *model.Sensor) error {
+	ctx, span := t.startRegistrySpan(ctx, "InsertSensor", "INSERT")
+	err := t.next.InsertSensor(ctx, s)
+	endSpan(span, err)
+	return err
+}
+
+func (t *tracedRepository) SelectSensor(ctx context.Context, id IDCombination) (model.Sensor, error) {
+	ctx, span := t.startRegistrySpan(ctx, "SelectSensor", "SELECT")
+	sensor, err := t.next.SelectSensor(ctx, id)
+	endSpan(span, err)
+	return sensor, err
+}
+
+func (t *tracedRepository) SelectSensorsByIDs(ctx context.Context, ids []IDCombination) ([]model.Sensor, error) {
+	ctx, span := t.startRegistrySpan(ctx, "SelectSensorsByIDs", "SELECT")
+	sensors, err := t.next.SelectSensorsByIDs(ctx, ids)
+	endSpan(span, err)
+	return sensors, err
+}
+
+func (t *tracedRepository) SelectRegisteredSensors(ctx context.Context, limit, offset int) ([]model.Sensor, error) {
+	ctx, span := t.startRegistrySpan(ctx, "SelectRegisteredSensors", "SELECT")
+	sensors, err := t.next.SelectRegisteredSensors(ctx, limit, offset)
+	endSpan(span, err)
+	return sensors, err
+}
+
+func (t *tracedRepository) SelectCountRegisteredSensors(ctx context.Context) (int64, error) {
+	ctx, span := t.startRegistrySpan(ctx, "SelectCountRegisteredSensors", "SELECT")
+	count, err := t.next.SelectCountRegisteredSensors(ctx)
+	endSpan(span, err)
+	return count, err
+}
+
+func (t *tracedRepository) UpdateSensor(ctx context.Context, s *model.Sensor) error {
+	ctx, span := t.startRegistrySpan(ctx, "UpdateSensor", "UPDATE")
+	err := t.next.UpdateSensor(ctx, s)
+	endSpan(span, err)
+	return err
+}
+
+func (t *tracedRepository) DeleteSensor(ctx context.Context, id IDCombination) error {
+	ctx, span := t.startRegistrySpan(ctx, "DeleteSensor", "DELETE")
+	err := t.next.DeleteSensor(ctx, id)
+	endSpan(span, err)
+	return err
+}
+
+func (t *tracedRepository) SelectSensorTypes(ctx context.Context) ([]model.SensorType, error) {
+	ctx, span := t.startCatalogSpan(ctx, "SelectSensorTypes", "SELECT")
+	types, err := t.next.SelectSensorTypes(ctx)
+	endSpan(span, err)
+	return types, err
+}
+
+func (t *tracedRepository) InsertSensorType(ctx context.Context, st *model.SensorType) error {
+	ctx, span := t.startCatalogSpan(ctx, "InsertSensorType", "INSERT")
+	err := t.next.InsertSensorType(ctx, st)
+	endSpan(span, err)
+	return err
+}
+
+func (t *tracedRepository) UpdateSensorType(ctx context.Context, st *model.SensorType) error {
+	ctx, span := t.startCatalogSpan(ctx, "UpdateSensorType", "UPDATE")
+	err := t.next.UpdateSensorType(ctx, st)
+	endSpan(span, err)
+	return err
+}
+
+func (t *tracedRepository) DeleteSensorType(ctx context.Context, name string) error {
+	ctx, span := t.startCatalogSpan(ctx, "DeleteSensorType", "DELETE")
+	err := t.next.DeleteSensorType(ctx, name)
+	endSpan(span, err)
+	return err
+}
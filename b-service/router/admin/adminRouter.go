@@ -0,0 +1,149 @@
+package router
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/Yusufzhafir/worlder-team-assignment/b-service/admission"
+	httpmodels "github.com/Yusufzhafir/worlder-team-assignment/b-service/shared/model"
+	"github.com/Yusufzhafir/worlder-team-assignment/b-service/spool"
+	"github.com/Yusufzhafir/worlder-team-assignment/b-service/usecase"
+	"github.com/Yusufzhafir/worlder-team-assignment/common/logging"
+	"github.com/labstack/echo/v4"
+)
+
+type AdminRouter interface {
+	GetSpoolStats(ctx echo.Context) error
+	GetAdmissionStats(ctx echo.Context) error
+	GetLogLevel(ctx echo.Context) error
+	SetLogLevel(ctx echo.Context) error
+	ListSensorTypes(ctx echo.Context) error
+	CreateSensorType(ctx echo.Context) error
+	GetSensorType(ctx echo.Context) error
+	UpdateSensorType(ctx echo.Context) error
+	DeleteSensorType(ctx echo.Context) error
+}
+
+type AdminRouterImpl struct {
+	spool         spool.Spool
+	admission     admission.Controller
+	logLevel      *slog.LevelVar
+	sensorUsecase *usecase.SensorUseCase
+}
+
+type AdminRouterOpts struct {
+	Spool         spool.Spool // optional, nil when spooling is disabled
+	Admission     admission.Controller
+	LogLevel      *slog.LevelVar
+	SensorUsecase *usecase.SensorUseCase // edits the sensor type catalog
+}
+
+func NewAdminRouter(opts AdminRouterOpts) AdminRouter {
+	return &AdminRouterImpl{
+		spool:         opts.Spool,
+		admission:     opts.Admission,
+		logLevel:      opts.LogLevel,
+		sensorUsecase: opts.SensorUsecase,
+	}
+}
+
+// GetSpoolStats godoc
+// @Summary     Local spool depth and replay progress
+// @Description Readings written to disk while the database was unavailable, and how far the replayer has drained them
+// @Tags        admin
+// @Produce     json
+// @Success     200 {object} model.Envelope{data=spool.Stats} "data: spool.Stats"
+// @Failure     404 {object} model.Envelope{data=model.Empty} "spooling is disabled"
+// @Router      /admin/spool [get]
+func (a *AdminRouterImpl) GetSpoolStats(ctx echo.Context) error {
+	if a.spool == nil {
+		return ctx.JSON(http.StatusNotFound, httpmodels.Body[httpmodels.Empty]{
+			Error:   true,
+			Message: "spooling is disabled",
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, httpmodels.Body[spool.Stats]{
+		Data: a.spool.Stats(),
+	})
+}
+
+// GetAdmissionStats godoc
+// @Summary     In-flight and queued gRPC ingest calls
+// @Description Per method limits and current load of the admission control in front of the ingest RPCs
+// @Tags        admin
+// @Produce     json
+// @Success     200 {object} model.Envelope{data=map[string]admission.Stats} "data: stats keyed by gRPC method"
+// @Failure     500 {object} model.Envelope{data=model.Empty}
+// @Router      /admin/admission [get]
+func (a *AdminRouterImpl) GetAdmissionStats(ctx echo.Context) error {
+	if a.admission == nil {
+		return ctx.JSON(http.StatusInternalServerError, httpmodels.Body[httpmodels.Empty]{
+			Error:   true,
+			Message: "admission control is not provided",
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, httpmodels.Body[map[string]admission.Stats]{
+		Data: a.admission.Stats(),
+	})
+}
+
+// GetLogLevel godoc
+// @Summary     Current log level
+// @Tags        admin
+// @Produce     json
+// @Success     200 {object} model.Envelope{data=logging.LevelRequest} "data: the level"
+// @Failure     500 {object} model.Envelope{data=model.Empty}
+// @Router      /admin/log-level [get]
+func (a *AdminRouterImpl) GetLogLevel(ctx echo.Context) error {
+	if a.logLevel == nil {
+		return ctx.JSON(http.StatusInternalServerError, httpmodels.Body[httpmodels.Empty]{
+			Error:   true,
+			Message: "log level is not provided",
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, httpmodels.Body[logging.LevelRequest]{
+		Data: logging.LevelRequest{Level: a.logLevel.Level().String()},
+	})
+}
+
+// SetLogLevel godoc
+// @Summary     Change the log level
+// @Description Takes effect at once and lasts until the process restarts, LOG_LEVEL applies again on the next start
+// @Tags        admin
+// @Accept      json
+// @Produce     json
+// @Param       body body logging.LevelRequest true "debug, info, warn or error"
+// @Success     200 {object} model.Envelope{data=logging.LevelRequest} "data: the new level"
+// @Failure     400 {object} model.Envelope{data=model.Empty} "error=true, message explains"
+// @Failure     500 {object} model.Envelope{data=model.Empty}
+// @Router      /admin/log-level [put]
+func (a *AdminRouterImpl) SetLogLevel(ctx echo.Context) error {
+	if a.logLevel == nil {
+		return ctx.JSON(http.StatusInternalServerError, httpmodels.Body[httpmodels.Empty]{
+			Error:   true,
+			Message: "log level is not provided",
+		})
+	}
+
+	var body logging.LevelRequest
+	if err := ctx.Bind(&body); err != nil {
+		return ctx.JSON(http.StatusBadRequest, httpmodels.Body[httpmodels.Empty]{
+			Error:   true,
+			Message: "body must be {\"level\": \"debug|info|warn|error\"}",
+		})
+	}
+	level, err := logging.SetLevel(ctx.Request().Context(), a.logLevel, body.Level)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, httpmodels.Body[httpmodels.Empty]{
+			Error:   true,
+			Message: err.Error(),
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, httpmodels.Body[logging.LevelRequest]{
+		Data: logging.LevelRequest{Level: level.String()},
+	})
+}
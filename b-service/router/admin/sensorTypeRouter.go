@@ -0,0 +1,222 @@
+package router
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	repository "github.com/Yusufzhafir/worlder-team-assignment/b-service/repository"
+	repomodel "github.com/Yusufzhafir/worlder-team-assignment/b-service/repository/model"
+	"github.com/Yusufzhafir/worlder-team-assignment/b-service/router/sensor/model"
+	httpmodels "github.com/Yusufzhafir/worlder-team-assignment/b-service/shared/model"
+	"github.com/Yusufzhafir/worlder-team-assignment/b-service/usecase"
+	"github.com/labstack/echo/v4"
+)
+
+func toSensorType(t repomodel.SensorType) model.SensorType {
+	aliases := t.Aliases
+	if aliases == nil {
+		aliases = []string{}
+	}
+	return model.SensorType{
+		Name:        t.Name,
+		Unit:        t.Unit,
+		Min:         t.MinValue,
+		Max:         t.MaxValue,
+		RangePolicy: t.RangePolicy,
+		Aliases:     aliases,
+		CreatedAtMs: t.CreatedAt.UnixMilli(),
+		UpdatedAtMs: t.UpdatedAt.UnixMilli(),
+	}
+}
+
+func fromSensorTypeRequest(name string, req model.UpdateSensorTypeRequest) repomodel.SensorType {
+	return repomodel.SensorType{
+		Name:        name,
+		Unit:        req.Unit,
+		MinValue:    req.Min,
+		MaxValue:    req.Max,
+		RangePolicy: req.RangePolicy,
+		Aliases:     req.Aliases,
+	}
+}
+
+// sensorTypeError maps catalog errors to their status codes.
+func sensorTypeError(ctx echo.Context, name string, err error) error {
+	status := http.StatusInternalServerError
+	message := err.Error()
+	switch {
+	case errors.Is(err, usecase.ErrInvalidSensorType):
+		status = http.StatusBadRequest
+	case errors.Is(err, repository.ErrNotFound):
+		status = http.StatusNotFound
+		message = fmt.Sprintf("sensor type %q is not in the catalog", name)
+	case errors.Is(err, repository.ErrAlreadyExists):
+		status = http.StatusConflict
+		message = fmt.Sprintf("the name or an alias of sensor type %q already names a type", name)
+	}
+	return ctx.JSON(status, httpmodels.Body[httpmodels.Empty]{Error: true, Message: message})
+}
+
+// ListSensorTypes godoc
+// @Summary     List the sensor type catalog
+// @Description Every type ordered by name, with its unit, plausible range, range policy and aliases.
+// @Tags        admin
+// @Produce     json
+// @Success     200 {object} model.Envelope{data=[]model.SensorType} "data: SensorType list"
+// @Failure     500 {object} model.Envelope{data=model.Empty}
+// @Router      /admin/sensor-types [get]
+func (a *AdminRouterImpl) ListSensorTypes(ctx echo.Context) error {
+	usecase := *a.sensorUsecase
+	if usecase == nil {
+		return ctx.JSON(http.StatusInternalServerError, httpmodels.Body[httpmodels.Empty]{
+			Error:   true,
+			Message: "usecase is not provided",
+		})
+	}
+
+	types, err := usecase.ListSensorTypes(ctx.Request().Context())
+	if err != nil {
+		return sensorTypeError(ctx, "", err)
+	}
+	items := make([]model.SensorType, len(types))
+	for i, t := range types {
+		items[i] = toSensorType(t)
+	}
+	return ctx.JSON(http.StatusOK, httpmodels.Body[[]model.SensorType]{Data: items})
+}
+
+// CreateSensorType godoc
+// @Summary     Add a sensor type to the catalog
+// @Description Readings sent with the name or an alias, in any case, are stored under the name. A value outside min and max is rejected with the reject policy and stored with outOfRange set with flag, the default.
+// @Tags        admin
+// @Accept      json
+// @Produce     json
+// @Param       request body model.CreateSensorTypeRequest true "Sensor type to add"
+// @Success     201 {object} model.Envelope{data=model.SensorType} "data: SensorType"
+// @Failure     400 {object} model.Envelope{data=model.Empty} "error=true, message explains"
+// @Failure     409 {object} model.Envelope{data=model.Empty} "the name or an alias already names a type"
+// @Failure     500 {object} model.Envelope{data=model.Empty}
+// @Router      /admin/sensor-types [post]
+func (a *AdminRouterImpl) CreateSensorType(ctx echo.Context) error {
+	usecase := *a.sensorUsecase
+	if usecase == nil {
+		return ctx.JSON(http.StatusInternalServerError, httpmodels.Body[httpmodels.Empty]{
+			Error:   true,
+			Message: "usecase is not provided",
+		})
+	}
+
+	var req model.CreateSensorTypeRequest
+	if err := ctx.Bind(&req); err != nil {
+		return ctx.JSON(http.StatusBadRequest, httpmodels.Body[httpmodels.Empty]{
+			Error:   true,
+			Message: fmt.Sprintf("Invalid request body: %v", err),
+		})
+	}
+
+	created, err := usecase.CreateSensorType(ctx.Request().Context(), fromSensorTypeRequest(req.Name, model.UpdateSensorTypeRequest{
+		Unit:        req.Unit,
+		Min:         req.Min,
+		Max:         req.Max,
+		RangePolicy: req.RangePolicy,
+		Aliases:     req.Aliases,
+	}))
+	if err != nil {
+		return sensorTypeError(ctx, req.Name, err)
+	}
+	return ctx.JSON(http.StatusCreated, httpmodels.Body[model.SensorType]{Data: toSensorType(created)})
+}
+
+// GetSensorType godoc
+// @Summary     One sensor type of the catalog
+// @Tags        admin
+// @Produce     json
+// @Param       name  path  string  true  "Name of the type, in any case" example(temperature)
+// @Success     200 {object} model.Envelope{data=model.SensorType} "data: SensorType"
+// @Failure     404 {object} model.Envelope{data=model.Empty} "the type is not in the catalog"
+// @Failure     500 {object} model.Envelope{data=model.Empty}
+// @Router      /admin/sensor-types/{name} [get]
+func (a *AdminRouterImpl) GetSensorType(ctx echo.Context) error {
+	usecase := *a.sensorUsecase
+	if usecase == nil {
+		return ctx.JSON(http.StatusInternalServerError, httpmodels.Body[httpmodels.Empty]{
+			Error:   true,
+			Message: "usecase is not provided",
+		})
+	}
+
+	name := ctx.Param("name")
+	sensorType, err := usecase.GetSensorType(ctx.Request().Context(), name)
+	if err != nil {
+		return sensorTypeError(ctx, name, err)
+	}
+	return ctx.JSON(http.StatusOK, httpmodels.Body[model.SensorType]{Data: toSensorType(sensorType)})
+}
+
+// UpdateSensorType godoc
+// @Summary     Replace a sensor type
+// @Description Replaces the unit, range, policy and aliases. Applies to readings ingested from now on, stored readings keep their outOfRange flag.
+// @Tags        admin
+// @Accept      json
+// @Produce     json
+// @Param       name     path  string                         true  "Name of the type, in any case" example(temperature)
+// @Param       request  body  model.UpdateSensorTypeRequest  true  "New definition"
+// @Success     200 {object} model.Envelope{data=model.SensorType} "data: SensorType"
+// @Failure     400 {object} model.Envelope{data=model.Empty} "error=true, message explains"
+// @Failure     404 {object} model.Envelope{data=model.Empty} "the type is not in the catalog"
+// @Failure     409 {object} model.Envelope{data=model.Empty} "an alias already names another type"
+// @Failure     500 {object} model.Envelope{data=model.Empty}
+// @Router      /admin/sensor-types/{name} [put]
+func (a *AdminRouterImpl) UpdateSensorType(ctx echo.Context) error {
+	usecase := *a.sensorUsecase
+	if usecase == nil {
+		return ctx.JSON(http.StatusInternalServerError, httpmodels.Body[httpmodels.Empty]{
+			Error:   true,
+			Message: "usecase is not provided",
+		})
+	}
+
+	name := ctx.Param("name")
+	var req model.UpdateSensorTypeRequest
+	if err := ctx.Bind(&req); err != nil {
+		return ctx.JSON(http.StatusBadRequest, httpmodels.Body[httpmodels.Empty]{
+			Error:   true,
+			Message: fmt.Sprintf("Invalid request body: %v", err),
+		})
+	}
+
+	updated, err := usecase.UpdateSensorType(ctx.Request().Context(), fromSensorTypeRequest(name, req))
+	if err != nil {
+		return sensorTypeError(ctx, name, err)
+	}
+	return ctx.JSON(http.StatusOK, httpmodels.Body[model.SensorType]{Data: toSensorType(updated)})
+}
+
+// DeleteSensorType godoc
+// @Summary     Remove a sensor type from the catalog
+// @Description Its name and aliases are no longer normalized or range checked. Stored readings are kept.
+// @Tags        admin
+// @Produce     json
+// @Param       name  path  string  true  "Name of the type, in any case" example(temperature)
+// @Success     200 {object} model.Envelope{data=model.Empty}
+// @Failure     404 {object} model.Envelope{data=model.Empty} "the type is not in the catalog"
+// @Failure     500 {object} model.Envelope{data=model.Empty}
+// @Router      /admin/sensor-types/{name} [delete]
+func (a *AdminRouterImpl) DeleteSensorType(ctx echo.Context) error {
+	usecase := *a.sensorUsecase
+	if usecase == nil {
+		return ctx.JSON(http.StatusInternalServerError, httpmodels.Body[httpmodels.Empty]{
+			Error:   true,
+			Message: "usecase is not provided",
+		})
+	}
+
+	name := ctx.Param("name")
+	if err := usecase.DeleteSensorType(ctx.Request().Context(), name); err != nil {
+		return sensorTypeError(ctx, name, err)
+	}
+	return ctx.JSON(http.StatusOK, httpmodels.Body[httpmodels.Empty]{
+		Message: fmt.Sprintf("sensor type %q removed from the catalog", name),
+	})
+}
@@ -0,0 +1,49 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/Yusufzhafir/worlder-team-assignment/b-service/health"
+	httpmodels "github.com/Yusufzhafir/worlder-team-assignment/b-service/shared/model"
+	"github.com/labstack/echo/v4"
+)
+
+// HealthRouter serves the probes at the root, outside /api/v1, next to where
+// orchestrators expect them.
+type HealthRouter interface {
+	GetHealthz(ctx echo.Context) error
+	GetReadyz(ctx echo.Context) error
+}
+
+type HealthRouterImpl struct {
+	checker health.Checker
+}
+
+func NewHealthRouter(checker health.Checker) HealthRouter {
+	return &HealthRouterImpl{checker: checker}
+}
+
+// GetHealthz answers 200 while the process serves and 503 once it shuts down.
+func (h *HealthRouterImpl) GetHealthz(ctx echo.Context) error {
+	return reply(ctx, h.checker.Live())
+}
+
+// GetReadyz answers 503 while the database is unreachable with nowhere to spool,
+// the ingest queue is saturated, or the process shuts down.
+func (h *HealthRouterImpl) GetReadyz(ctx echo.Context) error {
+	return reply(ctx, h.checker.Ready(ctx.Request().Context()))
+}
+
+func reply(ctx echo.Context, report health.Report) error {
+	if !report.Ready() {
+		return ctx.JSON(http.StatusServiceUnavailable, httpmodels.Body[health.Report]{
+			Data:    report,
+			Error:   true,
+			Message: string(report.Status),
+		})
+	}
+	return ctx.JSON(http.StatusOK, httpmodels.Body[health.Report]{
+		Data:    report,
+		Message: string(report.Status),
+	})
+}
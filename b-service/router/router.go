@@ -2,7 +2,10 @@ package router
 
 import (
 	"fmt"
+	"net/http"
 
+	adminRouter "github.com/Yusufzhafir/worlder-team-assignment/b-service/router/admin"
+	healthRouter "github.com/Yusufzhafir/worlder-team-assignment/b-service/router/health"
 	sensorRouter "github.com/Yusufzhafir/worlder-team-assignment/b-service/router/sensor"
 	"github.com/labstack/echo/v4"
 )
@@ -13,28 +16,78 @@ func bindSensor(e *echo.Group, sensorRouter *sensorRouter.SensorRouter) error {
 		return fmt.Errorf("router is empty %v", router)
 	}
 	e.GET("/sensor", router.GetSensorDataPaginated)                      //with no q-param
+	e.GET("/sensor/stream", router.StreamSensorData)                     //SSE, optional q-param id
+	e.GET("/sensor/stream/ws", router.StreamSensorDataWebSocket)         //WebSocket, optional q-param id
 	e.GET("/sensor/ids", router.GetSensorDataByIds)                      //with no q-param id
 	e.GET("/sensor/time", router.GetSensorDataByTime)                    //with no q-param time
 	e.GET("/sensor/ids-time", router.GetSensorDataByIdsAndTime)          //with no q-param id and time
+	e.GET("/sensor/aggregate", router.GetSensorAggregate)                //q-param id or sensor_type, time and bucket
 	e.DELETE("/sensor/delete/ids", router.DeleteSensorByIds)             //with no q-param id
 	e.DELETE("/sensor/delete/time", router.DeleteSensorByTime)           //with no q-param time
 	e.DELETE("/sensor/delete/ids-time", router.DeleteSensorByIdsAndTime) //with no q-param id and time
 	e.PUT("/sensor/update/ids", router.UpdateSensorByIds)                //with no q-param id
 	e.PUT("/sensor/update/time", router.UpdateSensorByTime)              //with no q-param time
 	e.PUT("/sensor/update/ids-time", router.UpdateSensorByIdsAndTime)    //with no q-param id and time
+	e.GET("/sensors", router.ListSensors)                                //one summary per id1/id2
+	e.GET("/sensors/:id1/:id2/latest", router.GetLatestReading)          //latest reading of one sensor
+	e.GET("/sensors/registry", router.ListRegisteredSensors)             //registered metadata, paginated
+	e.POST("/sensors/registry", router.RegisterSensor)                   //register one id1/id2
+	e.GET("/sensors/registry/:id1/:id2", router.GetRegisteredSensor)     //metadata of one sensor
+	e.PUT("/sensors/registry/:id1/:id2", router.UpdateRegisteredSensor)  //replace its metadata
+	e.DELETE("/sensors/registry/:id1/:id2", router.DeregisterSensor)     //metadata only, readings stay
 	return nil
 }
 
+func bindAdmin(e *echo.Group, adminRouter *adminRouter.AdminRouter) error {
+	router := *adminRouter
+	if router == nil {
+		return fmt.Errorf("router is empty %v", router)
+	}
+	e.GET("/admin/spool", router.GetSpoolStats)
+	e.GET("/admin/admission", router.GetAdmissionStats)
+	e.GET("/admin/log-level", router.GetLogLevel)
+	e.PUT("/admin/log-level", router.SetLogLevel)
+	e.GET("/admin/sensor-types", router.ListSensorTypes)
+	e.POST("/admin/sensor-types", router.CreateSensorType)
+	e.GET("/admin/sensor-types/:name", router.GetSensorType)
+	e.PUT("/admin/sensor-types/:name", router.UpdateSensorType)    //replace unit, range, policy and aliases
+	e.DELETE("/admin/sensor-types/:name", router.DeleteSensorType) //stored readings stay
+	return nil
+}
+
+func bindHealth(e *echo.Echo, healthRouter *healthRouter.HealthRouter) error {
+	router := *healthRouter
+	if router == nil {
+		return fmt.Errorf("router is empty %v", router)
+	}
+	e.GET("/healthz", router.GetHealthz)
+	e.GET("/readyz", router.GetReadyz)
+	return nil
+}
+
+func bindMetrics(e *echo.Echo, handler http.Handler) {
+	if handler == nil {
+		return
+	}
+	e.GET("/metrics", echo.WrapHandler(handler))
+}
+
 func bindOthers(e *echo.Group) {
 }
 
 type BindRouterOpts struct {
 	E            *echo.Echo
 	SensorRouter *sensorRouter.SensorRouter
+	AdminRouter  *adminRouter.AdminRouter
+	HealthRouter *healthRouter.HealthRouter
+	Metrics      http.Handler // optional, serves /metrics when set
 }
 
 func BindRouter(opts BindRouterOpts) {
 	apiGroup := opts.E.Group("/api/v1")
 	bindSensor(apiGroup, opts.SensorRouter)
+	bindAdmin(apiGroup, opts.AdminRouter)
 	bindOthers(apiGroup)
+	bindHealth(opts.E, opts.HealthRouter)
+	bindMetrics(opts.E, opts.Metrics)
 }
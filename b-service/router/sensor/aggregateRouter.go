@@ -0,0 +1,132 @@
+package router
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Yusufzhafir/worlder-team-assignment/b-service/router/sensor/model"
+	httpmodels "github.com/Yusufzhafir/worlder-team-assignment/b-service/shared/model"
+	"github.com/Yusufzhafir/worlder-team-assignment/b-service/usecase"
+	"github.com/labstack/echo/v4"
+)
+
+// defaultBucket is the bucket width when the bucket parameter is left out.
+const defaultBucket = time.Minute
+
+func toAggregatePoint(p usecase.AggregatePoint, funcs []usecase.AggregateFunc) model.AggregatePoint {
+	point := model.AggregatePoint{TimestampMs: p.Start.UnixMilli()}
+	for _, fn := range funcs {
+		value := p.Value(fn)
+		switch fn {
+		case usecase.AggregateAvg:
+			point.Avg = &value
+		case usecase.AggregateMin:
+			point.Min = &value
+		case usecase.AggregateMax:
+			point.Max = &value
+		case usecase.AggregateSum:
+			point.Sum = &value
+		case usecase.AggregateCount:
+			point.Count = &p.Count
+		case usecase.AggregateFirst:
+			point.First = &value
+		case usecase.AggregateLast:
+			point.Last = &value
+		case usecase.AggregateStddev:
+			point.Stddev = &value
+		}
+	}
+	return point
+}
+
+// GetSensorAggregate godoc
+// @Summary     Downsample sensor readings into time buckets
+// @Description One series per sensor matching the ID combinations or the sensor type, with a point per bucket that has readings. Buckets are counted from the from time; at most 10000 per sensor.
+// @Tags        sensor
+// @Produce     json
+// @Param       id1          query   string false  "Comma-separated ID1 values" example(A,B,C)
+// @Param       id2          query   string false  "Comma-separated ID2 values" example(0,1,2)
+// @Param       sensor_type  query   string false  "Sensor type, instead of or besides the IDs" example(temperature)
+// @Param       from         query   string true   "Start time (RFC3339Nano)" example(2025-08-25T00:00:00+07:00)
+// @Param       to           query   string true   "End time (RFC3339Nano), exclusive" example(2025-08-26T00:00:00+07:00)
+// @Param       bucket       query   string false  "Bucket width, at least 1s" default(1m) example(1h)
+// @Param       agg          query   string false  "Comma-separated avg, min, max, sum, count, first, last, stddev" default(avg) example(avg,min,max)
+// @Success     200 {object} model.Envelope{data=model.AggregateResult} "data: AggregateResult"
+// @Failure     400 {object} model.Envelope{data=model.Empty} "error=true, message explains"
+// @Failure     500 {object} model.Envelope{data=model.Empty}
+// @Router      /sensor/aggregate [get]
+func (s *SensorRouterImpl) GetSensorAggregate(ctx echo.Context) error {
+	usecaseImpl := *s.sensorUsecase
+	if usecaseImpl == nil {
+		return ctx.JSON(http.StatusInternalServerError, httpmodels.Body[httpmodels.Empty]{
+			Error:   true,
+			Message: "usecase is not provided",
+		})
+	}
+
+	idCombinations, err := parseIDCombinations(ctx)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, httpmodels.Body[httpmodels.Empty]{
+			Error:   true,
+			Message: fmt.Sprintf("Invalid ID parameters: %v", err),
+		})
+	}
+
+	fromTime, toTime, err := parseTimeRange(ctx)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, httpmodels.Body[httpmodels.Empty]{
+			Error:   true,
+			Message: fmt.Sprintf("Invalid time parameters: %v", err),
+		})
+	}
+
+	bucket := defaultBucket
+	if bucketStr := ctx.QueryParam("bucket"); bucketStr != "" {
+		bucket, err = time.ParseDuration(bucketStr)
+		if err != nil {
+			return ctx.JSON(http.StatusBadRequest, httpmodels.Body[httpmodels.Empty]{
+				Error:   true,
+				Message: fmt.Sprintf("Invalid bucket: %v (use a duration like 1m or 1h)", err),
+			})
+		}
+	}
+
+	funcs, err := usecase.ParseAggregateFuncs(ctx.QueryParam("agg"))
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, httpmodels.Body[httpmodels.Empty]{
+			Error:   true,
+			Message: err.Error(),
+		})
+	}
+
+	series, err := usecaseImpl.GetSensorAggregate(ctx.Request().Context(), usecase.AggregateRequest{
+		IDs:        idCombinations,
+		SensorType: strings.TrimSpace(ctx.QueryParam("sensor_type")),
+		From:       fromTime,
+		To:         toTime,
+		Bucket:     bucket,
+		Funcs:      funcs,
+	})
+	if errors.Is(err, usecase.ErrInvalidAggregate) {
+		return ctx.JSON(http.StatusBadRequest, httpmodels.Body[httpmodels.Empty]{Error: true, Message: err.Error()})
+	}
+	if err != nil {
+		return ctx.JSON(http.StatusInternalServerError, httpmodels.Body[httpmodels.Empty]{Error: true, Message: err.Error()})
+	}
+
+	result := model.AggregateResult{
+		BucketMs: bucket.Milliseconds(),
+		Series:   make([]model.AggregateSeries, len(series)),
+	}
+	for i, sensor := range series {
+		points := make([]model.AggregatePoint, len(sensor.Points))
+		for j, p := range sensor.Points {
+			points[j] = toAggregatePoint(p, funcs)
+		}
+		result.Series[i] = model.AggregateSeries{ID1: sensor.ID1, ID2: sensor.ID2, Points: points}
+	}
+	return ctx.JSON(http.StatusOK, httpmodels.Body[model.AggregateResult]{Data: result})
+}
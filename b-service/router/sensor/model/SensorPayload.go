@@ -10,6 +10,10 @@ type SensorPayload struct {
 	SensorType  string  `json:"sensorType"  example:"temperature"`
 	Value       float64 `json:"value"       example:"23.5"`
 	TimestampMs int64   `json:"timestampMs" example:"1724550000000"`
+	// OutOfRange is set on readings stored outside the range of their sensor type
+	OutOfRange bool `json:"outOfRange,omitempty"`
+	// Sensor is only set with with_metadata=true and a registered sensor
+	Sensor *SensorMetadata `json:"sensor,omitempty"`
 }
 
 // swagger:model SensorPage
@@ -87,3 +91,144 @@ type UpdateResponse struct {
 	UpdatedCount int64  `json:"updated_count"`
 	Message      string `json:"message"`
 }
+
+// AggregatePoint carries only the aggregates that were asked for.
+// swagger:model AggregatePoint
+type AggregatePoint struct {
+	TimestampMs int64    `json:"timestampMs"      example:"1724550000000"` // bucket start
+	Avg         *float64 `json:"avg,omitempty"    example:"23.5"`
+	Min         *float64 `json:"min,omitempty"    example:"21.0"`
+	Max         *float64 `json:"max,omitempty"    example:"26.2"`
+	Sum         *float64 `json:"sum,omitempty"    example:"14100"`
+	Count       *int64   `json:"count,omitempty"  example:"600"`
+	First       *float64 `json:"first,omitempty"  example:"22.9"`
+	Last        *float64 `json:"last,omitempty"   example:"24.1"`
+	Stddev      *float64 `json:"stddev,omitempty" example:"1.3"`
+}
+
+// swagger:model AggregateSeries
+type AggregateSeries struct {
+	ID1    string           `json:"id1" example:"ABC123"`
+	ID2    int              `json:"id2" example:"42"`
+	Points []AggregatePoint `json:"points"`
+}
+
+// swagger:model AggregateResult
+type AggregateResult struct {
+	BucketMs int64             `json:"bucketMs" example:"60000"`
+	Series   []AggregateSeries `json:"series"`
+}
+
+// swagger:model SensorSummary
+type SensorSummary struct {
+	ID1              string  `json:"id1"              example:"ABC123"`
+	ID2              int     `json:"id2"              example:"42"`
+	SensorType       string  `json:"sensorType"       example:"temperature"` // of the latest reading
+	LatestValue      float64 `json:"latestValue"      example:"23.5"`
+	FirstTimestampMs int64   `json:"firstTimestampMs" example:"1724500000000"`
+	LastTimestampMs  int64   `json:"lastTimestampMs"  example:"1724550000000"`
+	Count            int64   `json:"count"            example:"86400"`
+	IngestRate       float64 `json:"ingestRate"       example:"1.5"` // readings per second over the rate window
+}
+
+// swagger:model SensorSummaryPage
+type SensorSummaryPage struct {
+	Items       []SensorSummary `json:"items"`
+	Page        int             `json:"page"        example:"1"`
+	PageSize    int             `json:"pageSize"    example:"50"`
+	Total       int64           `json:"total"       example:"12"`
+	RateMinutes int             `json:"rateMinutes" example:"5"`
+}
+
+// swagger:model LatestReading
+type LatestReading struct {
+	ReadingID   uint64  `json:"readingId"   example:"1001"`
+	ID1         string  `json:"id1"         example:"ABC123"`
+	ID2         int     `json:"id2"         example:"42"`
+	SensorType  string  `json:"sensorType"  example:"temperature"`
+	Value       float64 `json:"value"       example:"23.5"`
+	TimestampMs int64   `json:"timestampMs" example:"1724550000000"`
+	CreatedAtMs int64   `json:"createdAtMs" example:"1724550000120"`
+	// OutOfRange is set on readings stored outside the range of their sensor type
+	OutOfRange bool `json:"outOfRange,omitempty"`
+}
+
+// swagger:model SensorMetadata
+type SensorMetadata struct {
+	ID1          string            `json:"id1"          example:"ABC123"`
+	ID2          int               `json:"id2"          example:"42"`
+	Name         string            `json:"name"         example:"boiler inlet"`
+	Unit         string            `json:"unit"         example:"C"`
+	Location     string            `json:"location"     example:"plant 2, hall B"`
+	Owner        string            `json:"owner"        example:"facilities"`
+	ExpectedRate float64           `json:"expectedRate" example:"0.5"` // readings per second, 0 when unknown
+	Enabled      bool              `json:"enabled"      example:"true"`
+	Labels       map[string]string `json:"labels"`
+	CreatedAtMs  int64             `json:"createdAtMs"  example:"1724500000000"`
+	UpdatedAtMs  int64             `json:"updatedAtMs"  example:"1724550000000"`
+}
+
+// swagger:model SensorMetadataPage
+type SensorMetadataPage struct {
+	Items    []SensorMetadata `json:"items"`
+	Page     int              `json:"page"     example:"1"`
+	PageSize int              `json:"pageSize" example:"50"`
+	Total    int64            `json:"total"    example:"12"`
+}
+
+// UpdateSensorMetadataRequest replaces every field, so omitted ones are cleared.
+// swagger:model UpdateSensorMetadataRequest
+type UpdateSensorMetadataRequest struct {
+	Name         string            `json:"name"              example:"boiler inlet"`
+	Unit         string            `json:"unit"              example:"C"`
+	Location     string            `json:"location"          example:"plant 2, hall B"`
+	Owner        string            `json:"owner"             example:"facilities"`
+	ExpectedRate float64           `json:"expectedRate"      example:"0.5"`
+	Enabled      *bool             `json:"enabled,omitempty" example:"true"` // true when omitted
+	Labels       map[string]string `json:"labels"`
+}
+
+// swagger:model RegisterSensorRequest
+type RegisterSensorRequest struct {
+	ID1          string            `json:"id1"               validate:"required" example:"ABC123"`
+	ID2          int               `json:"id2"               validate:"required" example:"42"`
+	Name         string            `json:"name"              example:"boiler inlet"`
+	Unit         string            `json:"unit"              example:"C"`
+	Location     string            `json:"location"          example:"plant 2, hall B"`
+	Owner        string            `json:"owner"             example:"facilities"`
+	ExpectedRate float64           `json:"expectedRate"      example:"0.5"`
+	Enabled      *bool             `json:"enabled,omitempty" example:"true"` // true when omitted
+	Labels       map[string]string `json:"labels"`
+}
+
+// swagger:model SensorType
+type SensorType struct {
+	Name        string   `json:"name"        example:"temperature"`
+	Unit        string   `json:"unit"        example:"C"`
+	Min         *float64 `json:"min"         example:"-50"` // null leaves the range open below
+	Max         *float64 `json:"max"         example:"150"` // null leaves the range open above
+	RangePolicy string   `json:"rangePolicy" example:"flag"`
+	Aliases     []string `json:"aliases"`
+	CreatedAtMs int64    `json:"createdAtMs" example:"1724500000000"`
+	UpdatedAtMs int64    `json:"updatedAtMs" example:"1724550000000"`
+}
+
+// UpdateSensorTypeRequest replaces every field, so omitted ones are cleared.
+// swagger:model UpdateSensorTypeRequest
+type UpdateSensorTypeRequest struct {
+	Unit        string   `json:"unit"                  example:"C"`
+	Min         *float64 `json:"min,omitempty"         example:"-50"`
+	Max         *float64 `json:"max,omitempty"         example:"150"`
+	RangePolicy string   `json:"rangePolicy,omitempty" example:"reject"` // flag when omitted
+	Aliases     []string `json:"aliases"`
+}
+
+// swagger:model CreateSensorTypeRequest
+type CreateSensorTypeRequest struct {
+	Name        string   `json:"name"                  validate:"required" example:"temperature"`
+	Unit        string   `json:"unit"                  example:"C"`
+	Min         *float64 `json:"min,omitempty"         example:"-50"`
+	Max         *float64 `json:"max,omitempty"         example:"150"`
+	RangePolicy string   `json:"rangePolicy,omitempty" example:"reject"` // flag when omitted
+	Aliases     []string `json:"aliases"`
+}
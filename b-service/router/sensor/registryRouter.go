@@ -0,0 +1,314 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/Yusufzhafir/worlder-team-assignment/b-service/repository"
+	repoModel "github.com/Yusufzhafir/worlder-team-assignment/b-service/repository/model"
+	"github.com/Yusufzhafir/worlder-team-assignment/b-service/router/sensor/model"
+	httpmodels "github.com/Yusufzhafir/worlder-team-assignment/b-service/shared/model"
+	"github.com/Yusufzhafir/worlder-team-assignment/b-service/usecase"
+	"github.com/labstack/echo/v4"
+)
+
+// parseSensorPath reads the :id1/:id2 path parameters.
+func parseSensorPath(ctx echo.Context) (repository.IDCombination, error) {
+	id2, err := strconv.Atoi(ctx.Param("id2"))
+	if err != nil {
+		return repository.IDCombination{}, fmt.Errorf("invalid id2 value '%s': must be integer", ctx.Param("id2"))
+	}
+	return repository.IDCombination{ID1: ctx.Param("id1"), ID2: id2}, nil
+}
+
+// parseWithMetadata reads the with_metadata query parameter of the reading queries.
+func parseWithMetadata(ctx echo.Context) (bool, error) {
+	raw := ctx.QueryParam("with_metadata")
+	if raw == "" {
+		return false, nil
+	}
+	withMetadata, err := strconv.ParseBool(raw)
+	if err != nil {
+		return false, fmt.Errorf("with_metadata must be true or false, got %q", raw)
+	}
+	return withMetadata, nil
+}
+
+// joinSensorMetadata sets Sensor on every item whose sensor is registered.
+func joinSensorMetadata(ctx context.Context, uc usecase.SensorUseCase, items []model.SensorPayload) error {
+	if len(items) == 0 {
+		return nil
+	}
+	ids := make([]repository.IDCombination, len(items))
+	for i, item := range items {
+		ids[i] = repository.IDCombination{ID1: item.ID1, ID2: item.ID2}
+	}
+	sensors, err := uc.LookupSensors(ctx, ids)
+	if err != nil {
+		return err
+	}
+	metadata := make(map[repository.IDCombination]*model.SensorMetadata, len(sensors))
+	for id, sensor := range sensors {
+		m := toSensorMetadata(sensor)
+		metadata[id] = &m
+	}
+	for i := range items {
+		items[i].Sensor = metadata[ids[i]]
+	}
+	return nil
+}
+
+func toSensorMetadata(s repoModel.Sensor) model.SensorMetadata {
+	labels := map[string]string(s.Labels)
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	return model.SensorMetadata{
+		ID1:          s.ID1,
+		ID2:          s.ID2,
+		Name:         s.Name,
+		Unit:         s.Unit,
+		Location:     s.Location,
+		Owner:        s.Owner,
+		ExpectedRate: s.ExpectedRate,
+		Enabled:      s.Enabled,
+		Labels:       labels,
+		CreatedAtMs:  s.CreatedAt.UnixMilli(),
+		UpdatedAtMs:  s.UpdatedAt.UnixMilli(),
+	}
+}
+
+func fromSensorMetadataRequest(id repository.IDCombination, req model.UpdateSensorMetadataRequest) repoModel.Sensor {
+	enabled := true
+	if req.Enabled != nil {
+		enabled = *req.Enabled
+	}
+	return repoModel.Sensor{
+		ID1:          id.ID1,
+		ID2:          id.ID2,
+		Name:         req.Name,
+		Unit:         req.Unit,
+		Location:     req.Location,
+		Owner:        req.Owner,
+		ExpectedRate: req.ExpectedRate,
+		Enabled:      enabled,
+		Labels:       repoModel.Labels(req.Labels),
+	}
+}
+
+// registryError maps what the registry usecase returns to a response.
+func registryError(ctx echo.Context, id repository.IDCombination, err error) error {
+	status := http.StatusInternalServerError
+	message := err.Error()
+	switch {
+	case errors.Is(err, usecase.ErrInvalidSensor):
+		status = http.StatusBadRequest
+	case errors.Is(err, repository.ErrNotFound):
+		status = http.StatusNotFound
+		message = fmt.Sprintf("sensor %s/%d is not registered", id.ID1, id.ID2)
+	case errors.Is(err, repository.ErrAlreadyExists):
+		status = http.StatusConflict
+		message = fmt.Sprintf("sensor %s/%d is already registered", id.ID1, id.ID2)
+	}
+	return ctx.JSON(status, httpmodels.Body[httpmodels.Empty]{Error: true, Message: message})
+}
+
+// ListRegisteredSensors godoc
+// @Summary     List the sensor registry (paginated)
+// @Description Registered sensors ordered by id1 and id2, whether or not they have readings.
+// @Tags        registry
+// @Produce     json
+// @Param       page       query   int    false  "Page number"  minimum(1) default(1)
+// @Param       page_size  query   int    false  "Page size"    minimum(1) maximum(500) default(50)
+// @Success     200 {object} model.Envelope{data=model.SensorMetadataPage} "data: SensorMetadataPage"
+// @Failure     400 {object} model.Envelope{data=model.Empty} "error=true, message explains"
+// @Failure     500 {object} model.Envelope{data=model.Empty}
+// @Router      /sensors/registry [get]
+func (s *SensorRouterImpl) ListRegisteredSensors(ctx echo.Context) error {
+	usecase := *s.sensorUsecase
+	if usecase == nil {
+		return ctx.JSON(http.StatusInternalServerError, httpmodels.Body[httpmodels.Empty]{
+			Error:   true,
+			Message: "usecase is not provided",
+		})
+	}
+
+	page, size, offset, err := validatePagination(ctx)
+	if err != nil {
+		return err
+	}
+
+	result, err := usecase.ListRegisteredSensors(ctx.Request().Context(), size, offset)
+	if err != nil {
+		return ctx.JSON(http.StatusInternalServerError, httpmodels.Body[httpmodels.Empty]{Error: true, Message: err.Error()})
+	}
+
+	items := make([]model.SensorMetadata, len(result.Data))
+	for i, sensor := range result.Data {
+		items[i] = toSensorMetadata(sensor)
+	}
+	return ctx.JSON(http.StatusOK, httpmodels.Body[model.SensorMetadataPage]{
+		Data: model.SensorMetadataPage{
+			Items:    items,
+			Page:     page,
+			PageSize: size,
+			Total:    result.Count,
+		},
+	})
+}
+
+// RegisterSensor godoc
+// @Summary     Register a sensor
+// @Description Adds the metadata of one id1/id2 pair to the registry. enabled defaults to true.
+// @Tags        registry
+// @Accept      json
+// @Produce     json
+// @Param       request body model.RegisterSensorRequest true "Sensor to register"
+// @Success     201 {object} model.Envelope{data=model.SensorMetadata} "data: SensorMetadata"
+// @Failure     400 {object} model.Envelope{data=model.Empty} "error=true, message explains"
+// @Failure     409 {object} model.Envelope{data=model.Empty} "the sensor is already registered"
+// @Failure     500 {object} model.Envelope{data=model.Empty}
+// @Router      /sensors/registry [post]
+func (s *SensorRouterImpl) RegisterSensor(ctx echo.Context) error {
+	usecase := *s.sensorUsecase
+	if usecase == nil {
+		return ctx.JSON(http.StatusInternalServerError, httpmodels.Body[httpmodels.Empty]{
+			Error:   true,
+			Message: "usecase is not provided",
+		})
+	}
+
+	var req model.RegisterSensorRequest
+	if err := ctx.Bind(&req); err != nil {
+		return ctx.JSON(http.StatusBadRequest, httpmodels.Body[httpmodels.Empty]{
+			Error:   true,
+			Message: fmt.Sprintf("Invalid request body: %v", err),
+		})
+	}
+
+	id := repository.IDCombination{ID1: req.ID1, ID2: req.ID2}
+	sensor, err := usecase.RegisterSensor(ctx.Request().Context(), fromSensorMetadataRequest(id, model.UpdateSensorMetadataRequest{
+		Name:         req.Name,
+		Unit:         req.Unit,
+		Location:     req.Location,
+		Owner:        req.Owner,
+		ExpectedRate: req.ExpectedRate,
+		Enabled:      req.Enabled,
+		Labels:       req.Labels,
+	}))
+	if err != nil {
+		return registryError(ctx, id, err)
+	}
+	return ctx.JSON(http.StatusCreated, httpmodels.Body[model.SensorMetadata]{Data: toSensorMetadata(sensor)})
+}
+
+// GetRegisteredSensor godoc
+// @Summary     Registered metadata of one sensor
+// @Tags        registry
+// @Produce     json
+// @Param       id1  path  string  true  "ID1" example(ABC123)
+// @Param       id2  path  int     true  "ID2" example(42)
+// @Success     200 {object} model.Envelope{data=model.SensorMetadata} "data: SensorMetadata"
+// @Failure     400 {object} model.Envelope{data=model.Empty} "error=true, message explains"
+// @Failure     404 {object} model.Envelope{data=model.Empty} "the sensor is not registered"
+// @Failure     500 {object} model.Envelope{data=model.Empty}
+// @Router      /sensors/registry/{id1}/{id2} [get]
+func (s *SensorRouterImpl) GetRegisteredSensor(ctx echo.Context) error {
+	usecase := *s.sensorUsecase
+	if usecase == nil {
+		return ctx.JSON(http.StatusInternalServerError, httpmodels.Body[httpmodels.Empty]{
+			Error:   true,
+			Message: "usecase is not provided",
+		})
+	}
+
+	id, err := parseSensorPath(ctx)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, httpmodels.Body[httpmodels.Empty]{Error: true, Message: err.Error()})
+	}
+
+	sensor, err := usecase.GetRegisteredSensor(ctx.Request().Context(), id)
+	if err != nil {
+		return registryError(ctx, id, err)
+	}
+	return ctx.JSON(http.StatusOK, httpmodels.Body[model.SensorMetadata]{Data: toSensorMetadata(sensor)})
+}
+
+// UpdateRegisteredSensor godoc
+// @Summary     Replace the metadata of a registered sensor
+// @Description Every field is replaced, omitted ones are cleared and enabled becomes true.
+// @Tags        registry
+// @Accept      json
+// @Produce     json
+// @Param       id1      path  string  true  "ID1" example(ABC123)
+// @Param       id2      path  int     true  "ID2" example(42)
+// @Param       request  body  model.UpdateSensorMetadataRequest true "New metadata"
+// @Success     200 {object} model.Envelope{data=model.SensorMetadata} "data: SensorMetadata"
+// @Failure     400 {object} model.Envelope{data=model.Empty} "error=true, message explains"
+// @Failure     404 {object} model.Envelope{data=model.Empty} "the sensor is not registered"
+// @Failure     500 {object} model.Envelope{data=model.Empty}
+// @Router      /sensors/registry/{id1}/{id2} [put]
+func (s *SensorRouterImpl) UpdateRegisteredSensor(ctx echo.Context) error {
+	usecase := *s.sensorUsecase
+	if usecase == nil {
+		return ctx.JSON(http.StatusInternalServerError, httpmodels.Body[httpmodels.Empty]{
+			Error:   true,
+			Message: "usecase is not provided",
+		})
+	}
+
+	id, err := parseSensorPath(ctx)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, httpmodels.Body[httpmodels.Empty]{Error: true, Message: err.Error()})
+	}
+	var req model.UpdateSensorMetadataRequest
+	if err := ctx.Bind(&req); err != nil {
+		return ctx.JSON(http.StatusBadRequest, httpmodels.Body[httpmodels.Empty]{
+			Error:   true,
+			Message: fmt.Sprintf("Invalid request body: %v", err),
+		})
+	}
+
+	sensor, err := usecase.UpdateRegisteredSensor(ctx.Request().Context(), fromSensorMetadataRequest(id, req))
+	if err != nil {
+		return registryError(ctx, id, err)
+	}
+	return ctx.JSON(http.StatusOK, httpmodels.Body[model.SensorMetadata]{Data: toSensorMetadata(sensor)})
+}
+
+// DeregisterSensor godoc
+// @Summary     Remove a sensor from the registry
+// @Description Only the metadata is removed, the readings of the sensor are kept.
+// @Tags        registry
+// @Produce     json
+// @Param       id1  path  string  true  "ID1" example(ABC123)
+// @Param       id2  path  int     true  "ID2" example(42)
+// @Success     200 {object} model.Envelope{data=model.Empty}
+// @Failure     400 {object} model.Envelope{data=model.Empty} "error=true, message explains"
+// @Failure     404 {object} model.Envelope{data=model.Empty} "the sensor is not registered"
+// @Failure     500 {object} model.Envelope{data=model.Empty}
+// @Router      /sensors/registry/{id1}/{id2} [delete]
+func (s *SensorRouterImpl) DeregisterSensor(ctx echo.Context) error {
+	usecase := *s.sensorUsecase
+	if usecase == nil {
+		return ctx.JSON(http.StatusInternalServerError, httpmodels.Body[httpmodels.Empty]{
+			Error:   true,
+			Message: "usecase is not provided",
+		})
+	}
+
+	id, err := parseSensorPath(ctx)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, httpmodels.Body[httpmodels.Empty]{Error: true, Message: err.Error()})
+	}
+
+	if err := usecase.DeregisterSensor(ctx.Request().Context(), id); err != nil {
+		return registryError(ctx, id, err)
+	}
+	return ctx.JSON(http.StatusOK, httpmodels.Body[httpmodels.Empty]{
+		Message: fmt.Sprintf("sensor %s/%d removed from the registry", id.ID1, id.ID2),
+	})
+}
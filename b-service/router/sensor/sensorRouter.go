@@ -7,6 +7,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/Yusufzhafir/worlder-team-assignment/b-service/live"
 	"github.com/Yusufzhafir/worlder-team-assignment/b-service/repository"
 	"github.com/Yusufzhafir/worlder-team-assignment/b-service/router/sensor/model"
 	httpmodels "github.com/Yusufzhafir/worlder-team-assignment/b-service/shared/model"
@@ -25,15 +26,32 @@ type SensorRouter interface {
 	UpdateSensorByTime(ctx echo.Context) error
 	UpdateSensorByIdsAndTime(ctx echo.Context) error
 	GetSensorDataPaginated(ctx echo.Context) error
+	StreamSensorData(ctx echo.Context) error
+	StreamSensorDataWebSocket(ctx echo.Context) error
+	GetSensorAggregate(ctx echo.Context) error
+	ListSensors(ctx echo.Context) error
+	GetLatestReading(ctx echo.Context) error
+	ListRegisteredSensors(ctx echo.Context) error
+	RegisterSensor(ctx echo.Context) error
+	GetRegisteredSensor(ctx echo.Context) error
+	UpdateRegisteredSensor(ctx echo.Context) error
+	DeregisterSensor(ctx echo.Context) error
 }
 
 type SensorRouterImpl struct {
-	sensorUsecase *usecase.SensorUseCase
+	sensorUsecase  *usecase.SensorUseCase
+	feed           live.Hub
+	allowedOrigins []string
 }
 
-func NewSensorRouter(sensorUsecase *usecase.SensorUseCase) SensorRouter {
+// NewSensorRouter builds the sensor routes. feed backs the live streams and may be nil.
+// allowedOrigins lists the pages, e.g. https://dashboard.example.com, that may open the
+// WebSocket feed besides the API's own host; "*" allows any.
+func NewSensorRouter(sensorUsecase *usecase.SensorUseCase, feed live.Hub, allowedOrigins []string) SensorRouter {
 	return &SensorRouterImpl{
-		sensorUsecase: sensorUsecase,
+		sensorUsecase:  sensorUsecase,
+		feed:           feed,
+		allowedOrigins: allowedOrigins,
 	}
 }
 
@@ -84,6 +102,7 @@ func validatePagination(ctx echo.Context) (int, int, int, error) {
 // @Produce     json
 // @Param       page       query   int    false  "Page number"              minimum(1) default(1)
 // @Param       page_size  query   int    false  "Page size"                minimum(1) maximum(500) default(50)
+// @Param       with_metadata  query  bool  false  "Join the registered metadata of each sensor" default(false)
 // @Param       id1        query   string false  "Comma-separated ID1 values" example(0,1,2)
 // @Param       id2        query   string false  "Comma-separated ID2 values" example(A,B,C)
 // @Success     200 {object} model.Envelope{data=model.SensorPage} "data: SensorPage"
@@ -104,6 +123,10 @@ func (s *SensorRouterImpl) GetSensorDataByIds(ctx echo.Context) error {
 	if err != nil {
 		return err
 	}
+	withMetadata, err := parseWithMetadata(ctx)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, httpmodels.Body[httpmodels.Empty]{Error: true, Message: err.Error()})
+	}
 
 	// Parse ID combinations from query parameters
 	idCombinations, err := parseIDCombinations(ctx)
@@ -139,9 +162,15 @@ func (s *SensorRouterImpl) GetSensorDataByIds(ctx echo.Context) error {
 			SensorType:  currElement.SensorType,
 			Value:       currElement.SensorValue,
 			TimestampMs: currElement.TS.Unix(),
+			OutOfRange:  currElement.OutOfRange,
 		}
 	}
 
+	if withMetadata {
+		if err := joinSensorMetadata(ctx.Request().Context(), usecase, newPayload); err != nil {
+			return ctx.JSON(http.StatusInternalServerError, httpmodels.Body[httpmodels.Empty]{Error: true, Message: err.Error()})
+		}
+	}
 	body := httpmodels.Body[model.SensorPage]{
 		Data: model.SensorPage{
 			Items:    newPayload,
@@ -206,6 +235,7 @@ func parseIDCombinations(ctx echo.Context) ([]repository.IDCombination, error) {
 // @Produce     json
 // @Param       page       query   int    false  "Page number"  minimum(1) default(1)
 // @Param       page_size  query   int    false  "Page size"    minimum(1) maximum(500) default(50)
+// @Param       with_metadata  query  bool  false  "Join the registered metadata of each sensor" default(false)
 // @Param       from       query   string    false  "from time"  default(2006-01-02T15:04:05.999999999+07:00)
 // @Param       to  	query   string    false  "to time"    default(2006-01-02T16:04:05.999999999+07:00)
 // @Success     200 {object} model.Envelope{data=model.SensorPage} "data: SensorPage"
@@ -226,6 +256,10 @@ func (s *SensorRouterImpl) GetSensorDataByTime(ctx echo.Context) error {
 	if err != nil {
 		return err
 	}
+	withMetadata, err := parseWithMetadata(ctx)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, httpmodels.Body[httpmodels.Empty]{Error: true, Message: err.Error()})
+	}
 
 	// time range
 	fromTime, toTime, err := parseTimeRange(ctx)
@@ -250,6 +284,12 @@ func (s *SensorRouterImpl) GetSensorDataByTime(ctx echo.Context) error {
 			SensorType:  currElement.SensorType,
 			Value:       currElement.SensorValue,
 			TimestampMs: currElement.TS.Unix(),
+			OutOfRange:  currElement.OutOfRange,
+		}
+	}
+	if withMetadata {
+		if err := joinSensorMetadata(ctx.Request().Context(), usecase, newPayload); err != nil {
+			return ctx.JSON(http.StatusInternalServerError, httpmodels.Body[httpmodels.Empty]{Error: true, Message: err.Error()})
 		}
 	}
 	body := httpmodels.Body[model.SensorPage]{
@@ -270,6 +310,7 @@ func (s *SensorRouterImpl) GetSensorDataByTime(ctx echo.Context) error {
 // @Produce     json
 // @Param       page       query   int    false  "Page number"              minimum(1) default(1)
 // @Param       page_size  query   int    false  "Page size"                minimum(1) maximum(500) default(50)
+// @Param       with_metadata  query  bool  false  "Join the registered metadata of each sensor" default(false)
 // @Param       id1        query   string false  "Comma-separated ID1 values" example(0,1,2)
 // @Param       id2        query   string false  "Comma-separated ID2 values" example(A,B,C)
 // @Param       from  query   string true   "Start time (RFC3339Nano)"     example(2025-08-25T18:00:24.947000+07:00)
@@ -292,6 +333,10 @@ func (s *SensorRouterImpl) GetSensorDataByIdsAndTime(ctx echo.Context) error {
 	if err != nil {
 		return err
 	}
+	withMetadata, err := parseWithMetadata(ctx)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, httpmodels.Body[httpmodels.Empty]{Error: true, Message: err.Error()})
+	}
 
 	// Parse ID combinations
 	idCombinations, err := parseIDCombinations(ctx)
@@ -336,9 +381,15 @@ func (s *SensorRouterImpl) GetSensorDataByIdsAndTime(ctx echo.Context) error {
 			SensorType:  currElement.SensorType,
 			Value:       currElement.SensorValue,
 			TimestampMs: currElement.TS.Unix(),
+			OutOfRange:  currElement.OutOfRange,
 		}
 	}
 
+	if withMetadata {
+		if err := joinSensorMetadata(ctx.Request().Context(), usecase, newPayload); err != nil {
+			return ctx.JSON(http.StatusInternalServerError, httpmodels.Body[httpmodels.Empty]{Error: true, Message: err.Error()})
+		}
+	}
 	body := httpmodels.Body[model.SensorPage]{
 		Data: model.SensorPage{
 			Items:    newPayload,
@@ -778,6 +829,7 @@ func (s *SensorRouterImpl) UpdateSensorByIdsAndTime(ctx echo.Context) error {
 // @Produce     json
 // @Param       page       query   int    false  "Page number"  minimum(1) default(1)
 // @Param       page_size  query   int    false  "Page size"    minimum(1) maximum(500) default(50)
+// @Param       with_metadata  query  bool  false  "Join the registered metadata of each sensor" default(false)
 // @Success     200 {object} model.Envelope{data=model.SensorPage} "data: SensorPage"
 // @Failure     400 {object} model.Envelope{data=model.Empty} "error=true, message explains"
 // @Failure     500 {object} model.Envelope{data=model.Empty}
@@ -788,6 +840,10 @@ func (s *SensorRouterImpl) GetSensorDataPaginated(ctx echo.Context) error {
 	if err != nil {
 		return err
 	}
+	withMetadata, err := parseWithMetadata(ctx)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, httpmodels.Body[httpmodels.Empty]{Error: true, Message: err.Error()})
+	}
 	result, err := usecase.GetSensorPaginated(ctx.Request().Context(), size, offset)
 	if err != nil {
 		return ctx.JSON(http.StatusInternalServerError, httpmodels.Body[httpmodels.Empty]{
@@ -804,6 +860,12 @@ func (s *SensorRouterImpl) GetSensorDataPaginated(ctx echo.Context) error {
 			SensorType:  currElement.SensorType,
 			Value:       currElement.SensorValue,
 			TimestampMs: currElement.TS.Unix(),
+			OutOfRange:  currElement.OutOfRange,
+		}
+	}
+	if withMetadata {
+		if err := joinSensorMetadata(ctx.Request().Context(), usecase, newPayload); err != nil {
+			return ctx.JSON(http.StatusInternalServerError, httpmodels.Body[httpmodels.Empty]{Error: true, Message: err.Error()})
 		}
 	}
 	body := httpmodels.Body[model.SensorPage]{
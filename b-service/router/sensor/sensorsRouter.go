@@ -0,0 +1,135 @@
+package router
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Yusufzhafir/worlder-team-assignment/b-service/repository"
+	"github.com/Yusufzhafir/worlder-team-assignment/b-service/router/sensor/model"
+	httpmodels "github.com/Yusufzhafir/worlder-team-assignment/b-service/shared/model"
+	"github.com/labstack/echo/v4"
+)
+
+// maxRateMinutes bounds the ingest rate window of GET /sensors to a day.
+const maxRateMinutes = 24 * 60
+
+// ListSensors godoc
+// @Summary     List every sensor that has readings (paginated)
+// @Description A sensor is a distinct id1/id2 pair. Each comes with its first and last timestamp, its latest value and type, its reading count and its ingest rate in readings per second over the last rate_minutes.
+// @Tags        sensor
+// @Produce     json
+// @Param       page          query   int    false  "Page number"  minimum(1) default(1)
+// @Param       page_size     query   int    false  "Page size"    minimum(1) maximum(500) default(50)
+// @Param       rate_minutes  query   int    false  "Ingest rate window in minutes" minimum(1) maximum(1440) default(5)
+// @Success     200 {object} model.Envelope{data=model.SensorSummaryPage} "data: SensorSummaryPage"
+// @Failure     400 {object} model.Envelope{data=model.Empty} "error=true, message explains"
+// @Failure     500 {object} model.Envelope{data=model.Empty}
+// @Router      /sensors [get]
+func (s *SensorRouterImpl) ListSensors(ctx echo.Context) error {
+	usecase := *s.sensorUsecase
+	if usecase == nil {
+		return ctx.JSON(http.StatusInternalServerError, httpmodels.Body[httpmodels.Empty]{
+			Error:   true,
+			Message: "usecase is not provided",
+		})
+	}
+
+	page, size, offset, err := validatePagination(ctx)
+	if err != nil {
+		return err
+	}
+
+	rateMinutes := 5
+	if rateStr := ctx.QueryParam("rate_minutes"); rateStr != "" {
+		rateMinutes, err = strconv.Atoi(rateStr)
+		if err != nil || rateMinutes < 1 || rateMinutes > maxRateMinutes {
+			return ctx.JSON(http.StatusBadRequest, httpmodels.Body[httpmodels.Empty]{
+				Error:   true,
+				Message: fmt.Sprintf("rate_minutes must be 1..%d", maxRateMinutes),
+			})
+		}
+	}
+
+	result, err := usecase.ListSensors(ctx.Request().Context(), time.Duration(rateMinutes)*time.Minute, size, offset)
+	if err != nil {
+		return ctx.JSON(http.StatusInternalServerError, httpmodels.Body[httpmodels.Empty]{Error: true, Message: err.Error()})
+	}
+
+	items := make([]model.SensorSummary, len(result.Data))
+	for i, sensor := range result.Data {
+		items[i] = model.SensorSummary{
+			ID1:              sensor.ID1,
+			ID2:              sensor.ID2,
+			SensorType:       sensor.SensorType,
+			LatestValue:      sensor.LatestValue,
+			FirstTimestampMs: sensor.FirstTS.UnixMilli(),
+			LastTimestampMs:  sensor.LastTS.UnixMilli(),
+			Count:            sensor.Count,
+			IngestRate:       sensor.IngestRate,
+		}
+	}
+	body := httpmodels.Body[model.SensorSummaryPage]{
+		Data: model.SensorSummaryPage{
+			Items:       items,
+			Page:        page,
+			PageSize:    size,
+			Total:       result.Count,
+			RateMinutes: rateMinutes,
+		},
+	}
+	return ctx.JSON(http.StatusOK, body)
+}
+
+// GetLatestReading godoc
+// @Summary     Latest reading of one sensor
+// @Description The reading with the latest timestamp for the id1/id2 pair.
+// @Tags        sensor
+// @Produce     json
+// @Param       id1  path  string  true  "ID1" example(ABC123)
+// @Param       id2  path  int     true  "ID2" example(42)
+// @Success     200 {object} model.Envelope{data=model.LatestReading} "data: LatestReading"
+// @Failure     400 {object} model.Envelope{data=model.Empty} "error=true, message explains"
+// @Failure     404 {object} model.Envelope{data=model.Empty} "the sensor has no readings"
+// @Failure     500 {object} model.Envelope{data=model.Empty}
+// @Router      /sensors/{id1}/{id2}/latest [get]
+func (s *SensorRouterImpl) GetLatestReading(ctx echo.Context) error {
+	usecase := *s.sensorUsecase
+	if usecase == nil {
+		return ctx.JSON(http.StatusInternalServerError, httpmodels.Body[httpmodels.Empty]{
+			Error:   true,
+			Message: "usecase is not provided",
+		})
+	}
+
+	id, err := parseSensorPath(ctx)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, httpmodels.Body[httpmodels.Empty]{Error: true, Message: err.Error()})
+	}
+
+	reading, err := usecase.GetLatestReading(ctx.Request().Context(), id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ctx.JSON(http.StatusNotFound, httpmodels.Body[httpmodels.Empty]{
+			Error:   true,
+			Message: fmt.Sprintf("sensor %s/%d has no readings", id.ID1, id.ID2),
+		})
+	}
+	if err != nil {
+		return ctx.JSON(http.StatusInternalServerError, httpmodels.Body[httpmodels.Empty]{Error: true, Message: err.Error()})
+	}
+
+	return ctx.JSON(http.StatusOK, httpmodels.Body[model.LatestReading]{
+		Data: model.LatestReading{
+			ReadingID:   reading.ReadingID,
+			ID1:         reading.ID1,
+			ID2:         reading.ID2,
+			SensorType:  reading.SensorType,
+			Value:       reading.SensorValue,
+			TimestampMs: reading.TS.UnixMilli(),
+			CreatedAtMs: reading.CreatedAt.UnixMilli(),
+			OutOfRange:  reading.OutOfRange,
+		},
+	})
+}
@@ -0,0 +1,182 @@
+package router
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Yusufzhafir/worlder-team-assignment/b-service/live"
+	"github.com/Yusufzhafir/worlder-team-assignment/b-service/router/sensor/model"
+	httpmodels "github.com/Yusufzhafir/worlder-team-assignment/b-service/shared/model"
+	pb "github.com/Yusufzhafir/worlder-team-assignment/common/protobuf"
+	"github.com/labstack/echo/v4"
+	"golang.org/x/net/websocket"
+)
+
+// sseHeartbeat keeps idle SSE connections open through proxies.
+const sseHeartbeat = 15 * time.Second
+
+func toPayload(r *pb.SensorReading) model.SensorPayload {
+	return model.SensorPayload{
+		ID1:         r.GetId1(),
+		ID2:         int(r.GetId2()),
+		SensorType:  r.GetSensorType(),
+		Value:       r.GetValue(),
+		TimestampMs: r.GetTimestampMs(),
+	}
+}
+
+// subscribe opens a live subscription filtered by the id1/id2 query parameters.
+func (s *SensorRouterImpl) subscribe(ctx echo.Context) (live.Subscription, error) {
+	if s.feed == nil {
+		return nil, ctx.JSON(http.StatusNotFound, httpmodels.Body[httpmodels.Empty]{
+			Error:   true,
+			Message: "live feed is disabled",
+		})
+	}
+
+	idCombinations, err := parseIDCombinations(ctx)
+	if err != nil {
+		return nil, ctx.JSON(http.StatusBadRequest, httpmodels.Body[httpmodels.Empty]{
+			Error:   true,
+			Message: fmt.Sprintf("Invalid ID parameters: %v", err),
+		})
+	}
+	filter := live.Filter{}
+	for _, id := range idCombinations {
+		filter.IDs = append(filter.IDs, live.ID{ID1: id.ID1, ID2: int32(id.ID2)})
+	}
+
+	sub, err := s.feed.Subscribe(filter, "")
+	if err != nil {
+		return nil, ctx.JSON(http.StatusServiceUnavailable, httpmodels.Body[httpmodels.Empty]{
+			Error:   true,
+			Message: err.Error(),
+		})
+	}
+	return sub, nil
+}
+
+// StreamSensorData godoc
+// @Summary     Live feed of stored readings as Server-Sent Events
+// @Description Every stored reading matching the ID combinations is sent as a "reading" event with a SensorPayload as data. Without id1/id2 every reading is sent. An "error" event ends the stream when the client falls behind or the server shuts down.
+// @Tags        sensor
+// @Produce     text/event-stream
+// @Param       id1        query   string false  "Comma-separated ID1 values" example(A,B,C)
+// @Param       id2        query   string false  "Comma-separated ID2 values" example(0,1,2)
+// @Success     200 {object} model.SensorPayload "one per reading event"
+// @Failure     400 {object} model.Envelope{data=model.Empty} "error=true, message explains"
+// @Failure     503 {object} model.Envelope{data=model.Empty} "too many subscribers"
+// @Router      /sensor/stream [get]
+func (s *SensorRouterImpl) StreamSensorData(ctx echo.Context) error {
+	sub, err := s.subscribe(ctx)
+	if sub == nil {
+		return err
+	}
+	defer sub.Close()
+
+	res := ctx.Response()
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set(echo.HeaderCacheControl, "no-cache")
+	res.Header().Set(echo.HeaderConnection, "keep-alive")
+	res.WriteHeader(http.StatusOK)
+	res.Flush()
+
+	heartbeat := time.NewTicker(sseHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Request().Context().Done():
+			return nil
+		case <-sub.Done():
+			if err := sub.Err(); err != nil {
+				fmt.Fprintf(res, "event: error\ndata: %s\n\n", err)
+				res.Flush()
+			}
+			return nil
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(res, ": ping\n\n"); err != nil {
+				return nil
+			}
+			res.Flush()
+		case reading := <-sub.C():
+			data, err := json.Marshal(toPayload(reading))
+			if err != nil {
+				return err
+			}
+			if _, err := fmt.Fprintf(res, "event: reading\ndata: %s\n\n", data); err != nil {
+				return nil
+			}
+			res.Flush()
+		}
+	}
+}
+
+// checkOrigin refuses handshakes from browser pages on other hosts unless they are
+// allowed, so a page cannot read the feed with the visitor's network access.
+// Clients that send no Origin are not browsers and are let through.
+func (s *SensorRouterImpl) checkOrigin(config *websocket.Config, req *http.Request) error {
+	origin, err := websocket.Origin(config, req)
+	if err != nil {
+		return err
+	}
+	config.Origin = origin
+	if origin == nil || strings.EqualFold(origin.Host, req.Host) {
+		return nil
+	}
+	for _, allowed := range s.allowedOrigins {
+		if allowed == "*" || strings.EqualFold(strings.TrimSuffix(allowed, "/"), origin.Scheme+"://"+origin.Host) {
+			return nil
+		}
+	}
+	return fmt.Errorf("origin %s is not allowed", origin)
+}
+
+// StreamSensorDataWebSocket godoc
+// @Summary     Live feed of stored readings over WebSocket
+// @Description Upgrades to a WebSocket and sends every stored reading matching the ID combinations as a JSON SensorPayload text message. Without id1/id2 every reading is sent. Messages from the client are ignored. The server closes the socket when the client falls behind or the server shuts down.
+// @Tags        sensor
+// @Param       id1        query   string false  "Comma-separated ID1 values" example(A,B,C)
+// @Param       id2        query   string false  "Comma-separated ID2 values" example(0,1,2)
+// @Success     101 {object} model.SensorPayload "one per message"
+// @Failure     400 {object} model.Envelope{data=model.Empty} "error=true, message explains"
+// @Failure     403 "Origin is neither the API's host nor in WS_ALLOWED_ORIGINS"
+// @Failure     503 {object} model.Envelope{data=model.Empty} "too many subscribers"
+// @Router      /sensor/stream/ws [get]
+func (s *SensorRouterImpl) StreamSensorDataWebSocket(ctx echo.Context) error {
+	sub, err := s.subscribe(ctx)
+	if sub == nil {
+		return err
+	}
+	defer sub.Close()
+
+	websocket.Server{Handshake: s.checkOrigin, Handler: func(ws *websocket.Conn) {
+		defer ws.Close()
+
+		// the client only ever closes, reading tells us when it does
+		closed := make(chan struct{})
+		go func() {
+			defer close(closed)
+			var discard []byte
+			for websocket.Message.Receive(ws, &discard) == nil {
+			}
+		}()
+
+		for {
+			select {
+			case <-closed:
+				return
+			case <-sub.Done():
+				return
+			case reading := <-sub.C():
+				if err := websocket.JSON.Send(ws, toPayload(reading)); err != nil {
+					return
+				}
+			}
+		}
+	}}.ServeHTTP(ctx.Response(), ctx.Request())
+	return nil
+}
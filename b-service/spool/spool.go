@@ -0,0 +1,591 @@
+package spool
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	model "github.com/Yusufzhafir/worlder-team-assignment/b-service/repository/model"
+)
+
+var (
+	ErrSpoolFull   = errors.New("spool is full")
+	ErrSpoolClosed = errors.New("spool is closed")
+)
+
+// ReplayFunc writes spooled rows to the database.
+type ReplayFunc func(ctx context.Context, rows []model.SensorReadingInsert) error
+
+// PingFunc reports whether the database is reachable.
+type PingFunc func(ctx context.Context) error
+
+// Spool is a local write-ahead log for readings that could not be written to
+// the database. Once anything is spooled, new readings keep going to the spool
+// (preserving order) until the replayer has drained it into the database.
+type Spool interface {
+	// Active reports whether readings should be appended here instead of written to the database.
+	Active() bool
+	// MarkUnavailable switches to spooling after a failed database write.
+	MarkUnavailable()
+	// Append durably stores rows, returning once they are fsynced.
+	Append(rows []model.SensorReadingInsert) error
+	Stats() Stats
+	// Start launches the background replayer.
+	Start()
+	// Close stops the replayer and closes the active segment. Spooled rows stay on disk.
+	Close() error
+}
+
+type Stats struct {
+	Active         bool   `json:"active"`
+	Segments       int    `json:"segments"`
+	Bytes          int64  `json:"bytes"`
+	MaxBytes       int64  `json:"max_bytes"`
+	PendingRecords int64  `json:"pending_records"`
+	Replayed       uint64 `json:"replayed"`
+	Dropped        uint64 `json:"dropped"`
+	Corrupted      uint64 `json:"corrupted"`
+}
+
+// Segment files hold back to back records of
+//
+//	uint32 payload length | uint32 CRC-32C of payload | JSON payload
+//
+// in little endian. A record with a short read or a bad checksum ends the segment.
+const (
+	segmentExt     = ".seg"
+	checkpointName = "checkpoint"
+	headerSize     = 8
+	maxRecordSize  = 1 << 20
+)
+
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+type segment struct {
+	seq      uint64
+	size     int64
+	records  int64 // records appended successfully
+	replayed int64 // records handed to replay, including dropped ones
+}
+
+// checkpoint marks how far the oldest segment has been replayed.
+type checkpoint struct {
+	Segment uint64 `json:"segment"`
+	Offset  int64  `json:"offset"`
+}
+
+type SpoolImpl struct {
+	mu          sync.Mutex
+	segments    []*segment // oldest first, the last one is written to while active is set
+	active      *os.File
+	nextSeq     uint64
+	checkpoint  checkpoint
+	bytes       int64
+	pending     int64
+	unavailable bool
+	closed      bool
+	replayed    uint64
+	dropped     uint64
+	corrupted   uint64
+
+	dir            string
+	segmentBytes   int64
+	maxBytes       int64
+	replayInterval time.Duration
+	replayBatch    int
+	replay         ReplayFunc
+	ping           PingFunc
+	isRetryable    func(error) bool
+	logger         *slog.Logger
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+type SpoolOpts struct {
+	Dir            string
+	SegmentBytes   int64         // rotate the active segment past this size
+	MaxBytes       int64         // Append fails with ErrSpoolFull past this total size
+	ReplayInterval time.Duration // how often the replayer checks the database
+	ReplayBatch    int           // rows per replayed transaction
+	Replay         ReplayFunc
+	Ping           PingFunc
+	// IsRetryable tells a database outage apart from rows that can never be written,
+	// which are dropped so they cannot block the spool. Every error is retried when nil.
+	IsRetryable func(error) bool
+	Logger      *slog.Logger
+}
+
+func NewSpool(opts SpoolOpts) (Spool, error) {
+	if opts.SegmentBytes <= 0 {
+		opts.SegmentBytes = 16 << 20
+	}
+	if opts.MaxBytes <= 0 {
+		opts.MaxBytes = 1 << 30
+	}
+	if opts.ReplayInterval <= 0 {
+		opts.ReplayInterval = time.Second
+	}
+	if opts.ReplayBatch <= 0 {
+		opts.ReplayBatch = 500
+	}
+	if opts.IsRetryable == nil {
+		opts.IsRetryable = func(error) bool { return true }
+	}
+	if opts.Logger == nil {
+		opts.Logger = slog.Default()
+	}
+
+	if err := os.MkdirAll(opts.Dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create spool dir: %w", err)
+	}
+
+	s := &SpoolImpl{
+		dir:            opts.Dir,
+		segmentBytes:   opts.SegmentBytes,
+		maxBytes:       opts.MaxBytes,
+		replayInterval: opts.ReplayInterval,
+		replayBatch:    opts.ReplayBatch,
+		replay:         opts.Replay,
+		ping:           opts.Ping,
+		isRetryable:    opts.IsRetryable,
+		logger:         opts.Logger,
+	}
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// load picks up segments left behind by a previous run.
+func (s *SpoolImpl) load() error {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return err
+	}
+
+	var seqs []uint64
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, segmentExt) {
+			continue
+		}
+		seq, err := strconv.ParseUint(strings.TrimSuffix(name, segmentExt), 10, 64)
+		if err != nil {
+			continue
+		}
+		seqs = append(seqs, seq)
+	}
+	sort.Slice(seqs, func(i, j int) bool { return seqs[i] < seqs[j] })
+
+	if raw, err := os.ReadFile(filepath.Join(s.dir, checkpointName)); err == nil {
+		if err := json.Unmarshal(raw, &s.checkpoint); err != nil {
+			return fmt.Errorf("failed to read spool checkpoint: %w", err)
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	for _, seq := range seqs {
+		path := s.segmentPath(seq)
+		if seq < s.checkpoint.Segment {
+			// fully replayed before the last shutdown
+			if err := os.Remove(path); err != nil {
+				return err
+			}
+			continue
+		}
+
+		seg := &segment{seq: seq}
+		var offset int64
+		if seq == s.checkpoint.Segment {
+			offset = s.checkpoint.Offset
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		seg.size = info.Size()
+		seg.records, err = countRecords(path, offset)
+		if err != nil {
+			return err
+		}
+
+		s.segments = append(s.segments, seg)
+		s.bytes += seg.size
+		s.pending += seg.records
+	}
+
+	if len(s.segments) > 0 {
+		s.nextSeq = s.segments[len(s.segments)-1].seq + 1
+		if s.checkpoint.Segment < s.segments[0].seq {
+			s.checkpoint = checkpoint{Segment: s.segments[0].seq}
+		}
+	} else {
+		s.nextSeq = max(s.checkpoint.Segment, 1)
+		s.checkpoint = checkpoint{Segment: s.nextSeq}
+	}
+
+	// drain leftovers before writing new readings to the database again
+	s.unavailable = s.pending > 0
+	if s.pending > 0 {
+		s.logger.Info("spool holds readings from a previous run", "readings", s.pending)
+	}
+	return nil
+}
+
+func (s *SpoolImpl) segmentPath(seq uint64) string {
+	return filepath.Join(s.dir, fmt.Sprintf("%020d%s", seq, segmentExt))
+}
+
+func (s *SpoolImpl) Active() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.unavailable
+}
+
+func (s *SpoolImpl) MarkUnavailable() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.unavailable {
+		s.logger.Warn("database unavailable, spooling readings to disk")
+	}
+	s.unavailable = true
+}
+
+func (s *SpoolImpl) Append(rows []model.SensorReadingInsert) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	var buf []byte
+	for i := range rows {
+		payload, err := json.Marshal(&rows[i])
+		if err != nil {
+			return err
+		}
+		buf = binary.LittleEndian.AppendUint32(buf, uint32(len(payload)))
+		buf = binary.LittleEndian.AppendUint32(buf, crc32.Checksum(payload, crcTable))
+		buf = append(buf, payload...)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return ErrSpoolClosed
+	}
+	if s.bytes+int64(len(buf)) > s.maxBytes {
+		return ErrSpoolFull
+	}
+
+	if s.active == nil || s.segments[len(s.segments)-1].size >= s.segmentBytes {
+		if err := s.rotate(); err != nil {
+			return err
+		}
+	}
+	seg := s.segments[len(s.segments)-1]
+
+	n, err := s.active.Write(buf)
+	if err == nil {
+		err = s.active.Sync()
+	}
+	seg.size += int64(n)
+	s.bytes += int64(n)
+	if err != nil {
+		// a torn record ends the segment for the reader, so never append after it
+		s.sealActive()
+		return fmt.Errorf("failed to write spool segment: %w", err)
+	}
+
+	seg.records += int64(len(rows))
+	s.pending += int64(len(rows))
+	s.unavailable = true
+	return nil
+}
+
+// rotate seals the active segment and opens a new one. Callers hold mu.
+func (s *SpoolImpl) rotate() error {
+	s.sealActive()
+
+	seq := s.nextSeq
+	f, err := os.OpenFile(s.segmentPath(seq), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o640)
+	if err != nil {
+		return fmt.Errorf("failed to create spool segment: %w", err)
+	}
+	s.nextSeq++
+	s.active = f
+	s.segments = append(s.segments, &segment{seq: seq})
+	return nil
+}
+
+// sealActive closes the active segment so it can be replayed. Callers hold mu.
+func (s *SpoolImpl) sealActive() {
+	if s.active == nil {
+		return
+	}
+	if err := s.active.Close(); err != nil {
+		s.logger.Error("failed to close spool segment", "error", err)
+	}
+	s.active = nil
+}
+
+func (s *SpoolImpl) Stats() Stats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return Stats{
+		Active:         s.unavailable,
+		Segments:       len(s.segments),
+		Bytes:          s.bytes,
+		MaxBytes:       s.maxBytes,
+		PendingRecords: s.pending,
+		Replayed:       s.replayed,
+		Dropped:        s.dropped,
+		Corrupted:      s.corrupted,
+	}
+}
+
+func (s *SpoolImpl) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(s.replayInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.drain(ctx)
+			}
+		}
+	}()
+}
+
+func (s *SpoolImpl) Close() error {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	s.sealActive()
+	return nil
+}
+
+// drain replays sealed segments oldest first until the spool is empty, the
+// database stops answering or ctx is canceled.
+func (s *SpoolImpl) drain(ctx context.Context) {
+	if !s.Active() {
+		return
+	}
+	if err := s.ping(ctx); err != nil {
+		return
+	}
+
+	for ctx.Err() == nil {
+		seg, offset, ok := s.oldestSealed()
+		if !ok {
+			return
+		}
+		if err := s.replaySegment(ctx, seg, offset); err != nil {
+			s.logger.Warn("spool replay paused", "error", err)
+			return
+		}
+	}
+}
+
+// oldestSealed returns the segment to replay next, sealing the active one if it is
+// the only one left. When nothing is pending the spool switches off instead.
+func (s *SpoolImpl) oldestSealed() (*segment, int64, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.pending == 0 && (len(s.segments) == 0 || s.active != nil && len(s.segments) == 1) {
+		s.unavailable = false
+		s.logger.Info("spool drained, writing readings to the database again")
+		return nil, 0, false
+	}
+	if len(s.segments) == 0 {
+		return nil, 0, false
+	}
+	if len(s.segments) == 1 && s.active != nil {
+		s.sealActive()
+	}
+	return s.segments[0], s.checkpoint.Offset, true
+}
+
+func (s *SpoolImpl) replaySegment(ctx context.Context, seg *segment, offset int64) error {
+	f, err := os.Open(s.segmentPath(seg.seq))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return err
+	}
+	reader := bufio.NewReader(f)
+
+	for {
+		rows, read, done, err := readRecords(reader, s.replayBatch)
+		if err != nil {
+			s.logger.Error("spool segment is damaged", "segment", seg.seq, "offset", offset+read, "error", err)
+		}
+
+		if len(rows) > 0 {
+			if replayErr := s.replay(ctx, rows); replayErr != nil {
+				if s.isRetryable(replayErr) {
+					return replayErr
+				}
+				s.logger.Error("dropping spooled readings that cannot be written", "readings", len(rows), "error", replayErr)
+				s.advance(seg, offset+read, int64(len(rows)), true)
+			} else {
+				s.advance(seg, offset+read, int64(len(rows)), false)
+			}
+		}
+		offset += read
+
+		if done || err != nil {
+			break
+		}
+	}
+
+	return s.finishSegment(seg)
+}
+
+// advance records replay progress within seg.
+func (s *SpoolImpl) advance(seg *segment, offset int64, rows int64, dropped bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	seg.replayed += rows
+	s.pending -= rows
+	if dropped {
+		s.dropped += uint64(rows)
+	} else {
+		s.replayed += uint64(rows)
+	}
+	s.checkpoint = checkpoint{Segment: seg.seq, Offset: offset}
+	s.saveCheckpoint()
+}
+
+// finishSegment deletes a fully replayed segment and moves the checkpoint past it.
+func (s *SpoolImpl) finishSegment(seg *segment) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if lost := seg.records - seg.replayed; lost > 0 {
+		// records that were acked but could not be read back
+		s.corrupted += uint64(lost)
+		s.pending -= lost
+	}
+
+	s.segments = s.segments[1:]
+	s.bytes -= seg.size
+
+	next := s.nextSeq
+	if len(s.segments) > 0 {
+		next = s.segments[0].seq
+	}
+	s.checkpoint = checkpoint{Segment: next}
+	s.saveCheckpoint()
+
+	return os.Remove(s.segmentPath(seg.seq))
+}
+
+// saveCheckpoint atomically replaces the checkpoint file. Callers hold mu.
+func (s *SpoolImpl) saveCheckpoint() {
+	raw, err := json.Marshal(s.checkpoint)
+	if err != nil {
+		s.logger.Error("failed to encode spool checkpoint", "error", err)
+		return
+	}
+	tmp := filepath.Join(s.dir, checkpointName+".tmp")
+	if err := os.WriteFile(tmp, raw, 0o640); err != nil {
+		s.logger.Error("failed to write spool checkpoint", "error", err)
+		return
+	}
+	if err := os.Rename(tmp, filepath.Join(s.dir, checkpointName)); err != nil {
+		s.logger.Error("failed to write spool checkpoint", "error", err)
+	}
+}
+
+// readRecords decodes up to limit records, returning how many bytes they took and
+// whether the end of the segment was reached. A torn or corrupt record is reported
+// as an error along with the records read before it.
+func readRecords(reader *bufio.Reader, limit int) ([]model.SensorReadingInsert, int64, bool, error) {
+	rows := make([]model.SensorReadingInsert, 0, limit)
+	var read int64
+	header := make([]byte, headerSize)
+
+	for len(rows) < limit {
+		if _, err := io.ReadFull(reader, header); err != nil {
+			if errors.Is(err, io.EOF) {
+				return rows, read, true, nil
+			}
+			return rows, read, true, fmt.Errorf("torn record header: %w", err)
+		}
+
+		length := binary.LittleEndian.Uint32(header[0:4])
+		sum := binary.LittleEndian.Uint32(header[4:8])
+		if length > maxRecordSize {
+			return rows, read, true, fmt.Errorf("record length %d is implausible", length)
+		}
+
+		payload := make([]byte, length)
+		if _, err := io.ReadFull(reader, payload); err != nil {
+			return rows, read, true, fmt.Errorf("torn record payload: %w", err)
+		}
+		if crc32.Checksum(payload, crcTable) != sum {
+			return rows, read, true, errors.New("record checksum mismatch")
+		}
+
+		var row model.SensorReadingInsert
+		if err := json.Unmarshal(payload, &row); err != nil {
+			return rows, read, true, fmt.Errorf("undecodable record: %w", err)
+		}
+		rows = append(rows, row)
+		read += headerSize + int64(length)
+	}
+	return rows, read, false, nil
+}
+
+func countRecords(path string, offset int64) (int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return 0, err
+	}
+	reader := bufio.NewReader(f)
+
+	var count int64
+	for {
+		rows, _, done, err := readRecords(reader, 1024)
+		count += int64(len(rows))
+		if done || err != nil {
+			return count, nil
+		}
+	}
+}
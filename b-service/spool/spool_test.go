@@ -0,0 +1,444 @@
+package spool
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	model "github.com/Yusufzhafir/worlder-team-assignment/b-service/repository/model"
+	"github.com/Yusufzhafir/worlder-team-assignment/common/logging"
+)
+
+var errDatabaseDown = errors.New("database down")
+
+// recorder is a ReplayFunc that keeps what it was given. failAfter > 0 makes it
+// fail every call once that many rows have been replayed.
+type recorder struct {
+	rows      []model.SensorReadingInsert
+	failAfter int
+}
+
+func (r *recorder) replay(ctx context.Context, rows []model.SensorReadingInsert) error {
+	if r.failAfter > 0 && len(r.rows) >= r.failAfter {
+		return errDatabaseDown
+	}
+	r.rows = append(r.rows, rows...)
+	return nil
+}
+
+func (r *recorder) ids() []int {
+	ids := make([]int, len(r.rows))
+	for i, row := range r.rows {
+		ids[i] = row.ID2
+	}
+	return ids
+}
+
+func rows(from, to int) []model.SensorReadingInsert {
+	var out []model.SensorReadingInsert
+	for id := from; id < to; id++ {
+		out = append(out, model.SensorReadingInsert{
+			SensorValue: float64(id),
+			SensorType:  "TEMP",
+			ID1:         "A",
+			ID2:         id,
+			TS:          time.UnixMilli(int64(id)).UTC(),
+		})
+	}
+	return out
+}
+
+func openSpool(t *testing.T, dir string, rec *recorder, opts SpoolOpts) *SpoolImpl {
+	t.Helper()
+	opts.Dir = dir
+	opts.Replay = rec.replay
+	opts.Ping = func(context.Context) error { return nil }
+	opts.IsRetryable = func(err error) bool { return errors.Is(err, errDatabaseDown) }
+	opts.Logger = logging.Discard()
+	s, err := NewSpool(opts)
+	if err != nil {
+		t.Fatalf("NewSpool: %v", err)
+	}
+	return s.(*SpoolImpl)
+}
+
+func mustAppend(t *testing.T, s Spool, rs []model.SensorReadingInsert) {
+	t.Helper()
+	for i := range rs {
+		if err := s.Append(rs[i : i+1]); err != nil {
+			t.Fatalf("Append: %v", err)
+		}
+	}
+}
+
+func segmentFiles(t *testing.T, dir string) []string {
+	t.Helper()
+	files, err := filepath.Glob(filepath.Join(dir, "*"+segmentExt))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
+
+// recordOffsets returns where each record of the segment at path starts.
+func recordOffsets(t *testing.T, path string) []int64 {
+	t.Helper()
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var offsets []int64
+	for off := int64(0); off < int64(len(raw)); {
+		offsets = append(offsets, off)
+		off += headerSize + int64(binary.LittleEndian.Uint32(raw[off:off+4]))
+	}
+	return offsets
+}
+
+func equalIDs(got []int, want ...int) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestAppendReopenReplay(t *testing.T) {
+	dir := t.TempDir()
+	rec := &recorder{}
+
+	s := openSpool(t, dir, rec, SpoolOpts{SegmentBytes: 256})
+	if s.Active() {
+		t.Fatal("empty spool is active")
+	}
+	mustAppend(t, s, rows(0, 10))
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if err := s.Append(rows(10, 11)); !errors.Is(err, ErrSpoolClosed) {
+		t.Fatalf("Append after Close = %v, want ErrSpoolClosed", err)
+	}
+	if len(segmentFiles(t, dir)) < 2 {
+		t.Fatal("SegmentBytes did not rotate segments")
+	}
+
+	s = openSpool(t, dir, rec, SpoolOpts{SegmentBytes: 256, ReplayBatch: 3})
+	stats := s.Stats()
+	if !stats.Active || stats.PendingRecords != 10 {
+		t.Fatalf("reopened stats = %+v, want active with 10 pending", stats)
+	}
+
+	s.drain(context.Background())
+	if !equalIDs(rec.ids(), 0, 1, 2, 3, 4, 5, 6, 7, 8, 9) {
+		t.Fatalf("replayed %v", rec.ids())
+	}
+	stats = s.Stats()
+	if stats.Active || stats.PendingRecords != 0 || stats.Replayed != 10 || stats.Segments != 0 || stats.Bytes != 0 {
+		t.Fatalf("drained stats = %+v", stats)
+	}
+	if files := segmentFiles(t, dir); len(files) != 0 {
+		t.Fatalf("segments left after drain: %v", files)
+	}
+}
+
+func TestDamagedSegment(t *testing.T) {
+	tests := []struct {
+		name string
+		// damage breaks the segment at path, whose records start at offsets
+		damage        func(t *testing.T, path string, offsets []int64)
+		reopen        bool
+		wantReplayed  []int
+		wantCorrupted uint64
+	}{
+		{
+			name: "truncated final record is not counted on reopen",
+			damage: func(t *testing.T, path string, offsets []int64) {
+				info, _ := os.Stat(path)
+				if err := os.Truncate(path, info.Size()-3); err != nil {
+					t.Fatal(err)
+				}
+			},
+			reopen:       true,
+			wantReplayed: []int{0, 1, 2},
+		},
+		{
+			name: "truncated final record of a live segment counts as corrupted",
+			damage: func(t *testing.T, path string, offsets []int64) {
+				if err := os.Truncate(path, offsets[3]+headerSize+1); err != nil {
+					t.Fatal(err)
+				}
+			},
+			wantReplayed:  []int{0, 1, 2},
+			wantCorrupted: 1,
+		},
+		{
+			name: "checksum mismatch ends the segment on reopen",
+			damage: func(t *testing.T, path string, offsets []int64) {
+				flipByte(t, path, offsets[1]+headerSize)
+			},
+			reopen:       true,
+			wantReplayed: []int{0},
+		},
+		{
+			name: "checksum mismatch in a live segment loses the records after it",
+			damage: func(t *testing.T, path string, offsets []int64) {
+				flipByte(t, path, offsets[1]+headerSize)
+			},
+			wantReplayed:  []int{0},
+			wantCorrupted: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			rec := &recorder{}
+			s := openSpool(t, dir, rec, SpoolOpts{})
+			mustAppend(t, s, rows(0, 4))
+
+			files := segmentFiles(t, dir)
+			if len(files) != 1 {
+				t.Fatalf("got %d segments, want 1", len(files))
+			}
+			tt.damage(t, files[0], recordOffsets(t, files[0]))
+
+			if tt.reopen {
+				if err := s.Close(); err != nil {
+					t.Fatal(err)
+				}
+				s = openSpool(t, dir, rec, SpoolOpts{})
+				if got := s.Stats().PendingRecords; got != int64(len(tt.wantReplayed)) {
+					t.Fatalf("reopened with %d pending, want %d", got, len(tt.wantReplayed))
+				}
+			}
+
+			s.drain(context.Background())
+			if !equalIDs(rec.ids(), tt.wantReplayed...) {
+				t.Fatalf("replayed %v, want %v", rec.ids(), tt.wantReplayed)
+			}
+			stats := s.Stats()
+			if stats.Corrupted != tt.wantCorrupted || stats.PendingRecords != 0 || stats.Active {
+				t.Fatalf("stats = %+v, want %d corrupted and nothing pending", stats, tt.wantCorrupted)
+			}
+		})
+	}
+}
+
+func flipByte(t *testing.T, path string, offset int64) {
+	t.Helper()
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw[offset] ^= 0xff
+	if err := os.WriteFile(path, raw, 0o640); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAppendFull(t *testing.T) {
+	s := openSpool(t, t.TempDir(), &recorder{}, SpoolOpts{MaxBytes: 300})
+	var err error
+	appended := 0
+	for ; appended < 100; appended++ {
+		if err = s.Append(rows(appended, appended+1)); err != nil {
+			break
+		}
+	}
+	if !errors.Is(err, ErrSpoolFull) {
+		t.Fatalf("Append = %v, want ErrSpoolFull", err)
+	}
+	stats := s.Stats()
+	if stats.Bytes > stats.MaxBytes || stats.PendingRecords != int64(appended) {
+		t.Fatalf("stats = %+v after %d appends", stats, appended)
+	}
+}
+
+func TestResumeFromCheckpoint(t *testing.T) {
+	dir := t.TempDir()
+	rec := &recorder{failAfter: 5}
+
+	// two records per segment, the database goes away after five rows
+	s := openSpool(t, dir, rec, SpoolOpts{SegmentBytes: 150, ReplayBatch: 1})
+	mustAppend(t, s, rows(0, 8))
+	if got := len(segmentFiles(t, dir)); got != 4 {
+		t.Fatalf("got %d segments, want 4", got)
+	}
+	s.drain(context.Background())
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if !equalIDs(rec.ids(), 0, 1, 2, 3, 4) {
+		t.Fatalf("replayed %v before the outage", rec.ids())
+	}
+
+	// the first two segments are gone, the third is replayed halfway
+	s = openSpool(t, dir, rec, SpoolOpts{SegmentBytes: 150, ReplayBatch: 1})
+	if s.checkpoint.Segment != 3 || s.checkpoint.Offset == 0 {
+		t.Fatalf("checkpoint = %+v, want partway into segment 3", s.checkpoint)
+	}
+	if stats := s.Stats(); stats.Segments != 2 || stats.PendingRecords != 3 {
+		t.Fatalf("reopened stats = %+v, want 2 segments and 3 pending", stats)
+	}
+
+	rec.failAfter = 0
+	s.drain(context.Background())
+	if !equalIDs(rec.ids(), 0, 1, 2, 3, 4, 5, 6, 7) {
+		t.Fatalf("replayed %v", rec.ids())
+	}
+	if stats := s.Stats(); stats.Active || stats.PendingRecords != 0 {
+		t.Fatalf("drained stats = %+v", stats)
+	}
+}
+
+func TestLoadDropsReplayedSegments(t *testing.T) {
+	dir := t.TempDir()
+	s := openSpool(t, dir, &recorder{}, SpoolOpts{SegmentBytes: 1})
+	mustAppend(t, s, rows(0, 3))
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	// a checkpoint written just before the segment was removed
+	if err := os.WriteFile(filepath.Join(dir, checkpointName), []byte(`{"segment":3,"offset":0}`), 0o640); err != nil {
+		t.Fatal(err)
+	}
+
+	s = openSpool(t, dir, &recorder{}, SpoolOpts{SegmentBytes: 1})
+	if stats := s.Stats(); stats.Segments != 1 || stats.PendingRecords != 1 {
+		t.Fatalf("stats = %+v, want only segment 3", stats)
+	}
+	if _, err := os.Stat(s.segmentPath(1)); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("replayed segment 1 still on disk: %v", err)
+	}
+
+	// new segments continue after the old ones
+	mustAppend(t, s, rows(3, 4))
+	if s.segments[len(s.segments)-1].seq != 4 {
+		t.Fatalf("appended to segment %d, want 4", s.segments[len(s.segments)-1].seq)
+	}
+}
+
+func TestOldestSealedRace(t *testing.T) {
+	rec := &recorder{}
+	s := openSpool(t, t.TempDir(), rec, SpoolOpts{})
+	mustAppend(t, s, rows(0, 2))
+
+	// the replayer seals the only segment, an append arriving meanwhile opens a new one
+	seg, offset, ok := s.oldestSealed()
+	if !ok || s.active != nil {
+		t.Fatal("oldestSealed did not seal the active segment")
+	}
+	mustAppend(t, s, rows(2, 3))
+	if got := s.Stats().Segments; got != 2 {
+		t.Fatalf("got %d segments, want 2", got)
+	}
+
+	if err := s.replaySegment(context.Background(), seg, offset); err != nil {
+		t.Fatalf("replaySegment: %v", err)
+	}
+	if stats := s.Stats(); stats.Segments != 1 || stats.PendingRecords != 1 || stats.Corrupted != 0 {
+		t.Fatalf("stats = %+v, want the new segment pending", stats)
+	}
+
+	s.drain(context.Background())
+	if !equalIDs(rec.ids(), 0, 1, 2) {
+		t.Fatalf("replayed %v", rec.ids())
+	}
+	if s.Active() {
+		t.Fatal("spool still active after draining")
+	}
+}
+
+func TestDropsRowsTheDatabaseRefuses(t *testing.T) {
+	s := openSpool(t, t.TempDir(), &recorder{}, SpoolOpts{})
+	s.replay = func(ctx context.Context, rows []model.SensorReadingInsert) error {
+		return errors.New("Data too long for column 'sensor_type'")
+	}
+	mustAppend(t, s, rows(0, 3))
+
+	s.drain(context.Background())
+	if stats := s.Stats(); stats.Dropped != 3 || stats.PendingRecords != 0 || stats.Active {
+		t.Fatalf("stats = %+v, want 3 dropped", stats)
+	}
+}
+
+func TestReadRecords(t *testing.T) {
+	good := encode(t, rows(0, 3))
+	first := encode(t, rows(0, 1))
+
+	tests := []struct {
+		name     string
+		data     []byte
+		limit    int
+		wantRows int
+		wantRead int
+		wantDone bool
+		wantErr  bool
+	}{
+		{name: "empty", data: nil, limit: 10, wantDone: true},
+		{name: "all records", data: good, limit: 10, wantRows: 3, wantRead: len(good), wantDone: true},
+		{name: "stops at limit", data: good, limit: 1, wantRows: 1, wantRead: len(first)},
+		{name: "torn header", data: append(clone(first), 1, 2, 3), limit: 10, wantRows: 1, wantRead: len(first), wantDone: true, wantErr: true},
+		{name: "torn payload", data: good[:len(good)-1], limit: 10, wantRows: 2, wantRead: len(encode(t, rows(0, 2))), wantDone: true, wantErr: true},
+		{name: "checksum mismatch", data: corrupt(good, len(first)+headerSize), limit: 10, wantRows: 1, wantRead: len(first), wantDone: true, wantErr: true},
+		{name: "implausible length", data: append(binary.LittleEndian.AppendUint32(nil, maxRecordSize+1), 0, 0, 0, 0), limit: 10, wantDone: true, wantErr: true},
+		{name: "undecodable payload", data: record([]byte("not json")), limit: 10, wantDone: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, read, done, err := readRecords(bufio.NewReader(bytes.NewReader(tt.data)), tt.limit)
+			if len(got) != tt.wantRows || read != int64(tt.wantRead) || done != tt.wantDone || (err != nil) != tt.wantErr {
+				t.Fatalf("readRecords = %d rows, %d bytes, done %v, err %v; want %d rows, %d bytes, done %v, err %v",
+					len(got), read, done, err, tt.wantRows, tt.wantRead, tt.wantDone, tt.wantErr)
+			}
+			for i, row := range got {
+				if row.ID2 != i {
+					t.Fatalf("row %d has id2 %d", i, row.ID2)
+				}
+			}
+		})
+	}
+}
+
+// encode returns rs as Append writes them to a segment.
+func encode(t *testing.T, rs []model.SensorReadingInsert) []byte {
+	t.Helper()
+	dir := t.TempDir()
+	s := openSpool(t, dir, &recorder{}, SpoolOpts{})
+	if err := s.Append(rs); err != nil {
+		t.Fatal(err)
+	}
+	raw, err := os.ReadFile(s.segmentPath(s.segments[0].seq))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return raw
+}
+
+func record(payload []byte) []byte {
+	buf := binary.LittleEndian.AppendUint32(nil, uint32(len(payload)))
+	buf = binary.LittleEndian.AppendUint32(buf, crc32.Checksum(payload, crcTable))
+	return append(buf, payload...)
+}
+
+func clone(b []byte) []byte {
+	return append([]byte(nil), b...)
+}
+
+func corrupt(b []byte, offset int) []byte {
+	b = clone(b)
+	b[offset] ^= 0xff
+	return b
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/Yusufzhafir/worlder-team-assignment/b-service/config"
+	"github.com/Yusufzhafir/worlder-team-assignment/b-service/migrate"
+	"github.com/Yusufzhafir/worlder-team-assignment/b-service/repository"
+
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// openStorage opens the backend cfg.BackendName() names. db is the SQL handle
+// behind repo, for the migrator and the pool metrics, and nil for the memory
+// backend. Everything else goes through repo.
+func openStorage(ctx context.Context, cfg config.Database) (repository.SensorRepository, *sqlx.DB, error) {
+	switch cfg.BackendName() {
+	case repository.BackendMemory:
+		return repository.NewMemoryRepository(), nil, nil
+	case repository.BackendSQLite:
+		if err := os.MkdirAll(filepath.Dir(cfg.SQLitePath), 0o755); err != nil {
+			return nil, nil, fmt.Errorf("failed to create the directory of %s: %w", cfg.SQLitePath, err)
+		}
+		db, err := openDB(ctx, "sqlite3", repository.SQLiteDSN(cfg.SQLitePath), cfg)
+		if err != nil {
+			return nil, nil, err
+		}
+		return repository.NewSQLiteRepository(db), db, nil
+	case repository.BackendPostgres:
+		db, err := openDB(ctx, "postgres", cfg.DSN, cfg)
+		if err != nil {
+			return nil, nil, err
+		}
+		return repository.NewPostgresRepository(db), db, nil
+	default:
+		db, err := openDB(ctx, "mysql", cfg.ConnString(), cfg)
+		if err != nil {
+			return nil, nil, err
+		}
+		return repository.NewMySQLRepository(db), db, nil
+	}
+}
+
+// openDB connects to the database and checks it answers within ConnectTimeout.
+func openDB(ctx context.Context, driverName, dsn string, cfg config.Database) (*sqlx.DB, error) {
+	db, err := sqlx.Open(driverName, dsn)
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+
+	pingCtx, cancel := context.WithTimeout(ctx, cfg.ConnectTimeout)
+	defer cancel()
+	if err := db.PingContext(pingCtx); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// newMigrator migrates db with the migrations of cfg.BackendName().
+func newMigrator(cfg config.Database, db *sqlx.DB, logger *slog.Logger) (migrate.Migrator, error) {
+	dialect := migrate.MySQLDialect
+	switch cfg.BackendName() {
+	case repository.BackendPostgres:
+		dialect = migrate.PostgresDialect
+	case repository.BackendSQLite:
+		dialect = migrate.SQLiteDialect
+	}
+	return migrate.NewMigrator(migrate.MigratorOpts{DB: db.DB, Dialect: dialect, LockTimeout: cfg.MigrateLockTimeout, Logger: logger})
+}
+
+// createHypertable makes sensor_readings a TimescaleDB hypertable when
+// database.timescale asks for one, after the migrations.
+func createHypertable(ctx context.Context, cfg config.Database, db *sqlx.DB, logger *slog.Logger) error {
+	if !cfg.Timescale || cfg.BackendName() != repository.BackendPostgres {
+		return nil
+	}
+	created, err := migrate.CreateHypertable(ctx, db.DB, cfg.TimescaleChunk)
+	if err != nil {
+		return err
+	}
+	if !created {
+		logger.WarnContext(ctx, "database.timescale is on but the server has no timescaledb extension, sensor_readings stays a plain table")
+	}
+	return nil
+}
+
+// sqlHandle is db's *sql.DB, nil without one.
+func sqlHandle(db *sqlx.DB) *sql.DB {
+	if db == nil {
+		return nil
+	}
+	return db.DB
+}
@@ -0,0 +1,186 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	repository "github.com/Yusufzhafir/worlder-team-assignment/b-service/repository"
+	"github.com/Yusufzhafir/worlder-team-assignment/b-service/repository/model"
+)
+
+// AggregateFunc is what GetSensorAggregate reports for each bucket.
+type AggregateFunc string
+
+const (
+	AggregateAvg    AggregateFunc = "avg"
+	AggregateMin    AggregateFunc = "min"
+	AggregateMax    AggregateFunc = "max"
+	AggregateSum    AggregateFunc = "sum"
+	AggregateCount  AggregateFunc = "count"
+	AggregateFirst  AggregateFunc = "first"  // value with the earliest ts
+	AggregateLast   AggregateFunc = "last"   // value with the latest ts
+	AggregateStddev AggregateFunc = "stddev" // population standard deviation
+)
+
+// ParseAggregateFuncs parses a comma-separated list such as "avg,max",
+// dropping repeats. An empty list means avg.
+func ParseAggregateFuncs(list string) ([]AggregateFunc, error) {
+	if strings.TrimSpace(list) == "" {
+		return []AggregateFunc{AggregateAvg}, nil
+	}
+	var funcs []AggregateFunc
+	seen := make(map[AggregateFunc]bool)
+	for _, name := range strings.Split(list, ",") {
+		fn := AggregateFunc(strings.ToLower(strings.TrimSpace(name)))
+		switch fn {
+		case AggregateAvg, AggregateMin, AggregateMax, AggregateSum, AggregateCount, AggregateFirst, AggregateLast, AggregateStddev:
+		default:
+			return nil, fmt.Errorf("unknown aggregate %q, expected avg, min, max, sum, count, first, last or stddev", name)
+		}
+		if !seen[fn] {
+			seen[fn] = true
+			funcs = append(funcs, fn)
+		}
+	}
+	return funcs, nil
+}
+
+// Limits of an aggregate request. Buckets are counted per series.
+const (
+	MinAggregateBucket  = time.Second
+	MaxAggregateBuckets = 10000
+)
+
+// ErrInvalidAggregate wraps every reason GetSensorAggregate refuses a request.
+var ErrInvalidAggregate = errors.New("invalid aggregate request")
+
+// AggregateRequest downsamples the readings of IDs or of every sensor of
+// SensorType with From <= ts < To into buckets of Bucket counted from From.
+type AggregateRequest struct {
+	IDs        []repository.IDCombination
+	SensorType string
+	From       time.Time
+	To         time.Time
+	Bucket     time.Duration
+	Funcs      []AggregateFunc // avg when empty
+}
+
+func (req *AggregateRequest) validate() error {
+	if len(req.IDs) == 0 && req.SensorType == "" {
+		return fmt.Errorf("%w: ids or a sensor type must be provided", ErrInvalidAggregate)
+	}
+	if !req.From.Before(req.To) {
+		return fmt.Errorf("%w: from must be before to", ErrInvalidAggregate)
+	}
+	if req.Bucket < MinAggregateBucket || req.Bucket%time.Millisecond != 0 {
+		return fmt.Errorf("%w: bucket must be whole milliseconds and at least %s", ErrInvalidAggregate, MinAggregateBucket)
+	}
+	if buckets := (req.To.Sub(req.From) + req.Bucket - 1) / req.Bucket; buckets > MaxAggregateBuckets {
+		return fmt.Errorf("%w: %d buckets of %s per sensor, at most %d are allowed", ErrInvalidAggregate, buckets, req.Bucket, MaxAggregateBuckets)
+	}
+	return nil
+}
+
+// AggregateSeries is the non-empty buckets of one sensor, in time order.
+type AggregateSeries struct {
+	ID1    string
+	ID2    int
+	Points []AggregatePoint
+}
+
+// AggregatePoint is one bucket. First and Last are only set when requested.
+type AggregatePoint struct {
+	Start  time.Time
+	Count  int64
+	Avg    float64
+	Min    float64
+	Max    float64
+	Sum    float64
+	First  float64
+	Last   float64
+	Stddev float64
+}
+
+// Value returns the result of fn for the bucket.
+func (p AggregatePoint) Value(fn AggregateFunc) float64 {
+	switch fn {
+	case AggregateAvg:
+		return p.Avg
+	case AggregateMin:
+		return p.Min
+	case AggregateMax:
+		return p.Max
+	case AggregateSum:
+		return p.Sum
+	case AggregateCount:
+		return float64(p.Count)
+	case AggregateFirst:
+		return p.First
+	case AggregateLast:
+		return p.Last
+	case AggregateStddev:
+		return p.Stddev
+	}
+	return math.NaN()
+}
+
+func toAggregatePoint(from time.Time, width time.Duration, b model.AggregateBucket) AggregatePoint {
+	avg := b.Sum / float64(b.Count)
+	// rounding can leave the variance of a flat bucket slightly negative
+	variance := max(b.SumOfSquares/float64(b.Count)-avg*avg, 0)
+	return AggregatePoint{
+		Start:  from.Add(time.Duration(b.Bucket) * width),
+		Count:  b.Count,
+		Avg:    avg,
+		Min:    b.Min,
+		Max:    b.Max,
+		Sum:    b.Sum,
+		First:  b.First,
+		Last:   b.Last,
+		Stddev: math.Sqrt(variance),
+	}
+}
+
+// GetSensorAggregate returns one series per matching sensor, ordered by id1 and
+// id2. The database does the aggregation, buckets without readings are left out.
+// Requests outside the limits above fail with ErrInvalidAggregate.
+func (sensorUseCase *SensorUseCaseImpl) GetSensorAggregate(ctx context.Context, req AggregateRequest) ([]AggregateSeries, error) {
+	repo := *sensorUseCase.repo
+	if repo == nil {
+		return nil, fmt.Errorf("repository object is nil %v", repo)
+	}
+	if err := req.validate(); err != nil {
+		return nil, err
+	}
+
+	firstLast := false
+	for _, fn := range req.Funcs {
+		firstLast = firstLast || fn == AggregateFirst || fn == AggregateLast
+	}
+
+	buckets, err := repo.SelectAggregate(ctx, repository.AggregateQuery{
+		IDs:        req.IDs,
+		SensorType: req.SensorType,
+		Start:      req.From,
+		Stop:       req.To,
+		Bucket:     req.Bucket,
+		FirstLast:  firstLast,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	series := make([]AggregateSeries, 0)
+	for _, b := range buckets {
+		if n := len(series); n == 0 || series[n-1].ID1 != b.ID1 || series[n-1].ID2 != b.ID2 {
+			series = append(series, AggregateSeries{ID1: b.ID1, ID2: b.ID2})
+		}
+		last := &series[len(series)-1]
+		last.Points = append(last.Points, toAggregatePoint(req.From, req.Bucket, b))
+	}
+	return series, nil
+}
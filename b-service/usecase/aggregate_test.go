@@ -0,0 +1,90 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	repository "github.com/Yusufzhafir/worlder-team-assignment/b-service/repository"
+	"github.com/Yusufzhafir/worlder-team-assignment/b-service/repository/model"
+)
+
+func TestParseAggregateFuncs(t *testing.T) {
+	tests := []struct {
+		list    string
+		want    []AggregateFunc
+		wantErr bool
+	}{
+		{list: "", want: []AggregateFunc{AggregateAvg}},
+		{list: "max, MIN,max", want: []AggregateFunc{AggregateMax, AggregateMin}},
+		{list: "avg,median", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseAggregateFuncs(tt.list)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("ParseAggregateFuncs(%q) error = %v, wantErr %v", tt.list, err, tt.wantErr)
+		}
+		if len(got) != len(tt.want) {
+			t.Fatalf("ParseAggregateFuncs(%q) = %v, want %v", tt.list, got, tt.want)
+		}
+		for i := range tt.want {
+			if got[i] != tt.want[i] {
+				t.Fatalf("ParseAggregateFuncs(%q) = %v, want %v", tt.list, got, tt.want)
+			}
+		}
+	}
+}
+
+func TestGetSensorAggregate(t *testing.T) {
+	ctx := context.Background()
+	base := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	repo := repository.NewMemoryRepository()
+	var rows []model.SensorReadingInsert
+	for i, value := range []float64{2, 4, 4, 4, 5, 5, 7, 9} {
+		rows = append(rows, model.SensorReadingInsert{SensorValue: value, SensorType: "TEMP", ID1: "A", ID2: 1, TS: base.Add(time.Duration(i) * 10 * time.Second)})
+	}
+	rows = append(rows, model.SensorReadingInsert{SensorValue: 1, SensorType: "TEMP", ID1: "B", ID2: 2, TS: base.Add(90 * time.Second)})
+	if _, err := repo.InsertReadingsBatchTx(ctx, rows, repository.ConflictKeepFirst); err != nil {
+		t.Fatal(err)
+	}
+	uc := NewSensorUseCase(&repo, DedupConfig{}, nil, nil, nil, nil)
+
+	for _, tt := range []struct {
+		name string
+		req  AggregateRequest
+	}{
+		{name: "no filter", req: AggregateRequest{From: base, To: base.Add(time.Hour), Bucket: time.Minute}},
+		{name: "empty range", req: AggregateRequest{SensorType: "TEMP", From: base, To: base, Bucket: time.Minute}},
+		{name: "bucket under a second", req: AggregateRequest{SensorType: "TEMP", From: base, To: base.Add(time.Hour), Bucket: time.Millisecond}},
+		{name: "too many buckets", req: AggregateRequest{SensorType: "TEMP", From: base, To: base.Add(24 * time.Hour), Bucket: time.Second}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := uc.GetSensorAggregate(ctx, tt.req); !errors.Is(err, ErrInvalidAggregate) {
+				t.Fatalf("GetSensorAggregate error = %v, want ErrInvalidAggregate", err)
+			}
+		})
+	}
+
+	series, err := uc.GetSensorAggregate(ctx, AggregateRequest{
+		SensorType: "TEMP",
+		From:       base,
+		To:         base.Add(2 * time.Minute),
+		Bucket:     2 * time.Minute,
+		Funcs:      []AggregateFunc{AggregateStddev, AggregateLast},
+	})
+	if err != nil {
+		t.Fatalf("GetSensorAggregate: %v", err)
+	}
+	if len(series) != 2 || series[0].ID1 != "A" || series[1].ID1 != "B" || len(series[0].Points) != 1 {
+		t.Fatalf("series = %+v, want one point for A/1 and B/2", series)
+	}
+	p := series[0].Points[0]
+	if !p.Start.Equal(base) || p.Count != 8 || p.Avg != 5 || p.Last != 9 || math.Abs(p.Stddev-2) > 1e-9 {
+		t.Fatalf("A/1 point = %+v, want 8 readings averaging 5 with stddev 2 ending at 9", p)
+	}
+	if p.Value(AggregateCount) != 8 || p.Value(AggregateStddev) != p.Stddev {
+		t.Fatalf("Value does not match the point %+v", p)
+	}
+}
@@ -0,0 +1,307 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	repository "github.com/Yusufzhafir/worlder-team-assignment/b-service/repository"
+	"github.com/Yusufzhafir/worlder-team-assignment/b-service/repository/model"
+	pb "github.com/Yusufzhafir/worlder-team-assignment/common/protobuf"
+)
+
+// RangePolicy decides what ingest does with a value outside the range of its sensor type.
+type RangePolicy string
+
+const (
+	RangeFlag   RangePolicy = "flag"   // store the reading with out_of_range set
+	RangeReject RangePolicy = "reject" // refuse the reading as invalid
+)
+
+func ParseRangePolicy(policy string) (RangePolicy, error) {
+	switch RangePolicy(policy) {
+	case RangeFlag, RangeReject:
+		return RangePolicy(policy), nil
+	}
+	return "", fmt.Errorf("unknown range policy %q, expected flag or reject", policy)
+}
+
+// ErrInvalidSensorType wraps every reason the catalog refuses a sensor type.
+var ErrInvalidSensorType = errors.New("invalid sensor type")
+
+// maxSensorTypeAliases bounds the aliases of one type, names and aliases share
+// maxSensorTypeLen with the sensor_type column.
+const maxSensorTypeAliases = 32
+
+// SensorTypeCatalog resolves the sensor type of incoming readings. It serves
+// lookups from memory and reloads from the repository after every edit and
+// every refresh interval, so a catalog edited on another replica shows up
+// within that interval.
+type SensorTypeCatalog interface {
+	// Lookup finds the type named or aliased sensorType, ignoring case.
+	Lookup(sensorType string) (model.SensorType, bool)
+	// Reload replaces the cached catalog with what the repository holds.
+	Reload(ctx context.Context) error
+	// Start loads the catalog, then reloads it every refresh interval until ctx ends.
+	Start(ctx context.Context)
+}
+
+type SensorTypeCatalogImpl struct {
+	repo            *repository.SensorRepository
+	refreshInterval time.Duration
+	logger          *slog.Logger
+	byAlias         atomic.Pointer[map[string]model.SensorType] // lowercase name or alias to its type
+}
+
+type SensorTypeCatalogOpts struct {
+	Repo            *repository.SensorRepository
+	RefreshInterval time.Duration // 0 disables the periodic reload
+	Logger          *slog.Logger
+}
+
+func NewSensorTypeCatalog(opts SensorTypeCatalogOpts) SensorTypeCatalog {
+	if opts.Logger == nil {
+		opts.Logger = slog.Default()
+	}
+	c := &SensorTypeCatalogImpl{
+		repo:            opts.Repo,
+		refreshInterval: opts.RefreshInterval,
+		logger:          opts.Logger,
+	}
+	c.byAlias.Store(&map[string]model.SensorType{})
+	return c
+}
+
+func (c *SensorTypeCatalogImpl) Lookup(sensorType string) (model.SensorType, bool) {
+	t, ok := (*c.byAlias.Load())[strings.ToLower(sensorType)]
+	return t, ok
+}
+
+// Reload keeps the previous catalog when the repository fails, and logs why.
+func (c *SensorTypeCatalogImpl) Reload(ctx context.Context) error {
+	types, err := (*c.repo).SelectSensorTypes(ctx)
+	if err != nil {
+		c.logger.WarnContext(ctx, "failed to reload the sensor type catalog, keeping the previous one", "err", err)
+		return err
+	}
+	byAlias := make(map[string]model.SensorType, len(types))
+	for _, t := range types {
+		byAlias[strings.ToLower(t.Name)] = t
+		for _, alias := range t.Aliases {
+			byAlias[alias] = t
+		}
+	}
+	c.byAlias.Store(&byAlias)
+	return nil
+}
+
+func (c *SensorTypeCatalogImpl) Start(ctx context.Context) {
+	_ = c.Reload(ctx)
+	if c.refreshInterval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(c.refreshInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				_ = c.Reload(ctx)
+			}
+		}
+	}()
+}
+
+// applyCatalog rewrites the sensor type of data to its canonical name, then
+// checks the value against the range of that type. Under the reject policy a
+// value outside it comes back as a violation, under flag as outOfRange. Types
+// missing from the catalog are left as sent.
+func (sensorUseCase *SensorUseCaseImpl) applyCatalog(data *pb.SensorReading) (outOfRange bool, violation *pb.FieldViolation) {
+	if sensorUseCase.catalog == nil {
+		return false, nil
+	}
+	t, ok := sensorUseCase.catalog.Lookup(data.GetSensorType())
+	if !ok {
+		return false, nil
+	}
+	data.SensorType = t.Name
+
+	value := data.GetValue()
+	if (t.MinValue == nil || value >= *t.MinValue) && (t.MaxValue == nil || value <= *t.MaxValue) {
+		return false, nil
+	}
+	if RangePolicy(t.RangePolicy) == RangeReject {
+		return false, &pb.FieldViolation{Field: "value", Description: fmt.Sprintf("%v is outside the range of %s, %s", value, t.Name, describeRange(t))}
+	}
+	return true, nil
+}
+
+func describeRange(t model.SensorType) string {
+	unit := ""
+	if t.Unit != "" {
+		unit = " " + t.Unit
+	}
+	switch {
+	case t.MinValue != nil && t.MaxValue != nil:
+		return fmt.Sprintf("%v to %v%s", *t.MinValue, *t.MaxValue, unit)
+	case t.MinValue != nil:
+		return fmt.Sprintf("at least %v%s", *t.MinValue, unit)
+	default:
+		return fmt.Sprintf("at most %v%s", *t.MaxValue, unit)
+	}
+}
+
+// withViolation adds violation to the error a ReadingValidator returned for the same reading.
+func withViolation(err error, violation *pb.FieldViolation) error {
+	if violation == nil {
+		return err
+	}
+	var invalid *ValidationError
+	if errors.As(err, &invalid) {
+		invalid.Violations = append(invalid.Violations, violation)
+		return invalid
+	}
+	return &ValidationError{Violations: []*pb.FieldViolation{violation}}
+}
+
+// normalizeSensorType checks t and puts it in the form the catalog stores:
+// aliases lowercased, without duplicates or the name itself, and sorted.
+func normalizeSensorType(t *model.SensorType) error {
+	if t.Name == "" || len(t.Name) > maxSensorTypeLen {
+		return fmt.Errorf("%w: name must be 1-%d characters", ErrInvalidSensorType, maxSensorTypeLen)
+	}
+	if t.Name != strings.ToLower(t.Name) || t.Name != strings.TrimSpace(t.Name) {
+		return fmt.Errorf("%w: name %q must be lowercase without surrounding spaces", ErrInvalidSensorType, t.Name)
+	}
+	if len(t.Unit) > maxSensorUnitLen {
+		return fmt.Errorf("%w: unit must be at most %d characters", ErrInvalidSensorType, maxSensorUnitLen)
+	}
+	for _, bound := range []*float64{t.MinValue, t.MaxValue} {
+		if bound != nil && (math.IsNaN(*bound) || math.IsInf(*bound, 0)) {
+			return fmt.Errorf("%w: min and max must be finite numbers", ErrInvalidSensorType)
+		}
+	}
+	if t.MinValue != nil && t.MaxValue != nil && *t.MinValue > *t.MaxValue {
+		return fmt.Errorf("%w: min %v is above max %v", ErrInvalidSensorType, *t.MinValue, *t.MaxValue)
+	}
+	if t.RangePolicy == "" {
+		t.RangePolicy = string(RangeFlag)
+	}
+	if _, err := ParseRangePolicy(t.RangePolicy); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSensorType, err)
+	}
+
+	if len(t.Aliases) > maxSensorTypeAliases {
+		return fmt.Errorf("%w: %d aliases, at most %d are allowed", ErrInvalidSensorType, len(t.Aliases), maxSensorTypeAliases)
+	}
+	aliases := make([]string, 0, len(t.Aliases))
+	seen := map[string]bool{t.Name: true}
+	for _, alias := range t.Aliases {
+		alias = strings.ToLower(strings.TrimSpace(alias))
+		if alias == "" || len(alias) > maxSensorTypeLen {
+			return fmt.Errorf("%w: aliases must be 1-%d characters", ErrInvalidSensorType, maxSensorTypeLen)
+		}
+		if !seen[alias] {
+			seen[alias] = true
+			aliases = append(aliases, alias)
+		}
+	}
+	sort.Strings(aliases)
+	t.Aliases = aliases
+	return nil
+}
+
+// reloadCatalog makes an edit visible to ingest straight away. A failed reload
+// is logged by the catalog and retried by its refresh loop, the edit stands.
+func (sensorUseCase *SensorUseCaseImpl) reloadCatalog(ctx context.Context) {
+	if sensorUseCase.catalog != nil {
+		_ = sensorUseCase.catalog.Reload(ctx)
+	}
+}
+
+// ListSensorTypes returns the whole catalog as stored, ordered by name.
+func (sensorUseCase *SensorUseCaseImpl) ListSensorTypes(ctx context.Context) ([]model.SensorType, error) {
+	repo := *sensorUseCase.repo
+	if repo == nil {
+		return nil, fmt.Errorf("repository object is nil %v", repo)
+	}
+	return repo.SelectSensorTypes(ctx)
+}
+
+// GetSensorType returns the type named name, ignoring case, or repository.ErrNotFound.
+func (sensorUseCase *SensorUseCaseImpl) GetSensorType(ctx context.Context, name string) (model.SensorType, error) {
+	types, err := sensorUseCase.ListSensorTypes(ctx)
+	if err != nil {
+		return model.SensorType{}, err
+	}
+	name = strings.ToLower(name)
+	for _, t := range types {
+		if t.Name == name {
+			return t, nil
+		}
+	}
+	return model.SensorType{}, repository.ErrNotFound
+}
+
+// CreateSensorType adds t to the catalog and returns it as stored. Fails with
+// ErrInvalidSensorType, or repository.ErrAlreadyExists when its name or an
+// alias already names a type.
+func (sensorUseCase *SensorUseCaseImpl) CreateSensorType(ctx context.Context, t model.SensorType) (model.SensorType, error) {
+	repo := *sensorUseCase.repo
+	if repo == nil {
+		return model.SensorType{}, fmt.Errorf("repository object is nil %v", repo)
+	}
+	if err := normalizeSensorType(&t); err != nil {
+		return model.SensorType{}, err
+	}
+	t.CreatedAt = registryNow()
+	t.UpdatedAt = t.CreatedAt
+	if err := repo.InsertSensorType(ctx, &t); err != nil {
+		return model.SensorType{}, err
+	}
+	sensorUseCase.reloadCatalog(ctx)
+	return t, nil
+}
+
+// UpdateSensorType replaces the unit, range, policy and aliases of the type t
+// names, ignoring case, and returns it as stored. Fails with ErrInvalidSensorType,
+// repository.ErrNotFound, or repository.ErrAlreadyExists when an alias names
+// another type. Readings already stored keep their out_of_range flag.
+func (sensorUseCase *SensorUseCaseImpl) UpdateSensorType(ctx context.Context, t model.SensorType) (model.SensorType, error) {
+	repo := *sensorUseCase.repo
+	if repo == nil {
+		return model.SensorType{}, fmt.Errorf("repository object is nil %v", repo)
+	}
+	t.Name = strings.ToLower(t.Name)
+	if err := normalizeSensorType(&t); err != nil {
+		return model.SensorType{}, err
+	}
+	t.UpdatedAt = registryNow()
+	if err := repo.UpdateSensorType(ctx, &t); err != nil {
+		return model.SensorType{}, err
+	}
+	sensorUseCase.reloadCatalog(ctx)
+	return sensorUseCase.GetSensorType(ctx, t.Name)
+}
+
+// DeleteSensorType removes the type named name and its aliases, or returns
+// repository.ErrNotFound. Readings of that type are kept and no longer normalized.
+func (sensorUseCase *SensorUseCaseImpl) DeleteSensorType(ctx context.Context, name string) error {
+	repo := *sensorUseCase.repo
+	if repo == nil {
+		return fmt.Errorf("repository object is nil %v", repo)
+	}
+	if err := repo.DeleteSensorType(ctx, strings.ToLower(name)); err != nil {
+		return err
+	}
+	sensorUseCase.reloadCatalog(ctx)
+	return nil
+}
@@ -0,0 +1,120 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	repository "github.com/Yusufzhafir/worlder-team-assignment/b-service/repository"
+	"github.com/Yusufzhafir/worlder-team-assignment/b-service/repository/model"
+	pb "github.com/Yusufzhafir/worlder-team-assignment/common/protobuf"
+)
+
+func TestSensorTypeCatalogOnIngest(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewMemoryRepository()
+	catalog := NewSensorTypeCatalog(SensorTypeCatalogOpts{Repo: &repo})
+	uc := NewSensorUseCase(&repo, DedupConfig{}, nil, NewReadingValidator(ReadingValidatorOpts{SensorTypes: []string{"temperature", "humidity"}}), nil, catalog)
+
+	for _, tt := range []struct {
+		name string
+		st   model.SensorType
+	}{
+		{name: "uppercase name", st: model.SensorType{Name: "Temperature"}},
+		{name: "long name", st: model.SensorType{Name: "temperature-of-the-boiler-inlet-pipe"}},
+		{name: "empty alias", st: model.SensorType{Name: "temperature", Aliases: []string{" "}}},
+		{name: "inverted range", st: model.SensorType{Name: "temperature", MinValue: ptr(10.0), MaxValue: ptr(0.0)}},
+		{name: "infinite bound", st: model.SensorType{Name: "temperature", MaxValue: ptr(math.Inf(1))}},
+		{name: "unknown policy", st: model.SensorType{Name: "temperature", RangePolicy: "drop"}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := uc.CreateSensorType(ctx, tt.st); !errors.Is(err, ErrInvalidSensorType) {
+				t.Fatalf("CreateSensorType error = %v, want ErrInvalidSensorType", err)
+			}
+		})
+	}
+
+	temperature, err := uc.CreateSensorType(ctx, model.SensorType{
+		Name: "temperature", Unit: "C", MinValue: ptr(-50.0), MaxValue: ptr(150.0), RangePolicy: "reject",
+		Aliases: []string{"TEMP", "temp", "temperature", "t"},
+	})
+	if err != nil {
+		t.Fatalf("CreateSensorType: %v", err)
+	}
+	if len(temperature.Aliases) != 2 || temperature.Aliases[0] != "t" || temperature.Aliases[1] != "temp" {
+		t.Fatalf("aliases = %q, want [t temp]", temperature.Aliases)
+	}
+	if _, err := uc.CreateSensorType(ctx, model.SensorType{Name: "humidity", MaxValue: ptr(100.0), Aliases: []string{"rh"}}); err != nil {
+		t.Fatalf("CreateSensorType with the default policy: %v", err)
+	}
+	if _, err := uc.CreateSensorType(ctx, model.SensorType{Name: "pressure", Aliases: []string{"Temp"}}); !errors.Is(err, repository.ErrAlreadyExists) {
+		t.Fatalf("CreateSensorType with a taken alias = %v, want ErrAlreadyExists", err)
+	}
+
+	type want struct {
+		status     pb.ItemStatus
+		sensorType string
+		outOfRange bool
+	}
+	for _, tt := range []struct {
+		name       string
+		sensorType string
+		value      float64
+		want       want
+	}{
+		{name: "alias in range", sensorType: "TEMP", value: 21.5, want: want{pb.ItemStatus_ITEM_STATUS_STORED, "temperature", false}},
+		{name: "name in another case", sensorType: "Temperature", value: 150, want: want{pb.ItemStatus_ITEM_STATUS_STORED, "temperature", false}},
+		{name: "rejected out of range", sensorType: "t", value: 900, want: want{pb.ItemStatus_ITEM_STATUS_REJECTED_VALIDATION, "temperature", false}},
+		{name: "flagged out of range", sensorType: "RH", value: 120, want: want{pb.ItemStatus_ITEM_STATUS_STORED, "humidity", true}},
+		{name: "unknown type against the allowlist", sensorType: "pressure", value: 1, want: want{pb.ItemStatus_ITEM_STATUS_REJECTED_VALIDATION, "pressure", false}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			data := &pb.SensorReading{Value: tt.value, SensorType: tt.sensorType, Id1: "A", Id2: 1, TimestampMs: time.Now().UnixMilli()}
+			result, err := uc.InsertSensor(ctx, data)
+			if err != nil {
+				t.Fatalf("InsertSensor: %v", err)
+			}
+			got := want{result.GetStatus(), data.GetSensorType(), result.GetOutOfRange()}
+			if got != tt.want {
+				t.Fatalf("InsertSensor = %+v, want %+v", got, tt.want)
+			}
+			if tt.want.status == pb.ItemStatus_ITEM_STATUS_STORED {
+				latest, err := uc.GetLatestReading(ctx, repository.IDCombination{ID1: "A", ID2: 1})
+				if err != nil || latest.SensorType != tt.want.sensorType || latest.OutOfRange != tt.want.outOfRange {
+					t.Fatalf("stored reading = %+v, %v", latest, err)
+				}
+			}
+		})
+	}
+
+	violations := uc.ValidateReading(&pb.SensorReading{Value: -60, SensorType: "temp", Id1: "a", Id2: 1, TimestampMs: time.Now().UnixMilli()})
+	var invalid *ValidationError
+	if !errors.As(violations, &invalid) || len(invalid.Violations) != 2 || invalid.Violations[1].GetField() != "value" {
+		t.Fatalf("ValidateReading = %v, want the id1 and value violations", violations)
+	}
+
+	temperature.RangePolicy, temperature.MaxValue = "flag", nil
+	if _, err := uc.UpdateSensorType(ctx, temperature); err != nil {
+		t.Fatalf("UpdateSensorType: %v", err)
+	}
+	result, err := uc.InsertSensor(ctx, &pb.SensorReading{Value: 900, SensorType: "temp", Id1: "A", Id2: 1, TimestampMs: time.Now().UnixMilli()})
+	if err != nil || result.GetStatus() != pb.ItemStatus_ITEM_STATUS_STORED || result.GetOutOfRange() {
+		t.Fatalf("InsertSensor after widening the range = %+v, %v, want stored in range", result, err)
+	}
+
+	if err := uc.DeleteSensorType(ctx, "HUMIDITY"); err != nil {
+		t.Fatalf("DeleteSensorType: %v", err)
+	}
+	if _, err := uc.GetSensorType(ctx, "humidity"); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("GetSensorType after DeleteSensorType = %v, want ErrNotFound", err)
+	}
+	if _, ok := catalog.Lookup("rh"); ok {
+		t.Fatalf("Lookup of a deleted alias found a type")
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
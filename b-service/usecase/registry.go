@@ -0,0 +1,182 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	repository "github.com/Yusufzhafir/worlder-team-assignment/b-service/repository"
+	"github.com/Yusufzhafir/worlder-team-assignment/b-service/repository/model"
+)
+
+// ErrInvalidSensor wraps every reason the registry refuses a sensor.
+var ErrInvalidSensor = errors.New("invalid sensor")
+
+// Limits of a registered sensor, the text ones match the sensors columns.
+const (
+	maxSensorNameLen     = 128
+	maxSensorUnitLen     = 32
+	maxSensorLocationLen = 128
+	maxSensorOwnerLen    = 64
+	maxSensorLabels      = 64
+	maxLabelKeyLen       = 64
+	maxLabelValueLen     = 256
+)
+
+func validateSensor(s *model.Sensor) error {
+	if !id1Pattern.MatchString(s.ID1) {
+		return fmt.Errorf("%w: id1 %q must be 1-8 uppercase letters or digits", ErrInvalidSensor, s.ID1)
+	}
+	for _, field := range []struct {
+		name  string
+		value string
+		max   int
+	}{
+		{"name", s.Name, maxSensorNameLen},
+		{"unit", s.Unit, maxSensorUnitLen},
+		{"location", s.Location, maxSensorLocationLen},
+		{"owner", s.Owner, maxSensorOwnerLen},
+	} {
+		if len(field.value) > field.max {
+			return fmt.Errorf("%w: %s must be at most %d characters", ErrInvalidSensor, field.name, field.max)
+		}
+	}
+	if math.IsNaN(s.ExpectedRate) || math.IsInf(s.ExpectedRate, 0) || s.ExpectedRate < 0 {
+		return fmt.Errorf("%w: expected rate must be a finite number of readings per second, 0 or more", ErrInvalidSensor)
+	}
+	if len(s.Labels) > maxSensorLabels {
+		return fmt.Errorf("%w: %d labels, at most %d are allowed", ErrInvalidSensor, len(s.Labels), maxSensorLabels)
+	}
+	for key, value := range s.Labels {
+		if key == "" || len(key) > maxLabelKeyLen {
+			return fmt.Errorf("%w: label keys must be 1-%d characters", ErrInvalidSensor, maxLabelKeyLen)
+		}
+		if len(value) > maxLabelValueLen {
+			return fmt.Errorf("%w: label %q must be at most %d characters", ErrInvalidSensor, key, maxLabelValueLen)
+		}
+	}
+	return nil
+}
+
+type PaginatedRegisteredSensors struct {
+	Data  []model.Sensor
+	Count int64
+}
+
+// registryNow is the time stamped on registry writes, at the microsecond
+// precision every backend keeps.
+func registryNow() time.Time {
+	return time.Now().UTC().Truncate(time.Microsecond)
+}
+
+// RegisterSensor adds s to the registry and returns it as stored. Fails with
+// ErrInvalidSensor, or repository.ErrAlreadyExists when its id1/id2 is taken.
+func (sensorUseCase *SensorUseCaseImpl) RegisterSensor(ctx context.Context, s model.Sensor) (model.Sensor, error) {
+	repo := *sensorUseCase.repo
+	if repo == nil {
+		return model.Sensor{}, fmt.Errorf("repository object is nil %v", repo)
+	}
+	if err := validateSensor(&s); err != nil {
+		return model.Sensor{}, err
+	}
+	if s.Labels == nil {
+		s.Labels = model.Labels{}
+	}
+	s.CreatedAt = registryNow()
+	s.UpdatedAt = s.CreatedAt
+	if err := repo.InsertSensor(ctx, &s); err != nil {
+		return model.Sensor{}, err
+	}
+	return s, nil
+}
+
+// GetRegisteredSensor returns the metadata of id, or repository.ErrNotFound.
+func (sensorUseCase *SensorUseCaseImpl) GetRegisteredSensor(ctx context.Context, id repository.IDCombination) (model.Sensor, error) {
+	repo := *sensorUseCase.repo
+	if repo == nil {
+		return model.Sensor{}, fmt.Errorf("repository object is nil %v", repo)
+	}
+	return repo.SelectSensor(ctx, id)
+}
+
+// ListRegisteredSensors pages through the registry ordered by id1 and id2.
+func (sensorUseCase *SensorUseCaseImpl) ListRegisteredSensors(ctx context.Context, limit int, offset int) (PaginatedRegisteredSensors, error) {
+	repo := *sensorUseCase.repo
+	result := PaginatedRegisteredSensors{}
+	if repo == nil {
+		return result, fmt.Errorf("repository object is nil %v", repo)
+	}
+
+	sensors, err := repo.SelectRegisteredSensors(ctx, limit, offset)
+	if err != nil {
+		return result, err
+	}
+	count, err := repo.SelectCountRegisteredSensors(ctx)
+	if err != nil {
+		return result, err
+	}
+	result.Data = sensors
+	result.Count = count
+	return result, nil
+}
+
+// UpdateRegisteredSensor replaces the metadata of the sensor s names and returns
+// it as stored. Fails with ErrInvalidSensor, or repository.ErrNotFound when the
+// sensor is not registered.
+func (sensorUseCase *SensorUseCaseImpl) UpdateRegisteredSensor(ctx context.Context, s model.Sensor) (model.Sensor, error) {
+	repo := *sensorUseCase.repo
+	if repo == nil {
+		return model.Sensor{}, fmt.Errorf("repository object is nil %v", repo)
+	}
+	if err := validateSensor(&s); err != nil {
+		return model.Sensor{}, err
+	}
+	if s.Labels == nil {
+		s.Labels = model.Labels{}
+	}
+	s.UpdatedAt = registryNow()
+	if err := repo.UpdateSensor(ctx, &s); err != nil {
+		return model.Sensor{}, err
+	}
+	return repo.SelectSensor(ctx, repository.IDCombination{ID1: s.ID1, ID2: s.ID2})
+}
+
+// DeregisterSensor removes id from the registry, or returns repository.ErrNotFound.
+// Its readings are kept.
+func (sensorUseCase *SensorUseCaseImpl) DeregisterSensor(ctx context.Context, id repository.IDCombination) error {
+	repo := *sensorUseCase.repo
+	if repo == nil {
+		return fmt.Errorf("repository object is nil %v", repo)
+	}
+	return repo.DeleteSensor(ctx, id)
+}
+
+// LookupSensors returns the registered metadata of the sensors among ids, for
+// joining into reading responses. Unregistered sensors have no entry.
+func (sensorUseCase *SensorUseCaseImpl) LookupSensors(ctx context.Context, ids []repository.IDCombination) (map[repository.IDCombination]model.Sensor, error) {
+	repo := *sensorUseCase.repo
+	if repo == nil {
+		return nil, fmt.Errorf("repository object is nil %v", repo)
+	}
+
+	distinct := make([]repository.IDCombination, 0, len(ids))
+	seen := make(map[repository.IDCombination]bool, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			distinct = append(distinct, id)
+		}
+	}
+
+	sensors, err := repo.SelectSensorsByIDs(ctx, distinct)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[repository.IDCombination]model.Sensor, len(sensors))
+	for _, sensor := range sensors {
+		byID[repository.IDCombination{ID1: sensor.ID1, ID2: sensor.ID2}] = sensor
+	}
+	return byID, nil
+}
@@ -0,0 +1,74 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+
+	repository "github.com/Yusufzhafir/worlder-team-assignment/b-service/repository"
+	"github.com/Yusufzhafir/worlder-team-assignment/b-service/repository/model"
+)
+
+func TestSensorRegistry(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewMemoryRepository()
+	uc := NewSensorUseCase(&repo, DedupConfig{}, nil, nil, nil, nil)
+
+	for _, tt := range []struct {
+		name   string
+		sensor model.Sensor
+	}{
+		{name: "lowercase id1", sensor: model.Sensor{ID1: "abc", ID2: 1}},
+		{name: "long unit", sensor: model.Sensor{ID1: "A", ID2: 1, Unit: strings.Repeat("m", 33)}},
+		{name: "negative rate", sensor: model.Sensor{ID1: "A", ID2: 1, ExpectedRate: -1}},
+		{name: "infinite rate", sensor: model.Sensor{ID1: "A", ID2: 1, ExpectedRate: math.Inf(1)}},
+		{name: "empty label key", sensor: model.Sensor{ID1: "A", ID2: 1, Labels: model.Labels{"": "x"}}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := uc.RegisterSensor(ctx, tt.sensor); !errors.Is(err, ErrInvalidSensor) {
+				t.Fatalf("RegisterSensor error = %v, want ErrInvalidSensor", err)
+			}
+		})
+	}
+
+	registered, err := uc.RegisterSensor(ctx, model.Sensor{ID1: "A", ID2: 1, Name: "boiler", Enabled: true})
+	if err != nil {
+		t.Fatalf("RegisterSensor: %v", err)
+	}
+	if registered.CreatedAt.IsZero() || !registered.UpdatedAt.Equal(registered.CreatedAt) || registered.Labels == nil {
+		t.Fatalf("RegisterSensor = %+v, want timestamps and empty labels set", registered)
+	}
+	if _, err := uc.RegisterSensor(ctx, model.Sensor{ID1: "A", ID2: 1}); !errors.Is(err, repository.ErrAlreadyExists) {
+		t.Fatalf("RegisterSensor twice = %v, want ErrAlreadyExists", err)
+	}
+
+	updated, err := uc.UpdateRegisteredSensor(ctx, model.Sensor{ID1: "A", ID2: 1, Name: "boiler inlet", Labels: model.Labels{"line": "3"}})
+	if err != nil {
+		t.Fatalf("UpdateRegisteredSensor: %v", err)
+	}
+	if updated.Name != "boiler inlet" || updated.Enabled || !updated.CreatedAt.Equal(registered.CreatedAt) || updated.Labels["line"] != "3" {
+		t.Fatalf("UpdateRegisteredSensor = %+v, want the new metadata and the original created_at", updated)
+	}
+	if _, err := uc.UpdateRegisteredSensor(ctx, model.Sensor{ID1: "B", ID2: 2}); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("UpdateRegisteredSensor of an unknown sensor = %v, want ErrNotFound", err)
+	}
+
+	byID, err := uc.LookupSensors(ctx, []repository.IDCombination{{ID1: "A", ID2: 1}, {ID1: "A", ID2: 1}, {ID1: "B", ID2: 2}})
+	if err != nil || len(byID) != 1 || byID[repository.IDCombination{ID1: "A", ID2: 1}].Name != "boiler inlet" {
+		t.Fatalf("LookupSensors = %+v, %v, want only A/1", byID, err)
+	}
+
+	page, err := uc.ListRegisteredSensors(ctx, 10, 0)
+	if err != nil || page.Count != 1 || len(page.Data) != 1 {
+		t.Fatalf("ListRegisteredSensors = %+v, %v, want A/1", page, err)
+	}
+
+	if err := uc.DeregisterSensor(ctx, repository.IDCombination{ID1: "A", ID2: 1}); err != nil {
+		t.Fatalf("DeregisterSensor: %v", err)
+	}
+	if _, err := uc.GetRegisteredSensor(ctx, repository.IDCombination{ID1: "A", ID2: 1}); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("GetRegisteredSensor after DeregisterSensor = %v, want ErrNotFound", err)
+	}
+}
@@ -2,88 +2,453 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
+	"github.com/Yusufzhafir/worlder-team-assignment/b-service/auth"
+	"github.com/Yusufzhafir/worlder-team-assignment/b-service/live"
 	repository "github.com/Yusufzhafir/worlder-team-assignment/b-service/repository"
 	"github.com/Yusufzhafir/worlder-team-assignment/b-service/repository/model"
+	"github.com/Yusufzhafir/worlder-team-assignment/b-service/spool"
 	pb "github.com/Yusufzhafir/worlder-team-assignment/common/protobuf"
-	"github.com/jmoiron/sqlx"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
+var tracer = otel.Tracer("github.com/Yusufzhafir/worlder-team-assignment/b-service/usecase")
+
 type SensorUseCase interface {
-	InsertSensor(ctx context.Context, data *pb.SensorReading) error
-	GetSensorPaginated(ctx context.Context, limit int, offset int) (paginatedSensor, error)
-	GetSensorByTime(ctx context.Context, from time.Time, to time.Time, limit int, offset int) (paginatedSensor, error)
-	GetSensorByIDs(ctx context.Context, idCombinationPtr *[]repository.IDCombination, limit int, offset int) (paginatedSensor, error)
-	GetSensorByIDsAndTime(ctx context.Context, idCombinationPtr *[]repository.IDCombination, from time.Time, to time.Time, limit int, offset int) (paginatedSensor, error)
-	DeleteSensorByIds(ctx context.Context, idCombinationPtr *[]repository.IDCombination) (mutatedResponse, error)
-	DeleteSensorByTime(ctx context.Context, from time.Time, to time.Time) (mutatedResponse, error)
-	DeleteSensorByIdsAndTime(ctx context.Context, idCombinationPtr *[]repository.IDCombination, from time.Time, to time.Time) (mutatedResponse, error)
-	UpdateSensorByIds(ctx context.Context, idCombinationPtr *[]repository.IDCombination, sensorValue float64, sensorType string) (mutatedResponse, error)
-	UpdateSensorByTime(ctx context.Context, from time.Time, to time.Time, sensorValue float64, sensorType string) (mutatedResponse, error)
-	UpdateSensorByIdsAndTime(ctx context.Context, idCombinationPtr *[]repository.IDCombination, from time.Time, to time.Time, sensorValue float64, sensorType string) (mutatedResponse, error)
+	// ValidateReading returns a *ValidationError when reading would be rejected on insert.
+	ValidateReading(data *pb.SensorReading) error
+	InsertSensor(ctx context.Context, data *pb.SensorReading) (*pb.ItemResult, error)
+	InsertSensorBatch(ctx context.Context, data []*pb.SensorReading) ([]*pb.ItemResult, error)
+	ReplaySpooled(ctx context.Context, rows []model.SensorReadingInsert) error
+	GetSensorPaginated(ctx context.Context, limit int, offset int) (PaginatedSensor, error)
+	GetSensorByTime(ctx context.Context, from time.Time, to time.Time, limit int, offset int) (PaginatedSensor, error)
+	GetSensorByIDs(ctx context.Context, idCombinationPtr *[]repository.IDCombination, limit int, offset int) (PaginatedSensor, error)
+	GetSensorByIDsAndTime(ctx context.Context, idCombinationPtr *[]repository.IDCombination, from time.Time, to time.Time, limit int, offset int) (PaginatedSensor, error)
+	DeleteSensorByIds(ctx context.Context, idCombinationPtr *[]repository.IDCombination) (MutatedResponse, error)
+	DeleteSensorByTime(ctx context.Context, from time.Time, to time.Time) (MutatedResponse, error)
+	DeleteSensorByIdsAndTime(ctx context.Context, idCombinationPtr *[]repository.IDCombination, from time.Time, to time.Time) (MutatedResponse, error)
+	UpdateSensorByIds(ctx context.Context, idCombinationPtr *[]repository.IDCombination, sensorValue float64, sensorType string) (MutatedResponse, error)
+	UpdateSensorByTime(ctx context.Context, from time.Time, to time.Time, sensorValue float64, sensorType string) (MutatedResponse, error)
+	UpdateSensorByIdsAndTime(ctx context.Context, idCombinationPtr *[]repository.IDCombination, from time.Time, to time.Time, sensorValue float64, sensorType string) (MutatedResponse, error)
+	GetSensorAggregate(ctx context.Context, req AggregateRequest) ([]AggregateSeries, error)
+	ListSensors(ctx context.Context, rateWindow time.Duration, limit int, offset int) (PaginatedSensorSummaries, error)
+	GetLatestReading(ctx context.Context, id repository.IDCombination) (model.SensorReading, error)
+	RegisterSensor(ctx context.Context, s model.Sensor) (model.Sensor, error)
+	GetRegisteredSensor(ctx context.Context, id repository.IDCombination) (model.Sensor, error)
+	ListRegisteredSensors(ctx context.Context, limit int, offset int) (PaginatedRegisteredSensors, error)
+	UpdateRegisteredSensor(ctx context.Context, s model.Sensor) (model.Sensor, error)
+	DeregisterSensor(ctx context.Context, id repository.IDCombination) error
+	LookupSensors(ctx context.Context, ids []repository.IDCombination) (map[repository.IDCombination]model.Sensor, error)
+	ListSensorTypes(ctx context.Context) ([]model.SensorType, error)
+	GetSensorType(ctx context.Context, name string) (model.SensorType, error)
+	CreateSensorType(ctx context.Context, t model.SensorType) (model.SensorType, error)
+	UpdateSensorType(ctx context.Context, t model.SensorType) (model.SensorType, error)
+	DeleteSensorType(ctx context.Context, name string) error
 }
 
 type SensorUseCaseImpl struct {
-	db   *sqlx.DB
-	repo *repository.SensorRepository
+	repo      *repository.SensorRepository
+	dedup     DedupConfig
+	spool     spool.Spool
+	validator ReadingValidator
+	feed      live.Hub
+	catalog   SensorTypeCatalog
+}
+
+// DedupConfig controls duplicate suppression on ingest.
+type DedupConfig struct {
+	// NaturalKey derives a reading_key from (id1, id2, timestamp_ms) when the client sends none.
+	// Leave it off for producers that may emit several readings per sensor per millisecond.
+	NaturalKey bool
+	Policy     repository.ConflictPolicy
 }
 
+// NewSensorUseCase builds the use case. readingSpool may be nil, in which case a
+// database outage fails the write instead of keeping the readings on local disk.
+// A nil validator only enforces what the sensor_readings columns require. Stored
+// readings are published to feed, which may be nil. Readings are normalized and
+// range checked against catalog, a nil catalog stores sensor types as sent.
 func NewSensorUseCase(
-	db *sqlx.DB,
 	repo *repository.SensorRepository,
+	dedup DedupConfig,
+	readingSpool spool.Spool,
+	validator ReadingValidator,
+	feed live.Hub,
+	catalog SensorTypeCatalog,
 ) SensorUseCase {
+	if dedup.Policy == "" {
+		dedup.Policy = repository.ConflictKeepFirst
+	}
+	if validator == nil {
+		validator = NewReadingValidator(ReadingValidatorOpts{})
+	}
 	return &SensorUseCaseImpl{
-		db:   db,
-		repo: repo,
+		repo:      repo,
+		dedup:     dedup,
+		spool:     readingSpool,
+		validator: validator,
+		feed:      feed,
+		catalog:   catalog,
 	}
 }
 
-func (sensorUseCase *SensorUseCaseImpl) InsertSensor(ctx context.Context, data *pb.SensorReading) error {
-	repo := *sensorUseCase.repo
+// publish hands freshly stored readings to live subscribers.
+func (sensorUseCase *SensorUseCaseImpl) publish(readings ...*pb.SensorReading) {
+	if sensorUseCase.feed != nil && len(readings) > 0 {
+		sensorUseCase.feed.Publish(readings...)
+	}
+}
 
-	if repo == nil {
-		return fmt.Errorf("repository object is nil %v", repo)
+// rejectInvalid marks result as rejected when data fails validation, or as out
+// of range when its sensor type flags the value. Catching these here keeps one
+// bad reading from failing the whole batch insert.
+func (sensorUseCase *SensorUseCaseImpl) rejectInvalid(result *pb.ItemResult, data *pb.SensorReading) bool {
+	outOfRange, violation := sensorUseCase.applyCatalog(data)
+	err := withViolation(sensorUseCase.validator.Validate(data), violation)
+	if err == nil {
+		result.OutOfRange = outOfRange
+		return false
+	}
+	result.Status = pb.ItemStatus_ITEM_STATUS_REJECTED_VALIDATION
+	result.Reason = err.Error()
+	var invalid *ValidationError
+	if errors.As(err, &invalid) {
+		result.Violations = invalid.Violations
 	}
+	return true
+}
+
+// ValidateReading normalizes the sensor type of data as InsertSensor would.
+func (sensorUseCase *SensorUseCaseImpl) ValidateReading(data *pb.SensorReading) error {
+	_, violation := sensorUseCase.applyCatalog(data)
+	return withViolation(sensorUseCase.validator.Validate(data), violation)
+}
+
+// readingKey returns the idempotency key for data, or nil when it should not be deduplicated.
+func (sensorUseCase *SensorUseCaseImpl) readingKey(data *pb.SensorReading) *string {
+	if key := data.GetReadingKey(); key != "" {
+		return &key
+	}
+	if sensorUseCase.dedup.NaturalKey {
+		key := fmt.Sprintf("%s:%d:%d", data.GetId1(), data.GetId2(), data.GetTimestampMs())
+		return &key
+	}
+	return nil
+}
+
+// principal returns the authenticated caller recorded with readings written under
+// ctx, falling back to the client certificate CN when no token was sent.
+func principal(ctx context.Context) *string {
+	if p, ok := auth.CallerFromContext(ctx); ok {
+		return &p
+	}
+	return nil
+}
 
-	id, err := repo.InsertReadingTx(ctx, sensorUseCase.db, &model.SensorReadingInsert{
+func (sensorUseCase *SensorUseCaseImpl) toInsertRow(data *pb.SensorReading, principal *string) model.SensorReadingInsert {
+	return model.SensorReadingInsert{
 		SensorValue: data.GetValue(),
 		SensorType:  data.GetSensorType(),
 		ID1:         data.GetId1(),
 		ID2:         int(data.GetId2()),
 		TS:          time.UnixMilli(data.GetTimestampMs()),
-	})
+		ReadingKey:  sensorUseCase.readingKey(data),
+		Principal:   principal,
+	}
+}
+
+// markDuplicate reports result as a repeat of an already stored reading.
+func (sensorUseCase *SensorUseCaseImpl) markDuplicate(result *pb.ItemResult, readingID uint64) {
+	result.ReadingId = readingID
+	if sensorUseCase.dedup.Policy == repository.ConflictReject {
+		result.Status = pb.ItemStatus_ITEM_STATUS_DUPLICATE_REJECTED
+		result.Reason = "reading_key is already stored"
+		return
+	}
+	result.Status = pb.ItemStatus_ITEM_STATUS_DUPLICATE
+}
+
+// spooling reports whether writes currently go to the spool instead of the database.
+func (sensorUseCase *SensorUseCaseImpl) spooling() bool {
+	return sensorUseCase.spool != nil && sensorUseCase.spool.Active()
+}
+
+// spoolOnFailure decides whether rows that failed to write with err should be
+// kept in the spool, switching the spool on if so.
+func (sensorUseCase *SensorUseCaseImpl) spoolOnFailure(err error) bool {
+	if sensorUseCase.spool == nil || errors.Is(err, context.Canceled) || !repository.IsUnavailable(err) {
+		return false
+	}
+	sensorUseCase.spool.MarkUnavailable()
+	return true
+}
+
+// appendToSpool stores rows on local disk. cause is the database error that sent
+// them there, if any, and is reported alongside a spool failure.
+func (sensorUseCase *SensorUseCaseImpl) appendToSpool(ctx context.Context, rows []model.SensorReadingInsert, cause error) error {
+	_, span := tracer.Start(ctx, "Spool.Append", trace.WithAttributes(attribute.Int("spool.rows", len(rows))))
+	err := sensorUseCase.spool.Append(rows)
+	endSpan(span, err)
+	if err == nil {
+		return nil
+	}
+	if cause != nil {
+		return fmt.Errorf("failed to spool readings after %v: %w", cause, err)
+	}
+	return fmt.Errorf("failed to spool readings: %w", err)
+}
+
+// bypassFullSpool reports whether rows the spool refused with err can go straight
+// to the database instead. A full spool must not fail ingest once the database
+// answers again, even though the replayer has not caught up.
+func (sensorUseCase *SensorUseCaseImpl) bypassFullSpool(ctx context.Context, err error) bool {
+	if !errors.Is(err, spool.ErrSpoolFull) {
+		return false
+	}
+	return (*sensorUseCase.repo).Ping(ctx) == nil
+}
+
+func (sensorUseCase *SensorUseCaseImpl) InsertSensor(ctx context.Context, data *pb.SensorReading) (*pb.ItemResult, error) {
+	ctx, span := tracer.Start(ctx, "SensorUseCase.InsertSensor")
+	result, err := sensorUseCase.insertSensor(ctx, data)
+	if err == nil {
+		span.SetAttributes(attribute.String("item.status", result.GetStatus().String()), attribute.String("item.durability", result.GetDurability().String()))
+	}
+	endSpan(span, err)
+	return result, err
+}
+
+func (sensorUseCase *SensorUseCaseImpl) insertSensor(ctx context.Context, data *pb.SensorReading) (*pb.ItemResult, error) {
+	repo := *sensorUseCase.repo
 
+	if repo == nil {
+		return nil, fmt.Errorf("repository object is nil %v", repo)
+	}
+
+	result := &pb.ItemResult{}
+	if sensorUseCase.rejectInvalid(result, data) {
+		return result, nil
+	}
+
+	row := sensorUseCase.toInsertRow(data, principal(ctx))
+	row.OutOfRange = result.GetOutOfRange()
+	if sensorUseCase.spooling() {
+		err := sensorUseCase.appendToSpool(ctx, []model.SensorReadingInsert{row}, nil)
+		if err == nil {
+			result.Status = pb.ItemStatus_ITEM_STATUS_STORED
+			result.Durability = pb.Durability_DURABILITY_SPOOLED
+			sensorUseCase.publish(data)
+			return result, nil
+		}
+		if !sensorUseCase.bypassFullSpool(ctx, err) {
+			return nil, err
+		}
+	}
+
+	outcome, err := repo.InsertReadingTx(ctx, &row, sensorUseCase.dedup.Policy)
 	if err != nil {
-		return err
+		if !sensorUseCase.spoolOnFailure(err) {
+			return nil, err
+		}
+		if err := sensorUseCase.appendToSpool(ctx, []model.SensorReadingInsert{row}, err); err != nil {
+			return nil, err
+		}
+		result.Status = pb.ItemStatus_ITEM_STATUS_STORED
+		result.Durability = pb.Durability_DURABILITY_SPOOLED
+		sensorUseCase.publish(data)
+		return result, nil
 	}
 
-	if id == 0 {
-		return fmt.Errorf("failed to insert because id returned with %d", id)
+	if outcome.ReadingID == 0 {
+		return nil, fmt.Errorf("failed to insert because id returned with %d", outcome.ReadingID)
 	}
 
-	return nil
+	if outcome.Duplicate {
+		sensorUseCase.markDuplicate(result, outcome.ReadingID)
+		return result, nil
+	}
+	result.Status = pb.ItemStatus_ITEM_STATUS_STORED
+	result.Durability = pb.Durability_DURABILITY_COMMITTED
+	result.ReadingId = outcome.ReadingID
+	sensorUseCase.publish(data)
+	return result, nil
+}
+
+// InsertSensorBatch stores data in a single transaction and reports an outcome for
+// every reading, in input order. Invalid readings are rejected, and readings whose
+// reading_key repeats one earlier in the batch or one already stored are reported
+// as duplicates according to the conflict policy. While the database is unavailable
+// the valid readings are spooled instead and reported as stored without a reading_id.
+// A full spool is bypassed once the database answers again.
+func (sensorUseCase *SensorUseCaseImpl) InsertSensorBatch(ctx context.Context, data []*pb.SensorReading) ([]*pb.ItemResult, error) {
+	ctx, span := tracer.Start(ctx, "SensorUseCase.InsertSensorBatch", trace.WithAttributes(attribute.Int("batch.size", len(data))))
+	results, err := sensorUseCase.insertSensorBatch(ctx, data)
+	endSpan(span, err)
+	return results, err
+}
+
+func (sensorUseCase *SensorUseCaseImpl) insertSensorBatch(ctx context.Context, data []*pb.SensorReading) ([]*pb.ItemResult, error) {
+	repo := *sensorUseCase.repo
+
+	if repo == nil {
+		return nil, fmt.Errorf("repository object is nil %v", repo)
+	}
+
+	results := make([]*pb.ItemResult, len(data))
+	rows := make([]model.SensorReadingInsert, 0, len(data))
+	rowIndex := make([]int, 0, len(data))
+	// rowReading is the reading each row was built from, published once stored
+	rowReading := make([]*pb.SensorReading, 0, len(data))
+	rowByKey := make(map[string]int)
+	// duplicateOf maps a repeated reading to the row that holds its key
+	duplicateOf := make(map[int]int)
+	caller := principal(ctx)
+
+	for i, reading := range data {
+		results[i] = &pb.ItemResult{Index: uint32(i)}
+
+		if sensorUseCase.rejectInvalid(results[i], reading) {
+			continue
+		}
+
+		row := sensorUseCase.toInsertRow(reading, caller)
+		row.OutOfRange = results[i].GetOutOfRange()
+		if row.ReadingKey != nil {
+			if n, ok := rowByKey[*row.ReadingKey]; ok {
+				// repeated within the batch, only one row per key goes to the database
+				if sensorUseCase.dedup.Policy == repository.ConflictKeepLast {
+					// the later reading is the one written, the earlier one becomes its duplicate
+					duplicateOf[rowIndex[n]] = n
+					rowIndex[n] = i
+					rows[n] = row
+					rowReading[n] = reading
+					continue
+				}
+				duplicateOf[i] = n
+				continue
+			}
+			rowByKey[*row.ReadingKey] = len(rows)
+		}
+
+		rowIndex = append(rowIndex, i)
+		rows = append(rows, row)
+		rowReading = append(rowReading, reading)
+	}
+
+	var outcomes []model.InsertOutcome
+	var err error
+	spooled := sensorUseCase.spooling()
+	if spooled {
+		err = sensorUseCase.appendToSpool(ctx, rows, nil)
+		if err != nil && sensorUseCase.bypassFullSpool(ctx, err) {
+			spooled = false
+		}
+	}
+	if !spooled {
+		outcomes, err = repo.InsertReadingsBatchTx(ctx, rows, sensorUseCase.dedup.Policy)
+		if err != nil && sensorUseCase.spoolOnFailure(err) {
+			// the transaction was rolled back, so every row goes to the spool
+			spooled = true
+			err = sensorUseCase.appendToSpool(ctx, rows, err)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for n, i := range rowIndex {
+		if spooled {
+			// keys are only checked against the database when the spool is replayed
+			results[i].Status = pb.ItemStatus_ITEM_STATUS_STORED
+			results[i].Durability = pb.Durability_DURABILITY_SPOOLED
+			continue
+		}
+		if outcomes[n].Duplicate {
+			sensorUseCase.markDuplicate(results[i], outcomes[n].ReadingID)
+			continue
+		}
+		results[i].Status = pb.ItemStatus_ITEM_STATUS_STORED
+		results[i].Durability = pb.Durability_DURABILITY_COMMITTED
+		results[i].ReadingId = outcomes[n].ReadingID
+	}
+	for i, n := range duplicateOf {
+		sensorUseCase.markDuplicate(results[i], results[rowIndex[n]].GetReadingId())
+	}
+
+	stored := make([]*pb.SensorReading, 0, len(rowIndex))
+	for n, i := range rowIndex {
+		if results[i].GetStatus() == pb.ItemStatus_ITEM_STATUS_STORED {
+			stored = append(stored, rowReading[n])
+		}
+	}
+	sensorUseCase.publish(stored...)
+
+	return results, nil
+}
+
+// ReplaySpooled writes rows taken back from the spool. They were never checked
+// against each other, so repeated reading_keys are collapsed per the conflict
+// policy before the batch goes to the database.
+func (sensorUseCase *SensorUseCaseImpl) ReplaySpooled(ctx context.Context, rows []model.SensorReadingInsert) error {
+	repo := *sensorUseCase.repo
+
+	if repo == nil {
+		return fmt.Errorf("repository object is nil %v", repo)
+	}
+
+	unique := make([]model.SensorReadingInsert, 0, len(rows))
+	rowByKey := make(map[string]int)
+	for _, row := range rows {
+		if row.ReadingKey != nil {
+			if n, ok := rowByKey[*row.ReadingKey]; ok {
+				if sensorUseCase.dedup.Policy == repository.ConflictKeepLast {
+					unique[n] = row
+				}
+				continue
+			}
+			rowByKey[*row.ReadingKey] = len(unique)
+		}
+		unique = append(unique, row)
+	}
+
+	ctx, span := tracer.Start(ctx, "SensorUseCase.ReplaySpooled", trace.WithAttributes(attribute.Int("batch.size", len(unique))))
+	_, err := repo.InsertReadingsBatchTx(ctx, unique, sensorUseCase.dedup.Policy)
+	endSpan(span, err)
+	return err
+}
+
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
 }
 
-type paginatedSensor struct {
+type PaginatedSensor struct {
 	Data  []model.SensorReading
 	Count int64
 }
 
-func (sensorUseCase *SensorUseCaseImpl) GetSensorByTime(ctx context.Context, from time.Time, to time.Time, limit int, offset int) (paginatedSensor, error) {
+func (sensorUseCase *SensorUseCaseImpl) GetSensorByTime(ctx context.Context, from time.Time, to time.Time, limit int, offset int) (PaginatedSensor, error) {
 	repo := *sensorUseCase.repo
-	result := paginatedSensor{}
+	result := PaginatedSensor{}
 
 	if repo == nil {
 		return result, fmt.Errorf("repository object is nil %v", repo)
 	}
 
-	rows, err := repo.SelectByTime(ctx, sensorUseCase.db, from, to, limit, offset)
+	rows, err := repo.SelectByTime(ctx, from, to, limit, offset)
 	if err != nil {
 		return result, err
 	}
-	count, err := repo.SelectCountByTime(ctx, sensorUseCase.db, from, to)
+	count, err := repo.SelectCountByTime(ctx, from, to)
 	if err != nil {
 		return result, err
 	}
@@ -94,18 +459,18 @@ func (sensorUseCase *SensorUseCaseImpl) GetSensorByTime(ctx context.Context, fro
 	return result, nil
 }
 
-func (sensorUseCase *SensorUseCaseImpl) GetSensorPaginated(ctx context.Context, limit int, offset int) (paginatedSensor, error) {
+func (sensorUseCase *SensorUseCaseImpl) GetSensorPaginated(ctx context.Context, limit int, offset int) (PaginatedSensor, error) {
 	repo := *sensorUseCase.repo
-	result := paginatedSensor{}
+	result := PaginatedSensor{}
 	if repo == nil {
 		return result, fmt.Errorf("repository object is nil %v", repo)
 	}
 
-	rows, err := repo.SelectSensorDataPaginated(ctx, sensorUseCase.db, limit, offset)
+	rows, err := repo.SelectSensorDataPaginated(ctx, limit, offset)
 	if err != nil {
 		return result, err
 	}
-	count, err := repo.SelectCountPagination(ctx, sensorUseCase.db)
+	count, err := repo.SelectCountPagination(ctx)
 	if err != nil {
 		return result, err
 	}
@@ -116,19 +481,19 @@ func (sensorUseCase *SensorUseCaseImpl) GetSensorPaginated(ctx context.Context,
 	return result, nil
 }
 
-func (sensorUseCase *SensorUseCaseImpl) GetSensorByIDs(ctx context.Context, idCombinationPtr *[]repository.IDCombination, limit int, offset int) (paginatedSensor, error) {
+func (sensorUseCase *SensorUseCaseImpl) GetSensorByIDs(ctx context.Context, idCombinationPtr *[]repository.IDCombination, limit int, offset int) (PaginatedSensor, error) {
 	repo := *sensorUseCase.repo
-	result := paginatedSensor{}
+	result := PaginatedSensor{}
 	idCombination := *idCombinationPtr
 	if repo == nil {
 		return result, fmt.Errorf("repository object is nil %v", repo)
 	}
 
-	rows, err := repo.SelectByIDs(ctx, sensorUseCase.db, idCombination, limit, offset)
+	rows, err := repo.SelectByIDs(ctx, idCombination, limit, offset)
 	if err != nil {
 		return result, err
 	}
-	count, err := repo.SelectCountByIDs(ctx, sensorUseCase.db, idCombination)
+	count, err := repo.SelectCountByIDs(ctx, idCombination)
 	if err != nil {
 		return result, err
 	}
@@ -139,19 +504,19 @@ func (sensorUseCase *SensorUseCaseImpl) GetSensorByIDs(ctx context.Context, idCo
 	return result, nil
 }
 
-func (sensorUseCase *SensorUseCaseImpl) GetSensorByIDsAndTime(ctx context.Context, idCombinationPtr *[]repository.IDCombination, from time.Time, to time.Time, limit int, offset int) (paginatedSensor, error) {
+func (sensorUseCase *SensorUseCaseImpl) GetSensorByIDsAndTime(ctx context.Context, idCombinationPtr *[]repository.IDCombination, from time.Time, to time.Time, limit int, offset int) (PaginatedSensor, error) {
 	repo := *sensorUseCase.repo
-	result := paginatedSensor{}
+	result := PaginatedSensor{}
 	idCombination := *idCombinationPtr
 	if repo == nil {
 		return result, fmt.Errorf("repository object is nil %v", repo)
 	}
 
-	rows, err := repo.SelectByIDsAndTime(ctx, sensorUseCase.db, idCombination, from, to, limit, offset)
+	rows, err := repo.SelectByIDsAndTime(ctx, idCombination, from, to, limit, offset)
 	if err != nil {
 		return result, err
 	}
-	count, err := repo.SelectCountByIDsAndTime(ctx, sensorUseCase.db, idCombination, from, to)
+	count, err := repo.SelectCountByIDsAndTime(ctx, idCombination, from, to)
 	if err != nil {
 		return result, err
 	}
@@ -162,20 +527,20 @@ func (sensorUseCase *SensorUseCaseImpl) GetSensorByIDsAndTime(ctx context.Contex
 	return result, nil
 }
 
-type mutatedResponse struct {
+type MutatedResponse struct {
 	Message string
 	Count   int64
 }
 
-func (sensorUseCase *SensorUseCaseImpl) DeleteSensorByIds(ctx context.Context, idCombinationPtr *[]repository.IDCombination) (mutatedResponse, error) {
+func (sensorUseCase *SensorUseCaseImpl) DeleteSensorByIds(ctx context.Context, idCombinationPtr *[]repository.IDCombination) (MutatedResponse, error) {
 	repo := *sensorUseCase.repo
-	result := mutatedResponse{}
+	result := MutatedResponse{}
 	idCombination := *idCombinationPtr
 	if repo == nil {
 		return result, fmt.Errorf("repository object is nil %v", repo)
 	}
 
-	rows, err := repo.DeleteByIDs(ctx, sensorUseCase.db, idCombination)
+	rows, err := repo.DeleteByIDs(ctx, idCombination)
 	if err != nil {
 		return result, err
 	}
@@ -186,15 +551,15 @@ func (sensorUseCase *SensorUseCaseImpl) DeleteSensorByIds(ctx context.Context, i
 	return result, nil
 }
 
-func (sensorUseCase *SensorUseCaseImpl) DeleteSensorByTime(ctx context.Context, from time.Time, to time.Time) (mutatedResponse, error) {
+func (sensorUseCase *SensorUseCaseImpl) DeleteSensorByTime(ctx context.Context, from time.Time, to time.Time) (MutatedResponse, error) {
 	repo := *sensorUseCase.repo
-	result := mutatedResponse{}
+	result := MutatedResponse{}
 
 	if repo == nil {
 		return result, fmt.Errorf("repository object is nil %v", repo)
 	}
 
-	rows, err := repo.DeleteByTime(ctx, sensorUseCase.db, from, to)
+	rows, err := repo.DeleteByTime(ctx, from, to)
 	if err != nil {
 		return result, err
 	}
@@ -205,15 +570,15 @@ func (sensorUseCase *SensorUseCaseImpl) DeleteSensorByTime(ctx context.Context,
 	return result, nil
 }
 
-func (sensorUseCase *SensorUseCaseImpl) DeleteSensorByIdsAndTime(ctx context.Context, idCombinationPtr *[]repository.IDCombination, from time.Time, to time.Time) (mutatedResponse, error) {
+func (sensorUseCase *SensorUseCaseImpl) DeleteSensorByIdsAndTime(ctx context.Context, idCombinationPtr *[]repository.IDCombination, from time.Time, to time.Time) (MutatedResponse, error) {
 	repo := *sensorUseCase.repo
-	result := mutatedResponse{}
+	result := MutatedResponse{}
 	idCombination := *idCombinationPtr
 	if repo == nil {
 		return result, fmt.Errorf("repository object is nil %v", repo)
 	}
 
-	rows, err := repo.DeleteByIDsAndTime(ctx, sensorUseCase.db, idCombination, from, to)
+	rows, err := repo.DeleteByIDsAndTime(ctx, idCombination, from, to)
 	if err != nil {
 		return result, err
 	}
@@ -224,15 +589,15 @@ func (sensorUseCase *SensorUseCaseImpl) DeleteSensorByIdsAndTime(ctx context.Con
 	return result, nil
 }
 
-func (sensorUseCase *SensorUseCaseImpl) UpdateSensorByIds(ctx context.Context, idCombinationPtr *[]repository.IDCombination, sensorValue float64, sensorType string) (mutatedResponse, error) {
+func (sensorUseCase *SensorUseCaseImpl) UpdateSensorByIds(ctx context.Context, idCombinationPtr *[]repository.IDCombination, sensorValue float64, sensorType string) (MutatedResponse, error) {
 	repo := *sensorUseCase.repo
-	result := mutatedResponse{}
+	result := MutatedResponse{}
 	idCombination := *idCombinationPtr
 	if repo == nil {
 		return result, fmt.Errorf("repository object is nil %v", repo)
 	}
 
-	rows, err := repo.UpdateByIDs(ctx, sensorUseCase.db, idCombination, sensorValue, sensorType)
+	rows, err := repo.UpdateByIDs(ctx, idCombination, sensorValue, sensorType)
 	if err != nil {
 		return result, err
 	}
@@ -243,15 +608,15 @@ func (sensorUseCase *SensorUseCaseImpl) UpdateSensorByIds(ctx context.Context, i
 	return result, nil
 }
 
-func (sensorUseCase *SensorUseCaseImpl) UpdateSensorByTime(ctx context.Context, from time.Time, to time.Time, sensorValue float64, sensorType string) (mutatedResponse, error) {
+func (sensorUseCase *SensorUseCaseImpl) UpdateSensorByTime(ctx context.Context, from time.Time, to time.Time, sensorValue float64, sensorType string) (MutatedResponse, error) {
 	repo := *sensorUseCase.repo
-	result := mutatedResponse{}
+	result := MutatedResponse{}
 
 	if repo == nil {
 		return result, fmt.Errorf("repository object is nil %v", repo)
 	}
 
-	rows, err := repo.UpdateByTime(ctx, sensorUseCase.db, from, to, sensorValue, sensorType)
+	rows, err := repo.UpdateByTime(ctx, from, to, sensorValue, sensorType)
 	if err != nil {
 		return result, err
 	}
@@ -262,15 +627,15 @@ func (sensorUseCase *SensorUseCaseImpl) UpdateSensorByTime(ctx context.Context,
 	return result, nil
 }
 
-func (sensorUseCase *SensorUseCaseImpl) UpdateSensorByIdsAndTime(ctx context.Context, idCombinationPtr *[]repository.IDCombination, from time.Time, to time.Time, sensorValue float64, sensorType string) (mutatedResponse, error) {
+func (sensorUseCase *SensorUseCaseImpl) UpdateSensorByIdsAndTime(ctx context.Context, idCombinationPtr *[]repository.IDCombination, from time.Time, to time.Time, sensorValue float64, sensorType string) (MutatedResponse, error) {
 	repo := *sensorUseCase.repo
-	result := mutatedResponse{}
+	result := MutatedResponse{}
 	idCombination := *idCombinationPtr
 	if repo == nil {
 		return result, fmt.Errorf("repository object is nil %v", repo)
 	}
 
-	rows, err := repo.UpdateByIDsAndTime(ctx, sensorUseCase.db, idCombination, from, to, sensorValue, sensorType)
+	rows, err := repo.UpdateByIDsAndTime(ctx, idCombination, from, to, sensorValue, sensorType)
 	if err != nil {
 		return result, err
 	}
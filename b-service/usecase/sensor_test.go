@@ -0,0 +1,145 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	repository "github.com/Yusufzhafir/worlder-team-assignment/b-service/repository"
+	"github.com/Yusufzhafir/worlder-team-assignment/b-service/repository/model"
+	pb "github.com/Yusufzhafir/worlder-team-assignment/common/protobuf"
+)
+
+// fakeRepository stores batches in memory. Keys in stored count as already in the database.
+type fakeRepository struct {
+	repository.SensorRepository
+	stored  map[string]uint64
+	nextID  uint64
+	written []model.SensorReadingInsert
+}
+
+func (f *fakeRepository) InsertReadingsBatchTx(ctx context.Context, rs []model.SensorReadingInsert, policy repository.ConflictPolicy) ([]model.InsertOutcome, error) {
+	outcomes := make([]model.InsertOutcome, len(rs))
+	for i, row := range rs {
+		if row.ReadingKey != nil {
+			if id, ok := f.stored[*row.ReadingKey]; ok {
+				outcomes[i] = model.InsertOutcome{ReadingID: id, Duplicate: true}
+				if policy == repository.ConflictKeepLast {
+					f.written = append(f.written, row)
+				}
+				continue
+			}
+		}
+		f.nextID++
+		outcomes[i] = model.InsertOutcome{ReadingID: f.nextID}
+		if row.ReadingKey != nil {
+			f.stored[*row.ReadingKey] = f.nextID
+		}
+		f.written = append(f.written, row)
+	}
+	return outcomes, nil
+}
+
+func reading(key string, value float64) *pb.SensorReading {
+	return &pb.SensorReading{
+		Value:       value,
+		SensorType:  "TEMP",
+		Id1:         "A",
+		Id2:         1,
+		TimestampMs: time.Now().UnixMilli(),
+		ReadingKey:  key,
+	}
+}
+
+func TestInsertSensorBatchCollapsesRepeatedKeys(t *testing.T) {
+	type want struct {
+		status    pb.ItemStatus
+		readingID uint64
+	}
+	tests := []struct {
+		name        string
+		policy      repository.ConflictPolicy
+		stored      map[string]uint64
+		batch       []*pb.SensorReading
+		want        []want
+		wantWritten []float64 // sensor values handed to the repository
+	}{
+		{
+			name:   "keep_first stores the first reading of a key",
+			policy: repository.ConflictKeepFirst,
+			batch:  []*pb.SensorReading{reading("k1", 1), reading("", 2), reading("k1", 3)},
+			want: []want{
+				{pb.ItemStatus_ITEM_STATUS_STORED, 1},
+				{pb.ItemStatus_ITEM_STATUS_STORED, 2},
+				{pb.ItemStatus_ITEM_STATUS_DUPLICATE, 1},
+			},
+			wantWritten: []float64{1, 2},
+		},
+		{
+			name:   "keep_last stores the last reading of a key",
+			policy: repository.ConflictKeepLast,
+			batch:  []*pb.SensorReading{reading("k1", 1), reading("k1", 2), reading("k1", 3)},
+			want: []want{
+				{pb.ItemStatus_ITEM_STATUS_DUPLICATE, 1},
+				{pb.ItemStatus_ITEM_STATUS_DUPLICATE, 1},
+				{pb.ItemStatus_ITEM_STATUS_STORED, 1},
+			},
+			wantWritten: []float64{3},
+		},
+		{
+			name:   "reject refuses repeats in the batch and in the database",
+			policy: repository.ConflictReject,
+			stored: map[string]uint64{"old": 40},
+			batch:  []*pb.SensorReading{reading("old", 1), reading("k1", 2), reading("k1", 3)},
+			want: []want{
+				{pb.ItemStatus_ITEM_STATUS_DUPLICATE_REJECTED, 40},
+				{pb.ItemStatus_ITEM_STATUS_STORED, 1},
+				{pb.ItemStatus_ITEM_STATUS_DUPLICATE_REJECTED, 1},
+			},
+			wantWritten: []float64{2},
+		},
+		{
+			name:   "invalid readings are rejected without reaching the repository",
+			policy: repository.ConflictKeepFirst,
+			batch:  []*pb.SensorReading{{Id1: "lower", TimestampMs: 1}, reading("k1", 2)},
+			want: []want{
+				{pb.ItemStatus_ITEM_STATUS_REJECTED_VALIDATION, 0},
+				{pb.ItemStatus_ITEM_STATUS_STORED, 1},
+			},
+			wantWritten: []float64{2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeRepository{stored: map[string]uint64{}}
+			for key, id := range tt.stored {
+				fake.stored[key] = id
+			}
+			var repo repository.SensorRepository = fake
+			uc := NewSensorUseCase(&repo, DedupConfig{Policy: tt.policy}, nil, nil, nil, nil)
+
+			results, err := uc.InsertSensorBatch(context.Background(), tt.batch)
+			if err != nil {
+				t.Fatalf("InsertSensorBatch: %v", err)
+			}
+			if len(results) != len(tt.want) {
+				t.Fatalf("got %d results, want %d", len(results), len(tt.want))
+			}
+			for i, w := range tt.want {
+				if results[i].GetStatus() != w.status || results[i].GetReadingId() != w.readingID {
+					t.Errorf("result %d = %s reading_id %d, want %s reading_id %d",
+						i, results[i].GetStatus(), results[i].GetReadingId(), w.status, w.readingID)
+				}
+			}
+			if len(fake.written) != len(tt.wantWritten) {
+				t.Fatalf("wrote %d rows, want %d", len(fake.written), len(tt.wantWritten))
+			}
+			for i, value := range tt.wantWritten {
+				if fake.written[i].SensorValue != value {
+					t.Errorf("row %d has value %v, want %v", i, fake.written[i].SensorValue, value)
+				}
+			}
+		})
+	}
+}
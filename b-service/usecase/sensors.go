@@ -0,0 +1,68 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	repository "github.com/Yusufzhafir/worlder-team-assignment/b-service/repository"
+	"github.com/Yusufzhafir/worlder-team-assignment/b-service/repository/model"
+)
+
+// DefaultRateWindow is how far back ListSensors measures the ingest rate when
+// the caller does not say.
+const DefaultRateWindow = 5 * time.Minute
+
+// SensorSummary is one distinct (id1, id2) of sensor_readings.
+type SensorSummary struct {
+	model.SensorSummary
+	// IngestRate is readings per second with a timestamp in the rate window.
+	IngestRate float64
+}
+
+type PaginatedSensorSummaries struct {
+	Data  []SensorSummary
+	Count int64
+}
+
+// ListSensors pages through every sensor that has readings, ordered by id1 and
+// id2. rateWindow is how far back from now the ingest rate is measured.
+func (sensorUseCase *SensorUseCaseImpl) ListSensors(ctx context.Context, rateWindow time.Duration, limit int, offset int) (PaginatedSensorSummaries, error) {
+	repo := *sensorUseCase.repo
+	result := PaginatedSensorSummaries{}
+	if repo == nil {
+		return result, fmt.Errorf("repository object is nil %v", repo)
+	}
+	if rateWindow <= 0 {
+		rateWindow = DefaultRateWindow
+	}
+
+	rows, err := repo.SelectSensorSummaries(ctx, time.Now().Add(-rateWindow), limit, offset)
+	if err != nil {
+		return result, err
+	}
+	count, err := repo.SelectCountSensors(ctx)
+	if err != nil {
+		return result, err
+	}
+
+	result.Count = count
+	result.Data = make([]SensorSummary, len(rows))
+	for i, row := range rows {
+		result.Data[i] = SensorSummary{
+			SensorSummary: row,
+			IngestRate:    float64(row.RecentCount) / rateWindow.Seconds(),
+		}
+	}
+	return result, nil
+}
+
+// GetLatestReading returns the reading of id with the latest timestamp, or
+// repository.ErrNotFound when the sensor has none.
+func (sensorUseCase *SensorUseCaseImpl) GetLatestReading(ctx context.Context, id repository.IDCombination) (model.SensorReading, error) {
+	repo := *sensorUseCase.repo
+	if repo == nil {
+		return model.SensorReading{}, fmt.Errorf("repository object is nil %v", repo)
+	}
+	return repo.SelectLatestReading(ctx, id)
+}
@@ -0,0 +1,51 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	repository "github.com/Yusufzhafir/worlder-team-assignment/b-service/repository"
+	"github.com/Yusufzhafir/worlder-team-assignment/b-service/repository/model"
+)
+
+func TestListSensors(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	repo := repository.NewMemoryRepository()
+	rows := []model.SensorReadingInsert{
+		{SensorValue: 1, SensorType: "TEMP", ID1: "A", ID2: 1, TS: now.Add(-10 * time.Minute)},
+		{SensorValue: 2, SensorType: "TEMP", ID1: "A", ID2: 1, TS: now.Add(-3 * time.Minute)},
+		{SensorValue: 3, SensorType: "TEMP", ID1: "A", ID2: 1, TS: now.Add(-2 * time.Minute)},
+		{SensorValue: 4, SensorType: "HUMIDITY", ID1: "A", ID2: 1, TS: now.Add(-time.Minute)},
+		{SensorValue: 9, SensorType: "TEMP", ID1: "B", ID2: 2, TS: now.Add(-time.Hour)},
+	}
+	if _, err := repo.InsertReadingsBatchTx(ctx, rows, repository.ConflictKeepFirst); err != nil {
+		t.Fatal(err)
+	}
+	uc := NewSensorUseCase(&repo, DedupConfig{}, nil, nil, nil, nil)
+
+	result, err := uc.ListSensors(ctx, 5*time.Minute, 10, 0)
+	if err != nil {
+		t.Fatalf("ListSensors: %v", err)
+	}
+	if result.Count != 2 || len(result.Data) != 2 {
+		t.Fatalf("ListSensors = %+v, want A/1 and B/2", result)
+	}
+	a, b := result.Data[0], result.Data[1]
+	if a.Count != 4 || a.LatestValue != 4 || a.SensorType != "HUMIDITY" || a.IngestRate != 3.0/300 {
+		t.Fatalf("A/1 = %+v, want 4 readings, latest 4 HUMIDITY, 3 in the last 5 minutes", a)
+	}
+	if b.IngestRate != 0 {
+		t.Fatalf("B/2 ingest rate = %v, want 0", b.IngestRate)
+	}
+
+	latest, err := uc.GetLatestReading(ctx, repository.IDCombination{ID1: "B", ID2: 2})
+	if err != nil || latest.SensorValue != 9 {
+		t.Fatalf("GetLatestReading = %+v, %v, want the reading of 9", latest, err)
+	}
+	if _, err := uc.GetLatestReading(ctx, repository.IDCombination{ID1: "C", ID2: 3}); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("GetLatestReading of an unknown sensor = %v, want ErrNotFound", err)
+	}
+}
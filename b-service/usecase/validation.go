@@ -0,0 +1,140 @@
+package usecase
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"time"
+
+	pb "github.com/Yusufzhafir/worlder-team-assignment/common/protobuf"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// id1Pattern mirrors the chk_id1_uppercase constraint on sensor_readings.
+var id1Pattern = regexp.MustCompile(`^[A-Z0-9]{1,8}$`)
+
+// maxSensorTypeLen and maxReadingKeyLen match the sensor_readings columns.
+const (
+	maxSensorTypeLen = 32
+	maxReadingKeyLen = 64
+)
+
+// ValidationError lists every field of a reading that failed validation. Returned
+// from a gRPC handler it becomes codes.InvalidArgument carrying a google.rpc.BadRequest.
+type ValidationError struct {
+	Violations []*pb.FieldViolation
+}
+
+func (e *ValidationError) Error() string {
+	parts := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		parts[i] = v.GetField() + ": " + v.GetDescription()
+	}
+	return "invalid reading: " + strings.Join(parts, "; ")
+}
+
+func (e *ValidationError) GRPCStatus() *status.Status {
+	st := status.New(codes.InvalidArgument, e.Error())
+	badRequest := &errdetails.BadRequest{}
+	for _, v := range e.Violations {
+		badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       v.GetField(),
+			Description: v.GetDescription(),
+		})
+	}
+	if detailed, err := st.WithDetails(badRequest); err == nil {
+		return detailed
+	}
+	return st
+}
+
+// ReadingValidator checks readings before they reach the database.
+type ReadingValidator interface {
+	// Validate returns a *ValidationError, or nil when the reading may be stored.
+	Validate(reading *pb.SensorReading) error
+}
+
+type ReadingValidatorImpl struct {
+	id2Min        int32
+	id2Max        int32
+	sensorTypes   map[string]bool
+	maxFutureSkew time.Duration
+	maxAge        time.Duration
+	now           func() time.Time
+}
+
+type ReadingValidatorOpts struct {
+	ID2Min        int32
+	ID2Max        int32         // id2 must lie in [ID2Min, ID2Max]; both zero allows any id2
+	SensorTypes   []string      // allowed sensor types, empty allows any
+	MaxFutureSkew time.Duration // how far ahead of server time timestamp_ms may be, 0 disables
+	MaxAge        time.Duration // how far behind server time timestamp_ms may be, 0 disables
+}
+
+func NewReadingValidator(opts ReadingValidatorOpts) ReadingValidator {
+	v := &ReadingValidatorImpl{
+		id2Min:        opts.ID2Min,
+		id2Max:        opts.ID2Max,
+		maxFutureSkew: opts.MaxFutureSkew,
+		maxAge:        opts.MaxAge,
+		now:           time.Now,
+	}
+	if len(opts.SensorTypes) > 0 {
+		v.sensorTypes = make(map[string]bool, len(opts.SensorTypes))
+		for _, sensorType := range opts.SensorTypes {
+			v.sensorTypes[sensorType] = true
+		}
+	}
+	return v
+}
+
+func (v *ReadingValidatorImpl) Validate(reading *pb.SensorReading) error {
+	var violations []*pb.FieldViolation
+	violate := func(field, format string, args ...any) {
+		violations = append(violations, &pb.FieldViolation{Field: field, Description: fmt.Sprintf(format, args...)})
+	}
+
+	if !id1Pattern.MatchString(reading.GetId1()) {
+		violate("id1", "%q must be 1-8 uppercase letters or digits", reading.GetId1())
+	}
+	if (v.id2Min != 0 || v.id2Max != 0) && (reading.GetId2() < v.id2Min || reading.GetId2() > v.id2Max) {
+		violate("id2", "%d must be between %d and %d", reading.GetId2(), v.id2Min, v.id2Max)
+	}
+
+	sensorType := reading.GetSensorType()
+	switch {
+	case sensorType == "" || len(sensorType) > maxSensorTypeLen:
+		violate("sensor_type", "must be 1-%d characters", maxSensorTypeLen)
+	case v.sensorTypes != nil && !v.sensorTypes[sensorType]:
+		violate("sensor_type", "%q is not an allowed sensor type", sensorType)
+	}
+
+	if value := reading.GetValue(); math.IsNaN(value) || math.IsInf(value, 0) {
+		violate("value", "must be a finite number, got %v", value)
+	}
+
+	if ts := reading.GetTimestampMs(); ts <= 0 {
+		violate("timestamp_ms", "must be a positive unix millisecond timestamp")
+	} else {
+		now := v.now()
+		at := time.UnixMilli(ts)
+		if v.maxFutureSkew > 0 && at.After(now.Add(v.maxFutureSkew)) {
+			violate("timestamp_ms", "is %s ahead of server time, at most %s is allowed", at.Sub(now).Round(time.Millisecond), v.maxFutureSkew)
+		}
+		if v.maxAge > 0 && at.Before(now.Add(-v.maxAge)) {
+			violate("timestamp_ms", "is %s behind server time, at most %s is allowed", now.Sub(at).Round(time.Millisecond), v.maxAge)
+		}
+	}
+
+	if len(reading.GetReadingKey()) > maxReadingKeyLen {
+		violate("reading_key", "must be at most %d characters", maxReadingKeyLen)
+	}
+
+	if len(violations) > 0 {
+		return &ValidationError{Violations: violations}
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package usecase
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/Yusufzhafir/worlder-team-assignment/common/protobuf"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestReadingValidator(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	validator := NewReadingValidator(ReadingValidatorOpts{
+		ID2Min:        1,
+		ID2Max:        100,
+		SensorTypes:   []string{"temperature", "humidity"},
+		MaxFutureSkew: 5 * time.Minute,
+		MaxAge:        24 * time.Hour,
+	})
+	validator.(*ReadingValidatorImpl).now = func() time.Time { return now }
+
+	valid := func(edit func(r *pb.SensorReading)) *pb.SensorReading {
+		r := &pb.SensorReading{
+			Value:       21.5,
+			SensorType:  "temperature",
+			Id1:         "A1B2",
+			Id2:         7,
+			TimestampMs: now.UnixMilli(),
+			ReadingKey:  "key",
+		}
+		if edit != nil {
+			edit(r)
+		}
+		return r
+	}
+
+	tests := []struct {
+		name    string
+		reading *pb.SensorReading
+		want    []string // violated fields, in order
+	}{
+		{name: "valid", reading: valid(nil)},
+		{name: "id1 of eight characters", reading: valid(func(r *pb.SensorReading) { r.Id1 = "ABCDEFG8" })},
+		{name: "id1 lowercase", reading: valid(func(r *pb.SensorReading) { r.Id1 = "abc" }), want: []string{"id1"}},
+		{name: "id1 empty", reading: valid(func(r *pb.SensorReading) { r.Id1 = "" }), want: []string{"id1"}},
+		{name: "id1 too long", reading: valid(func(r *pb.SensorReading) { r.Id1 = "ABCDEFGHI" }), want: []string{"id1"}},
+		{name: "id1 with punctuation", reading: valid(func(r *pb.SensorReading) { r.Id1 = "A-1" }), want: []string{"id1"}},
+		{name: "id2 at the bounds", reading: valid(func(r *pb.SensorReading) { r.Id2 = 100 })},
+		{name: "id2 below the range", reading: valid(func(r *pb.SensorReading) { r.Id2 = 0 }), want: []string{"id2"}},
+		{name: "id2 above the range", reading: valid(func(r *pb.SensorReading) { r.Id2 = 101 }), want: []string{"id2"}},
+		{name: "sensor type not allowed", reading: valid(func(r *pb.SensorReading) { r.SensorType = "pressure" }), want: []string{"sensor_type"}},
+		{name: "sensor type empty", reading: valid(func(r *pb.SensorReading) { r.SensorType = "" }), want: []string{"sensor_type"}},
+		{name: "sensor type too long", reading: valid(func(r *pb.SensorReading) { r.SensorType = strings.Repeat("t", maxSensorTypeLen+1) }), want: []string{"sensor_type"}},
+		{name: "NaN value", reading: valid(func(r *pb.SensorReading) { r.Value = math.NaN() }), want: []string{"value"}},
+		{name: "infinite value", reading: valid(func(r *pb.SensorReading) { r.Value = math.Inf(-1) }), want: []string{"value"}},
+		{name: "timestamp missing", reading: valid(func(r *pb.SensorReading) { r.TimestampMs = 0 }), want: []string{"timestamp_ms"}},
+		{name: "timestamp within the future skew", reading: valid(func(r *pb.SensorReading) { r.TimestampMs = now.Add(5 * time.Minute).UnixMilli() })},
+		{name: "timestamp too far ahead", reading: valid(func(r *pb.SensorReading) { r.TimestampMs = now.Add(6 * time.Minute).UnixMilli() }), want: []string{"timestamp_ms"}},
+		{name: "timestamp within the max age", reading: valid(func(r *pb.SensorReading) { r.TimestampMs = now.Add(-24 * time.Hour).UnixMilli() })},
+		{name: "timestamp too old", reading: valid(func(r *pb.SensorReading) { r.TimestampMs = now.Add(-25 * time.Hour).UnixMilli() }), want: []string{"timestamp_ms"}},
+		{name: "reading key at the limit", reading: valid(func(r *pb.SensorReading) { r.ReadingKey = strings.Repeat("k", maxReadingKeyLen) })},
+		{name: "reading key too long", reading: valid(func(r *pb.SensorReading) { r.ReadingKey = strings.Repeat("k", maxReadingKeyLen+1) }), want: []string{"reading_key"}},
+		{
+			name: "every field at once",
+			reading: &pb.SensorReading{
+				Value:       math.NaN(),
+				SensorType:  "pressure",
+				Id1:         "a",
+				Id2:         500,
+				TimestampMs: now.Add(time.Hour).UnixMilli(),
+				ReadingKey:  strings.Repeat("k", maxReadingKeyLen+1),
+			},
+			want: []string{"id1", "id2", "sensor_type", "value", "timestamp_ms", "reading_key"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.Validate(tt.reading)
+			if len(tt.want) == 0 {
+				if err != nil {
+					t.Fatalf("Validate = %v, want nil", err)
+				}
+				return
+			}
+
+			var invalid *ValidationError
+			if !errors.As(err, &invalid) {
+				t.Fatalf("Validate = %v, want a *ValidationError", err)
+			}
+			st := invalid.GRPCStatus()
+			if st.Code() != codes.InvalidArgument {
+				t.Fatalf("GRPCStatus code = %v, want InvalidArgument", st.Code())
+			}
+			if status.Code(err) != codes.InvalidArgument {
+				t.Fatalf("status.Code = %v, want InvalidArgument", status.Code(err))
+			}
+
+			var got []*errdetails.BadRequest_FieldViolation
+			for _, detail := range st.Details() {
+				if badRequest, ok := detail.(*errdetails.BadRequest); ok {
+					got = append(got, badRequest.GetFieldViolations()...)
+				}
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("field violations = %v, want fields %q", got, tt.want)
+			}
+			for i, v := range got {
+				if v.GetField() != tt.want[i] || v.GetDescription() == "" {
+					t.Fatalf("field violation %d = %v, want field %q with a description", i, v, tt.want[i])
+				}
+				if v.GetDescription() != invalid.Violations[i].GetDescription() {
+					t.Fatalf("field violation %d description = %q, want %q", i, v.GetDescription(), invalid.Violations[i].GetDescription())
+				}
+			}
+		})
+	}
+}
+
+func TestReadingValidatorDefaults(t *testing.T) {
+	validator := NewReadingValidator(ReadingValidatorOpts{})
+	reading := &pb.SensorReading{Value: 1, SensorType: "anything", Id1: "A", Id2: -3, TimestampMs: time.Now().Add(-365 * 24 * time.Hour).UnixMilli()}
+	if err := validator.Validate(reading); err != nil {
+		t.Fatalf("Validate with no limits = %v, want nil", err)
+	}
+}
@@ -0,0 +1,421 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrPrinted is returned by Load once -h or -print-defaults wrote its output,
+// the caller should exit without starting.
+var ErrPrinted = errors.New("config printed")
+
+type LoadOpts struct {
+	Args        []string                        // command line without the program name
+	DefaultPath string                          // read when it exists and neither -config nor PathEnv name a file
+	PathEnv     string                          // variable naming the config file, CONFIG_FILE when empty
+	LookupEnv   func(key string) (string, bool) // os.LookupEnv when nil
+	Output      io.Writer                       // usage and printed defaults, os.Stderr and os.Stdout when nil
+}
+
+// Load fills cfg, a pointer to a struct already holding the defaults, from the
+// YAML file, then the environment, then the command line, each overriding the
+// one before. It returns the path of the file it read, "" when there was none.
+//
+// Every leaf field needs a yaml tag, which also names its flag as the dotted
+// path from the root (-ingest.batch_size). An env tag names its variable, on a
+// struct field it is a prefix for the variables of the fields inside. A usage
+// tag describes the field in -h and -print-defaults. Supported leaf types are
+// strings, bools, ints, floats, time.Duration, []string (comma separated in
+// the environment and flags) and map[string]string (key=value pairs).
+func Load(cfg any, opts LoadOpts) (string, error) {
+	root := reflect.ValueOf(cfg)
+	if root.Kind() != reflect.Pointer || root.Elem().Kind() != reflect.Struct {
+		return "", fmt.Errorf("config must be a pointer to a struct, got %T", cfg)
+	}
+	if opts.LookupEnv == nil {
+		opts.LookupEnv = os.LookupEnv
+	}
+	if opts.PathEnv == "" {
+		opts.PathEnv = "CONFIG_FILE"
+	}
+	usageOutput, defaultsOutput := opts.Output, opts.Output
+	if opts.Output == nil {
+		usageOutput, defaultsOutput = os.Stderr, os.Stdout
+	}
+
+	fields, err := collect(root.Elem(), "", "")
+	if err != nil {
+		return "", err
+	}
+
+	// flags are parsed first to find -config, but applied last
+	flags := flag.NewFlagSet(filepath.Base(os.Args[0]), flag.ContinueOnError)
+	flags.SetOutput(usageOutput)
+	path := flags.String("config", "", "YAML config file, "+opts.PathEnv+" or "+opts.DefaultPath+" when empty")
+	printDefaults := flags.Bool("print-defaults", false, "print the defaults as YAML and exit")
+	raw := make(map[string]*flagValue, len(fields))
+	for _, f := range fields {
+		v := &flagValue{field: f, def: format(f.value)}
+		raw[f.path] = v
+		flags.Var(v, f.path, strings.TrimSpace(f.usage+envNote(f)))
+	}
+	if err := flags.Parse(opts.Args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return "", ErrPrinted
+		}
+		return "", err
+	}
+	if flags.NArg() > 0 {
+		return "", fmt.Errorf("unexpected arguments %q", flags.Args())
+	}
+	if *printDefaults {
+		out, err := Marshal(cfg)
+		if err != nil {
+			return "", err
+		}
+		_, err = defaultsOutput.Write(out)
+		return "", errors.Join(ErrPrinted, err)
+	}
+
+	file, err := readFile(cfg, *path, opts)
+	if err != nil {
+		return "", err
+	}
+
+	for _, f := range fields {
+		if f.env == "" {
+			continue
+		}
+		if val, ok := opts.LookupEnv(f.env); ok && val != "" {
+			if err := set(f.value, val); err != nil {
+				return "", fmt.Errorf("invalid %s=%q: %w", f.env, val, err)
+			}
+		}
+	}
+
+	var flagErr error
+	flags.Visit(func(fl *flag.Flag) {
+		if v, ok := raw[fl.Name]; ok && flagErr == nil {
+			if err := set(v.field.value, v.raw); err != nil {
+				flagErr = fmt.Errorf("invalid -%s=%q: %w", fl.Name, v.raw, err)
+			}
+		}
+	})
+	return file, flagErr
+}
+
+// readFile decodes the config file over cfg, refusing keys cfg does not have.
+func readFile(cfg any, path string, opts LoadOpts) (string, error) {
+	explicit := path != ""
+	if !explicit {
+		path, explicit = opts.LookupEnv(opts.PathEnv)
+		explicit = explicit && path != ""
+	}
+	if !explicit {
+		path = opts.DefaultPath
+	}
+	if path == "" {
+		return "", nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		if !explicit && errors.Is(err, fs.ErrNotExist) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to open config file: %w", err)
+	}
+	defer f.Close()
+
+	decoder := yaml.NewDecoder(f)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("failed to read config file %s: %w", path, err)
+	}
+	return path, nil
+}
+
+type field struct {
+	path   string
+	env    string
+	usage  string
+	secret bool
+	value  reflect.Value
+}
+
+func collect(v reflect.Value, path, envPrefix string) ([]field, error) {
+	var fields []field
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		name, _, _ := strings.Cut(sf.Tag.Get("yaml"), ",")
+		if !sf.IsExported() || name == "-" {
+			continue
+		}
+		if name == "" {
+			return nil, fmt.Errorf("config field %s has no yaml tag", sf.Name)
+		}
+		fieldPath := name
+		if path != "" {
+			fieldPath = path + "." + name
+		}
+		env := sf.Tag.Get("env")
+		if env != "" {
+			env = envPrefix + env
+		}
+		fv := v.Field(i)
+		if sf.Type.Kind() == reflect.Struct && sf.Type != durationType {
+			nested, err := collect(fv, fieldPath, env)
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, nested...)
+			continue
+		}
+		if err := supported(sf.Type); err != nil {
+			return nil, fmt.Errorf("config field %s: %w", fieldPath, err)
+		}
+		fields = append(fields, field{
+			path:   fieldPath,
+			env:    env,
+			usage:  sf.Tag.Get("usage"),
+			secret: sf.Tag.Get("secret") == "true",
+			value:  fv,
+		})
+	}
+	return fields, nil
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+func supported(t reflect.Type) error {
+	switch t.Kind() {
+	case reflect.String, reflect.Bool, reflect.Int, reflect.Int32, reflect.Int64, reflect.Uint32, reflect.Uint64, reflect.Float64:
+		return nil
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.String {
+			return nil
+		}
+	case reflect.Map:
+		if t.Key().Kind() == reflect.String && t.Elem().Kind() == reflect.String {
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported type %s", t)
+}
+
+// set parses s the way the environment and flags spell a value.
+func set(v reflect.Value, s string) error {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return errors.New("want true or false")
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		if v.Type() == durationType {
+			d, err := time.ParseDuration(s)
+			if err != nil {
+				return errors.New("want a duration such as 500ms or 5s")
+			}
+			v.SetInt(int64(d))
+			return nil
+		}
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return errors.New("want an integer")
+		}
+		v.SetInt(n)
+	case reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return errors.New("want a non-negative integer")
+		}
+		v.SetUint(n)
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return errors.New("want a number")
+		}
+		v.SetFloat(f)
+	case reflect.Slice:
+		var list []string
+		for _, item := range strings.Split(s, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+		v.Set(reflect.ValueOf(list).Convert(v.Type()))
+	case reflect.Map:
+		m := reflect.MakeMap(v.Type())
+		for _, pair := range strings.Split(s, ",") {
+			if pair = strings.TrimSpace(pair); pair == "" {
+				continue
+			}
+			key, val, ok := strings.Cut(pair, "=")
+			if !ok || key == "" || val == "" {
+				return errors.New("want key=value pairs separated by commas")
+			}
+			m.SetMapIndex(reflect.ValueOf(key), reflect.ValueOf(val))
+		}
+		v.Set(m)
+	}
+	return nil
+}
+
+// format spells v the way set parses it.
+func format(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.Slice:
+		return strings.Join(v.Interface().([]string), ",")
+	case reflect.Map:
+		keys := make([]string, 0, v.Len())
+		for _, key := range v.MapKeys() {
+			keys = append(keys, key.String()+"="+v.MapIndex(key).String())
+		}
+		sort.Strings(keys)
+		return strings.Join(keys, ",")
+	}
+	if v.Type() == durationType {
+		return time.Duration(v.Int()).String()
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+func envNote(f field) string {
+	if f.env == "" {
+		return ""
+	}
+	return " (env " + f.env + ")"
+}
+
+// flagValue keeps the command line value until the file and environment are applied.
+type flagValue struct {
+	field field
+	def   string
+	raw   string
+}
+
+func (v *flagValue) String() string {
+	if v == nil {
+		return ""
+	}
+	if v.field.secret {
+		return ""
+	}
+	return v.def
+}
+
+func (v *flagValue) Set(s string) error {
+	// check now so a typo fails before anything is read
+	if err := set(reflect.New(v.field.value.Type()).Elem(), s); err != nil {
+		return err
+	}
+	v.raw = s
+	return nil
+}
+
+// IsBoolFlag lets -spool.enabled stand for -spool.enabled=true.
+func (v *flagValue) IsBoolFlag() bool {
+	return v != nil && v.field.value.Kind() == reflect.Bool
+}
+
+// Marshal writes cfg as YAML, with each field's usage and environment variable
+// as a comment and secrets left empty. Loading the output gives cfg back.
+func Marshal(cfg any) ([]byte, error) {
+	root := reflect.ValueOf(cfg)
+	if root.Kind() == reflect.Pointer {
+		root = root.Elem()
+	}
+	node, err := marshalStruct(root, "")
+	if err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(node)
+}
+
+func marshalStruct(v reflect.Value, envPrefix string) (*yaml.Node, error) {
+	node := &yaml.Node{Kind: yaml.MappingNode}
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		name, _, _ := strings.Cut(sf.Tag.Get("yaml"), ",")
+		if !sf.IsExported() || name == "-" || name == "" {
+			continue
+		}
+		env := sf.Tag.Get("env")
+		if env != "" {
+			env = envPrefix + env
+		}
+		key := &yaml.Node{Kind: yaml.ScalarNode, Value: name}
+		fv := v.Field(i)
+
+		var value *yaml.Node
+		if sf.Type.Kind() == reflect.Struct && sf.Type != durationType {
+			nested, err := marshalStruct(fv, env)
+			if err != nil {
+				return nil, err
+			}
+			value = nested
+			key.HeadComment = sf.Tag.Get("usage")
+		} else {
+			comment := sf.Tag.Get("usage")
+			if env != "" {
+				comment = strings.TrimSpace(comment + envNote(field{env: env}))
+			}
+			key.HeadComment = comment
+			if sf.Tag.Get("secret") == "true" {
+				fv = reflect.Zero(sf.Type)
+			}
+			value = marshalLeaf(fv)
+		}
+		node.Content = append(node.Content, key, value)
+	}
+	return node, nil
+}
+
+func marshalLeaf(v reflect.Value) *yaml.Node {
+	switch v.Kind() {
+	case reflect.Slice:
+		node := &yaml.Node{Kind: yaml.SequenceNode, Style: yaml.FlowStyle}
+		for i := 0; i < v.Len(); i++ {
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: v.Index(i).String()})
+		}
+		return node
+	case reflect.Map:
+		node := &yaml.Node{Kind: yaml.MappingNode}
+		keys := make([]string, 0, v.Len())
+		for _, key := range v.MapKeys() {
+			keys = append(keys, key.String())
+		}
+		sort.Strings(keys)
+		if len(keys) == 0 {
+			node.Style = yaml.FlowStyle
+		}
+		for _, key := range keys {
+			node.Content = append(node.Content,
+				&yaml.Node{Kind: yaml.ScalarNode, Value: key},
+				&yaml.Node{Kind: yaml.ScalarNode, Value: v.MapIndex(reflect.ValueOf(key)).String()},
+			)
+		}
+		return node
+	case reflect.String:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: v.String()}
+	}
+	return &yaml.Node{Kind: yaml.ScalarNode, Value: format(v)}
+}
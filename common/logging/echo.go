@@ -0,0 +1,68 @@
+package logging
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// EchoMiddleware takes the request ID from X-Request-Id or makes one, echoes it
+// on the response and stores it in the request context, then writes one access
+// log per request. Requests to quietRoutes, such as probes and scrapes, are
+// logged at debug. It must run inside the tracing middleware to see the span.
+func EchoMiddleware(logger *slog.Logger, quietRoutes ...string) echo.MiddlewareFunc {
+	quiet := make(map[string]bool, len(quietRoutes))
+	for _, route := range quietRoutes {
+		quiet[route] = true
+	}
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			id := req.Header.Get(RequestIDHeader)
+			if id == "" || len(id) > 128 {
+				id = NewRequestID()
+			}
+			ctx := WithRequestID(req.Context(), id)
+			c.SetRequest(req.WithContext(ctx))
+			c.Response().Header().Set(RequestIDHeader, id)
+
+			start := time.Now()
+			err := next(c)
+
+			// the error handler has not written the response yet
+			code := c.Response().Status
+			if err != nil {
+				code = http.StatusInternalServerError
+				var httpErr *echo.HTTPError
+				if errors.As(err, &httpErr) {
+					code = httpErr.Code
+				}
+			}
+			level := slog.LevelInfo
+			switch {
+			case code >= http.StatusInternalServerError:
+				level = slog.LevelError
+			case code >= http.StatusBadRequest:
+				level = slog.LevelWarn
+			case quiet[c.Path()]:
+				level = slog.LevelDebug
+			}
+			attrs := []slog.Attr{
+				slog.String("method", req.Method),
+				slog.String("uri", req.RequestURI),
+				slog.String("route", c.Path()),
+				slog.Int("status", code),
+				slog.Duration("latency", time.Since(start)),
+				slog.String("remote_ip", c.RealIP()),
+			}
+			if err != nil {
+				attrs = append(attrs, slog.String("error", err.Error()))
+			}
+			logger.LogAttrs(ctx, level, "http request", attrs...)
+			return err
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+var requestIDMetadata = strings.ToLower(RequestIDHeader)
+
+// health probes succeed every few seconds and are only logged at debug
+const healthService = "/grpc.health.v1.Health/"
+
+// UnaryServerInterceptor takes the request ID from x-request-id metadata or
+// makes one, returns it as a response header and logs the call when it ends.
+// Successful calls are sampled to one in sampleEvery since Readings runs for
+// every reading, failures are always logged.
+func UnaryServerInterceptor(logger *slog.Logger, sampleEvery int) grpc.UnaryServerInterceptor {
+	sampled := Sample(logger, sampleEvery)
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, id := incomingRequestID(ctx)
+		_ = grpc.SetHeader(ctx, metadata.Pairs(requestIDMetadata, id))
+
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		logCall(ctx, logger, sampled, info.FullMethod, start, err)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor is UnaryServerInterceptor for streams. Every stream
+// is logged when it ends, streams are long lived and few.
+func StreamServerInterceptor(logger *slog.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, id := incomingRequestID(ss.Context())
+		_ = ss.SetHeader(metadata.Pairs(requestIDMetadata, id))
+
+		start := time.Now()
+		err := handler(srv, &requestIDStream{ServerStream: ss, ctx: ctx})
+		logCall(ctx, logger, logger, info.FullMethod, start, err)
+		return err
+	}
+}
+
+// UnaryClientInterceptor passes the request ID of ctx on as x-request-id.
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(outgoingRequestID(ctx), method, req, reply, cc, opts...)
+	}
+}
+
+// StreamClientInterceptor passes the request ID of ctx on as x-request-id.
+func StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(outgoingRequestID(ctx), desc, cc, method, opts...)
+	}
+}
+
+func incomingRequestID(ctx context.Context) (context.Context, string) {
+	if id := RequestID(ctx); id != "" {
+		return ctx, id
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	id := NewRequestID()
+	if ids := md.Get(requestIDMetadata); len(ids) > 0 && ids[0] != "" && len(ids[0]) <= 128 {
+		id = ids[0]
+	}
+	return WithRequestID(ctx, id), id
+}
+
+func outgoingRequestID(ctx context.Context) context.Context {
+	id := RequestID(ctx)
+	if id == "" {
+		return ctx
+	}
+	if md, ok := metadata.FromOutgoingContext(ctx); ok && len(md.Get(requestIDMetadata)) > 0 {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, requestIDMetadata, id)
+}
+
+func logCall(ctx context.Context, logger, sampled *slog.Logger, method string, start time.Time, err error) {
+	code := status.Code(err)
+	attrs := []slog.Attr{
+		slog.String("method", method),
+		slog.String("code", code.String()),
+		slog.Duration("latency", time.Since(start)),
+	}
+	switch code {
+	case codes.OK:
+		if strings.HasPrefix(method, healthService) {
+			logger.LogAttrs(ctx, slog.LevelDebug, "grpc call", attrs...)
+			return
+		}
+		sampled.LogAttrs(ctx, slog.LevelInfo, "grpc call", attrs...)
+	case codes.Internal, codes.Unknown, codes.Unavailable, codes.DataLoss:
+		logger.LogAttrs(ctx, slog.LevelError, "grpc call", append(attrs, slog.String("error", status.Convert(err).Message()))...)
+	default:
+		// the caller's mistake or a refusal such as admission control
+		logger.LogAttrs(ctx, slog.LevelWarn, "grpc call", append(attrs, slog.String("error", status.Convert(err).Message()))...)
+	}
+}
+
+// requestIDStream hands the handler a context carrying the request ID.
+type requestIDStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *requestIDStream) Context() context.Context { return s.ctx }
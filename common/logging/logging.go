@@ -0,0 +1,182 @@
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Formats accepted by New.
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// RequestIDHeader carries the request ID over HTTP, and in lower case over gRPC metadata.
+const RequestIDHeader = "X-Request-Id"
+
+type Opts struct {
+	Service string    // added to every record as service, omitted when empty
+	Level   string    // debug, info, warn or error, info when empty
+	Format  string    // text or json, text when empty
+	Output  io.Writer // os.Stderr when nil
+}
+
+// New builds a logger whose records carry the request_id, trace_id and span_id
+// of the context they are logged with. The returned LevelVar changes the level
+// while the process runs.
+func New(opts Opts) (*slog.Logger, *slog.LevelVar, error) {
+	level := new(slog.LevelVar)
+	if opts.Level != "" {
+		parsed, err := ParseLevel(opts.Level)
+		if err != nil {
+			return nil, nil, err
+		}
+		level.Set(parsed)
+	}
+	if opts.Output == nil {
+		opts.Output = os.Stderr
+	}
+
+	handlerOpts := &slog.HandlerOptions{Level: level}
+	var handler slog.Handler
+	switch strings.ToLower(opts.Format) {
+	case "", FormatText:
+		handler = slog.NewTextHandler(opts.Output, handlerOpts)
+	case FormatJSON:
+		handler = slog.NewJSONHandler(opts.Output, handlerOpts)
+	default:
+		return nil, nil, fmt.Errorf("unknown log format %q, want text or json", opts.Format)
+	}
+
+	logger := slog.New(contextHandler{handler})
+	if opts.Service != "" {
+		logger = logger.With(slog.String("service", opts.Service))
+	}
+	return logger, level, nil
+}
+
+// ParseLevel accepts debug, info, warn and error in any case, with an optional
+// offset such as info+2.
+func ParseLevel(s string) (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(s)); err != nil {
+		return 0, fmt.Errorf("unknown log level %q, want debug, info, warn or error", s)
+	}
+	return level, nil
+}
+
+// SetLevel parses s and applies it to level, logging the change with the
+// default logger.
+func SetLevel(ctx context.Context, level *slog.LevelVar, s string) (slog.Level, error) {
+	parsed, err := ParseLevel(s)
+	if err != nil {
+		return 0, err
+	}
+	previous := level.Level()
+	level.Set(parsed)
+	// at the higher of the two levels, so the change itself is never filtered out
+	slog.Log(ctx, max(previous, parsed, slog.LevelInfo), "log level changed", "from", previous.String(), "to", parsed.String())
+	return parsed, nil
+}
+
+// Discard is a logger that drops every record, for tests and optional loggers.
+func Discard() *slog.Logger {
+	return slog.New(slog.DiscardHandler)
+}
+
+type requestIDKey struct{}
+
+// WithRequestID returns a copy of ctx whose log records carry id.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request ID stored in ctx, or "".
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// NewRequestID returns a random 16 byte hex ID.
+func NewRequestID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// contextHandler adds the request and trace IDs of the logging context.
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if id := RequestID(ctx); id != "" {
+		r.AddAttrs(slog.String("request_id", id))
+	}
+	if spanCtx := trace.SpanContextFromContext(ctx); spanCtx.IsValid() {
+		r.AddAttrs(
+			slog.String("trace_id", spanCtx.TraceID().String()),
+			slog.String("span_id", spanCtx.SpanID().String()),
+		)
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
+
+// Sample returns a logger that writes the first record and then one in every
+// every records, for logs on paths that run thousands of times a second. Kept
+// records carry sample_every so readers can scale counts back up. Loggers
+// derived from the result with With share its counter.
+func Sample(logger *slog.Logger, every int) *slog.Logger {
+	if every <= 1 {
+		return logger
+	}
+	return slog.New(&samplingHandler{
+		Handler: logger.Handler(),
+		every:   uint64(every),
+		seen:    new(atomic.Uint64),
+	})
+}
+
+type samplingHandler struct {
+	slog.Handler
+	every uint64
+	seen  *atomic.Uint64
+}
+
+func (h *samplingHandler) Handle(ctx context.Context, r slog.Record) error {
+	if (h.seen.Add(1)-1)%h.every != 0 {
+		return nil
+	}
+	r.AddAttrs(slog.Uint64("sample_every", h.every))
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h *samplingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &samplingHandler{Handler: h.Handler.WithAttrs(attrs), every: h.every, seen: h.seen}
+}
+
+func (h *samplingHandler) WithGroup(name string) slog.Handler {
+	return &samplingHandler{Handler: h.Handler.WithGroup(name), every: h.every, seen: h.seen}
+}
+
+// LevelRequest is the body of the admin endpoints that read and change the level.
+type LevelRequest struct {
+	Level string `json:"level" example:"debug"`
+}
@@ -0,0 +1,288 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// records decodes the JSON lines written by a logger built with FormatJSON.
+func records(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var out []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var record map[string]any
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("record %q is not JSON: %v", line, err)
+		}
+		out = append(out, record)
+	}
+	return out
+}
+
+func newJSONLogger(t *testing.T, level string) (*slog.Logger, *slog.LevelVar, *bytes.Buffer) {
+	t.Helper()
+	buf := &bytes.Buffer{}
+	logger, levelVar, err := New(Opts{Service: "test", Level: level, Format: FormatJSON, Output: buf})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return logger, levelVar, buf
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    Opts
+		wantErr bool
+	}{
+		{name: "defaults", opts: Opts{}},
+		{name: "json at debug", opts: Opts{Level: "DEBUG", Format: "json"}},
+		{name: "level with offset", opts: Opts{Level: "info+2"}},
+		{name: "unknown level", opts: Opts{Level: "verbose"}, wantErr: true},
+		{name: "unknown format", opts: Opts{Format: "logfmt"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.Output = &bytes.Buffer{}
+			_, _, err := New(tt.opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("New = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestContextAttributes(t *testing.T) {
+	logger, _, buf := newJSONLogger(t, "info")
+
+	traceID, _ := trace.TraceIDFromHex("0af7651916cd43dd8448eb211c80319c")
+	spanID, _ := trace.SpanIDFromHex("b7ad6b7169203331")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: traceID, SpanID: spanID, TraceFlags: trace.FlagsSampled,
+	}))
+	ctx = WithRequestID(ctx, "req-1")
+
+	logger.InfoContext(ctx, "with ids")
+	logger.Info("without ids")
+
+	got := records(t, buf)
+	if len(got) != 2 {
+		t.Fatalf("wrote %d records, want 2", len(got))
+	}
+	want := map[string]any{"service": "test", "request_id": "req-1", "trace_id": traceID.String(), "span_id": spanID.String()}
+	for key, value := range want {
+		if got[0][key] != value {
+			t.Fatalf("%s = %v, want %v", key, got[0][key], value)
+		}
+	}
+	for _, key := range []string{"request_id", "trace_id", "span_id"} {
+		if _, ok := got[1][key]; ok {
+			t.Fatalf("record without context carries %s", key)
+		}
+	}
+}
+
+func TestSetLevel(t *testing.T) {
+	logger, level, buf := newJSONLogger(t, "info")
+	slog.SetDefault(logger)
+	defer slog.SetDefault(slog.New(slog.NewTextHandler(&bytes.Buffer{}, nil)))
+
+	logger.Debug("hidden")
+	if _, err := SetLevel(context.Background(), level, "debug"); err != nil {
+		t.Fatalf("SetLevel: %v", err)
+	}
+	logger.Debug("shown")
+	if _, err := SetLevel(context.Background(), level, "loud"); err == nil {
+		t.Fatal("SetLevel accepted an unknown level")
+	}
+	if level.Level() != slog.LevelDebug {
+		t.Fatalf("level = %s after a refused change, want DEBUG", level.Level())
+	}
+
+	var messages []string
+	for _, record := range records(t, buf) {
+		messages = append(messages, record["msg"].(string))
+	}
+	if strings.Join(messages, ",") != "log level changed,shown" {
+		t.Fatalf("messages = %v", messages)
+	}
+}
+
+func TestSample(t *testing.T) {
+	logger, _, buf := newJSONLogger(t, "info")
+	sampled := Sample(logger, 10)
+	derived := sampled.With("method", "x")
+	for i := 0; i < 15; i++ {
+		sampled.Info("hot")
+		derived.Info("hot")
+	}
+	// debug records are filtered before they count
+	sampled.Debug("hidden")
+
+	got := records(t, buf)
+	if len(got) != 3 {
+		t.Fatalf("kept %d of 30 records, want 3", len(got))
+	}
+	if got[0]["sample_every"] != float64(10) {
+		t.Fatalf("sample_every = %v, want 10", got[0]["sample_every"])
+	}
+	if Sample(logger, 1) != logger {
+		t.Fatal("Sample(1) should return the logger unchanged")
+	}
+}
+
+func TestEchoMiddleware(t *testing.T) {
+	tests := []struct {
+		name      string
+		path      string
+		requestID string
+		handler   echo.HandlerFunc
+		wantLevel string // no record when empty
+		wantID    string // any generated ID when empty
+	}{
+		{name: "keeps the caller's ID", path: "/api", requestID: "abc", wantLevel: "INFO", wantID: "abc"},
+		{name: "makes an ID", path: "/api", wantLevel: "INFO"},
+		{name: "client error", path: "/api", handler: func(c echo.Context) error {
+			return echo.NewHTTPError(http.StatusBadRequest)
+		}, wantLevel: "WARN"},
+		{name: "server error", path: "/api", handler: func(c echo.Context) error {
+			return errors.New("boom")
+		}, wantLevel: "ERROR"},
+		{name: "quiet route is debug", path: "/healthz"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, _, buf := newJSONLogger(t, "info")
+			e := echo.New()
+			e.Use(EchoMiddleware(logger, "/healthz"))
+			var handlerID string
+			handler := func(c echo.Context) error {
+				handlerID = RequestID(c.Request().Context())
+				if tt.handler != nil {
+					return tt.handler(c)
+				}
+				return c.NoContent(http.StatusOK)
+			}
+			e.GET(tt.path, handler)
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.requestID != "" {
+				req.Header.Set(RequestIDHeader, tt.requestID)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			responseID := rec.Header().Get(RequestIDHeader)
+			if responseID == "" || responseID != handlerID || (tt.wantID != "" && responseID != tt.wantID) {
+				t.Fatalf("response ID %q, handler saw %q, want %q", responseID, handlerID, tt.wantID)
+			}
+			got := records(t, buf)
+			if tt.wantLevel == "" {
+				if len(got) != 0 {
+					t.Fatalf("wrote %v, want nothing at info", got)
+				}
+				return
+			}
+			if len(got) != 1 || got[0]["level"] != tt.wantLevel || got[0]["request_id"] != responseID {
+				t.Fatalf("records = %v, want one %s record for %s", got, tt.wantLevel, responseID)
+			}
+		})
+	}
+}
+
+// headerStream records the header a unary interceptor sets through grpc.SetHeader.
+type headerStream struct {
+	header metadata.MD
+}
+
+func (s *headerStream) Method() string { return "/sensor.IngestService/Readings" }
+func (s *headerStream) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+func (s *headerStream) SendHeader(md metadata.MD) error { return nil }
+func (s *headerStream) SetTrailer(md metadata.MD) error { return nil }
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	tests := []struct {
+		name      string
+		method    string
+		err       error
+		requestID string
+		wantLevel string
+	}{
+		{name: "success", method: "/sensor.IngestService/Readings", requestID: "from-a-service", wantLevel: "INFO"},
+		{name: "client error", method: "/sensor.IngestService/Readings", err: status.Error(codes.InvalidArgument, "bad"), wantLevel: "WARN"},
+		{name: "server error", method: "/sensor.IngestService/Readings", err: errors.New("database down"), wantLevel: "ERROR"},
+		{name: "health check", method: "/grpc.health.v1.Health/Check"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, _, buf := newJSONLogger(t, "info")
+			stream := &headerStream{}
+			ctx := grpc.NewContextWithServerTransportStream(context.Background(), stream)
+			if tt.requestID != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("x-request-id", tt.requestID))
+			}
+
+			var handlerID string
+			_, err := UnaryServerInterceptor(logger, 1)(ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method}, func(ctx context.Context, req any) (any, error) {
+				handlerID = RequestID(ctx)
+				return nil, tt.err
+			})
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("err = %v, want the handler's %v", err, tt.err)
+			}
+			if header := stream.header.Get("x-request-id"); len(header) != 1 || header[0] != handlerID || (tt.requestID != "" && handlerID != tt.requestID) {
+				t.Fatalf("header %v, handler saw %q, want %q", header, handlerID, tt.requestID)
+			}
+
+			got := records(t, buf)
+			if tt.wantLevel == "" {
+				if len(got) != 0 {
+					t.Fatalf("wrote %v, want nothing at info", got)
+				}
+				return
+			}
+			if len(got) != 1 || got[0]["level"] != tt.wantLevel || got[0]["request_id"] != handlerID || got[0]["method"] != tt.method {
+				t.Fatalf("records = %v, want one %s record", got, tt.wantLevel)
+			}
+		})
+	}
+}
+
+func TestUnaryClientInterceptor(t *testing.T) {
+	var sent []string
+	invoker := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		md, _ := metadata.FromOutgoingContext(ctx)
+		sent = md.Get("x-request-id")
+		return nil
+	}
+
+	interceptor := UnaryClientInterceptor()
+	_ = interceptor(WithRequestID(context.Background(), "req-1"), "/m", nil, nil, nil, invoker)
+	if len(sent) != 1 || sent[0] != "req-1" {
+		t.Fatalf("sent x-request-id %v, want [req-1]", sent)
+	}
+	_ = interceptor(context.Background(), "/m", nil, nil, nil, invoker)
+	if len(sent) != 0 {
+		t.Fatalf("sent x-request-id %v without a request ID in the context", sent)
+	}
+}
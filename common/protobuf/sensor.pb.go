@@ -21,6 +21,226 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type Durability int32
+
+const (
+	Durability_DURABILITY_UNSPECIFIED Durability = 0
+	Durability_DURABILITY_COMMITTED   Durability = 1 // committed to the database
+	Durability_DURABILITY_SPOOLED     Durability = 2 // on b-service's local disk, written to the database once it is reachable
+)
+
+// Enum value maps for Durability.
+var (
+	Durability_name = map[int32]string{
+		0: "DURABILITY_UNSPECIFIED",
+		1: "DURABILITY_COMMITTED",
+		2: "DURABILITY_SPOOLED",
+	}
+	Durability_value = map[string]int32{
+		"DURABILITY_UNSPECIFIED": 0,
+		"DURABILITY_COMMITTED":   1,
+		"DURABILITY_SPOOLED":     2,
+	}
+)
+
+func (x Durability) Enum() *Durability {
+	p := new(Durability)
+	*p = x
+	return p
+}
+
+func (x Durability) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Durability) Descriptor() protoreflect.EnumDescriptor {
+	return file_common_protobuf_sensor_proto_enumTypes[0].Descriptor()
+}
+
+func (Durability) Type() protoreflect.EnumType {
+	return &file_common_protobuf_sensor_proto_enumTypes[0]
+}
+
+func (x Durability) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Durability.Descriptor instead.
+func (Durability) EnumDescriptor() ([]byte, []int) {
+	return file_common_protobuf_sensor_proto_rawDescGZIP(), []int{0}
+}
+
+type ItemStatus int32
+
+const (
+	ItemStatus_ITEM_STATUS_UNSPECIFIED         ItemStatus = 0
+	ItemStatus_ITEM_STATUS_STORED              ItemStatus = 1
+	ItemStatus_ITEM_STATUS_REJECTED_VALIDATION ItemStatus = 2
+	ItemStatus_ITEM_STATUS_DUPLICATE           ItemStatus = 3 // already stored, treated as success (keep_first / keep_last)
+	ItemStatus_ITEM_STATUS_DUPLICATE_REJECTED  ItemStatus = 4 // already stored, refused by the reject conflict policy
+)
+
+// Enum value maps for ItemStatus.
+var (
+	ItemStatus_name = map[int32]string{
+		0: "ITEM_STATUS_UNSPECIFIED",
+		1: "ITEM_STATUS_STORED",
+		2: "ITEM_STATUS_REJECTED_VALIDATION",
+		3: "ITEM_STATUS_DUPLICATE",
+		4: "ITEM_STATUS_DUPLICATE_REJECTED",
+	}
+	ItemStatus_value = map[string]int32{
+		"ITEM_STATUS_UNSPECIFIED":         0,
+		"ITEM_STATUS_STORED":              1,
+		"ITEM_STATUS_REJECTED_VALIDATION": 2,
+		"ITEM_STATUS_DUPLICATE":           3,
+		"ITEM_STATUS_DUPLICATE_REJECTED":  4,
+	}
+)
+
+func (x ItemStatus) Enum() *ItemStatus {
+	p := new(ItemStatus)
+	*p = x
+	return p
+}
+
+func (x ItemStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ItemStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_common_protobuf_sensor_proto_enumTypes[1].Descriptor()
+}
+
+func (ItemStatus) Type() protoreflect.EnumType {
+	return &file_common_protobuf_sensor_proto_enumTypes[1]
+}
+
+func (x ItemStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ItemStatus.Descriptor instead.
+func (ItemStatus) EnumDescriptor() ([]byte, []int) {
+	return file_common_protobuf_sensor_proto_rawDescGZIP(), []int{1}
+}
+
+type SlowConsumerPolicy int32
+
+const (
+	SlowConsumerPolicy_SLOW_CONSUMER_POLICY_UNSPECIFIED SlowConsumerPolicy = 0 // b-service's default
+	SlowConsumerPolicy_SLOW_CONSUMER_POLICY_DROP        SlowConsumerPolicy = 1 // skip readings while the subscriber's buffer is full
+	SlowConsumerPolicy_SLOW_CONSUMER_POLICY_DISCONNECT  SlowConsumerPolicy = 2 // end the stream with RESOURCE_EXHAUSTED
+)
+
+// Enum value maps for SlowConsumerPolicy.
+var (
+	SlowConsumerPolicy_name = map[int32]string{
+		0: "SLOW_CONSUMER_POLICY_UNSPECIFIED",
+		1: "SLOW_CONSUMER_POLICY_DROP",
+		2: "SLOW_CONSUMER_POLICY_DISCONNECT",
+	}
+	SlowConsumerPolicy_value = map[string]int32{
+		"SLOW_CONSUMER_POLICY_UNSPECIFIED": 0,
+		"SLOW_CONSUMER_POLICY_DROP":        1,
+		"SLOW_CONSUMER_POLICY_DISCONNECT":  2,
+	}
+)
+
+func (x SlowConsumerPolicy) Enum() *SlowConsumerPolicy {
+	p := new(SlowConsumerPolicy)
+	*p = x
+	return p
+}
+
+func (x SlowConsumerPolicy) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SlowConsumerPolicy) Descriptor() protoreflect.EnumDescriptor {
+	return file_common_protobuf_sensor_proto_enumTypes[2].Descriptor()
+}
+
+func (SlowConsumerPolicy) Type() protoreflect.EnumType {
+	return &file_common_protobuf_sensor_proto_enumTypes[2]
+}
+
+func (x SlowConsumerPolicy) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SlowConsumerPolicy.Descriptor instead.
+func (SlowConsumerPolicy) EnumDescriptor() ([]byte, []int) {
+	return file_common_protobuf_sensor_proto_rawDescGZIP(), []int{2}
+}
+
+type AggregateFunction int32
+
+const (
+	AggregateFunction_AGGREGATE_FUNCTION_UNSPECIFIED AggregateFunction = 0
+	AggregateFunction_AGGREGATE_FUNCTION_AVG         AggregateFunction = 1
+	AggregateFunction_AGGREGATE_FUNCTION_MIN         AggregateFunction = 2
+	AggregateFunction_AGGREGATE_FUNCTION_MAX         AggregateFunction = 3
+	AggregateFunction_AGGREGATE_FUNCTION_SUM         AggregateFunction = 4
+	AggregateFunction_AGGREGATE_FUNCTION_COUNT       AggregateFunction = 5
+	AggregateFunction_AGGREGATE_FUNCTION_FIRST       AggregateFunction = 6 // value with the earliest timestamp
+	AggregateFunction_AGGREGATE_FUNCTION_LAST        AggregateFunction = 7 // value with the latest timestamp
+	AggregateFunction_AGGREGATE_FUNCTION_STDDEV      AggregateFunction = 8 // population standard deviation
+)
+
+// Enum value maps for AggregateFunction.
+var (
+	AggregateFunction_name = map[int32]string{
+		0: "AGGREGATE_FUNCTION_UNSPECIFIED",
+		1: "AGGREGATE_FUNCTION_AVG",
+		2: "AGGREGATE_FUNCTION_MIN",
+		3: "AGGREGATE_FUNCTION_MAX",
+		4: "AGGREGATE_FUNCTION_SUM",
+		5: "AGGREGATE_FUNCTION_COUNT",
+		6: "AGGREGATE_FUNCTION_FIRST",
+		7: "AGGREGATE_FUNCTION_LAST",
+		8: "AGGREGATE_FUNCTION_STDDEV",
+	}
+	AggregateFunction_value = map[string]int32{
+		"AGGREGATE_FUNCTION_UNSPECIFIED": 0,
+		"AGGREGATE_FUNCTION_AVG":         1,
+		"AGGREGATE_FUNCTION_MIN":         2,
+		"AGGREGATE_FUNCTION_MAX":         3,
+		"AGGREGATE_FUNCTION_SUM":         4,
+		"AGGREGATE_FUNCTION_COUNT":       5,
+		"AGGREGATE_FUNCTION_FIRST":       6,
+		"AGGREGATE_FUNCTION_LAST":        7,
+		"AGGREGATE_FUNCTION_STDDEV":      8,
+	}
+)
+
+func (x AggregateFunction) Enum() *AggregateFunction {
+	p := new(AggregateFunction)
+	*p = x
+	return p
+}
+
+func (x AggregateFunction) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (AggregateFunction) Descriptor() protoreflect.EnumDescriptor {
+	return file_common_protobuf_sensor_proto_enumTypes[3].Descriptor()
+}
+
+func (AggregateFunction) Type() protoreflect.EnumType {
+	return &file_common_protobuf_sensor_proto_enumTypes[3]
+}
+
+func (x AggregateFunction) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use AggregateFunction.Descriptor instead.
+func (AggregateFunction) EnumDescriptor() ([]byte, []int) {
+	return file_common_protobuf_sensor_proto_rawDescGZIP(), []int{3}
+}
+
 type SensorReading struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Value         float64                `protobuf:"fixed64,1,opt,name=value,proto3" json:"value,omitempty"`
@@ -28,6 +248,7 @@ type SensorReading struct {
 	Id1           string                 `protobuf:"bytes,3,opt,name=id1,proto3" json:"id1,omitempty"`                                 // uppercase A–Z
 	Id2           int32                  `protobuf:"varint,4,opt,name=id2,proto3" json:"id2,omitempty"`
 	TimestampMs   int64                  `protobuf:"varint,5,opt,name=timestamp_ms,json=timestampMs,proto3" json:"timestamp_ms,omitempty"` // Unix ms
+	ReadingKey    string                 `protobuf:"bytes,6,opt,name=reading_key,json=readingKey,proto3" json:"reading_key,omitempty"`     // optional idempotency key, at most 64 chars; retries must reuse it
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -97,9 +318,24 @@ func (x *SensorReading) GetTimestampMs() int64 {
 	return 0
 }
 
+func (x *SensorReading) GetReadingKey() string {
+	if x != nil {
+		return x.ReadingKey
+	}
+	return ""
+}
+
+// StreamAck answers Readings and StreamReadings. Producers read the outcome from
+// the gRPC status and the counts below; ReadingsBatch's BatchAck is the
+// replacement for anyone who needs a result per reading.
 type StreamAck struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Deprecated: Marked as deprecated in common/protobuf/sensor.proto.
+	Status        string     `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`                                 // human-readable summary only, do not parse it
+	Accepted      uint64     `protobuf:"varint,2,opt,name=accepted,proto3" json:"accepted,omitempty"`                            // readings stored, committed or spooled (StreamReadings only)
+	Rejected      uint64     `protobuf:"varint,3,opt,name=rejected,proto3" json:"rejected,omitempty"`                            // readings refused by validation or the conflict policy (StreamReadings only)
+	Durability    Durability `protobuf:"varint,4,opt,name=durability,proto3,enum=sensor.Durability" json:"durability,omitempty"` // Readings only
+	Spooled       uint64     `protobuf:"varint,5,opt,name=spooled,proto3" json:"spooled,omitempty"`                              // accepted readings that are only on local disk so far (StreamReadings only)
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -134,6 +370,7 @@ func (*StreamAck) Descriptor() ([]byte, []int) {
 	return file_common_protobuf_sensor_proto_rawDescGZIP(), []int{1}
 }
 
+// Deprecated: Marked as deprecated in common/protobuf/sensor.proto.
 func (x *StreamAck) GetStatus() string {
 	if x != nil {
 		return x.Status
@@ -141,23 +378,2391 @@ func (x *StreamAck) GetStatus() string {
 	return ""
 }
 
-var File_common_protobuf_sensor_proto protoreflect.FileDescriptor
+func (x *StreamAck) GetAccepted() uint64 {
+	if x != nil {
+		return x.Accepted
+	}
+	return 0
+}
 
-const file_common_protobuf_sensor_proto_rawDesc = "" +
-	"\n" +
-	"\x1ccommon/protobuf/sensor.proto\x12\x06sensor\"\x8d\x01\n" +
-	"\rSensorReading\x12\x14\n" +
-	"\x05value\x18\x01 \x01(\x01R\x05value\x12\x1f\n" +
-	"\vsensor_type\x18\x02 \x01(\tR\n" +
-	"sensorType\x12\x10\n" +
-	"\x03id1\x18\x03 \x01(\tR\x03id1\x12\x10\n" +
-	"\x03id2\x18\x04 \x01(\x05R\x03id2\x12!\n" +
-	"\ftimestamp_ms\x18\x05 \x01(\x03R\vtimestampMs\"#\n" +
-	"\tStreamAck\x12\x16\n" +
-	"\x06status\x18\x01 \x01(\tR\x06status2\x83\x01\n" +
+func (x *StreamAck) GetRejected() uint64 {
+	if x != nil {
+		return x.Rejected
+	}
+	return 0
+}
+
+func (x *StreamAck) GetDurability() Durability {
+	if x != nil {
+		return x.Durability
+	}
+	return Durability_DURABILITY_UNSPECIFIED
+}
+
+func (x *StreamAck) GetSpooled() uint64 {
+	if x != nil {
+		return x.Spooled
+	}
+	return 0
+}
+
+type SensorReadingBatch struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Readings      []*SensorReading       `protobuf:"bytes,1,rep,name=readings,proto3" json:"readings,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SensorReadingBatch) Reset() {
+	*x = SensorReadingBatch{}
+	mi := &file_common_protobuf_sensor_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SensorReadingBatch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SensorReadingBatch) ProtoMessage() {}
+
+func (x *SensorReadingBatch) ProtoReflect() protoreflect.Message {
+	mi := &file_common_protobuf_sensor_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SensorReadingBatch.ProtoReflect.Descriptor instead.
+func (*SensorReadingBatch) Descriptor() ([]byte, []int) {
+	return file_common_protobuf_sensor_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *SensorReadingBatch) GetReadings() []*SensorReading {
+	if x != nil {
+		return x.Readings
+	}
+	return nil
+}
+
+// FieldViolation names a SensorReading field that failed validation, like
+// google.rpc.BadRequest.FieldViolation in the error details of Readings.
+type FieldViolation struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Field         string                 `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"` // e.g. "id1" or "timestamp_ms"
+	Description   string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FieldViolation) Reset() {
+	*x = FieldViolation{}
+	mi := &file_common_protobuf_sensor_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FieldViolation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FieldViolation) ProtoMessage() {}
+
+func (x *FieldViolation) ProtoReflect() protoreflect.Message {
+	mi := &file_common_protobuf_sensor_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FieldViolation.ProtoReflect.Descriptor instead.
+func (*FieldViolation) Descriptor() ([]byte, []int) {
+	return file_common_protobuf_sensor_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *FieldViolation) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *FieldViolation) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+type ItemResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Index         uint32                 `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"` // position of the reading in SensorReadingBatch.readings
+	Status        ItemStatus             `protobuf:"varint,2,opt,name=status,proto3,enum=sensor.ItemStatus" json:"status,omitempty"`
+	ReadingId     uint64                 `protobuf:"varint,3,opt,name=reading_id,json=readingId,proto3" json:"reading_id,omitempty"`         // set when committed or duplicate, unknown while spooled
+	Reason        string                 `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`                                 // set when rejected
+	Durability    Durability             `protobuf:"varint,5,opt,name=durability,proto3,enum=sensor.Durability" json:"durability,omitempty"` // set when stored
+	Violations    []*FieldViolation      `protobuf:"bytes,6,rep,name=violations,proto3" json:"violations,omitempty"`                         // set when rejected by validation
+	OutOfRange    bool                   `protobuf:"varint,7,opt,name=out_of_range,json=outOfRange,proto3" json:"out_of_range,omitempty"`    // the value is outside the range of its sensor type, which flags rather than rejects it
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ItemResult) Reset() {
+	*x = ItemResult{}
+	mi := &file_common_protobuf_sensor_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ItemResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ItemResult) ProtoMessage() {}
+
+func (x *ItemResult) ProtoReflect() protoreflect.Message {
+	mi := &file_common_protobuf_sensor_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ItemResult.ProtoReflect.Descriptor instead.
+func (*ItemResult) Descriptor() ([]byte, []int) {
+	return file_common_protobuf_sensor_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ItemResult) GetIndex() uint32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *ItemResult) GetStatus() ItemStatus {
+	if x != nil {
+		return x.Status
+	}
+	return ItemStatus_ITEM_STATUS_UNSPECIFIED
+}
+
+func (x *ItemResult) GetReadingId() uint64 {
+	if x != nil {
+		return x.ReadingId
+	}
+	return 0
+}
+
+func (x *ItemResult) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *ItemResult) GetDurability() Durability {
+	if x != nil {
+		return x.Durability
+	}
+	return Durability_DURABILITY_UNSPECIFIED
+}
+
+func (x *ItemResult) GetViolations() []*FieldViolation {
+	if x != nil {
+		return x.Violations
+	}
+	return nil
+}
+
+func (x *ItemResult) GetOutOfRange() bool {
+	if x != nil {
+		return x.OutOfRange
+	}
+	return false
+}
+
+type BatchAck struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Results       []*ItemResult          `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"` // one per reading, in request order
+	Stored        uint32                 `protobuf:"varint,2,opt,name=stored,proto3" json:"stored,omitempty"`
+	Rejected      uint32                 `protobuf:"varint,3,opt,name=rejected,proto3" json:"rejected,omitempty"`
+	Duplicates    uint32                 `protobuf:"varint,4,opt,name=duplicates,proto3" json:"duplicates,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchAck) Reset() {
+	*x = BatchAck{}
+	mi := &file_common_protobuf_sensor_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchAck) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchAck) ProtoMessage() {}
+
+func (x *BatchAck) ProtoReflect() protoreflect.Message {
+	mi := &file_common_protobuf_sensor_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchAck.ProtoReflect.Descriptor instead.
+func (*BatchAck) Descriptor() ([]byte, []int) {
+	return file_common_protobuf_sensor_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *BatchAck) GetResults() []*ItemResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *BatchAck) GetStored() uint32 {
+	if x != nil {
+		return x.Stored
+	}
+	return 0
+}
+
+func (x *BatchAck) GetRejected() uint32 {
+	if x != nil {
+		return x.Rejected
+	}
+	return 0
+}
+
+func (x *BatchAck) GetDuplicates() uint32 {
+	if x != nil {
+		return x.Duplicates
+	}
+	return 0
+}
+
+type SequencedReading struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Seq           uint64                 `protobuf:"varint,1,opt,name=seq,proto3" json:"seq,omitempty"` // strictly increasing within a stream
+	Reading       *SensorReading         `protobuf:"bytes,2,opt,name=reading,proto3" json:"reading,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SequencedReading) Reset() {
+	*x = SequencedReading{}
+	mi := &file_common_protobuf_sensor_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SequencedReading) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SequencedReading) ProtoMessage() {}
+
+func (x *SequencedReading) ProtoReflect() protoreflect.Message {
+	mi := &file_common_protobuf_sensor_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SequencedReading.ProtoReflect.Descriptor instead.
+func (*SequencedReading) Descriptor() ([]byte, []int) {
+	return file_common_protobuf_sensor_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *SequencedReading) GetSeq() uint64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *SequencedReading) GetReading() *SensorReading {
+	if x != nil {
+		return x.Reading
+	}
+	return nil
+}
+
+type Nack struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Seq           uint64                 `protobuf:"varint,1,opt,name=seq,proto3" json:"seq,omitempty"`
+	Status        ItemStatus             `protobuf:"varint,2,opt,name=status,proto3,enum=sensor.ItemStatus" json:"status,omitempty"`
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	Violations    []*FieldViolation      `protobuf:"bytes,4,rep,name=violations,proto3" json:"violations,omitempty"` // set when rejected by validation
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Nack) Reset() {
+	*x = Nack{}
+	mi := &file_common_protobuf_sensor_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Nack) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Nack) ProtoMessage() {}
+
+func (x *Nack) ProtoReflect() protoreflect.Message {
+	mi := &file_common_protobuf_sensor_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Nack.ProtoReflect.Descriptor instead.
+func (*Nack) Descriptor() ([]byte, []int) {
+	return file_common_protobuf_sensor_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Nack) GetSeq() uint64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *Nack) GetStatus() ItemStatus {
+	if x != nil {
+		return x.Status
+	}
+	return ItemStatus_ITEM_STATUS_UNSPECIFIED
+}
+
+func (x *Nack) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *Nack) GetViolations() []*FieldViolation {
+	if x != nil {
+		return x.Violations
+	}
+	return nil
+}
+
+type IngestAck struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CommittedSeq  uint64                 `protobuf:"varint,1,opt,name=committed_seq,json=committedSeq,proto3" json:"committed_seq,omitempty"` // every seq <= committed_seq is resolved, stored unless nacked
+	Nacks         []*Nack                `protobuf:"bytes,2,rep,name=nacks,proto3" json:"nacks,omitempty"`                                    // readings up to committed_seq that were not stored
+	Durability    Durability             `protobuf:"varint,3,opt,name=durability,proto3,enum=sensor.Durability" json:"durability,omitempty"`  // SPOOLED if any reading covered by this ack is only on local disk
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IngestAck) Reset() {
+	*x = IngestAck{}
+	mi := &file_common_protobuf_sensor_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IngestAck) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IngestAck) ProtoMessage() {}
+
+func (x *IngestAck) ProtoReflect() protoreflect.Message {
+	mi := &file_common_protobuf_sensor_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IngestAck.ProtoReflect.Descriptor instead.
+func (*IngestAck) Descriptor() ([]byte, []int) {
+	return file_common_protobuf_sensor_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *IngestAck) GetCommittedSeq() uint64 {
+	if x != nil {
+		return x.CommittedSeq
+	}
+	return 0
+}
+
+func (x *IngestAck) GetNacks() []*Nack {
+	if x != nil {
+		return x.Nacks
+	}
+	return nil
+}
+
+func (x *IngestAck) GetDurability() Durability {
+	if x != nil {
+		return x.Durability
+	}
+	return Durability_DURABILITY_UNSPECIFIED
+}
+
+type IdCombination struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id1           string                 `protobuf:"bytes,1,opt,name=id1,proto3" json:"id1,omitempty"`
+	Id2           int32                  `protobuf:"varint,2,opt,name=id2,proto3" json:"id2,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IdCombination) Reset() {
+	*x = IdCombination{}
+	mi := &file_common_protobuf_sensor_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IdCombination) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IdCombination) ProtoMessage() {}
+
+func (x *IdCombination) ProtoReflect() protoreflect.Message {
+	mi := &file_common_protobuf_sensor_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IdCombination.ProtoReflect.Descriptor instead.
+func (*IdCombination) Descriptor() ([]byte, []int) {
+	return file_common_protobuf_sensor_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *IdCombination) GetId1() string {
+	if x != nil {
+		return x.Id1
+	}
+	return ""
+}
+
+func (x *IdCombination) GetId2() int32 {
+	if x != nil {
+		return x.Id2
+	}
+	return 0
+}
+
+// StoredReading is a row of sensor_readings.
+type StoredReading struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ReadingId     uint64                 `protobuf:"varint,1,opt,name=reading_id,json=readingId,proto3" json:"reading_id,omitempty"`
+	Value         float64                `protobuf:"fixed64,2,opt,name=value,proto3" json:"value,omitempty"`
+	SensorType    string                 `protobuf:"bytes,3,opt,name=sensor_type,json=sensorType,proto3" json:"sensor_type,omitempty"`
+	Id1           string                 `protobuf:"bytes,4,opt,name=id1,proto3" json:"id1,omitempty"`
+	Id2           int32                  `protobuf:"varint,5,opt,name=id2,proto3" json:"id2,omitempty"`
+	TimestampMs   int64                  `protobuf:"varint,6,opt,name=timestamp_ms,json=timestampMs,proto3" json:"timestamp_ms,omitempty"`   // Unix ms
+	CreatedAtMs   int64                  `protobuf:"varint,7,opt,name=created_at_ms,json=createdAtMs,proto3" json:"created_at_ms,omitempty"` // Unix ms, when b-service stored it
+	Sensor        *SensorMetadata        `protobuf:"bytes,8,opt,name=sensor,proto3" json:"sensor,omitempty"`                                 // with ListReadingsRequest.with_metadata, for registered sensors
+	OutOfRange    bool                   `protobuf:"varint,9,opt,name=out_of_range,json=outOfRange,proto3" json:"out_of_range,omitempty"`    // outside the plausible range of its sensor type when stored
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StoredReading) Reset() {
+	*x = StoredReading{}
+	mi := &file_common_protobuf_sensor_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StoredReading) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StoredReading) ProtoMessage() {}
+
+func (x *StoredReading) ProtoReflect() protoreflect.Message {
+	mi := &file_common_protobuf_sensor_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StoredReading.ProtoReflect.Descriptor instead.
+func (*StoredReading) Descriptor() ([]byte, []int) {
+	return file_common_protobuf_sensor_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *StoredReading) GetReadingId() uint64 {
+	if x != nil {
+		return x.ReadingId
+	}
+	return 0
+}
+
+func (x *StoredReading) GetValue() float64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+func (x *StoredReading) GetSensorType() string {
+	if x != nil {
+		return x.SensorType
+	}
+	return ""
+}
+
+func (x *StoredReading) GetId1() string {
+	if x != nil {
+		return x.Id1
+	}
+	return ""
+}
+
+func (x *StoredReading) GetId2() int32 {
+	if x != nil {
+		return x.Id2
+	}
+	return 0
+}
+
+func (x *StoredReading) GetTimestampMs() int64 {
+	if x != nil {
+		return x.TimestampMs
+	}
+	return 0
+}
+
+func (x *StoredReading) GetCreatedAtMs() int64 {
+	if x != nil {
+		return x.CreatedAtMs
+	}
+	return 0
+}
+
+func (x *StoredReading) GetSensor() *SensorMetadata {
+	if x != nil {
+		return x.Sensor
+	}
+	return nil
+}
+
+func (x *StoredReading) GetOutOfRange() bool {
+	if x != nil {
+		return x.OutOfRange
+	}
+	return false
+}
+
+// The filters of the query and management calls. With ids set only those
+// (id1, id2) combinations match; with from_ms and to_ms set only readings with
+// from_ms <= timestamp_ms <= to_ms match. Both may be combined.
+type ListReadingsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Page          uint32                 `protobuf:"varint,1,opt,name=page,proto3" json:"page,omitempty"`                         // 1-based
+	PageSize      uint32                 `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"` // 1..500
+	Ids           []*IdCombination       `protobuf:"bytes,3,rep,name=ids,proto3" json:"ids,omitempty"`
+	FromMs        int64                  `protobuf:"varint,4,opt,name=from_ms,json=fromMs,proto3" json:"from_ms,omitempty"`
+	ToMs          int64                  `protobuf:"varint,5,opt,name=to_ms,json=toMs,proto3" json:"to_ms,omitempty"`
+	WithMetadata  bool                   `protobuf:"varint,6,opt,name=with_metadata,json=withMetadata,proto3" json:"with_metadata,omitempty"` // join the registered metadata of each sensor
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListReadingsRequest) Reset() {
+	*x = ListReadingsRequest{}
+	mi := &file_common_protobuf_sensor_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListReadingsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListReadingsRequest) ProtoMessage() {}
+
+func (x *ListReadingsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_protobuf_sensor_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListReadingsRequest.ProtoReflect.Descriptor instead.
+func (*ListReadingsRequest) Descriptor() ([]byte, []int) {
+	return file_common_protobuf_sensor_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ListReadingsRequest) GetPage() uint32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListReadingsRequest) GetPageSize() uint32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListReadingsRequest) GetIds() []*IdCombination {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+func (x *ListReadingsRequest) GetFromMs() int64 {
+	if x != nil {
+		return x.FromMs
+	}
+	return 0
+}
+
+func (x *ListReadingsRequest) GetToMs() int64 {
+	if x != nil {
+		return x.ToMs
+	}
+	return 0
+}
+
+func (x *ListReadingsRequest) GetWithMetadata() bool {
+	if x != nil {
+		return x.WithMetadata
+	}
+	return false
+}
+
+type ListReadingsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Items         []*StoredReading       `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	Page          uint32                 `protobuf:"varint,2,opt,name=page,proto3" json:"page,omitempty"`
+	PageSize      uint32                 `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	Total         int64                  `protobuf:"varint,4,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListReadingsResponse) Reset() {
+	*x = ListReadingsResponse{}
+	mi := &file_common_protobuf_sensor_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListReadingsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListReadingsResponse) ProtoMessage() {}
+
+func (x *ListReadingsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_protobuf_sensor_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListReadingsResponse.ProtoReflect.Descriptor instead.
+func (*ListReadingsResponse) Descriptor() ([]byte, []int) {
+	return file_common_protobuf_sensor_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ListReadingsResponse) GetItems() []*StoredReading {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *ListReadingsResponse) GetPage() uint32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListReadingsResponse) GetPageSize() uint32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListReadingsResponse) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+type UpdateReadingsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ids           []*IdCombination       `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"` // at least one of ids and the time range is required
+	FromMs        int64                  `protobuf:"varint,2,opt,name=from_ms,json=fromMs,proto3" json:"from_ms,omitempty"`
+	ToMs          int64                  `protobuf:"varint,3,opt,name=to_ms,json=toMs,proto3" json:"to_ms,omitempty"`
+	SensorValue   float64                `protobuf:"fixed64,4,opt,name=sensor_value,json=sensorValue,proto3" json:"sensor_value,omitempty"`
+	SensorType    string                 `protobuf:"bytes,5,opt,name=sensor_type,json=sensorType,proto3" json:"sensor_type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateReadingsRequest) Reset() {
+	*x = UpdateReadingsRequest{}
+	mi := &file_common_protobuf_sensor_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateReadingsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateReadingsRequest) ProtoMessage() {}
+
+func (x *UpdateReadingsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_protobuf_sensor_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateReadingsRequest.ProtoReflect.Descriptor instead.
+func (*UpdateReadingsRequest) Descriptor() ([]byte, []int) {
+	return file_common_protobuf_sensor_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *UpdateReadingsRequest) GetIds() []*IdCombination {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+func (x *UpdateReadingsRequest) GetFromMs() int64 {
+	if x != nil {
+		return x.FromMs
+	}
+	return 0
+}
+
+func (x *UpdateReadingsRequest) GetToMs() int64 {
+	if x != nil {
+		return x.ToMs
+	}
+	return 0
+}
+
+func (x *UpdateReadingsRequest) GetSensorValue() float64 {
+	if x != nil {
+		return x.SensorValue
+	}
+	return 0
+}
+
+func (x *UpdateReadingsRequest) GetSensorType() string {
+	if x != nil {
+		return x.SensorType
+	}
+	return ""
+}
+
+type UpdateReadingsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UpdatedCount  int64                  `protobuf:"varint,1,opt,name=updated_count,json=updatedCount,proto3" json:"updated_count,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateReadingsResponse) Reset() {
+	*x = UpdateReadingsResponse{}
+	mi := &file_common_protobuf_sensor_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateReadingsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateReadingsResponse) ProtoMessage() {}
+
+func (x *UpdateReadingsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_protobuf_sensor_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateReadingsResponse.ProtoReflect.Descriptor instead.
+func (*UpdateReadingsResponse) Descriptor() ([]byte, []int) {
+	return file_common_protobuf_sensor_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *UpdateReadingsResponse) GetUpdatedCount() int64 {
+	if x != nil {
+		return x.UpdatedCount
+	}
+	return 0
+}
+
+func (x *UpdateReadingsResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type DeleteReadingsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ids           []*IdCombination       `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"` // at least one of ids and the time range is required
+	FromMs        int64                  `protobuf:"varint,2,opt,name=from_ms,json=fromMs,proto3" json:"from_ms,omitempty"`
+	ToMs          int64                  `protobuf:"varint,3,opt,name=to_ms,json=toMs,proto3" json:"to_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteReadingsRequest) Reset() {
+	*x = DeleteReadingsRequest{}
+	mi := &file_common_protobuf_sensor_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteReadingsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteReadingsRequest) ProtoMessage() {}
+
+func (x *DeleteReadingsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_protobuf_sensor_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteReadingsRequest.ProtoReflect.Descriptor instead.
+func (*DeleteReadingsRequest) Descriptor() ([]byte, []int) {
+	return file_common_protobuf_sensor_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *DeleteReadingsRequest) GetIds() []*IdCombination {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+func (x *DeleteReadingsRequest) GetFromMs() int64 {
+	if x != nil {
+		return x.FromMs
+	}
+	return 0
+}
+
+func (x *DeleteReadingsRequest) GetToMs() int64 {
+	if x != nil {
+		return x.ToMs
+	}
+	return 0
+}
+
+type DeleteReadingsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DeletedCount  int64                  `protobuf:"varint,1,opt,name=deleted_count,json=deletedCount,proto3" json:"deleted_count,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteReadingsResponse) Reset() {
+	*x = DeleteReadingsResponse{}
+	mi := &file_common_protobuf_sensor_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteReadingsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteReadingsResponse) ProtoMessage() {}
+
+func (x *DeleteReadingsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_protobuf_sensor_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteReadingsResponse.ProtoReflect.Descriptor instead.
+func (*DeleteReadingsResponse) Descriptor() ([]byte, []int) {
+	return file_common_protobuf_sensor_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *DeleteReadingsResponse) GetDeletedCount() int64 {
+	if x != nil {
+		return x.DeletedCount
+	}
+	return 0
+}
+
+func (x *DeleteReadingsResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// WatchRequest filters the live feed. Empty filters match every reading.
+type WatchRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ids           []*IdCombination       `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"`
+	SensorTypes   []string               `protobuf:"bytes,2,rep,name=sensor_types,json=sensorTypes,proto3" json:"sensor_types,omitempty"`
+	MinValue      *float64               `protobuf:"fixed64,3,opt,name=min_value,json=minValue,proto3,oneof" json:"min_value,omitempty"` // inclusive
+	MaxValue      *float64               `protobuf:"fixed64,4,opt,name=max_value,json=maxValue,proto3,oneof" json:"max_value,omitempty"` // inclusive
+	SlowConsumer  SlowConsumerPolicy     `protobuf:"varint,5,opt,name=slow_consumer,json=slowConsumer,proto3,enum=sensor.SlowConsumerPolicy" json:"slow_consumer,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchRequest) Reset() {
+	*x = WatchRequest{}
+	mi := &file_common_protobuf_sensor_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchRequest) ProtoMessage() {}
+
+func (x *WatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_protobuf_sensor_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchRequest.ProtoReflect.Descriptor instead.
+func (*WatchRequest) Descriptor() ([]byte, []int) {
+	return file_common_protobuf_sensor_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *WatchRequest) GetIds() []*IdCombination {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+func (x *WatchRequest) GetSensorTypes() []string {
+	if x != nil {
+		return x.SensorTypes
+	}
+	return nil
+}
+
+func (x *WatchRequest) GetMinValue() float64 {
+	if x != nil && x.MinValue != nil {
+		return *x.MinValue
+	}
+	return 0
+}
+
+func (x *WatchRequest) GetMaxValue() float64 {
+	if x != nil && x.MaxValue != nil {
+		return *x.MaxValue
+	}
+	return 0
+}
+
+func (x *WatchRequest) GetSlowConsumer() SlowConsumerPolicy {
+	if x != nil {
+		return x.SlowConsumer
+	}
+	return SlowConsumerPolicy_SLOW_CONSUMER_POLICY_UNSPECIFIED
+}
+
+// AggregateReadingsRequest downsamples the readings of ids, or of every sensor
+// of sensor_type, with from_ms <= timestamp_ms < to_ms into buckets of
+// bucket_ms counted from from_ms. At least one of ids and sensor_type is required.
+type AggregateReadingsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ids           []*IdCombination       `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"`
+	SensorType    string                 `protobuf:"bytes,2,opt,name=sensor_type,json=sensorType,proto3" json:"sensor_type,omitempty"`
+	FromMs        int64                  `protobuf:"varint,3,opt,name=from_ms,json=fromMs,proto3" json:"from_ms,omitempty"`
+	ToMs          int64                  `protobuf:"varint,4,opt,name=to_ms,json=toMs,proto3" json:"to_ms,omitempty"`
+	BucketMs      int64                  `protobuf:"varint,5,opt,name=bucket_ms,json=bucketMs,proto3" json:"bucket_ms,omitempty"`                        // at least 1000, at most 10000 buckets per sensor
+	Functions     []AggregateFunction    `protobuf:"varint,6,rep,packed,name=functions,proto3,enum=sensor.AggregateFunction" json:"functions,omitempty"` // avg when empty
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AggregateReadingsRequest) Reset() {
+	*x = AggregateReadingsRequest{}
+	mi := &file_common_protobuf_sensor_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AggregateReadingsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AggregateReadingsRequest) ProtoMessage() {}
+
+func (x *AggregateReadingsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_protobuf_sensor_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AggregateReadingsRequest.ProtoReflect.Descriptor instead.
+func (*AggregateReadingsRequest) Descriptor() ([]byte, []int) {
+	return file_common_protobuf_sensor_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *AggregateReadingsRequest) GetIds() []*IdCombination {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+func (x *AggregateReadingsRequest) GetSensorType() string {
+	if x != nil {
+		return x.SensorType
+	}
+	return ""
+}
+
+func (x *AggregateReadingsRequest) GetFromMs() int64 {
+	if x != nil {
+		return x.FromMs
+	}
+	return 0
+}
+
+func (x *AggregateReadingsRequest) GetToMs() int64 {
+	if x != nil {
+		return x.ToMs
+	}
+	return 0
+}
+
+func (x *AggregateReadingsRequest) GetBucketMs() int64 {
+	if x != nil {
+		return x.BucketMs
+	}
+	return 0
+}
+
+func (x *AggregateReadingsRequest) GetFunctions() []AggregateFunction {
+	if x != nil {
+		return x.Functions
+	}
+	return nil
+}
+
+// AggregatePoint is one bucket with readings, only the requested aggregates are set.
+type AggregatePoint struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TimestampMs   int64                  `protobuf:"varint,1,opt,name=timestamp_ms,json=timestampMs,proto3" json:"timestamp_ms,omitempty"` // bucket start
+	Avg           *float64               `protobuf:"fixed64,2,opt,name=avg,proto3,oneof" json:"avg,omitempty"`
+	Min           *float64               `protobuf:"fixed64,3,opt,name=min,proto3,oneof" json:"min,omitempty"`
+	Max           *float64               `protobuf:"fixed64,4,opt,name=max,proto3,oneof" json:"max,omitempty"`
+	Sum           *float64               `protobuf:"fixed64,5,opt,name=sum,proto3,oneof" json:"sum,omitempty"`
+	Count         *int64                 `protobuf:"varint,6,opt,name=count,proto3,oneof" json:"count,omitempty"`
+	First         *float64               `protobuf:"fixed64,7,opt,name=first,proto3,oneof" json:"first,omitempty"`
+	Last          *float64               `protobuf:"fixed64,8,opt,name=last,proto3,oneof" json:"last,omitempty"`
+	Stddev        *float64               `protobuf:"fixed64,9,opt,name=stddev,proto3,oneof" json:"stddev,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AggregatePoint) Reset() {
+	*x = AggregatePoint{}
+	mi := &file_common_protobuf_sensor_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AggregatePoint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AggregatePoint) ProtoMessage() {}
+
+func (x *AggregatePoint) ProtoReflect() protoreflect.Message {
+	mi := &file_common_protobuf_sensor_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AggregatePoint.ProtoReflect.Descriptor instead.
+func (*AggregatePoint) Descriptor() ([]byte, []int) {
+	return file_common_protobuf_sensor_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *AggregatePoint) GetTimestampMs() int64 {
+	if x != nil {
+		return x.TimestampMs
+	}
+	return 0
+}
+
+func (x *AggregatePoint) GetAvg() float64 {
+	if x != nil && x.Avg != nil {
+		return *x.Avg
+	}
+	return 0
+}
+
+func (x *AggregatePoint) GetMin() float64 {
+	if x != nil && x.Min != nil {
+		return *x.Min
+	}
+	return 0
+}
+
+func (x *AggregatePoint) GetMax() float64 {
+	if x != nil && x.Max != nil {
+		return *x.Max
+	}
+	return 0
+}
+
+func (x *AggregatePoint) GetSum() float64 {
+	if x != nil && x.Sum != nil {
+		return *x.Sum
+	}
+	return 0
+}
+
+func (x *AggregatePoint) GetCount() int64 {
+	if x != nil && x.Count != nil {
+		return *x.Count
+	}
+	return 0
+}
+
+func (x *AggregatePoint) GetFirst() float64 {
+	if x != nil && x.First != nil {
+		return *x.First
+	}
+	return 0
+}
+
+func (x *AggregatePoint) GetLast() float64 {
+	if x != nil && x.Last != nil {
+		return *x.Last
+	}
+	return 0
+}
+
+func (x *AggregatePoint) GetStddev() float64 {
+	if x != nil && x.Stddev != nil {
+		return *x.Stddev
+	}
+	return 0
+}
+
+type AggregateSeries struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id1           string                 `protobuf:"bytes,1,opt,name=id1,proto3" json:"id1,omitempty"`
+	Id2           int32                  `protobuf:"varint,2,opt,name=id2,proto3" json:"id2,omitempty"`
+	Points        []*AggregatePoint      `protobuf:"bytes,3,rep,name=points,proto3" json:"points,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AggregateSeries) Reset() {
+	*x = AggregateSeries{}
+	mi := &file_common_protobuf_sensor_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AggregateSeries) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AggregateSeries) ProtoMessage() {}
+
+func (x *AggregateSeries) ProtoReflect() protoreflect.Message {
+	mi := &file_common_protobuf_sensor_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AggregateSeries.ProtoReflect.Descriptor instead.
+func (*AggregateSeries) Descriptor() ([]byte, []int) {
+	return file_common_protobuf_sensor_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *AggregateSeries) GetId1() string {
+	if x != nil {
+		return x.Id1
+	}
+	return ""
+}
+
+func (x *AggregateSeries) GetId2() int32 {
+	if x != nil {
+		return x.Id2
+	}
+	return 0
+}
+
+func (x *AggregateSeries) GetPoints() []*AggregatePoint {
+	if x != nil {
+		return x.Points
+	}
+	return nil
+}
+
+type AggregateReadingsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Series        []*AggregateSeries     `protobuf:"bytes,1,rep,name=series,proto3" json:"series,omitempty"` // one per sensor, ordered by id1 and id2
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AggregateReadingsResponse) Reset() {
+	*x = AggregateReadingsResponse{}
+	mi := &file_common_protobuf_sensor_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AggregateReadingsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AggregateReadingsResponse) ProtoMessage() {}
+
+func (x *AggregateReadingsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_protobuf_sensor_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AggregateReadingsResponse.ProtoReflect.Descriptor instead.
+func (*AggregateReadingsResponse) Descriptor() ([]byte, []int) {
+	return file_common_protobuf_sensor_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *AggregateReadingsResponse) GetSeries() []*AggregateSeries {
+	if x != nil {
+		return x.Series
+	}
+	return nil
+}
+
+// SensorSummary is one distinct (id1, id2) of the stored readings.
+type SensorSummary struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Id1              string                 `protobuf:"bytes,1,opt,name=id1,proto3" json:"id1,omitempty"`
+	Id2              int32                  `protobuf:"varint,2,opt,name=id2,proto3" json:"id2,omitempty"`
+	SensorType       string                 `protobuf:"bytes,3,opt,name=sensor_type,json=sensorType,proto3" json:"sensor_type,omitempty"` // of the latest reading
+	LatestValue      float64                `protobuf:"fixed64,4,opt,name=latest_value,json=latestValue,proto3" json:"latest_value,omitempty"`
+	FirstTimestampMs int64                  `protobuf:"varint,5,opt,name=first_timestamp_ms,json=firstTimestampMs,proto3" json:"first_timestamp_ms,omitempty"`
+	LastTimestampMs  int64                  `protobuf:"varint,6,opt,name=last_timestamp_ms,json=lastTimestampMs,proto3" json:"last_timestamp_ms,omitempty"`
+	Count            int64                  `protobuf:"varint,7,opt,name=count,proto3" json:"count,omitempty"`
+	IngestRate       float64                `protobuf:"fixed64,8,opt,name=ingest_rate,json=ingestRate,proto3" json:"ingest_rate,omitempty"` // readings per second over the rate window
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *SensorSummary) Reset() {
+	*x = SensorSummary{}
+	mi := &file_common_protobuf_sensor_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SensorSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SensorSummary) ProtoMessage() {}
+
+func (x *SensorSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_common_protobuf_sensor_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SensorSummary.ProtoReflect.Descriptor instead.
+func (*SensorSummary) Descriptor() ([]byte, []int) {
+	return file_common_protobuf_sensor_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *SensorSummary) GetId1() string {
+	if x != nil {
+		return x.Id1
+	}
+	return ""
+}
+
+func (x *SensorSummary) GetId2() int32 {
+	if x != nil {
+		return x.Id2
+	}
+	return 0
+}
+
+func (x *SensorSummary) GetSensorType() string {
+	if x != nil {
+		return x.SensorType
+	}
+	return ""
+}
+
+func (x *SensorSummary) GetLatestValue() float64 {
+	if x != nil {
+		return x.LatestValue
+	}
+	return 0
+}
+
+func (x *SensorSummary) GetFirstTimestampMs() int64 {
+	if x != nil {
+		return x.FirstTimestampMs
+	}
+	return 0
+}
+
+func (x *SensorSummary) GetLastTimestampMs() int64 {
+	if x != nil {
+		return x.LastTimestampMs
+	}
+	return 0
+}
+
+func (x *SensorSummary) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *SensorSummary) GetIngestRate() float64 {
+	if x != nil {
+		return x.IngestRate
+	}
+	return 0
+}
+
+type ListSensorsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Page          uint32                 `protobuf:"varint,1,opt,name=page,proto3" json:"page,omitempty"`                                  // 1-based
+	PageSize      uint32                 `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`          // 1..500
+	RateMinutes   uint32                 `protobuf:"varint,3,opt,name=rate_minutes,json=rateMinutes,proto3" json:"rate_minutes,omitempty"` // ingest rate window, 1..1440, 5 when unset
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSensorsRequest) Reset() {
+	*x = ListSensorsRequest{}
+	mi := &file_common_protobuf_sensor_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSensorsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSensorsRequest) ProtoMessage() {}
+
+func (x *ListSensorsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_protobuf_sensor_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSensorsRequest.ProtoReflect.Descriptor instead.
+func (*ListSensorsRequest) Descriptor() ([]byte, []int) {
+	return file_common_protobuf_sensor_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *ListSensorsRequest) GetPage() uint32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListSensorsRequest) GetPageSize() uint32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListSensorsRequest) GetRateMinutes() uint32 {
+	if x != nil {
+		return x.RateMinutes
+	}
+	return 0
+}
+
+type ListSensorsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Items         []*SensorSummary       `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	Page          uint32                 `protobuf:"varint,2,opt,name=page,proto3" json:"page,omitempty"`
+	PageSize      uint32                 `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	Total         int64                  `protobuf:"varint,4,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSensorsResponse) Reset() {
+	*x = ListSensorsResponse{}
+	mi := &file_common_protobuf_sensor_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSensorsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSensorsResponse) ProtoMessage() {}
+
+func (x *ListSensorsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_protobuf_sensor_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSensorsResponse.ProtoReflect.Descriptor instead.
+func (*ListSensorsResponse) Descriptor() ([]byte, []int) {
+	return file_common_protobuf_sensor_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *ListSensorsResponse) GetItems() []*SensorSummary {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *ListSensorsResponse) GetPage() uint32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListSensorsResponse) GetPageSize() uint32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListSensorsResponse) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+type GetLatestReadingRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            *IdCombination         `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLatestReadingRequest) Reset() {
+	*x = GetLatestReadingRequest{}
+	mi := &file_common_protobuf_sensor_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLatestReadingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLatestReadingRequest) ProtoMessage() {}
+
+func (x *GetLatestReadingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_protobuf_sensor_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLatestReadingRequest.ProtoReflect.Descriptor instead.
+func (*GetLatestReadingRequest) Descriptor() ([]byte, []int) {
+	return file_common_protobuf_sensor_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *GetLatestReadingRequest) GetId() *IdCombination {
+	if x != nil {
+		return x.Id
+	}
+	return nil
+}
+
+// SensorQueryService mirrors the REST sensor API for gRPC clients.
+// A sensor of the registry. enabled is always set in responses, and left unset
+// in a request it means true.
+type SensorMetadata struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id1           string                 `protobuf:"bytes,1,opt,name=id1,proto3" json:"id1,omitempty"`
+	Id2           int32                  `protobuf:"varint,2,opt,name=id2,proto3" json:"id2,omitempty"`
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Unit          string                 `protobuf:"bytes,4,opt,name=unit,proto3" json:"unit,omitempty"`
+	Location      string                 `protobuf:"bytes,5,opt,name=location,proto3" json:"location,omitempty"`
+	Owner         string                 `protobuf:"bytes,6,opt,name=owner,proto3" json:"owner,omitempty"`
+	ExpectedRate  float64                `protobuf:"fixed64,7,opt,name=expected_rate,json=expectedRate,proto3" json:"expected_rate,omitempty"` // readings per second, 0 when unknown
+	Enabled       *bool                  `protobuf:"varint,8,opt,name=enabled,proto3,oneof" json:"enabled,omitempty"`
+	Labels        map[string]string      `protobuf:"bytes,9,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	CreatedAtMs   int64                  `protobuf:"varint,10,opt,name=created_at_ms,json=createdAtMs,proto3" json:"created_at_ms,omitempty"` // Unix ms, ignored in requests
+	UpdatedAtMs   int64                  `protobuf:"varint,11,opt,name=updated_at_ms,json=updatedAtMs,proto3" json:"updated_at_ms,omitempty"` // Unix ms, ignored in requests
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SensorMetadata) Reset() {
+	*x = SensorMetadata{}
+	mi := &file_common_protobuf_sensor_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SensorMetadata) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SensorMetadata) ProtoMessage() {}
+
+func (x *SensorMetadata) ProtoReflect() protoreflect.Message {
+	mi := &file_common_protobuf_sensor_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SensorMetadata.ProtoReflect.Descriptor instead.
+func (*SensorMetadata) Descriptor() ([]byte, []int) {
+	return file_common_protobuf_sensor_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *SensorMetadata) GetId1() string {
+	if x != nil {
+		return x.Id1
+	}
+	return ""
+}
+
+func (x *SensorMetadata) GetId2() int32 {
+	if x != nil {
+		return x.Id2
+	}
+	return 0
+}
+
+func (x *SensorMetadata) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SensorMetadata) GetUnit() string {
+	if x != nil {
+		return x.Unit
+	}
+	return ""
+}
+
+func (x *SensorMetadata) GetLocation() string {
+	if x != nil {
+		return x.Location
+	}
+	return ""
+}
+
+func (x *SensorMetadata) GetOwner() string {
+	if x != nil {
+		return x.Owner
+	}
+	return ""
+}
+
+func (x *SensorMetadata) GetExpectedRate() float64 {
+	if x != nil {
+		return x.ExpectedRate
+	}
+	return 0
+}
+
+func (x *SensorMetadata) GetEnabled() bool {
+	if x != nil && x.Enabled != nil {
+		return *x.Enabled
+	}
+	return false
+}
+
+func (x *SensorMetadata) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *SensorMetadata) GetCreatedAtMs() int64 {
+	if x != nil {
+		return x.CreatedAtMs
+	}
+	return 0
+}
+
+func (x *SensorMetadata) GetUpdatedAtMs() int64 {
+	if x != nil {
+		return x.UpdatedAtMs
+	}
+	return 0
+}
+
+type RegisterSensorRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sensor        *SensorMetadata        `protobuf:"bytes,1,opt,name=sensor,proto3" json:"sensor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisterSensorRequest) Reset() {
+	*x = RegisterSensorRequest{}
+	mi := &file_common_protobuf_sensor_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterSensorRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterSensorRequest) ProtoMessage() {}
+
+func (x *RegisterSensorRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_protobuf_sensor_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterSensorRequest.ProtoReflect.Descriptor instead.
+func (*RegisterSensorRequest) Descriptor() ([]byte, []int) {
+	return file_common_protobuf_sensor_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *RegisterSensorRequest) GetSensor() *SensorMetadata {
+	if x != nil {
+		return x.Sensor
+	}
+	return nil
+}
+
+type GetSensorRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            *IdCombination         `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSensorRequest) Reset() {
+	*x = GetSensorRequest{}
+	mi := &file_common_protobuf_sensor_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSensorRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSensorRequest) ProtoMessage() {}
+
+func (x *GetSensorRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_protobuf_sensor_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSensorRequest.ProtoReflect.Descriptor instead.
+func (*GetSensorRequest) Descriptor() ([]byte, []int) {
+	return file_common_protobuf_sensor_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *GetSensorRequest) GetId() *IdCombination {
+	if x != nil {
+		return x.Id
+	}
+	return nil
+}
+
+type ListRegisteredSensorsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Page          uint32                 `protobuf:"varint,1,opt,name=page,proto3" json:"page,omitempty"`                         // 1-based
+	PageSize      uint32                 `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"` // 1..500
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRegisteredSensorsRequest) Reset() {
+	*x = ListRegisteredSensorsRequest{}
+	mi := &file_common_protobuf_sensor_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRegisteredSensorsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRegisteredSensorsRequest) ProtoMessage() {}
+
+func (x *ListRegisteredSensorsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_protobuf_sensor_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRegisteredSensorsRequest.ProtoReflect.Descriptor instead.
+func (*ListRegisteredSensorsRequest) Descriptor() ([]byte, []int) {
+	return file_common_protobuf_sensor_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *ListRegisteredSensorsRequest) GetPage() uint32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListRegisteredSensorsRequest) GetPageSize() uint32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+type ListRegisteredSensorsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Items         []*SensorMetadata      `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	Page          uint32                 `protobuf:"varint,2,opt,name=page,proto3" json:"page,omitempty"`
+	PageSize      uint32                 `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	Total         int64                  `protobuf:"varint,4,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRegisteredSensorsResponse) Reset() {
+	*x = ListRegisteredSensorsResponse{}
+	mi := &file_common_protobuf_sensor_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRegisteredSensorsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRegisteredSensorsResponse) ProtoMessage() {}
+
+func (x *ListRegisteredSensorsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_protobuf_sensor_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRegisteredSensorsResponse.ProtoReflect.Descriptor instead.
+func (*ListRegisteredSensorsResponse) Descriptor() ([]byte, []int) {
+	return file_common_protobuf_sensor_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *ListRegisteredSensorsResponse) GetItems() []*SensorMetadata {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *ListRegisteredSensorsResponse) GetPage() uint32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListRegisteredSensorsResponse) GetPageSize() uint32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListRegisteredSensorsResponse) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+// UpdateSensorRequest replaces every field of the sensor its id1 and id2 name.
+type UpdateSensorRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sensor        *SensorMetadata        `protobuf:"bytes,1,opt,name=sensor,proto3" json:"sensor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateSensorRequest) Reset() {
+	*x = UpdateSensorRequest{}
+	mi := &file_common_protobuf_sensor_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateSensorRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateSensorRequest) ProtoMessage() {}
+
+func (x *UpdateSensorRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_protobuf_sensor_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateSensorRequest.ProtoReflect.Descriptor instead.
+func (*UpdateSensorRequest) Descriptor() ([]byte, []int) {
+	return file_common_protobuf_sensor_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *UpdateSensorRequest) GetSensor() *SensorMetadata {
+	if x != nil {
+		return x.Sensor
+	}
+	return nil
+}
+
+type DeleteSensorRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            *IdCombination         `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteSensorRequest) Reset() {
+	*x = DeleteSensorRequest{}
+	mi := &file_common_protobuf_sensor_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteSensorRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteSensorRequest) ProtoMessage() {}
+
+func (x *DeleteSensorRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_protobuf_sensor_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteSensorRequest.ProtoReflect.Descriptor instead.
+func (*DeleteSensorRequest) Descriptor() ([]byte, []int) {
+	return file_common_protobuf_sensor_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *DeleteSensorRequest) GetId() *IdCombination {
+	if x != nil {
+		return x.Id
+	}
+	return nil
+}
+
+type DeleteSensorResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteSensorResponse) Reset() {
+	*x = DeleteSensorResponse{}
+	mi := &file_common_protobuf_sensor_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteSensorResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteSensorResponse) ProtoMessage() {}
+
+func (x *DeleteSensorResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_protobuf_sensor_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteSensorResponse.ProtoReflect.Descriptor instead.
+func (*DeleteSensorResponse) Descriptor() ([]byte, []int) {
+	return file_common_protobuf_sensor_proto_rawDescGZIP(), []int{33}
+}
+
+var File_common_protobuf_sensor_proto protoreflect.FileDescriptor
+
+const file_common_protobuf_sensor_proto_rawDesc = "" +
+	"\n" +
+	"\x1ccommon/protobuf/sensor.proto\x12\x06sensor\"\xae\x01\n" +
+	"\rSensorReading\x12\x14\n" +
+	"\x05value\x18\x01 \x01(\x01R\x05value\x12\x1f\n" +
+	"\vsensor_type\x18\x02 \x01(\tR\n" +
+	"sensorType\x12\x10\n" +
+	"\x03id1\x18\x03 \x01(\tR\x03id1\x12\x10\n" +
+	"\x03id2\x18\x04 \x01(\x05R\x03id2\x12!\n" +
+	"\ftimestamp_ms\x18\x05 \x01(\x03R\vtimestampMs\x12\x1f\n" +
+	"\vreading_key\x18\x06 \x01(\tR\n" +
+	"readingKey\"\xad\x01\n" +
+	"\tStreamAck\x12\x1a\n" +
+	"\x06status\x18\x01 \x01(\tB\x02\x18\x01R\x06status\x12\x1a\n" +
+	"\baccepted\x18\x02 \x01(\x04R\baccepted\x12\x1a\n" +
+	"\brejected\x18\x03 \x01(\x04R\brejected\x122\n" +
+	"\n" +
+	"durability\x18\x04 \x01(\x0e2\x12.sensor.DurabilityR\n" +
+	"durability\x12\x18\n" +
+	"\aspooled\x18\x05 \x01(\x04R\aspooled\"G\n" +
+	"\x12SensorReadingBatch\x121\n" +
+	"\breadings\x18\x01 \x03(\v2\x15.sensor.SensorReadingR\breadings\"H\n" +
+	"\x0eFieldViolation\x12\x14\n" +
+	"\x05field\x18\x01 \x01(\tR\x05field\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\"\x93\x02\n" +
+	"\n" +
+	"ItemResult\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\rR\x05index\x12*\n" +
+	"\x06status\x18\x02 \x01(\x0e2\x12.sensor.ItemStatusR\x06status\x12\x1d\n" +
+	"\n" +
+	"reading_id\x18\x03 \x01(\x04R\treadingId\x12\x16\n" +
+	"\x06reason\x18\x04 \x01(\tR\x06reason\x122\n" +
+	"\n" +
+	"durability\x18\x05 \x01(\x0e2\x12.sensor.DurabilityR\n" +
+	"durability\x126\n" +
+	"\n" +
+	"violations\x18\x06 \x03(\v2\x16.sensor.FieldViolationR\n" +
+	"violations\x12 \n" +
+	"\fout_of_range\x18\a \x01(\bR\n" +
+	"outOfRange\"\x8c\x01\n" +
+	"\bBatchAck\x12,\n" +
+	"\aresults\x18\x01 \x03(\v2\x12.sensor.ItemResultR\aresults\x12\x16\n" +
+	"\x06stored\x18\x02 \x01(\rR\x06stored\x12\x1a\n" +
+	"\brejected\x18\x03 \x01(\rR\brejected\x12\x1e\n" +
+	"\n" +
+	"duplicates\x18\x04 \x01(\rR\n" +
+	"duplicates\"U\n" +
+	"\x10SequencedReading\x12\x10\n" +
+	"\x03seq\x18\x01 \x01(\x04R\x03seq\x12/\n" +
+	"\areading\x18\x02 \x01(\v2\x15.sensor.SensorReadingR\areading\"\x94\x01\n" +
+	"\x04Nack\x12\x10\n" +
+	"\x03seq\x18\x01 \x01(\x04R\x03seq\x12*\n" +
+	"\x06status\x18\x02 \x01(\x0e2\x12.sensor.ItemStatusR\x06status\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\x126\n" +
+	"\n" +
+	"violations\x18\x04 \x03(\v2\x16.sensor.FieldViolationR\n" +
+	"violations\"\x88\x01\n" +
+	"\tIngestAck\x12#\n" +
+	"\rcommitted_seq\x18\x01 \x01(\x04R\fcommittedSeq\x12\"\n" +
+	"\x05nacks\x18\x02 \x03(\v2\f.sensor.NackR\x05nacks\x122\n" +
+	"\n" +
+	"durability\x18\x03 \x01(\x0e2\x12.sensor.DurabilityR\n" +
+	"durability\"3\n" +
+	"\rIdCombination\x12\x10\n" +
+	"\x03id1\x18\x01 \x01(\tR\x03id1\x12\x10\n" +
+	"\x03id2\x18\x02 \x01(\x05R\x03id2\"\xa2\x02\n" +
+	"\rStoredReading\x12\x1d\n" +
+	"\n" +
+	"reading_id\x18\x01 \x01(\x04R\treadingId\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x01R\x05value\x12\x1f\n" +
+	"\vsensor_type\x18\x03 \x01(\tR\n" +
+	"sensorType\x12\x10\n" +
+	"\x03id1\x18\x04 \x01(\tR\x03id1\x12\x10\n" +
+	"\x03id2\x18\x05 \x01(\x05R\x03id2\x12!\n" +
+	"\ftimestamp_ms\x18\x06 \x01(\x03R\vtimestampMs\x12\"\n" +
+	"\rcreated_at_ms\x18\a \x01(\x03R\vcreatedAtMs\x12.\n" +
+	"\x06sensor\x18\b \x01(\v2\x16.sensor.SensorMetadataR\x06sensor\x12 \n" +
+	"\fout_of_range\x18\t \x01(\bR\n" +
+	"outOfRange\"\xc2\x01\n" +
+	"\x13ListReadingsRequest\x12\x12\n" +
+	"\x04page\x18\x01 \x01(\rR\x04page\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\rR\bpageSize\x12'\n" +
+	"\x03ids\x18\x03 \x03(\v2\x15.sensor.IdCombinationR\x03ids\x12\x17\n" +
+	"\afrom_ms\x18\x04 \x01(\x03R\x06fromMs\x12\x13\n" +
+	"\x05to_ms\x18\x05 \x01(\x03R\x04toMs\x12#\n" +
+	"\rwith_metadata\x18\x06 \x01(\bR\fwithMetadata\"\x8a\x01\n" +
+	"\x14ListReadingsResponse\x12+\n" +
+	"\x05items\x18\x01 \x03(\v2\x15.sensor.StoredReadingR\x05items\x12\x12\n" +
+	"\x04page\x18\x02 \x01(\rR\x04page\x12\x1b\n" +
+	"\tpage_size\x18\x03 \x01(\rR\bpageSize\x12\x14\n" +
+	"\x05total\x18\x04 \x01(\x03R\x05total\"\xb2\x01\n" +
+	"\x15UpdateReadingsRequest\x12'\n" +
+	"\x03ids\x18\x01 \x03(\v2\x15.sensor.IdCombinationR\x03ids\x12\x17\n" +
+	"\afrom_ms\x18\x02 \x01(\x03R\x06fromMs\x12\x13\n" +
+	"\x05to_ms\x18\x03 \x01(\x03R\x04toMs\x12!\n" +
+	"\fsensor_value\x18\x04 \x01(\x01R\vsensorValue\x12\x1f\n" +
+	"\vsensor_type\x18\x05 \x01(\tR\n" +
+	"sensorType\"W\n" +
+	"\x16UpdateReadingsResponse\x12#\n" +
+	"\rupdated_count\x18\x01 \x01(\x03R\fupdatedCount\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"n\n" +
+	"\x15DeleteReadingsRequest\x12'\n" +
+	"\x03ids\x18\x01 \x03(\v2\x15.sensor.IdCombinationR\x03ids\x12\x17\n" +
+	"\afrom_ms\x18\x02 \x01(\x03R\x06fromMs\x12\x13\n" +
+	"\x05to_ms\x18\x03 \x01(\x03R\x04toMs\"W\n" +
+	"\x16DeleteReadingsResponse\x12#\n" +
+	"\rdeleted_count\x18\x01 \x01(\x03R\fdeletedCount\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"\xfb\x01\n" +
+	"\fWatchRequest\x12'\n" +
+	"\x03ids\x18\x01 \x03(\v2\x15.sensor.IdCombinationR\x03ids\x12!\n" +
+	"\fsensor_types\x18\x02 \x03(\tR\vsensorTypes\x12 \n" +
+	"\tmin_value\x18\x03 \x01(\x01H\x00R\bminValue\x88\x01\x01\x12 \n" +
+	"\tmax_value\x18\x04 \x01(\x01H\x01R\bmaxValue\x88\x01\x01\x12?\n" +
+	"\rslow_consumer\x18\x05 \x01(\x0e2\x1a.sensor.SlowConsumerPolicyR\fslowConsumerB\f\n" +
+	"\n" +
+	"_min_valueB\f\n" +
+	"\n" +
+	"_max_value\"\xe8\x01\n" +
+	"\x18AggregateReadingsRequest\x12'\n" +
+	"\x03ids\x18\x01 \x03(\v2\x15.sensor.IdCombinationR\x03ids\x12\x1f\n" +
+	"\vsensor_type\x18\x02 \x01(\tR\n" +
+	"sensorType\x12\x17\n" +
+	"\afrom_ms\x18\x03 \x01(\x03R\x06fromMs\x12\x13\n" +
+	"\x05to_ms\x18\x04 \x01(\x03R\x04toMs\x12\x1b\n" +
+	"\tbucket_ms\x18\x05 \x01(\x03R\bbucketMs\x127\n" +
+	"\tfunctions\x18\x06 \x03(\x0e2\x19.sensor.AggregateFunctionR\tfunctions\"\xc3\x02\n" +
+	"\x0eAggregatePoint\x12!\n" +
+	"\ftimestamp_ms\x18\x01 \x01(\x03R\vtimestampMs\x12\x15\n" +
+	"\x03avg\x18\x02 \x01(\x01H\x00R\x03avg\x88\x01\x01\x12\x15\n" +
+	"\x03min\x18\x03 \x01(\x01H\x01R\x03min\x88\x01\x01\x12\x15\n" +
+	"\x03max\x18\x04 \x01(\x01H\x02R\x03max\x88\x01\x01\x12\x15\n" +
+	"\x03sum\x18\x05 \x01(\x01H\x03R\x03sum\x88\x01\x01\x12\x19\n" +
+	"\x05count\x18\x06 \x01(\x03H\x04R\x05count\x88\x01\x01\x12\x19\n" +
+	"\x05first\x18\a \x01(\x01H\x05R\x05first\x88\x01\x01\x12\x17\n" +
+	"\x04last\x18\b \x01(\x01H\x06R\x04last\x88\x01\x01\x12\x1b\n" +
+	"\x06stddev\x18\t \x01(\x01H\aR\x06stddev\x88\x01\x01B\x06\n" +
+	"\x04_avgB\x06\n" +
+	"\x04_minB\x06\n" +
+	"\x04_maxB\x06\n" +
+	"\x04_sumB\b\n" +
+	"\x06_countB\b\n" +
+	"\x06_firstB\a\n" +
+	"\x05_lastB\t\n" +
+	"\a_stddev\"e\n" +
+	"\x0fAggregateSeries\x12\x10\n" +
+	"\x03id1\x18\x01 \x01(\tR\x03id1\x12\x10\n" +
+	"\x03id2\x18\x02 \x01(\x05R\x03id2\x12.\n" +
+	"\x06points\x18\x03 \x03(\v2\x16.sensor.AggregatePointR\x06points\"L\n" +
+	"\x19AggregateReadingsResponse\x12/\n" +
+	"\x06series\x18\x01 \x03(\v2\x17.sensor.AggregateSeriesR\x06series\"\x88\x02\n" +
+	"\rSensorSummary\x12\x10\n" +
+	"\x03id1\x18\x01 \x01(\tR\x03id1\x12\x10\n" +
+	"\x03id2\x18\x02 \x01(\x05R\x03id2\x12\x1f\n" +
+	"\vsensor_type\x18\x03 \x01(\tR\n" +
+	"sensorType\x12!\n" +
+	"\flatest_value\x18\x04 \x01(\x01R\vlatestValue\x12,\n" +
+	"\x12first_timestamp_ms\x18\x05 \x01(\x03R\x10firstTimestampMs\x12*\n" +
+	"\x11last_timestamp_ms\x18\x06 \x01(\x03R\x0flastTimestampMs\x12\x14\n" +
+	"\x05count\x18\a \x01(\x03R\x05count\x12\x1f\n" +
+	"\vingest_rate\x18\b \x01(\x01R\n" +
+	"ingestRate\"h\n" +
+	"\x12ListSensorsRequest\x12\x12\n" +
+	"\x04page\x18\x01 \x01(\rR\x04page\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\rR\bpageSize\x12!\n" +
+	"\frate_minutes\x18\x03 \x01(\rR\vrateMinutes\"\x89\x01\n" +
+	"\x13ListSensorsResponse\x12+\n" +
+	"\x05items\x18\x01 \x03(\v2\x15.sensor.SensorSummaryR\x05items\x12\x12\n" +
+	"\x04page\x18\x02 \x01(\rR\x04page\x12\x1b\n" +
+	"\tpage_size\x18\x03 \x01(\rR\bpageSize\x12\x14\n" +
+	"\x05total\x18\x04 \x01(\x03R\x05total\"@\n" +
+	"\x17GetLatestReadingRequest\x12%\n" +
+	"\x02id\x18\x01 \x01(\v2\x15.sensor.IdCombinationR\x02id\"\x9d\x03\n" +
+	"\x0eSensorMetadata\x12\x10\n" +
+	"\x03id1\x18\x01 \x01(\tR\x03id1\x12\x10\n" +
+	"\x03id2\x18\x02 \x01(\x05R\x03id2\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x12\n" +
+	"\x04unit\x18\x04 \x01(\tR\x04unit\x12\x1a\n" +
+	"\blocation\x18\x05 \x01(\tR\blocation\x12\x14\n" +
+	"\x05owner\x18\x06 \x01(\tR\x05owner\x12#\n" +
+	"\rexpected_rate\x18\a \x01(\x01R\fexpectedRate\x12\x1d\n" +
+	"\aenabled\x18\b \x01(\bH\x00R\aenabled\x88\x01\x01\x12:\n" +
+	"\x06labels\x18\t \x03(\v2\".sensor.SensorMetadata.LabelsEntryR\x06labels\x12\"\n" +
+	"\rcreated_at_ms\x18\n" +
+	" \x01(\x03R\vcreatedAtMs\x12\"\n" +
+	"\rupdated_at_ms\x18\v \x01(\x03R\vupdatedAtMs\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01B\n" +
+	"\n" +
+	"\b_enabled\"G\n" +
+	"\x15RegisterSensorRequest\x12.\n" +
+	"\x06sensor\x18\x01 \x01(\v2\x16.sensor.SensorMetadataR\x06sensor\"9\n" +
+	"\x10GetSensorRequest\x12%\n" +
+	"\x02id\x18\x01 \x01(\v2\x15.sensor.IdCombinationR\x02id\"O\n" +
+	"\x1cListRegisteredSensorsRequest\x12\x12\n" +
+	"\x04page\x18\x01 \x01(\rR\x04page\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\rR\bpageSize\"\x94\x01\n" +
+	"\x1dListRegisteredSensorsResponse\x12,\n" +
+	"\x05items\x18\x01 \x03(\v2\x16.sensor.SensorMetadataR\x05items\x12\x12\n" +
+	"\x04page\x18\x02 \x01(\rR\x04page\x12\x1b\n" +
+	"\tpage_size\x18\x03 \x01(\rR\bpageSize\x12\x14\n" +
+	"\x05total\x18\x04 \x01(\x03R\x05total\"E\n" +
+	"\x13UpdateSensorRequest\x12.\n" +
+	"\x06sensor\x18\x01 \x01(\v2\x16.sensor.SensorMetadataR\x06sensor\"<\n" +
+	"\x13DeleteSensorRequest\x12%\n" +
+	"\x02id\x18\x01 \x01(\v2\x15.sensor.IdCombinationR\x02id\"\x16\n" +
+	"\x14DeleteSensorResponse*Z\n" +
+	"\n" +
+	"Durability\x12\x1a\n" +
+	"\x16DURABILITY_UNSPECIFIED\x10\x00\x12\x18\n" +
+	"\x14DURABILITY_COMMITTED\x10\x01\x12\x16\n" +
+	"\x12DURABILITY_SPOOLED\x10\x02*\xa5\x01\n" +
+	"\n" +
+	"ItemStatus\x12\x1b\n" +
+	"\x17ITEM_STATUS_UNSPECIFIED\x10\x00\x12\x16\n" +
+	"\x12ITEM_STATUS_STORED\x10\x01\x12#\n" +
+	"\x1fITEM_STATUS_REJECTED_VALIDATION\x10\x02\x12\x19\n" +
+	"\x15ITEM_STATUS_DUPLICATE\x10\x03\x12\"\n" +
+	"\x1eITEM_STATUS_DUPLICATE_REJECTED\x10\x04*~\n" +
+	"\x12SlowConsumerPolicy\x12$\n" +
+	" SLOW_CONSUMER_POLICY_UNSPECIFIED\x10\x00\x12\x1d\n" +
+	"\x19SLOW_CONSUMER_POLICY_DROP\x10\x01\x12#\n" +
+	"\x1fSLOW_CONSUMER_POLICY_DISCONNECT\x10\x02*\x9f\x02\n" +
+	"\x11AggregateFunction\x12\"\n" +
+	"\x1eAGGREGATE_FUNCTION_UNSPECIFIED\x10\x00\x12\x1a\n" +
+	"\x16AGGREGATE_FUNCTION_AVG\x10\x01\x12\x1a\n" +
+	"\x16AGGREGATE_FUNCTION_MIN\x10\x02\x12\x1a\n" +
+	"\x16AGGREGATE_FUNCTION_MAX\x10\x03\x12\x1a\n" +
+	"\x16AGGREGATE_FUNCTION_SUM\x10\x04\x12\x1c\n" +
+	"\x18AGGREGATE_FUNCTION_COUNT\x10\x05\x12\x1c\n" +
+	"\x18AGGREGATE_FUNCTION_FIRST\x10\x06\x12\x1b\n" +
+	"\x17AGGREGATE_FUNCTION_LAST\x10\a\x12\x1d\n" +
+	"\x19AGGREGATE_FUNCTION_STDDEV\x10\b2\x83\x02\n" +
 	"\rIngestService\x12<\n" +
 	"\x0eStreamReadings\x12\x15.sensor.SensorReading\x1a\x11.sensor.StreamAck(\x01\x124\n" +
-	"\bReadings\x12\x15.sensor.SensorReading\x1a\x11.sensor.StreamAckB@Z>github.com/Yusufzhafir/worlder-team-assignment/common/protobufb\x06proto3"
+	"\bReadings\x12\x15.sensor.SensorReading\x1a\x11.sensor.StreamAck\x12=\n" +
+	"\rReadingsBatch\x12\x1a.sensor.SensorReadingBatch\x1a\x10.sensor.BatchAck\x12?\n" +
+	"\fIngestStream\x12\x18.sensor.SequencedReading\x1a\x11.sensor.IngestAck(\x010\x012\xad\a\n" +
+	"\x12SensorQueryService\x12I\n" +
+	"\fListReadings\x12\x1b.sensor.ListReadingsRequest\x1a\x1c.sensor.ListReadingsResponse\x12O\n" +
+	"\x0eUpdateReadings\x12\x1d.sensor.UpdateReadingsRequest\x1a\x1e.sensor.UpdateReadingsResponse\x12O\n" +
+	"\x0eDeleteReadings\x12\x1d.sensor.DeleteReadingsRequest\x1a\x1e.sensor.DeleteReadingsResponse\x12X\n" +
+	"\x11AggregateReadings\x12 .sensor.AggregateReadingsRequest\x1a!.sensor.AggregateReadingsResponse\x12F\n" +
+	"\vListSensors\x12\x1a.sensor.ListSensorsRequest\x1a\x1b.sensor.ListSensorsResponse\x12J\n" +
+	"\x10GetLatestReading\x12\x1f.sensor.GetLatestReadingRequest\x1a\x15.sensor.StoredReading\x12G\n" +
+	"\x0eRegisterSensor\x12\x1d.sensor.RegisterSensorRequest\x1a\x16.sensor.SensorMetadata\x12=\n" +
+	"\tGetSensor\x12\x18.sensor.GetSensorRequest\x1a\x16.sensor.SensorMetadata\x12d\n" +
+	"\x15ListRegisteredSensors\x12$.sensor.ListRegisteredSensorsRequest\x1a%.sensor.ListRegisteredSensorsResponse\x12C\n" +
+	"\fUpdateSensor\x12\x1b.sensor.UpdateSensorRequest\x1a\x16.sensor.SensorMetadata\x12I\n" +
+	"\fDeleteSensor\x12\x1b.sensor.DeleteSensorRequest\x1a\x1c.sensor.DeleteSensorResponse\x12>\n" +
+	"\rWatchReadings\x12\x14.sensor.WatchRequest\x1a\x15.sensor.SensorReading0\x01B@Z>github.com/Yusufzhafir/worlder-team-assignment/common/protobufb\x06proto3"
 
 var (
 	file_common_protobuf_sensor_proto_rawDescOnce sync.Once
@@ -171,21 +2776,117 @@ func file_common_protobuf_sensor_proto_rawDescGZIP() []byte {
 	return file_common_protobuf_sensor_proto_rawDescData
 }
 
-var file_common_protobuf_sensor_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_common_protobuf_sensor_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
+var file_common_protobuf_sensor_proto_msgTypes = make([]protoimpl.MessageInfo, 35)
 var file_common_protobuf_sensor_proto_goTypes = []any{
-	(*SensorReading)(nil), // 0: sensor.SensorReading
-	(*StreamAck)(nil),     // 1: sensor.StreamAck
+	(Durability)(0),                       // 0: sensor.Durability
+	(ItemStatus)(0),                       // 1: sensor.ItemStatus
+	(SlowConsumerPolicy)(0),               // 2: sensor.SlowConsumerPolicy
+	(AggregateFunction)(0),                // 3: sensor.AggregateFunction
+	(*SensorReading)(nil),                 // 4: sensor.SensorReading
+	(*StreamAck)(nil),                     // 5: sensor.StreamAck
+	(*SensorReadingBatch)(nil),            // 6: sensor.SensorReadingBatch
+	(*FieldViolation)(nil),                // 7: sensor.FieldViolation
+	(*ItemResult)(nil),                    // 8: sensor.ItemResult
+	(*BatchAck)(nil),                      // 9: sensor.BatchAck
+	(*SequencedReading)(nil),              // 10: sensor.SequencedReading
+	(*Nack)(nil),                          // 11: sensor.Nack
+	(*IngestAck)(nil),                     // 12: sensor.IngestAck
+	(*IdCombination)(nil),                 // 13: sensor.IdCombination
+	(*StoredReading)(nil),                 // 14: sensor.StoredReading
+	(*ListReadingsRequest)(nil),           // 15: sensor.ListReadingsRequest
+	(*ListReadingsResponse)(nil),          // 16: sensor.ListReadingsResponse
+	(*UpdateReadingsRequest)(nil),         // 17: sensor.UpdateReadingsRequest
+	(*UpdateReadingsResponse)(nil),        // 18: sensor.UpdateReadingsResponse
+	(*DeleteReadingsRequest)(nil),         // 19: sensor.DeleteReadingsRequest
+	(*DeleteReadingsResponse)(nil),        // 20: sensor.DeleteReadingsResponse
+	(*WatchRequest)(nil),                  // 21: sensor.WatchRequest
+	(*AggregateReadingsRequest)(nil),      // 22: sensor.AggregateReadingsRequest
+	(*AggregatePoint)(nil),                // 23: sensor.AggregatePoint
+	(*AggregateSeries)(nil),               // 24: sensor.AggregateSeries
+	(*AggregateReadingsResponse)(nil),     // 25: sensor.AggregateReadingsResponse
+	(*SensorSummary)(nil),                 // 26: sensor.SensorSummary
+	(*ListSensorsRequest)(nil),            // 27: sensor.ListSensorsRequest
+	(*ListSensorsResponse)(nil),           // 28: sensor.ListSensorsResponse
+	(*GetLatestReadingRequest)(nil),       // 29: sensor.GetLatestReadingRequest
+	(*SensorMetadata)(nil),                // 30: sensor.SensorMetadata
+	(*RegisterSensorRequest)(nil),         // 31: sensor.RegisterSensorRequest
+	(*GetSensorRequest)(nil),              // 32: sensor.GetSensorRequest
+	(*ListRegisteredSensorsRequest)(nil),  // 33: sensor.ListRegisteredSensorsRequest
+	(*ListRegisteredSensorsResponse)(nil), // 34: sensor.ListRegisteredSensorsResponse
+	(*UpdateSensorRequest)(nil),           // 35: sensor.UpdateSensorRequest
+	(*DeleteSensorRequest)(nil),           // 36: sensor.DeleteSensorRequest
+	(*DeleteSensorResponse)(nil),          // 37: sensor.DeleteSensorResponse
+	nil,                                   // 38: sensor.SensorMetadata.LabelsEntry
 }
 var file_common_protobuf_sensor_proto_depIdxs = []int32{
-	0, // 0: sensor.IngestService.StreamReadings:input_type -> sensor.SensorReading
-	0, // 1: sensor.IngestService.Readings:input_type -> sensor.SensorReading
-	1, // 2: sensor.IngestService.StreamReadings:output_type -> sensor.StreamAck
-	1, // 3: sensor.IngestService.Readings:output_type -> sensor.StreamAck
-	2, // [2:4] is the sub-list for method output_type
-	0, // [0:2] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	0,  // 0: sensor.StreamAck.durability:type_name -> sensor.Durability
+	4,  // 1: sensor.SensorReadingBatch.readings:type_name -> sensor.SensorReading
+	1,  // 2: sensor.ItemResult.status:type_name -> sensor.ItemStatus
+	0,  // 3: sensor.ItemResult.durability:type_name -> sensor.Durability
+	7,  // 4: sensor.ItemResult.violations:type_name -> sensor.FieldViolation
+	8,  // 5: sensor.BatchAck.results:type_name -> sensor.ItemResult
+	4,  // 6: sensor.SequencedReading.reading:type_name -> sensor.SensorReading
+	1,  // 7: sensor.Nack.status:type_name -> sensor.ItemStatus
+	7,  // 8: sensor.Nack.violations:type_name -> sensor.FieldViolation
+	11, // 9: sensor.IngestAck.nacks:type_name -> sensor.Nack
+	0,  // 10: sensor.IngestAck.durability:type_name -> sensor.Durability
+	30, // 11: sensor.StoredReading.sensor:type_name -> sensor.SensorMetadata
+	13, // 12: sensor.ListReadingsRequest.ids:type_name -> sensor.IdCombination
+	14, // 13: sensor.ListReadingsResponse.items:type_name -> sensor.StoredReading
+	13, // 14: sensor.UpdateReadingsRequest.ids:type_name -> sensor.IdCombination
+	13, // 15: sensor.DeleteReadingsRequest.ids:type_name -> sensor.IdCombination
+	13, // 16: sensor.WatchRequest.ids:type_name -> sensor.IdCombination
+	2,  // 17: sensor.WatchRequest.slow_consumer:type_name -> sensor.SlowConsumerPolicy
+	13, // 18: sensor.AggregateReadingsRequest.ids:type_name -> sensor.IdCombination
+	3,  // 19: sensor.AggregateReadingsRequest.functions:type_name -> sensor.AggregateFunction
+	23, // 20: sensor.AggregateSeries.points:type_name -> sensor.AggregatePoint
+	24, // 21: sensor.AggregateReadingsResponse.series:type_name -> sensor.AggregateSeries
+	26, // 22: sensor.ListSensorsResponse.items:type_name -> sensor.SensorSummary
+	13, // 23: sensor.GetLatestReadingRequest.id:type_name -> sensor.IdCombination
+	38, // 24: sensor.SensorMetadata.labels:type_name -> sensor.SensorMetadata.LabelsEntry
+	30, // 25: sensor.RegisterSensorRequest.sensor:type_name -> sensor.SensorMetadata
+	13, // 26: sensor.GetSensorRequest.id:type_name -> sensor.IdCombination
+	30, // 27: sensor.ListRegisteredSensorsResponse.items:type_name -> sensor.SensorMetadata
+	30, // 28: sensor.UpdateSensorRequest.sensor:type_name -> sensor.SensorMetadata
+	13, // 29: sensor.DeleteSensorRequest.id:type_name -> sensor.IdCombination
+	4,  // 30: sensor.IngestService.StreamReadings:input_type -> sensor.SensorReading
+	4,  // 31: sensor.IngestService.Readings:input_type -> sensor.SensorReading
+	6,  // 32: sensor.IngestService.ReadingsBatch:input_type -> sensor.SensorReadingBatch
+	10, // 33: sensor.IngestService.IngestStream:input_type -> sensor.SequencedReading
+	15, // 34: sensor.SensorQueryService.ListReadings:input_type -> sensor.ListReadingsRequest
+	17, // 35: sensor.SensorQueryService.UpdateReadings:input_type -> sensor.UpdateReadingsRequest
+	19, // 36: sensor.SensorQueryService.DeleteReadings:input_type -> sensor.DeleteReadingsRequest
+	22, // 37: sensor.SensorQueryService.AggregateReadings:input_type -> sensor.AggregateReadingsRequest
+	27, // 38: sensor.SensorQueryService.ListSensors:input_type -> sensor.ListSensorsRequest
+	29, // 39: sensor.SensorQueryService.GetLatestReading:input_type -> sensor.GetLatestReadingRequest
+	31, // 40: sensor.SensorQueryService.RegisterSensor:input_type -> sensor.RegisterSensorRequest
+	32, // 41: sensor.SensorQueryService.GetSensor:input_type -> sensor.GetSensorRequest
+	33, // 42: sensor.SensorQueryService.ListRegisteredSensors:input_type -> sensor.ListRegisteredSensorsRequest
+	35, // 43: sensor.SensorQueryService.UpdateSensor:input_type -> sensor.UpdateSensorRequest
+	36, // 44: sensor.SensorQueryService.DeleteSensor:input_type -> sensor.DeleteSensorRequest
+	21, // 45: sensor.SensorQueryService.WatchReadings:input_type -> sensor.WatchRequest
+	5,  // 46: sensor.IngestService.StreamReadings:output_type -> sensor.StreamAck
+	5,  // 47: sensor.IngestService.Readings:output_type -> sensor.StreamAck
+	9,  // 48: sensor.IngestService.ReadingsBatch:output_type -> sensor.BatchAck
+	12, // 49: sensor.IngestService.IngestStream:output_type -> sensor.IngestAck
+	16, // 50: sensor.SensorQueryService.ListReadings:output_type -> sensor.ListReadingsResponse
+	18, // 51: sensor.SensorQueryService.UpdateReadings:output_type -> sensor.UpdateReadingsResponse
+	20, // 52: sensor.SensorQueryService.DeleteReadings:output_type -> sensor.DeleteReadingsResponse
+	25, // 53: sensor.SensorQueryService.AggregateReadings:output_type -> sensor.AggregateReadingsResponse
+	28, // 54: sensor.SensorQueryService.ListSensors:output_type -> sensor.ListSensorsResponse
+	14, // 55: sensor.SensorQueryService.GetLatestReading:output_type -> sensor.StoredReading
+	30, // 56: sensor.SensorQueryService.RegisterSensor:output_type -> sensor.SensorMetadata
+	30, // 57: sensor.SensorQueryService.GetSensor:output_type -> sensor.SensorMetadata
+	34, // 58: sensor.SensorQueryService.ListRegisteredSensors:output_type -> sensor.ListRegisteredSensorsResponse
+	30, // 59: sensor.SensorQueryService.UpdateSensor:output_type -> sensor.SensorMetadata
+	37, // 60: sensor.SensorQueryService.DeleteSensor:output_type -> sensor.DeleteSensorResponse
+	4,  // 61: sensor.SensorQueryService.WatchReadings:output_type -> sensor.SensorReading
+	46, // [46:62] is the sub-list for method output_type
+	30, // [30:46] is the sub-list for method input_type
+	30, // [30:30] is the sub-list for extension type_name
+	30, // [30:30] is the sub-list for extension extendee
+	0,  // [0:30] is the sub-list for field type_name
 }
 
 func init() { file_common_protobuf_sensor_proto_init() }
@@ -193,18 +2894,22 @@ func file_common_protobuf_sensor_proto_init() {
 	if File_common_protobuf_sensor_proto != nil {
 		return
 	}
+	file_common_protobuf_sensor_proto_msgTypes[17].OneofWrappers = []any{}
+	file_common_protobuf_sensor_proto_msgTypes[19].OneofWrappers = []any{}
+	file_common_protobuf_sensor_proto_msgTypes[26].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_common_protobuf_sensor_proto_rawDesc), len(file_common_protobuf_sensor_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   2,
+			NumEnums:      4,
+			NumMessages:   35,
 			NumExtensions: 0,
-			NumServices:   1,
+			NumServices:   2,
 		},
 		GoTypes:           file_common_protobuf_sensor_proto_goTypes,
 		DependencyIndexes: file_common_protobuf_sensor_proto_depIdxs,
+		EnumInfos:         file_common_protobuf_sensor_proto_enumTypes,
 		MessageInfos:      file_common_protobuf_sensor_proto_msgTypes,
 	}.Build()
 	File_common_protobuf_sensor_proto = out.File
@@ -21,6 +21,8 @@ const _ = grpc.SupportPackageIsVersion9
 const (
 	IngestService_StreamReadings_FullMethodName = "/sensor.IngestService/StreamReadings"
 	IngestService_Readings_FullMethodName       = "/sensor.IngestService/Readings"
+	IngestService_ReadingsBatch_FullMethodName  = "/sensor.IngestService/ReadingsBatch"
+	IngestService_IngestStream_FullMethodName   = "/sensor.IngestService/IngestStream"
 )
 
 // IngestServiceClient is the client API for IngestService service.
@@ -29,6 +31,8 @@ const (
 type IngestServiceClient interface {
 	StreamReadings(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[SensorReading, StreamAck], error)
 	Readings(ctx context.Context, in *SensorReading, opts ...grpc.CallOption) (*StreamAck, error)
+	ReadingsBatch(ctx context.Context, in *SensorReadingBatch, opts ...grpc.CallOption) (*BatchAck, error)
+	IngestStream(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[SequencedReading, IngestAck], error)
 }
 
 type ingestServiceClient struct {
@@ -62,12 +66,37 @@ func (c *ingestServiceClient) Readings(ctx context.Context, in *SensorReading, o
 	return out, nil
 }
 
+func (c *ingestServiceClient) ReadingsBatch(ctx context.Context, in *SensorReadingBatch, opts ...grpc.CallOption) (*BatchAck, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchAck)
+	err := c.cc.Invoke(ctx, IngestService_ReadingsBatch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ingestServiceClient) IngestStream(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[SequencedReading, IngestAck], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &IngestService_ServiceDesc.Streams[1], IngestService_IngestStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SequencedReading, IngestAck]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type IngestService_IngestStreamClient = grpc.BidiStreamingClient[SequencedReading, IngestAck]
+
 // IngestServiceServer is the server API for IngestService service.
 // All implementations must embed UnimplementedIngestServiceServer
 // for forward compatibility.
 type IngestServiceServer interface {
 	StreamReadings(grpc.ClientStreamingServer[SensorReading, StreamAck]) error
 	Readings(context.Context, *SensorReading) (*StreamAck, error)
+	ReadingsBatch(context.Context, *SensorReadingBatch) (*BatchAck, error)
+	IngestStream(grpc.BidiStreamingServer[SequencedReading, IngestAck]) error
 	mustEmbedUnimplementedIngestServiceServer()
 }
 
@@ -84,6 +113,12 @@ func (UnimplementedIngestServiceServer) StreamReadings(grpc.ClientStreamingServe
 func (UnimplementedIngestServiceServer) Readings(context.Context, *SensorReading) (*StreamAck, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Readings not implemented")
 }
+func (UnimplementedIngestServiceServer) ReadingsBatch(context.Context, *SensorReadingBatch) (*BatchAck, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReadingsBatch not implemented")
+}
+func (UnimplementedIngestServiceServer) IngestStream(grpc.BidiStreamingServer[SequencedReading, IngestAck]) error {
+	return status.Errorf(codes.Unimplemented, "method IngestStream not implemented")
+}
 func (UnimplementedIngestServiceServer) mustEmbedUnimplementedIngestServiceServer() {}
 func (UnimplementedIngestServiceServer) testEmbeddedByValue()                       {}
 
@@ -130,6 +165,31 @@ func _IngestService_Readings_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _IngestService_ReadingsBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SensorReadingBatch)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IngestServiceServer).ReadingsBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IngestService_ReadingsBatch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IngestServiceServer).ReadingsBatch(ctx, req.(*SensorReadingBatch))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IngestService_IngestStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(IngestServiceServer).IngestStream(&grpc.GenericServerStream[SequencedReading, IngestAck]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type IngestService_IngestStreamServer = grpc.BidiStreamingServer[SequencedReading, IngestAck]
+
 // IngestService_ServiceDesc is the grpc.ServiceDesc for IngestService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -141,6 +201,10 @@ var IngestService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Readings",
 			Handler:    _IngestService_Readings_Handler,
 		},
+		{
+			MethodName: "ReadingsBatch",
+			Handler:    _IngestService_ReadingsBatch_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -148,6 +212,548 @@ var IngestService_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _IngestService_StreamReadings_Handler,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "IngestStream",
+			Handler:       _IngestService_IngestStream_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "common/protobuf/sensor.proto",
+}
+
+const (
+	SensorQueryService_ListReadings_FullMethodName          = "/sensor.SensorQueryService/ListReadings"
+	SensorQueryService_UpdateReadings_FullMethodName        = "/sensor.SensorQueryService/UpdateReadings"
+	SensorQueryService_DeleteReadings_FullMethodName        = "/sensor.SensorQueryService/DeleteReadings"
+	SensorQueryService_AggregateReadings_FullMethodName     = "/sensor.SensorQueryService/AggregateReadings"
+	SensorQueryService_ListSensors_FullMethodName           = "/sensor.SensorQueryService/ListSensors"
+	SensorQueryService_GetLatestReading_FullMethodName      = "/sensor.SensorQueryService/GetLatestReading"
+	SensorQueryService_RegisterSensor_FullMethodName        = "/sensor.SensorQueryService/RegisterSensor"
+	SensorQueryService_GetSensor_FullMethodName             = "/sensor.SensorQueryService/GetSensor"
+	SensorQueryService_ListRegisteredSensors_FullMethodName = "/sensor.SensorQueryService/ListRegisteredSensors"
+	SensorQueryService_UpdateSensor_FullMethodName          = "/sensor.SensorQueryService/UpdateSensor"
+	SensorQueryService_DeleteSensor_FullMethodName          = "/sensor.SensorQueryService/DeleteSensor"
+	SensorQueryService_WatchReadings_FullMethodName         = "/sensor.SensorQueryService/WatchReadings"
+)
+
+// SensorQueryServiceClient is the client API for SensorQueryService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type SensorQueryServiceClient interface {
+	ListReadings(ctx context.Context, in *ListReadingsRequest, opts ...grpc.CallOption) (*ListReadingsResponse, error)
+	UpdateReadings(ctx context.Context, in *UpdateReadingsRequest, opts ...grpc.CallOption) (*UpdateReadingsResponse, error)
+	DeleteReadings(ctx context.Context, in *DeleteReadingsRequest, opts ...grpc.CallOption) (*DeleteReadingsResponse, error)
+	AggregateReadings(ctx context.Context, in *AggregateReadingsRequest, opts ...grpc.CallOption) (*AggregateReadingsResponse, error)
+	ListSensors(ctx context.Context, in *ListSensorsRequest, opts ...grpc.CallOption) (*ListSensorsResponse, error)
+	// GetLatestReading fails with NOT_FOUND when the sensor has no readings.
+	GetLatestReading(ctx context.Context, in *GetLatestReadingRequest, opts ...grpc.CallOption) (*StoredReading, error)
+	// The sensor registry. RegisterSensor fails with ALREADY_EXISTS for a
+	// registered id1/id2, the others with NOT_FOUND for an unregistered one.
+	// DeleteSensor keeps the readings of the sensor.
+	RegisterSensor(ctx context.Context, in *RegisterSensorRequest, opts ...grpc.CallOption) (*SensorMetadata, error)
+	GetSensor(ctx context.Context, in *GetSensorRequest, opts ...grpc.CallOption) (*SensorMetadata, error)
+	ListRegisteredSensors(ctx context.Context, in *ListRegisteredSensorsRequest, opts ...grpc.CallOption) (*ListRegisteredSensorsResponse, error)
+	UpdateSensor(ctx context.Context, in *UpdateSensorRequest, opts ...grpc.CallOption) (*SensorMetadata, error)
+	DeleteSensor(ctx context.Context, in *DeleteSensorRequest, opts ...grpc.CallOption) (*DeleteSensorResponse, error)
+	// WatchReadings pushes readings as b-service stores them, from the moment the
+	// call starts. Nothing is replayed.
+	WatchReadings(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[SensorReading], error)
+}
+
+type sensorQueryServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSensorQueryServiceClient(cc grpc.ClientConnInterface) SensorQueryServiceClient {
+	return &sensorQueryServiceClient{cc}
+}
+
+func (c *sensorQueryServiceClient) ListReadings(ctx context.Context, in *ListReadingsRequest, opts ...grpc.CallOption) (*ListReadingsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListReadingsResponse)
+	err := c.cc.Invoke(ctx, SensorQueryService_ListReadings_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sensorQueryServiceClient) UpdateReadings(ctx context.Context, in *UpdateReadingsRequest, opts ...grpc.CallOption) (*UpdateReadingsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateReadingsResponse)
+	err := c.cc.Invoke(ctx, SensorQueryService_UpdateReadings_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sensorQueryServiceClient) DeleteReadings(ctx context.Context, in *DeleteReadingsRequest, opts ...grpc.CallOption) (*DeleteReadingsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteReadingsResponse)
+	err := c.cc.Invoke(ctx, SensorQueryService_DeleteReadings_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sensorQueryServiceClient) AggregateReadings(ctx context.Context, in *AggregateReadingsRequest, opts ...grpc.CallOption) (*AggregateReadingsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AggregateReadingsResponse)
+	err := c.cc.Invoke(ctx, SensorQueryService_AggregateReadings_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sensorQueryServiceClient) ListSensors(ctx context.Context, in *ListSensorsRequest, opts ...grpc.CallOption) (*ListSensorsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListSensorsResponse)
+	err := c.cc.Invoke(ctx, SensorQueryService_ListSensors_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sensorQueryServiceClient) GetLatestReading(ctx context.Context, in *GetLatestReadingRequest, opts ...grpc.CallOption) (*StoredReading, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StoredReading)
+	err := c.cc.Invoke(ctx, SensorQueryService_GetLatestReading_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sensorQueryServiceClient) RegisterSensor(ctx context.Context, in *RegisterSensorRequest, opts ...grpc.CallOption) (*SensorMetadata, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SensorMetadata)
+	err := c.cc.Invoke(ctx, SensorQueryService_RegisterSensor_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sensorQueryServiceClient) GetSensor(ctx context.Context, in *GetSensorRequest, opts ...grpc.CallOption) (*SensorMetadata, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SensorMetadata)
+	err := c.cc.Invoke(ctx, SensorQueryService_GetSensor_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sensorQueryServiceClient) ListRegisteredSensors(ctx context.Context, in *ListRegisteredSensorsRequest, opts ...grpc.CallOption) (*ListRegisteredSensorsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListRegisteredSensorsResponse)
+	err := c.cc.Invoke(ctx, SensorQueryService_ListRegisteredSensors_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sensorQueryServiceClient) UpdateSensor(ctx context.Context, in *UpdateSensorRequest, opts ...grpc.CallOption) (*SensorMetadata, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SensorMetadata)
+	err := c.cc.Invoke(ctx, SensorQueryService_UpdateSensor_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sensorQueryServiceClient) DeleteSensor(ctx context.Context, in *DeleteSensorRequest, opts ...grpc.CallOption) (*DeleteSensorResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteSensorResponse)
+	err := c.cc.Invoke(ctx, SensorQueryService_DeleteSensor_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sensorQueryServiceClient) WatchReadings(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[SensorReading], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &SensorQueryService_ServiceDesc.Streams[0], SensorQueryService_WatchReadings_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchRequest, SensorReading]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type SensorQueryService_WatchReadingsClient = grpc.ServerStreamingClient[SensorReading]
+
+// SensorQueryServiceServer is the server API for SensorQueryService service.
+// All implementations must embed UnimplementedSensorQueryServiceServer
+// for forward compatibility.
+type SensorQueryServiceServer interface {
+	ListReadings(context.Context, *ListReadingsRequest) (*ListReadingsResponse, error)
+	UpdateReadings(context.Context, *UpdateReadingsRequest) (*UpdateReadingsResponse, error)
+	DeleteReadings(context.Context, *DeleteReadingsRequest) (*DeleteReadingsResponse, error)
+	AggregateReadings(context.Context, *AggregateReadingsRequest) (*AggregateReadingsResponse, error)
+	ListSensors(context.Context, *ListSensorsRequest) (*ListSensorsResponse, error)
+	// GetLatestReading fails with NOT_FOUND when the sensor has no readings.
+	GetLatestReading(context.Context, *GetLatestReadingRequest) (*StoredReading, error)
+	// The sensor registry. RegisterSensor fails with ALREADY_EXISTS for a
+	// registered id1/id2, the others with NOT_FOUND for an unregistered one.
+	// DeleteSensor keeps the readings of the sensor.
+	RegisterSensor(context.Context, *RegisterSensorRequest) (*SensorMetadata, error)
+	GetSensor(context.Context, *GetSensorRequest) (*SensorMetadata, error)
+	ListRegisteredSensors(context.Context, *ListRegisteredSensorsRequest) (*ListRegisteredSensorsResponse, error)
+	UpdateSensor(context.Context, *UpdateSensorRequest) (*SensorMetadata, error)
+	DeleteSensor(context.Context, *DeleteSensorRequest) (*DeleteSensorResponse, error)
+	// WatchReadings pushes readings as b-service stores them, from the moment the
+	// call starts. Nothing is replayed.
+	WatchReadings(*WatchRequest, grpc.ServerStreamingServer[SensorReading]) error
+	mustEmbedUnimplementedSensorQueryServiceServer()
+}
+
+// UnimplementedSensorQueryServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedSensorQueryServiceServer struct{}
+
+func (UnimplementedSensorQueryServiceServer) ListReadings(context.Context, *ListReadingsRequest) (*ListReadingsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListReadings not implemented")
+}
+func (UnimplementedSensorQueryServiceServer) UpdateReadings(context.Context, *UpdateReadingsRequest) (*UpdateReadingsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateReadings not implemented")
+}
+func (UnimplementedSensorQueryServiceServer) DeleteReadings(context.Context, *DeleteReadingsRequest) (*DeleteReadingsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteReadings not implemented")
+}
+func (UnimplementedSensorQueryServiceServer) AggregateReadings(context.Context, *AggregateReadingsRequest) (*AggregateReadingsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AggregateReadings not implemented")
+}
+func (UnimplementedSensorQueryServiceServer) ListSensors(context.Context, *ListSensorsRequest) (*ListSensorsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSensors not implemented")
+}
+func (UnimplementedSensorQueryServiceServer) GetLatestReading(context.Context, *GetLatestReadingRequest) (*StoredReading, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLatestReading not implemented")
+}
+func (UnimplementedSensorQueryServiceServer) RegisterSensor(context.Context, *RegisterSensorRequest) (*SensorMetadata, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegisterSensor not implemented")
+}
+func (UnimplementedSensorQueryServiceServer) GetSensor(context.Context, *GetSensorRequest) (*SensorMetadata, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSensor not implemented")
+}
+func (UnimplementedSensorQueryServiceServer) ListRegisteredSensors(context.Context, *ListRegisteredSensorsRequest) (*ListRegisteredSensorsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRegisteredSensors not implemented")
+}
+func (UnimplementedSensorQueryServiceServer) UpdateSensor(context.Context, *UpdateSensorRequest) (*SensorMetadata, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateSensor not implemented")
+}
+func (UnimplementedSensorQueryServiceServer) DeleteSensor(context.Context, *DeleteSensorRequest) (*DeleteSensorResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteSensor not implemented")
+}
+func (UnimplementedSensorQueryServiceServer) WatchReadings(*WatchRequest, grpc.ServerStreamingServer[SensorReading]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchReadings not implemented")
+}
+func (UnimplementedSensorQueryServiceServer) mustEmbedUnimplementedSensorQueryServiceServer() {}
+func (UnimplementedSensorQueryServiceServer) testEmbeddedByValue()                            {}
+
+// UnsafeSensorQueryServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SensorQueryServiceServer will
+// result in compilation errors.
+type UnsafeSensorQueryServiceServer interface {
+	mustEmbedUnimplementedSensorQueryServiceServer()
+}
+
+func RegisterSensorQueryServiceServer(s grpc.ServiceRegistrar, srv SensorQueryServiceServer) {
+	// If the following call pancis, it indicates UnimplementedSensorQueryServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&SensorQueryService_ServiceDesc, srv)
+}
+
+func _SensorQueryService_ListReadings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListReadingsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SensorQueryServiceServer).ListReadings(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SensorQueryService_ListReadings_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SensorQueryServiceServer).ListReadings(ctx, req.(*ListReadingsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SensorQueryService_UpdateReadings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateReadingsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SensorQueryServiceServer).UpdateReadings(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SensorQueryService_UpdateReadings_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SensorQueryServiceServer).UpdateReadings(ctx, req.(*UpdateReadingsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SensorQueryService_DeleteReadings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteReadingsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SensorQueryServiceServer).DeleteReadings(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SensorQueryService_DeleteReadings_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SensorQueryServiceServer).DeleteReadings(ctx, req.(*DeleteReadingsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SensorQueryService_AggregateReadings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AggregateReadingsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SensorQueryServiceServer).AggregateReadings(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SensorQueryService_AggregateReadings_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SensorQueryServiceServer).AggregateReadings(ctx, req.(*AggregateReadingsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SensorQueryService_ListSensors_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSensorsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SensorQueryServiceServer).ListSensors(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SensorQueryService_ListSensors_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SensorQueryServiceServer).ListSensors(ctx, req.(*ListSensorsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SensorQueryService_GetLatestReading_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLatestReadingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SensorQueryServiceServer).GetLatestReading(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SensorQueryService_GetLatestReading_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SensorQueryServiceServer).GetLatestReading(ctx, req.(*GetLatestReadingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SensorQueryService_RegisterSensor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterSensorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SensorQueryServiceServer).RegisterSensor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SensorQueryService_RegisterSensor_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SensorQueryServiceServer).RegisterSensor(ctx, req.(*RegisterSensorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SensorQueryService_GetSensor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSensorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SensorQueryServiceServer).GetSensor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SensorQueryService_GetSensor_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SensorQueryServiceServer).GetSensor(ctx, req.(*GetSensorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SensorQueryService_ListRegisteredSensors_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRegisteredSensorsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SensorQueryServiceServer).ListRegisteredSensors(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SensorQueryService_ListRegisteredSensors_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SensorQueryServiceServer).ListRegisteredSensors(ctx, req.(*ListRegisteredSensorsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SensorQueryService_UpdateSensor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateSensorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SensorQueryServiceServer).UpdateSensor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SensorQueryService_UpdateSensor_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SensorQueryServiceServer).UpdateSensor(ctx, req.(*UpdateSensorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SensorQueryService_DeleteSensor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteSensorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SensorQueryServiceServer).DeleteSensor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SensorQueryService_DeleteSensor_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SensorQueryServiceServer).DeleteSensor(ctx, req.(*DeleteSensorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SensorQueryService_WatchReadings_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SensorQueryServiceServer).WatchReadings(m, &grpc.GenericServerStream[WatchRequest, SensorReading]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type SensorQueryService_WatchReadingsServer = grpc.ServerStreamingServer[SensorReading]
+
+// SensorQueryService_ServiceDesc is the grpc.ServiceDesc for SensorQueryService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var SensorQueryService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "sensor.SensorQueryService",
+	HandlerType: (*SensorQueryServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListReadings",
+			Handler:    _SensorQueryService_ListReadings_Handler,
+		},
+		{
+			MethodName: "UpdateReadings",
+			Handler:    _SensorQueryService_UpdateReadings_Handler,
+		},
+		{
+			MethodName: "DeleteReadings",
+			Handler:    _SensorQueryService_DeleteReadings_Handler,
+		},
+		{
+			MethodName: "AggregateReadings",
+			Handler:    _SensorQueryService_AggregateReadings_Handler,
+		},
+		{
+			MethodName: "ListSensors",
+			Handler:    _SensorQueryService_ListSensors_Handler,
+		},
+		{
+			MethodName: "GetLatestReading",
+			Handler:    _SensorQueryService_GetLatestReading_Handler,
+		},
+		{
+			MethodName: "RegisterSensor",
+			Handler:    _SensorQueryService_RegisterSensor_Handler,
+		},
+		{
+			MethodName: "GetSensor",
+			Handler:    _SensorQueryService_GetSensor_Handler,
+		},
+		{
+			MethodName: "ListRegisteredSensors",
+			Handler:    _SensorQueryService_ListRegisteredSensors_Handler,
+		},
+		{
+			MethodName: "UpdateSensor",
+			Handler:    _SensorQueryService_UpdateSensor_Handler,
+		},
+		{
+			MethodName: "DeleteSensor",
+			Handler:    _SensorQueryService_DeleteSensor_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchReadings",
+			Handler:       _SensorQueryService_WatchReadings_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "common/protobuf/sensor.proto",
 }
@@ -1,6 +1,9 @@
 -- Create tables for sensor ingestion
 -- Database is created by env MYSQL_DATABASE=sensors (docker entrypoint)
 
+-- b-service/migrate/mysql is the source of truth, `b-service migrate up` applies it.
+-- This file is the same schema for setups that load it by hand (db-init.sh).
+
 -- Use strict SQL mode and UTC timestamps in your server config for consistency.
 
 -- Drop & recreate table for idempotent local dev (comment DROP in prod)
@@ -14,9 +17,14 @@ CREATE TABLE IF NOT EXISTS sensor_readings (
   id2           INT NOT NULL,
   ts            TIMESTAMP(6) NOT NULL,        -- event time (microsecond precision)
   created_at    TIMESTAMP(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+  reading_key   VARCHAR(64) NULL,             -- idempotency key, client supplied or "id1:id2:timestamp_ms"
+  principal     VARCHAR(64) NULL,             -- authenticated gRPC caller that ingested the reading
 
   PRIMARY KEY (reading_id),
 
+  -- Duplicate suppression; NULL keys never conflict
+  UNIQUE KEY uk_reading_key (reading_key),
+
   -- Common query patterns
   KEY idx_ids_ts (id1, id2, ts),
   KEY idx_ts (ts),
@@ -26,6 +34,9 @@ CREATE TABLE IF NOT EXISTS sensor_readings (
   CONSTRAINT chk_id1_uppercase CHECK (id1 REGEXP '^[A-Z0-9]{1,8}$')
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
 
+-- Databases created before reading_key or principal existed are upgraded by
+-- migrations 0002 and 0003.
+
 -- Optional helper view for quick counts per type per minute
 -- CREATE OR REPLACE VIEW v_counts_per_minute AS
 -- SELECT sensor_type, DATE_FORMAT(ts, '%Y-%m-%d %H:%i:00') AS minute, COUNT(*) AS cnt